@@ -196,6 +196,8 @@ func (h *ChainTaskHandler) RunTaskChain(video models2.TbVideo) {
 	// 任务2: 生成字幕文件
 	subtitleTask := handlers.NewGenerateSubtitles("生成字幕", h.App, stateManager, h.App.CosClient, h.SavedVideoService)
 	chain.AddTask(h.wrapTaskWithStepTracking(subtitleTask, video.VideoId))
+	// 翻译前对原文字幕重新断句
+	chain.AddTask(handlers.NewResegmentSubtitles("字幕断句", h.App, stateManager, h.App.CosClient, handlers.SegmentStageSource))
 
 	chain.AddTask(handlers.NewDownloadImgHandler("下载封面", h.App, stateManager, h.App.CosClient))
 	// 任务3: 翻译字幕（动态检查配置）
@@ -206,6 +208,10 @@ func (h *ChainTaskHandler) RunTaskChain(video models2.TbVideo) {
 	metadataTask := handlers.NewGenerateMetadata("生成视频元数据", h.App, stateManager, h.App.CosClient, "", h.Db, h.SavedVideoService)
 	chain.AddTask(h.wrapTaskWithStepTracking(metadataTask, video.VideoId))
 
+	// 任务5: 根据带时间的译文字幕生成分段章节（长视频）
+	chapterTask := handlers.NewGenerateChapters("生成章节", h.App, stateManager, h.App.CosClient, h.Db, h.SavedVideoService)
+	chain.AddTask(h.wrapTaskWithStepTracking(chapterTask, video.VideoId))
+
 	// 注意: 上传任务已移至 UploadScheduler 定时执行
 	// - 视频上传: 每小时上传一个视频
 	// - 字幕上传: 视频上传后1小时再上传字幕
@@ -227,7 +233,14 @@ func (h *ChainTaskHandler) RunTaskChain(video models2.TbVideo) {
 	}
 
 	// 根据执行结果更新任务状态
-	if success {
+	if success && services.NewSubtitleEditService(h.Db, h.App.Config).RequireReview() {
+		// 需要人工审核字幕，审核通过后才进入上传队列
+		if err := h.updateSavedVideoStatus(video.Id, services.VideoStatusAwaitingReview); err != nil {
+			h.App.Logger.Errorf("更新任务状态为等待字幕审核时出错: %v", err)
+		} else {
+			h.App.Logger.Infof("任务 %s 执行成功，等待字幕审核", video.VideoId)
+		}
+	} else if success {
 		// 任务成功完成，更新状态为完成
 		if err := h.updateSavedVideoStatus(video.Id, "200"); err != nil {
 			h.App.Logger.Errorf("更新任务状态为完成时出错: %v", err)
@@ -301,6 +314,8 @@ func (h *ChainTaskHandler) RunSingleTaskStep(videoID, stepName string) error {
 	case "生成元数据":
 		// 不再在这里检查配置，让任务运行时动态检查最新配置
 		task = handlers.NewGenerateMetadata("生成元数据", h.App, stateManager, h.App.CosClient, "", h.Db, h.SavedVideoService)
+	case "生成章节":
+		task = handlers.NewGenerateChapters("生成章节", h.App, stateManager, h.App.CosClient, h.Db, h.SavedVideoService)
 	case "上传到Bilibili":
 		task = handlers.NewUploadToBilibili("上传到Bilibili", h.App, stateManager, h.App.CosClient, h.SavedVideoService)
 	case "上传字幕到Bilibili":
@@ -314,6 +329,11 @@ func (h *ChainTaskHandler) RunSingleTaskStep(videoID, stepName string) error {
 	if task != nil {
 		chain.AddTask(task)
 	}
+	// 重新生成字幕后需要重新校正时间轴和断句
+	if stepName == "生成字幕" {
+		chain.AddTask(handlers.NewAlignSubtitles("字幕对齐", h.App, stateManager, h.App.CosClient, h.SavedVideoService))
+		chain.AddTask(handlers.NewResegmentSubtitles("字幕断句", h.App, stateManager, h.App.CosClient, handlers.SegmentStageSource))
+	}
 
 	h.App.Logger.Infof("开始执行单个任务步骤: %s (VideoID: %s)", stepName, videoID)
 
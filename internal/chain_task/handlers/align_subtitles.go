@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/difyz9/ytb2bili/internal/chain_task/base"
+	"github.com/difyz9/ytb2bili/internal/chain_task/manager"
+	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/services"
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/cos"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
+	"github.com/difyz9/ytb2bili/pkg/utils"
+)
+
+// AlignSubtitles 字幕时间轴校正
+// 提交的字幕或 YouTube 字幕可能与下载的视频剪辑不一致（片头不同、删除了赞助片段、帧率换算等），
+// 用 ffmpeg silencedetect 检测语音起点，对原文字幕估计整体偏移或线性拉伸并应用，校正结果写入 context["subtitle_alignment"]
+// 视频在上传阶段才下载，此时译文和章节已按校正前的时间轴生成，一并应用相同的校正
+type AlignSubtitles struct {
+	base.BaseTask
+	App               *core.AppServer
+	SavedVideoService *services.SavedVideoService
+}
+
+func NewAlignSubtitles(name string, app *core.AppServer, stateManager *manager.StateManager, client *cos.CosClient, savedVideoService *services.SavedVideoService) *AlignSubtitles {
+	return &AlignSubtitles{
+		BaseTask: base.BaseTask{
+			Name:         name,
+			StateManager: stateManager,
+			Client:       client,
+		},
+		App:               app,
+		SavedVideoService: savedVideoService,
+	}
+}
+
+func (t *AlignSubtitles) Execute(context map[string]interface{}) bool {
+	if t.App.Config.SubtitleConfig == nil || !t.App.Config.SubtitleConfig.Align.Enabled {
+		t.App.Logger.Debug("字幕时间轴校正未启用，跳过")
+		return true
+	}
+	config := &t.App.Config.SubtitleConfig.Align
+
+	// 语音识别的时间轴本身来自视频音频，无需校正
+	if source, _ := context["subtitle_source"].(string); source == model.SubtitleSourceASR {
+		t.App.Logger.Debug("字幕来自语音识别，跳过时间轴校正")
+		return true
+	}
+
+	srtPath := filepath.Join(t.StateManager.CurrentDir, fmt.Sprintf("%s.srt", t.StateManager.VideoID))
+	if _, err := os.Stat(srtPath); os.IsNotExist(err) {
+		t.App.Logger.Debug("字幕文件不存在，跳过时间轴校正")
+		return true
+	}
+
+	videoPath := sourceVideo(t.StateManager)
+	if videoPath == "" {
+		t.App.Logger.Info("ℹ️  视频文件尚未下载，跳过字幕时间轴校正")
+		context["subtitle_alignment"] = map[string]interface{}{
+			"method": subtitle.AlignMethodNone,
+			"reason": "视频文件尚未下载",
+		}
+		return true
+	}
+
+	cues, err := subtitle.ReadFile(srtPath)
+	if err != nil {
+		t.App.Logger.Errorf("❌ 读取字幕文件失败: %v", err)
+		context["error"] = fmt.Sprintf("读取字幕文件失败: %v", err)
+		return false
+	}
+	if len(cues) == 0 {
+		return true
+	}
+
+	t.App.Logger.Infof("⏱️  检测语音区间: %s", filepath.Base(videoPath))
+	silences, duration, err := utils.DetectSilence(videoPath, config.NoiseDB, config.MinSilence)
+	if err != nil {
+		// 校正只是优化，检测失败不影响后续任务
+		t.App.Logger.Warnf("⚠️  %v，跳过字幕时间轴校正", err)
+		return true
+	}
+
+	intervals := make([]subtitle.Interval, len(silences))
+	for i, silence := range silences {
+		intervals[i] = subtitle.Interval{Start: subtitle.Seconds(silence.Start), End: subtitle.Seconds(silence.End)}
+	}
+	speech := subtitle.SpeechIntervals(intervals, subtitle.Seconds(duration))
+
+	alignment := subtitle.EstimateAlignment(cues, speech, alignOptions(config))
+	context["subtitle_alignment"] = alignmentReport(alignment)
+
+	if alignment.Method == subtitle.AlignMethodNone {
+		t.App.Logger.Infof("✓ 字幕时间轴无需校正: %s（语音起点匹配 %d/%d 条）", alignment.Reason, alignment.Matched, alignment.Total)
+		return true
+	}
+
+	aligned := subtitle.ApplyAlignment(cues, alignment)
+	// <videoID>.srt 是翻译的输入，en.srt 是上传的英文字幕
+	for _, path := range []string{srtPath, t.StateManager.OriginalSRT} {
+		if err := subtitle.WriteFile(path, aligned); err != nil {
+			t.App.Logger.Errorf("❌ 写入校正后的字幕失败: %v", err)
+			context["error"] = fmt.Sprintf("写入校正后的字幕失败: %v", err)
+			return false
+		}
+	}
+	if err := t.alignTranslations(aligned, alignment); err != nil {
+		t.App.Logger.Errorf("❌ 校正译文字幕失败: %v", err)
+		context["error"] = fmt.Sprintf("校正译文字幕失败: %v", err)
+		return false
+	}
+	t.alignChapters(alignment)
+
+	t.App.Logger.Infof("✅ 字幕时间轴已校正 (%s): 偏移 %+.2fs, 拉伸 ×%.4f, 语音起点匹配 %d → %d/%d 条",
+		alignment.Method, alignment.Offset.Seconds(), alignment.Scale, alignment.Baseline, alignment.Matched, alignment.Total)
+	return true
+}
+
+// alignTranslations 已翻译时对各语言的译文应用相同的校正，并重新生成双语字幕
+// 断句后的字幕由之后的断句步骤重新生成
+func (t *AlignSubtitles) alignTranslations(original []subtitle.Cue, alignment subtitle.Alignment) error {
+	languages := TargetLanguages(t.App.Config)
+	for _, lang := range languages {
+		for _, path := range []string{
+			t.StateManager.TranslatedSRTPath(lang),
+			t.StateManager.TranslatedVTTPath(lang),
+		} {
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+			cues, err := subtitle.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if err := subtitle.WriteFile(path, subtitle.ApplyAlignment(cues, alignment)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := os.Stat(t.StateManager.BilingualSRT); err != nil {
+		return nil
+	}
+	translated, err := subtitle.ReadFile(t.StateManager.TranslatedSRTPath(languages[0]))
+	if err != nil {
+		return nil
+	}
+	_, err = WriteBilingualSubtitles(t.App.Config, t.StateManager, original, translated)
+	return err
+}
+
+// alignChapters 已生成章节时对章节时间应用相同的校正
+func (t *AlignSubtitles) alignChapters(alignment subtitle.Alignment) {
+	savedVideo, err := t.SavedVideoService.GetVideoByVideoID(t.StateManager.VideoID)
+	if err != nil || savedVideo.GeneratedChapters == "" {
+		return
+	}
+	var chapters []services.MetadataChapter
+	if err := json.Unmarshal([]byte(savedVideo.GeneratedChapters), &chapters); err != nil || len(chapters) == 0 {
+		return
+	}
+
+	aligned := services.AlignChapters(chapters, alignment)
+	saveVideoChapters(t.App, t.SavedVideoService, t.StateManager, aligned)
+	t.App.Logger.Infof("✓ 已按校正后的时间轴更新 %d 个章节", len(aligned))
+}
+
+// alignOptions 将配置转换为时间轴校正规则
+func alignOptions(config *types.AlignConfig) subtitle.AlignOptions {
+	return subtitle.AlignOptions{
+		MaxShift:      subtitle.Seconds(config.MaxShift),
+		MaxStretch:    config.MaxStretch,
+		MinShift:      subtitle.Seconds(config.MinShift),
+		Tolerance:     subtitle.Seconds(config.Tolerance),
+		MinMatchRatio: config.MinMatchRatio,
+	}
+}
+
+// alignmentReport 校正结果（保存到任务步骤结果中）
+func alignmentReport(alignment subtitle.Alignment) map[string]interface{} {
+	return map[string]interface{}{
+		"method":        alignment.Method,
+		"offset_ms":     alignment.Offset.Milliseconds(),
+		"scale":         alignment.Scale,
+		"total":         alignment.Total,
+		"baseline":      alignment.Baseline,
+		"matched":       alignment.Matched,
+		"mean_error_ms": alignment.MeanErrorMs,
+		"speech_onsets": alignment.SpeechOnsets,
+		"reason":        alignment.Reason,
+	}
+}
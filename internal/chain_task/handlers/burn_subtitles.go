@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/difyz9/ytb2bili/internal/chain_task/base"
+	"github.com/difyz9/ytb2bili/internal/chain_task/manager"
+	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/services"
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/cos"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
+	"github.com/difyz9/ytb2bili/pkg/utils"
+)
+
+// BurnSubtitles 硬字幕烧录
+// 将译文（或双语）字幕按配置的字体、字号、描边和位置渲染为 ASS，再用 ffmpeg 烧录到 <videoID>_hardsub.mp4，
+// 原视频保留不变；启用硬字幕时 UploadToBilibili 优先上传烧录后的视频
+type BurnSubtitles struct {
+	base.BaseTask
+	App *core.AppServer
+}
+
+func NewBurnSubtitles(name string, app *core.AppServer, stateManager *manager.StateManager, client *cos.CosClient) *BurnSubtitles {
+	return &BurnSubtitles{
+		BaseTask: base.BaseTask{
+			Name:         name,
+			StateManager: stateManager,
+			Client:       client,
+		},
+		App: app,
+	}
+}
+
+func (t *BurnSubtitles) Execute(context map[string]interface{}) bool {
+	config := t.App.Config.HardSubConfig
+	video, _ := services.NewSavedVideoService(t.App.DB).GetVideoByVideoID(t.StateManager.VideoID)
+	tid := PartitionTid(t.App.Config, video)
+	if !config.AppliesTo(tid) {
+		t.App.Logger.Debugf("硬字幕未启用或分区 %d 不需要烧录，跳过", tid)
+		return true
+	}
+
+	subtitlePath := t.subtitlePath(config)
+	if subtitlePath == "" {
+		t.App.Logger.Warn("⚠️  没有找到可烧录的字幕文件，将上传原视频")
+		return true
+	}
+
+	inputVideo := sourceVideo(t.StateManager)
+	if inputVideo == "" {
+		t.App.Logger.Error("❌ 未找到原视频文件，无法烧录字幕")
+		context["error"] = "未找到原视频文件，无法烧录字幕"
+		return false
+	}
+
+	// 重试上传时，字幕和原视频都没有变化则直接使用上次烧录的结果
+	if isNewerThan(t.StateManager.HardSubVideo, subtitlePath, inputVideo) {
+		t.App.Logger.Infof("✓ 已存在烧录字幕的视频，跳过: %s", filepath.Base(t.StateManager.HardSubVideo))
+		context["hardsub_video_path"] = t.StateManager.HardSubVideo
+		return true
+	}
+
+	cues, err := subtitle.ReadFile(subtitlePath)
+	if err != nil {
+		t.App.Logger.Errorf("❌ 读取字幕文件失败: %v", err)
+		context["error"] = fmt.Sprintf("读取字幕文件失败: %v", err)
+		return false
+	}
+	if len(cues) == 0 {
+		t.App.Logger.Warn("⚠️  字幕内容为空，将上传原视频")
+		return true
+	}
+
+	style := hardSubStyle(config)
+	var data []byte
+	if subtitlePath == t.StateManager.BilingualSRT {
+		secondary := subtitle.DefaultSecondaryASSStyle()
+		secondary.FontName = style.FontName
+		secondary.FontSize = style.FontSize * 3 / 4
+		secondary.OutlineColour = style.OutlineColour
+		secondary.Outline = style.Outline
+		secondary.Alignment = style.Alignment
+		secondary.MarginV = style.MarginV
+		data = subtitle.MarshalBilingualASS(cues, style, secondary)
+	} else {
+		data = subtitle.MarshalASS(cues, style)
+	}
+	if err := os.WriteFile(t.StateManager.HardSubASS, data, 0644); err != nil {
+		t.App.Logger.Errorf("❌ 写入 ASS 字幕失败: %v", err)
+		context["error"] = fmt.Sprintf("写入 ASS 字幕失败: %v", err)
+		return false
+	}
+
+	t.App.Logger.Infof("🔥 开始烧录字幕: %s + %s", filepath.Base(inputVideo), filepath.Base(subtitlePath))
+
+	// 先输出到临时文件，成功后再改名，避免中断时留下不完整的视频被上传
+	tmpPath := t.StateManager.HardSubVideo + ".part"
+	defer os.Remove(tmpPath)
+	if err := utils.BurnSubtitles(inputVideo, t.StateManager.HardSubASS, tmpPath, config.FontsDir, config.Preset, config.CRF); err != nil {
+		t.App.Logger.Errorf("❌ %v", err)
+		context["error"] = err.Error()
+		return false
+	}
+	if err := os.Rename(tmpPath, t.StateManager.HardSubVideo); err != nil {
+		t.App.Logger.Errorf("❌ 保存烧录字幕的视频失败: %v", err)
+		context["error"] = fmt.Sprintf("保存烧录字幕的视频失败: %v", err)
+		return false
+	}
+
+	t.App.Logger.Infof("✅ 字幕烧录完成: %s", filepath.Base(t.StateManager.HardSubVideo))
+	context["hardsub_video_path"] = t.StateManager.HardSubVideo
+	return true
+}
+
+// subtitlePath 需要烧录的字幕：双语模式使用双语字幕，否则使用主语言最终的译文字幕
+func (t *BurnSubtitles) subtitlePath(config *types.HardSubConfig) string {
+	candidates := []string{t.StateManager.FinalSRTPath(TargetLanguages(t.App.Config)[0])}
+	if config.Source == types.HardSubSourceBilingual {
+		candidates = append([]string{t.StateManager.BilingualSRT}, candidates...)
+	}
+	for _, path := range candidates {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// sourceVideo 原视频：优先 <videoID>.mp4，否则使用目录中其他非烧录的视频文件
+func sourceVideo(stateManager *manager.StateManager) string {
+	if _, err := os.Stat(stateManager.InputVideoPath); err == nil {
+		return stateManager.InputVideoPath
+	}
+	files, err := listVideoFiles(stateManager.CurrentDir)
+	if err != nil {
+		return ""
+	}
+	for _, file := range files {
+		if file != stateManager.HardSubVideo {
+			return file
+		}
+	}
+	return ""
+}
+
+// hardSubStyle 根据配置生成 ASS 样式
+func hardSubStyle(config *types.HardSubConfig) subtitle.ASSStyle {
+	style := subtitle.DefaultASSStyle()
+	if config.FontName != "" {
+		style.FontName = config.FontName
+	}
+	if config.FontSize > 0 {
+		style.FontSize = config.FontSize
+	}
+	if config.PrimaryColour != "" {
+		style.PrimaryColour = config.PrimaryColour
+	}
+	if config.OutlineColour != "" {
+		style.OutlineColour = config.OutlineColour
+	}
+	if config.Outline > 0 {
+		style.Outline = config.Outline
+	}
+	if config.MarginV > 0 {
+		style.MarginV = config.MarginV
+	}
+	style.Bold = config.Bold
+
+	switch strings.ToLower(config.Position) {
+	case types.HardSubPositionTop:
+		style.Alignment = 8
+	case types.HardSubPositionMiddle:
+		style.Alignment = 5
+	default:
+		style.Alignment = 2
+	}
+	return style
+}
+
+// PartitionTid 投稿分区（与 buildStudioInfo 保持一致，默认 122）
+// 启用 MetadataConfig.UseSuggestedTid 时优先使用元数据生成步骤建议的分区，video 可以为 nil
+func PartitionTid(config *types.AppConfig, video *model.SavedVideo) int {
+	if config.MetadataConfig != nil && config.MetadataConfig.UseSuggestedTid && video != nil && video.GeneratedTid > 0 {
+		return video.GeneratedTid
+	}
+	if config.BilibiliConfig != nil && config.BilibiliConfig.Tid > 0 {
+		return config.BilibiliConfig.Tid
+	}
+	return 122
+}
+
+// isNewerThan 文件 path 存在且比 sources 都新
+func isNewerThan(path string, sources ...string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	for _, source := range sources {
+		sourceInfo, err := os.Stat(source)
+		if err != nil || sourceInfo.ModTime().After(info.ModTime()) {
+			return false
+		}
+	}
+	return true
+}
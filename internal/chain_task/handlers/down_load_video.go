@@ -103,27 +103,40 @@ func (t *DownloadVideo) Execute(context map[string]interface{}) bool {
 		return false
 	}
 
-	// 3. 尝试下载（先用代理，失败后不用代理重试）
+	// 3. 尝试下载（依次使用代理池中的代理，开启 direct_fallback 时全部失败后不用代理重试）
 	videoURL := t.getVideoURL()
-	useProxy := t.App.Config != nil && t.App.Config.ProxyConfig != nil &&
-		t.App.Config.ProxyConfig.UseProxy && t.App.Config.ProxyConfig.ProxyHost != ""
 
-	// 第一次尝试：使用代理（如果配置了）
-	if useProxy {
-		t.App.Logger.Info("🔄 尝试使用代理下载...")
-		if t.executeDownload(ytdlpPath, videoURL, true, context) {
-			return true
+	channel := ""
+	if t.SavedVideoService != nil {
+		if savedVideo, err := t.SavedVideoService.GetVideoByVideoID(t.StateManager.VideoID); err == nil {
+			channel = savedVideo.ChannelID
 		}
-		t.App.Logger.Warn("⚠️ 代理下载失败，尝试不使用代理重试...")
 	}
+	cookies := NewYtDlpCookieJar(t.App, videoURL, channel)
+	defer cookies.Cleanup()
 
-	// 第二次尝试：不使用代理
-	t.App.Logger.Info("🔄 尝试不使用代理下载...")
-	return t.executeDownload(ytdlpPath, videoURL, false, context)
+	for _, proxy := range t.App.ProxyPool.Attempts(videoURL) {
+		if proxy != "" {
+			t.App.Logger.Info("🔄 尝试使用代理下载...")
+		} else {
+			t.App.Logger.Info("🔄 尝试不使用代理下载...")
+			t.App.ProxyPool.ReportDirect(videoURL)
+		}
+		if t.executeDownload(ytdlpPath, videoURL, proxy, cookies, context) {
+			t.App.ProxyPool.ReportSuccess(proxy)
+			return true
+		}
+		if proxy != "" {
+			errMsg, _ := context["error"].(string)
+			t.App.ProxyPool.ReportFailure(proxy, errMsg)
+			t.App.Logger.Warn("⚠️ 代理下载失败，切换到下一个代理重试...")
+		}
+	}
+	return false
 }
 
 // executeDownload 执行实际的下载操作
-func (t *DownloadVideo) executeDownload(ytdlpPath, videoURL string, useProxy bool, context map[string]interface{}) bool {
+func (t *DownloadVideo) executeDownload(ytdlpPath, videoURL, proxy string, cookies *YtDlpCookieJar, context map[string]interface{}) bool {
 	// 构建下载命令
 	command := []string{
 		ytdlpPath,
@@ -132,33 +145,14 @@ func (t *DownloadVideo) executeDownload(ytdlpPath, videoURL string, useProxy boo
 		"--merge-output-format", "mp4",
 	}
 
-	// 检查是否存在 cookies.txt
-	configDir := filepath.Dir(t.App.Config.Path)
-	cookiesPath := filepath.Join(configDir, "cookies.txt")
-
-	// 如果配置文件目录下的 cookies.txt 不存在，尝试当前目录
-	if _, err := os.Stat(cookiesPath); err != nil {
-		cookiesPath = "cookies.txt"
-	}
-
-	if _, err := os.Stat(cookiesPath); err == nil {
-		absPath, _ := filepath.Abs(cookiesPath)
-		command = append(command, "--cookies", absPath)
-		t.App.Logger.Infof("🍪 使用 Cookies 文件: %s", absPath)
-	} else {
-		// 如果没有 cookies 文件，尝试从浏览器读取（Chrome 优先）
-		t.App.Logger.Info("🍪 未找到 cookies 文件，尝试从浏览器读取...")
-		command = append(command, "--cookies-from-browser", "chrome")
-		t.App.Logger.Info("🍪 将从 Chrome 浏览器读取 cookies")
-		t.App.Logger.Warn("⚠️ 未找到 cookies.txt，可能会遇到 'Sign in to confirm you're not a bot' 错误")
-	}
+	// 添加 cookies 参数（托管的 cookies 配置，或旧的 cookies.txt）
+	command = append(command, cookies.Args()...)
 
 	// 添加代理配置（如果需要）
-	if useProxy && t.App.Config != nil && t.App.Config.ProxyConfig != nil &&
-		t.App.Config.ProxyConfig.UseProxy && t.App.Config.ProxyConfig.ProxyHost != "" {
-		command = append(command, "--proxy", t.App.Config.ProxyConfig.ProxyHost)
-		t.App.Logger.Infof("📡 使用代理: %s", t.App.Config.ProxyConfig.ProxyHost)
-	} else if !useProxy {
+	if proxy != "" {
+		command = append(command, "--proxy", proxy)
+		t.App.Logger.Info("📡 使用代理池中的代理")
+	} else {
 		t.App.Logger.Info("🌐 不使用代理")
 	}
 
@@ -222,6 +216,12 @@ func (t *DownloadVideo) executeDownload(ytdlpPath, videoURL string, useProxy boo
 
 	// 等待命令完成
 	if err := cmd.Wait(); err != nil {
+		// cookies 被限流或年龄限制时切换到下一个 cookies 配置重试
+		if cookies.Rotate(errorOutput.String()) {
+			t.App.Logger.Info("🔄 使用新的 Cookies 配置重试下载...")
+			return t.executeDownload(ytdlpPath, videoURL, proxy, cookies, context)
+		}
+
 		// 构建详细的错误信息
 		errorMsg := fmt.Sprintf("下载失败: %v", err)
 
@@ -242,7 +242,7 @@ func (t *DownloadVideo) executeDownload(ytdlpPath, videoURL string, useProxy boo
 		// 检查常见错误并给出建议
 		if strings.Contains(errorOutput.String(), "Sign in to confirm") ||
 			strings.Contains(errorOutput.String(), "not a bot") {
-			errorMsg += "\n\n💡 建议: 需要 cookies.txt 文件来绕过机器人验证"
+			errorMsg += "\n\n💡 建议: 需要 cookies 来绕过机器人验证，请通过 /api/v1/cookies 上传 cookies 文件"
 			errorMsg += "\n   请参考文档: docs/setup/cookies-setup.md"
 		} else if strings.Contains(errorOutput.String(), "HTTP Error 403") {
 			errorMsg += "\n\n💡 建议: 访问被拒绝，可能需要配置代理或更新 cookies"
@@ -276,12 +276,13 @@ func (t *DownloadVideo) executeDownload(ytdlpPath, videoURL string, useProxy boo
 	}
 
 	// 11. 保存文件信息到 context
+	cookies.MarkSuccess()
 	context["downloaded_file"] = downloadedFile
 	t.App.Logger.Infof("✓ 视频下载成功: %s", downloadedFile)
 
 	// 12. 获取视频元数据（标题、描述等）
 	t.App.Logger.Info("📋 获取视频元数据...")
-	metadata, err := t.getVideoMetadata(ytdlpPath)
+	metadata, err := t.getVideoMetadata(ytdlpPath, cookies)
 	if err != nil {
 		t.App.Logger.Warnf("⚠️ 获取视频元数据失败: %v，将使用默认值", err)
 	} else {
@@ -298,6 +299,10 @@ func (t *DownloadVideo) executeDownload(ytdlpPath, videoURL string, useProxy boo
 			if err == nil {
 				savedVideo.Title = metadata.Title
 				savedVideo.Description = metadata.Description
+				if metadata.ChannelID != "" {
+					savedVideo.ChannelID = metadata.ChannelID
+					savedVideo.ChannelName = metadata.Channel
+				}
 				if err := t.SavedVideoService.UpdateVideo(savedVideo); err != nil {
 					t.App.Logger.Errorf("❌ 保存原始元数据到数据库失败: %v", err)
 				} else {
@@ -384,59 +389,49 @@ type VideoMetadataInfo struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
 	Uploader    string `json:"uploader"`
+	ChannelID   string `json:"channel_id"`
+	Channel     string `json:"channel"`
 	Duration    int    `json:"duration"`
 }
 
 // getVideoMetadata 使用 yt-dlp 获取视频元数据（带代理回退）
-func (t *DownloadVideo) getVideoMetadata(ytdlpPath string) (*VideoMetadataInfo, error) {
+func (t *DownloadVideo) getVideoMetadata(ytdlpPath string, cookies *YtDlpCookieJar) (*VideoMetadataInfo, error) {
 	videoURL := t.getVideoURL()
 
 	// 构建基础命令参数
 	args := []string{"--dump-json", "--no-download"}
 
 	// 添加 cookies 支持
-	configDir := filepath.Dir(t.App.Config.Path)
-	cookiesPath := filepath.Join(configDir, "cookies.txt")
-	if _, err := os.Stat(cookiesPath); err != nil {
-		cookiesPath = "cookies.txt"
-	}
-
-	if _, err := os.Stat(cookiesPath); err == nil {
-		absPath, _ := filepath.Abs(cookiesPath)
-		args = append(args, "--cookies", absPath)
-		t.App.Logger.Debugf("🍪 使用 Cookies 文件获取元数据: %s", absPath)
-	} else {
-		// 从浏览器读取 cookies
-		args = append(args, "--cookies-from-browser", "chrome")
-		t.App.Logger.Debug("🍪 从 Chrome 浏览器读取 cookies 获取元数据")
-	}
-
-	// 尝试使用代理
-	useProxy := t.App.Config != nil && t.App.Config.ProxyConfig != nil &&
-		t.App.Config.ProxyConfig.UseProxy && t.App.Config.ProxyConfig.ProxyHost != ""
-
-	if useProxy {
-		args = append(args, "--proxy", t.App.Config.ProxyConfig.ProxyHost)
-		t.App.Logger.Debugf("📡 使用代理获取元数据: %s", t.App.Config.ProxyConfig.ProxyHost)
-	}
-
-	args = append(args, videoURL)
-
-	// 第一次尝试（可能带代理）
-	cmd := exec.Command(ytdlpPath, args...)
-	output, err := cmd.Output()
+	args = append(args, cookies.Args()...)
+
+	// 依次尝试代理池中的代理，开启 direct_fallback 时最后直连
+	var output []byte
+	var err error
+	for _, proxy := range t.App.ProxyPool.Attempts(videoURL) {
+		cmdArgs := append([]string{}, args...)
+		if proxy != "" {
+			cmdArgs = append(cmdArgs, "--proxy", proxy)
+		} else {
+			t.App.ProxyPool.ReportDirect(videoURL)
+		}
+		cmdArgs = append(cmdArgs, videoURL)
 
-	// 如果使用代理失败，尝试不使用代理
-	if err != nil && useProxy {
-		t.App.Logger.Warnf("⚠️ 使用代理获取元数据失败，尝试不使用代理...")
-		argsNoProxy := []string{"--dump-json", "--no-download", videoURL}
-		cmd = exec.Command(ytdlpPath, argsNoProxy...)
+		cmd := exec.Command(ytdlpPath, cmdArgs...)
 		output, err = cmd.Output()
-		if err != nil {
-			return nil, fmt.Errorf("获取元数据失败: %v", err)
+		if err == nil {
+			t.App.ProxyPool.ReportSuccess(proxy)
+			break
 		}
-		t.App.Logger.Info("✓ 不使用代理成功获取元数据")
-	} else if err != nil {
+		if proxy != "" {
+			stderr := ""
+			if exitErr, ok := err.(*exec.ExitError); ok {
+				stderr = string(exitErr.Stderr)
+			}
+			t.App.ProxyPool.ReportFailure(proxy, stderr)
+			t.App.Logger.Warnf("⚠️ 使用代理获取元数据失败，尝试下一个代理...")
+		}
+	}
+	if err != nil {
 		return nil, fmt.Errorf("获取元数据失败: %v", err)
 	}
 
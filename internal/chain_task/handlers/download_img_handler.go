@@ -40,6 +40,7 @@ func (t *DownloadImgHandler) Execute(context map[string]interface{}) bool {
 		QualityFallback:  true,
 		CreateDirs:       true,
 		Overwrite:        false,
+		Transport:        t.App.ProxyPool.Transport(),
 	}
 
 	//utils.QualityMax,
@@ -0,0 +1,352 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
+)
+
+// CaptionFormat yt-dlp 返回的单个字幕格式
+type CaptionFormat struct {
+	Ext  string `json:"ext"`
+	URL  string `json:"url"`
+	Name string `json:"name"`
+}
+
+// captionInfo yt-dlp --dump-json 中与字幕相关的字段
+type captionInfo struct {
+	ChannelID         string                     `json:"channel_id"`
+	Channel           string                     `json:"channel"`
+	Language          string                     `json:"language"`
+	Subtitles         map[string][]CaptionFormat `json:"subtitles"`
+	AutomaticCaptions map[string][]CaptionFormat `json:"automatic_captions"`
+}
+
+// CaptionResult 字幕获取结果
+type CaptionResult struct {
+	Source    string                     // manual / auto
+	Lang      string                     // 选中的语言代码
+	FilePath  string                     // 下载的原始字幕文件
+	Subtitles []model.SavedVideoSubtitle // 解析后的字幕条目
+}
+
+// CaptionFetcher 通过 yt-dlp 获取 YouTube 人工/自动字幕
+type CaptionFetcher struct {
+	App       *core.AppServer
+	YtDlpPath string
+	OutputDir string
+	Cookies   *YtDlpCookieJar
+
+	ChannelID   string // 从 yt-dlp 元数据中获取的频道ID
+	ChannelName string // 频道名称
+}
+
+// NewCaptionFetcher 创建字幕获取器，channel 用于选择绑定到频道的 cookies 配置
+func NewCaptionFetcher(app *core.AppServer, ytdlpPath, outputDir, videoURL, channel string) *CaptionFetcher {
+	return &CaptionFetcher{
+		App:       app,
+		YtDlpPath: ytdlpPath,
+		OutputDir: outputDir,
+		Cookies:   NewYtDlpCookieJar(app, videoURL, channel),
+	}
+}
+
+// Close 清理临时 cookies 文件
+func (f *CaptionFetcher) Close() {
+	f.Cookies.Cleanup()
+}
+
+// Fetch 按 人工字幕 → 自动字幕 的顺序获取字幕
+func (f *CaptionFetcher) Fetch(videoURL string) (*CaptionResult, error) {
+	info, err := f.listCaptions(videoURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// 只使用视频原始语言的字幕，其他语言的字幕通常是 YouTube 自动翻译的
+	original := originalCaptionLang(info)
+	if original != "" {
+		f.App.Logger.Infof("🌐 视频原始语言: %s", original)
+	}
+
+	candidates := []struct {
+		source string
+		tracks map[string][]CaptionFormat
+	}{
+		{model.SubtitleSourceManual, info.Subtitles},
+		{model.SubtitleSourceAuto, info.AutomaticCaptions},
+	}
+
+	var preferred []string
+	if config := f.App.Config.SubtitleConfig; config != nil {
+		preferred = config.CaptionLanguages
+	}
+
+	for _, candidate := range candidates {
+		lang := pickCaptionLang(candidate.tracks, original, preferred)
+		if lang == "" {
+			f.App.Logger.Infof("ℹ️ 没有原始语言的%s字幕", captionSourceLabel(candidate.source))
+			continue
+		}
+
+		f.App.Logger.Infof("🎯 选择%s字幕语言: %s", captionSourceLabel(candidate.source), lang)
+		result, err := f.download(videoURL, candidate.source, lang)
+		if err != nil {
+			f.App.Logger.Warnf("⚠️ 下载%s字幕失败: %v", captionSourceLabel(candidate.source), err)
+			continue
+		}
+		if len(result.Subtitles) == 0 {
+			f.App.Logger.Warnf("⚠️ %s字幕解析结果为空", captionSourceLabel(candidate.source))
+			continue
+		}
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("视频没有原始语言的字幕")
+}
+
+// listCaptions 获取视频可用的字幕列表
+func (f *CaptionFetcher) listCaptions(videoURL string) (*captionInfo, error) {
+	output, err := f.runYtDlp([]string{"--dump-json", "--skip-download"}, videoURL)
+	if err != nil {
+		return nil, fmt.Errorf("获取字幕列表失败: %v", err)
+	}
+
+	var info captionInfo
+	if err := json.Unmarshal(output, &info); err != nil {
+		return nil, fmt.Errorf("解析字幕列表失败: %v", err)
+	}
+	f.ChannelID = info.ChannelID
+	f.ChannelName = info.Channel
+	return &info, nil
+}
+
+// download 下载指定来源和语言的字幕并解析
+func (f *CaptionFetcher) download(videoURL, source, lang string) (*CaptionResult, error) {
+	writeFlag := "--write-subs"
+	if source == model.SubtitleSourceAuto {
+		writeFlag = "--write-auto-subs"
+	}
+
+	// 删除之前下载的字幕文件，避免读到上次下载的其他来源或语言的字幕
+	if err := f.removeCaptionFiles(); err != nil {
+		return nil, err
+	}
+
+	args := []string{
+		"--skip-download",
+		writeFlag,
+		"--sub-langs", lang,
+		"--sub-format", "srv3/vtt/best",
+		"-P", f.OutputDir,
+		"-o", "captions.%(ext)s",
+	}
+	if _, err := f.runYtDlp(args, videoURL); err != nil {
+		return nil, err
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(f.OutputDir, "captions."+lang+".*"))
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("未找到下载的字幕文件")
+	}
+
+	filePath := matches[0]
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("读取字幕文件失败: %v", err)
+	}
+
+	format, err := subtitle.DetectFormat(filePath)
+	if err != nil {
+		return nil, err
+	}
+	cues, err := subtitle.Parse(format, data)
+	if err != nil {
+		return nil, err
+	}
+	// YouTube 自动字幕是滚动式的（每条重复上一条的内容），需要去掉重复行
+	if format == subtitle.FormatVTT {
+		cues = subtitle.RemoveRollingDuplicates(cues)
+	}
+	subtitles := cuesToSubtitles(subtitle.ClipOverlaps(cues), lang)
+
+	return &CaptionResult{
+		Source:    source,
+		Lang:      lang,
+		FilePath:  filePath,
+		Subtitles: subtitles,
+	}, nil
+}
+
+// removeCaptionFiles 删除输出目录中已下载的字幕文件
+func (f *CaptionFetcher) removeCaptionFiles() error {
+	matches, _ := filepath.Glob(filepath.Join(f.OutputDir, "captions.*"))
+	for _, path := range matches {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("删除旧字幕文件失败: %v", err)
+		}
+	}
+	return nil
+}
+
+// DownloadAudio 仅下载音频（用于语音识别），返回下载的文件路径
+func (f *CaptionFetcher) DownloadAudio(videoURL string) (string, error) {
+	args := []string{
+		"-f", "bestaudio/best",
+		"-P", f.OutputDir,
+		"-o", "audio_src.%(ext)s",
+	}
+	if _, err := f.runYtDlp(args, videoURL); err != nil {
+		return "", fmt.Errorf("下载音频失败: %v", err)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(f.OutputDir, "audio_src.*"))
+	if len(matches) == 0 {
+		return "", fmt.Errorf("未找到下载的音频文件")
+	}
+	return matches[0], nil
+}
+
+// runYtDlp 执行 yt-dlp（依次使用代理池中的代理，开启 direct_fallback 时全部失败后直连重试）
+// cookies 被限流或年龄限制时切换到下一个 cookies 配置重试
+func (f *CaptionFetcher) runYtDlp(args []string, videoURL string) ([]byte, error) {
+	var lastErr error
+	for _, proxy := range f.App.ProxyPool.Attempts(videoURL) {
+		if proxy == "" {
+			f.App.ProxyPool.ReportDirect(videoURL)
+		}
+		stderr := ""
+		for {
+			command := append([]string{}, args...)
+			command = append(command, f.Cookies.Args()...)
+			if proxy != "" {
+				command = append(command, "--proxy", proxy)
+			}
+			command = append(command, videoURL)
+
+			cmd := exec.Command(f.YtDlpPath, command...)
+			output, err := cmd.Output()
+			if err == nil {
+				f.Cookies.MarkSuccess()
+				f.App.ProxyPool.ReportSuccess(proxy)
+				return output, nil
+			}
+
+			stderr = ""
+			if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+				stderr = strings.TrimSpace(string(exitErr.Stderr))
+				lastErr = fmt.Errorf("%v: %s", err, stderr)
+			} else {
+				lastErr = err
+			}
+
+			if !f.Cookies.Rotate(stderr) {
+				break
+			}
+		}
+		if proxy != "" {
+			f.App.ProxyPool.ReportFailure(proxy, stderr)
+			f.App.Logger.Warnf("⚠️ 使用代理执行 yt-dlp 失败，尝试下一个代理: %v", lastErr)
+		}
+	}
+
+	return nil, lastErr
+}
+
+// originalCaptionLang 视频的原始语言：yt-dlp 元数据中的 language，没有时取自动字幕中的原始轨道(-orig)
+func originalCaptionLang(info *captionInfo) string {
+	if info.Language != "" {
+		return info.Language
+	}
+	var langs []string
+	for lang := range info.AutomaticCaptions {
+		if strings.HasSuffix(lang, "-orig") {
+			langs = append(langs, strings.TrimSuffix(lang, "-orig"))
+		}
+	}
+	if len(langs) == 0 {
+		return ""
+	}
+	sort.Strings(langs)
+	return langs[0]
+}
+
+// pickCaptionLang 从可用字幕中选择视频原始语言的字幕，没有时返回空字符串（改用自动字幕或语音识别）
+// 优先级：原始轨道(-orig) → 原始语言 → 原始语言的地区变体（en-US 也匹配 en）；
+// 原始语言未知时依次使用原始轨道、唯一的一条字幕、preferred 中的语言，其余语言通常是 YouTube 自动翻译的
+func pickCaptionLang(tracks map[string][]CaptionFormat, original string, preferred []string) string {
+	var langs []string
+	for lang := range tracks {
+		if lang == "live_chat" {
+			continue
+		}
+		langs = append(langs, lang)
+	}
+	if len(langs) == 0 {
+		return ""
+	}
+	sort.Strings(langs)
+
+	if original == "" {
+		for _, lang := range langs {
+			if strings.HasSuffix(lang, "-orig") {
+				return lang
+			}
+		}
+		if len(langs) == 1 {
+			return langs[0]
+		}
+		return matchCaptionLang(tracks, langs, preferred)
+	}
+
+	candidates := []string{original}
+	if base, _, ok := strings.Cut(original, "-"); ok {
+		candidates = append(candidates, base)
+	}
+	return matchCaptionLang(tracks, langs, candidates)
+}
+
+// matchCaptionLang 按顺序查找第一个可用的语言：原始轨道(-orig) → 同名轨道 → 地区变体
+func matchCaptionLang(tracks map[string][]CaptionFormat, langs, wants []string) string {
+	for _, want := range wants {
+		if want == "" {
+			continue
+		}
+		if _, ok := tracks[want+"-orig"]; ok {
+			return want + "-orig"
+		}
+		if _, ok := tracks[want]; ok {
+			return want
+		}
+		for _, lang := range langs {
+			if strings.HasPrefix(lang, want+"-") {
+				return lang
+			}
+		}
+	}
+	return ""
+}
+
+// captionSourceLabel 字幕来源的中文名称（用于日志）
+func captionSourceLabel(source string) string {
+	switch source {
+	case model.SubtitleSourceSubmitted:
+		return "提交的"
+	case model.SubtitleSourceManual:
+		return "人工"
+	case model.SubtitleSourceAuto:
+		return "自动"
+	case model.SubtitleSourceASR:
+		return "语音识别"
+	default:
+		return source
+	}
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import "testing"
+
+// captionTracks 按语言代码构造字幕列表
+func captionTracks(langs ...string) map[string][]CaptionFormat {
+	tracks := make(map[string][]CaptionFormat)
+	for _, lang := range langs {
+		tracks[lang] = []CaptionFormat{{Ext: "vtt"}}
+	}
+	return tracks
+}
+
+func TestOriginalCaptionLang(t *testing.T) {
+	tests := []struct {
+		name string
+		info *captionInfo
+		want string
+	}{
+		{
+			name: "metadata language",
+			info: &captionInfo{Language: "ja", AutomaticCaptions: captionTracks("en-orig")},
+			want: "ja",
+		},
+		{
+			name: "original auto track",
+			info: &captionInfo{AutomaticCaptions: captionTracks("de", "en-orig", "fr")},
+			want: "en",
+		},
+		{
+			name: "first of several original tracks",
+			info: &captionInfo{AutomaticCaptions: captionTracks("fr-orig", "de-orig")},
+			want: "de",
+		},
+		{
+			name: "unknown",
+			info: &captionInfo{Subtitles: captionTracks("en"), AutomaticCaptions: captionTracks("de", "fr")},
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := originalCaptionLang(tt.info); got != tt.want {
+				t.Errorf("originalCaptionLang() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPickCaptionLang(t *testing.T) {
+	tests := []struct {
+		name      string
+		tracks    map[string][]CaptionFormat
+		original  string
+		preferred []string
+		want      string
+	}{
+		{
+			name:     "original track first",
+			tracks:   captionTracks("en", "en-orig", "ja"),
+			original: "en",
+			want:     "en-orig",
+		},
+		{
+			name:     "original language",
+			tracks:   captionTracks("de", "ja"),
+			original: "ja",
+			want:     "ja",
+		},
+		{
+			name:     "regional variant",
+			tracks:   captionTracks("de", "en-US"),
+			original: "en",
+			want:     "en-US",
+		},
+		{
+			name:     "base language of original",
+			tracks:   captionTracks("en", "fr"),
+			original: "en-GB",
+			want:     "en",
+		},
+		{
+			name:      "translations only",
+			tracks:    captionTracks("de", "fr"),
+			original:  "ja",
+			preferred: []string{"de"},
+			want:      "",
+		},
+		{
+			name:   "unknown original uses original track",
+			tracks: captionTracks("de", "fr-orig", "ja"),
+			want:   "fr-orig",
+		},
+		{
+			name:   "unknown original uses the only track",
+			tracks: captionTracks("ko", "live_chat"),
+			want:   "ko",
+		},
+		{
+			name:      "unknown original uses preferred language",
+			tracks:    captionTracks("de", "en-US", "ja"),
+			preferred: []string{"zh-Hans", "en"},
+			want:      "en-US",
+		},
+		{
+			name:      "unknown original without preferred language",
+			tracks:    captionTracks("de", "ja"),
+			preferred: []string{"en"},
+			want:      "",
+		},
+		{
+			name:   "no tracks",
+			tracks: captionTracks("live_chat"),
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pickCaptionLang(tt.tracks, tt.original, tt.preferred); got != tt.want {
+				t.Errorf("pickCaptionLang() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/chain_task/base"
+	"github.com/difyz9/ytb2bili/internal/chain_task/manager"
+	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/services"
+	"github.com/difyz9/ytb2bili/pkg/cos"
+	"github.com/difyz9/ytb2bili/pkg/llm"
+	"github.com/difyz9/ytb2bili/pkg/prompt"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
+	"gorm.io/gorm"
+)
+
+// GenerateChapters 分段章节生成
+// 把带时间的译文字幕（第一个目标语言）发给模型，按内容主题划分章节并校验（时间格式、从 00:00 开始、递增、最短时长、标题长度、违禁词），
+// 章节保存到视频记录的 GeneratedChapters（替换元数据步骤生成的章节），投稿时按 MetadataConfig.IncludeChapters 写入简介
+type GenerateChapters struct {
+	base.BaseTask
+	App               *core.AppServer
+	SavedVideoService *services.SavedVideoService
+	AIManager         *services.AIServiceManager
+	Prompts           *services.PromptService // 提示词模板（未启用自定义模板时使用内置默认）
+}
+
+func NewGenerateChapters(name string, app *core.AppServer, stateManager *manager.StateManager, client *cos.CosClient, db *gorm.DB, savedVideoService *services.SavedVideoService) *GenerateChapters {
+	// 调用用量记录到当前视频的这一步骤
+	aiManager := services.NewAIServiceManager(app.Config, app.Logger)
+	aiManager.TrackUsage(services.NewAIUsageService(db, app.Config), stateManager.VideoID, name)
+
+	return &GenerateChapters{
+		BaseTask: base.BaseTask{
+			Name:         name,
+			StateManager: stateManager,
+			Client:       client,
+		},
+		App:               app,
+		SavedVideoService: savedVideoService,
+		AIManager:         aiManager,
+		Prompts:           services.NewPromptService(db),
+	}
+}
+
+func (t *GenerateChapters) Execute(taskContext map[string]interface{}) bool {
+	config := t.App.Config.ChapterConfig
+	if config == nil || !config.Enabled {
+		t.App.Logger.Debug("分段章节生成未启用，跳过")
+		return true
+	}
+
+	translatedPath := t.StateManager.TranslatedSRTPath(TargetLanguages(t.App.Config)[0])
+	if _, err := os.Stat(translatedPath); os.IsNotExist(err) {
+		t.App.Logger.Info("ℹ️  译文字幕文件不存在，跳过章节生成")
+		return true
+	}
+	cues, err := subtitle.ReadFile(translatedPath)
+	if err != nil {
+		t.App.Logger.Errorf("❌ 读取译文字幕文件失败: %v", err)
+		taskContext["error"] = fmt.Sprintf("读取翻译字幕失败: %v", err)
+		return false
+	}
+
+	// 以最后一条字幕的结束时间作为视频时长
+	duration := services.SubtitleDuration(cues)
+	if minDuration := time.Duration(config.MinVideoMinutes) * time.Minute; duration < minDuration {
+		t.App.Logger.Infof("ℹ️  视频时长 %s 不足 %d 分钟，跳过章节生成", services.FormatChapterTime(duration), config.MinVideoMinutes)
+		return true
+	}
+
+	t.AIManager.RefreshConfig(t.App.Config)
+	if _, err := t.AIManager.GetPreferredProvider(); err != nil {
+		t.App.Logger.Warnf("⚠️ 没有可用的AI服务，跳过章节生成: %v", err)
+		return true
+	}
+
+	t.App.Logger.Infof("📑 根据 %d 条字幕生成分段章节 (时长 %s)...", len(cues), services.FormatChapterTime(duration))
+	prompts := t.loadPrompts()
+	rules := services.NewChapterRules(config, t.App.Config.MetadataConfig)
+	chapters, validation, err := t.generate(prompts, rules, cues, config.MaxTranscriptChars)
+	if used := prompts.Used(); len(used) > 0 {
+		taskContext["chapter_prompt_versions"] = used
+	}
+	if validation != nil {
+		taskContext["chapter_validation"] = validation
+	}
+	if err != nil {
+		// 章节只是锦上添花，生成失败保留元数据步骤的结果，不影响投稿
+		t.App.Logger.Warnf("⚠️ 生成章节失败，不影响视频上传: %v", err)
+		return true
+	}
+	if validation.Sanitized {
+		t.App.Logger.Warnf("⚠️ 重新生成 %d 次后章节仍未通过校验，已自动修正: %s", validation.Attempts-1, strings.Join(validation.Issues, "；"))
+	}
+
+	taskContext["video_chapters"] = chapters
+	saveVideoChapters(t.App, t.SavedVideoService, t.StateManager, chapters)
+
+	t.App.Logger.Infof("✅ 已生成 %d 个章节:", len(chapters))
+	for _, chapter := range chapters {
+		t.App.Logger.Infof("  %s %s", chapter.Time, chapter.Title)
+	}
+	return true
+}
+
+// generate 请求模型划分章节，未通过校验时带上问题重新生成，仍不通过时自动修正
+func (t *GenerateChapters) generate(prompts *prompt.Set, rules services.ChapterRules, cues []subtitle.Cue, maxChars int) ([]services.MetadataChapter, *services.MetadataValidation, error) {
+	userPrompt := prompts.Render(prompt.MetadataChapters, prompt.Vars{
+		Subtitles:         services.ChapterTranscript(cues, maxChars),
+		Duration:          services.FormatChapterTime(services.SubtitleDuration(cues)),
+		MaxChapters:       rules.MaxChapters,
+		MinChapterSeconds: int(rules.MinLength / time.Second),
+		MaxTitleLength:    rules.MaxTitleLength,
+	})
+
+	// 由AI服务管理器按首选服务和故障转移选择提供商
+	chat := func(ctx context.Context, req *llm.Request) (*llm.Response, error) {
+		resp, provider, err := t.AIManager.Chat(ctx, "", req)
+		if err != nil {
+			return nil, fmt.Errorf("AI服务调用失败: %v", err)
+		}
+		t.App.Logger.Debugf("章节生成使用AI服务: %s", provider)
+		return resp, nil
+	}
+	req := &llm.Request{Messages: []llm.Message{{Role: llm.RoleUser, Content: userPrompt}}, Temperature: 0.3}
+	return services.GenerateChapters(context.Background(), chat, req, rules, cues, func(issues []string) string {
+		t.App.Logger.Warnf("⚠️ 章节未通过校验，要求模型修正: %s", strings.Join(issues, "；"))
+		return prompts.Render(prompt.MetadataRepair, prompt.Vars{Issues: services.MetadataIssueList(issues)})
+	})
+}
+
+// loadPrompts 加载视频所属频道选用的提示词模板，并填入原视频标题、频道等公共变量
+func (t *GenerateChapters) loadPrompts() *prompt.Set {
+	vars := prompt.Vars{VideoID: t.StateManager.VideoID}
+	if video, err := t.SavedVideoService.GetVideoByVideoID(t.StateManager.VideoID); err == nil {
+		vars.SourceTitle = video.Title
+		vars.Channel = video.ChannelName
+		vars.ChannelID = video.ChannelID
+	}
+	return t.Prompts.Load(vars.ChannelID, vars)
+}
+
+// saveVideoChapters 保存章节到视频记录和 meta.json
+func saveVideoChapters(app *core.AppServer, savedVideoService *services.SavedVideoService, stateManager *manager.StateManager, chapters []services.MetadataChapter) {
+	data, err := json.Marshal(chapters)
+	if err != nil {
+		app.Logger.Errorf("❌ 序列化章节失败: %v", err)
+		return
+	}
+
+	savedVideo, err := savedVideoService.GetVideoByVideoID(stateManager.VideoID)
+	if err != nil {
+		app.Logger.Errorf("❌ 获取视频记录失败: %v", err)
+	} else {
+		savedVideo.GeneratedChapters = string(data)
+		if err := savedVideoService.UpdateVideo(savedVideo); err != nil {
+			app.Logger.Errorf("❌ 保存章节到数据库失败: %v", err)
+		}
+	}
+
+	// 元数据步骤已生成 meta.json 时同步更新其中的章节
+	metaFilePath := filepath.Join(stateManager.CurrentDir, "meta.json")
+	content, err := os.ReadFile(metaFilePath)
+	if err != nil {
+		return
+	}
+	var meta map[string]interface{}
+	if err := json.Unmarshal(content, &meta); err != nil {
+		return
+	}
+	meta["chapters"] = chapters
+	if content, err = json.MarshalIndent(meta, "", "  "); err == nil {
+		if err := os.WriteFile(metaFilePath, content, 0644); err != nil {
+			app.Logger.Warnf("⚠️ 更新 meta.json 章节失败: %v", err)
+		}
+	}
+}
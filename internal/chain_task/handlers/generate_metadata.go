@@ -14,22 +14,31 @@ import (
 	"github.com/difyz9/ytb2bili/internal/core"
 	"github.com/difyz9/ytb2bili/internal/core/services"
 	"github.com/difyz9/ytb2bili/pkg/cos"
+	"github.com/difyz9/ytb2bili/pkg/llm"
+	"github.com/difyz9/ytb2bili/pkg/prompt"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
 	"gorm.io/gorm"
 )
 
 type GenerateMetadata struct {
 	base.BaseTask
 	App               *core.AppServer
-	DeepSeekClient    *DeepSeekClient
-	GeminiClient      *GeminiClient
 	SavedVideoService *services.SavedVideoService
 	AIManager         *services.AIServiceManager
 	LastProvider      services.AIProvider
+	Prompts           *services.PromptService          // 提示词模板（未启用自定义模板时使用内置默认）
+	Variants          *services.MetadataVariantService // 标题/封面文字候选
+
+	prompts    *prompt.Set                  // 本次执行选用的提示词模板
+	rules      services.MetadataRules       // 元数据校验规则（MetadataConfig）
+	validation *services.MetadataValidation // 最近一次生成的校验结果
+	promptName string                       // 生成元数据使用的主要提示词模板，与标题候选一起保存
 }
 
 func NewGenerateMetadata(name string, app *core.AppServer, stateManager *manager.StateManager, client *cos.CosClient, apiKey string, db *gorm.DB, savedVideoService *services.SavedVideoService) *GenerateMetadata {
-	// 创建AI服务管理器
+	// 创建AI服务管理器，调用用量记录到当前视频的这一步骤
 	aiManager := services.NewAIServiceManager(app.Config, app.Logger)
+	aiManager.TrackUsage(services.NewAIUsageService(db, app.Config), stateManager.VideoID, name)
 
 	return &GenerateMetadata{
 		BaseTask: base.BaseTask{
@@ -38,9 +47,10 @@ func NewGenerateMetadata(name string, app *core.AppServer, stateManager *manager
 			Client:       client,
 		},
 		App:               app,
-		DeepSeekClient:    nil, // 不再固化客户端，运行时动态创建
 		SavedVideoService: savedVideoService,
 		AIManager:         aiManager,
+		Prompts:           services.NewPromptService(db),
+		Variants:          services.NewMetadataVariantService(db),
 	}
 }
 
@@ -58,27 +68,76 @@ func (g *GenerateMetadata) getCurrentAIProvider() (services.AIProvider, error) {
 	return provider, nil
 }
 
-// getCurrentDeepSeekClient 获取当前的DeepSeek客户端（使用最新配置，兼容旧代码）
-func (g *GenerateMetadata) getCurrentDeepSeekClient() (*DeepSeekClient, error) {
-	if g.App.Config.DeepSeekTransConfig == nil || !g.App.Config.DeepSeekTransConfig.Enabled {
-		return nil, fmt.Errorf("DeepSeek 翻译服务未启用")
+// newGeminiProvider 创建 Gemini 服务（多个 API Key 时按 AIKeyPoolConfig 轮换，被限流自动换用下一个）
+func (g *GenerateMetadata) newGeminiProvider(ctx context.Context) (llm.Provider, error) {
+	g.App.Logger.Infof("🔧 创建 Gemini 客户端 (%d 个 API Key)...", g.App.Config.GeminiConfig.GetApiKeysCount())
+
+	provider, err := g.AIManager.NewProvider(ctx, services.AIProviderGemini)
+	if err != nil {
+		return nil, err
 	}
+	return g.AIManager.Metered(services.AIProviderGemini, provider), nil
+}
+
+func (g *GenerateMetadata) Execute(context map[string]interface{}) bool {
+	g.prompts = g.loadPrompts()
+	g.rules = services.NewMetadataRules(g.App.Config.MetadataConfig)
+	ok := g.execute(context)
 
-	apiKey := g.App.Config.DeepSeekTransConfig.ApiKey
-	if apiKey == "" {
-		return nil, fmt.Errorf("DeepSeek API Key 未配置")
+	// 记录使用的提示词模板版本，便于复现生成结果
+	if used := g.prompts.Used(); len(used) > 0 {
+		context["prompt_versions"] = used
+	}
+	if g.validation != nil {
+		context["metadata_validation"] = g.validation
 	}
+	return ok
+}
 
-	return NewDeepSeekClient(apiKey), nil
+// metadataVars 生成元数据的提示词变量
+func (g *GenerateMetadata) metadataVars(subtitleText string) prompt.Vars {
+	return prompt.Vars{
+		Subtitles:  subtitleText,
+		Partitions: g.rules.PartitionList(),
+		Variants:   g.rules.Variants,
+		Styles:     g.rules.StyleList(),
+	}
 }
 
-type VideoMetadata struct {
-	Title       string   `json:"title"`
-	Description string   `json:"description"`
-	Tags        []string `json:"tags"`
+// generate 请求模型按 JSON Schema 生成元数据并按B站限制校验，不通过时带上问题重新生成，仍不通过时自动修正
+// promptName 为生成使用的主要提示词模板，与标题候选一起保存以比较不同模板的效果
+func (g *GenerateMetadata) generate(ctx context.Context, promptName string, chat func(ctx context.Context, req *llm.Request) (*llm.Response, error), req *llm.Request) (*services.VideoMetadata, error) {
+	g.promptName = promptName
+	metadata, validation, err := services.GenerateVideoMetadata(ctx, chat, req, g.rules, func(issues []string) string {
+		g.App.Logger.Warnf("⚠️ 元数据未通过校验，要求模型修正: %s", strings.Join(issues, "；"))
+		return g.prompts.Render(prompt.MetadataRepair, prompt.Vars{Issues: services.MetadataIssueList(issues)})
+	})
+	g.validation = validation
+	if err != nil {
+		return nil, err
+	}
+	if validation.Sanitized {
+		g.App.Logger.Warnf("⚠️ 重新生成 %d 次后仍未通过校验，已自动修正: %s", validation.Attempts-1, strings.Join(validation.Issues, "；"))
+	}
+	return metadata, nil
 }
 
-func (g *GenerateMetadata) Execute(context map[string]interface{}) bool {
+// loadPrompts 加载视频所属频道选用的提示词模板，并填入原视频标题、频道等公共变量
+func (g *GenerateMetadata) loadPrompts() *prompt.Set {
+	vars := prompt.Vars{VideoID: g.StateManager.VideoID}
+	if g.SavedVideoService != nil {
+		if video, err := g.SavedVideoService.GetVideoByVideoID(g.StateManager.VideoID); err == nil {
+			vars.SourceTitle = video.Title
+			vars.SourceDescription = video.Description
+			vars.Channel = video.ChannelName
+			vars.ChannelID = video.ChannelID
+		}
+	}
+	return g.Prompts.Load(vars.ChannelID, vars)
+}
+
+// execute 生成元数据的主流程：Gemini 分析视频 → Gemini 分析字幕 → 首选AI服务 → DeepSeek
+func (g *GenerateMetadata) execute(context map[string]interface{}) bool {
 	g.App.Logger.Info("========================================")
 	g.App.Logger.Infof("开始生成视频标题和描述: VideoID=%s", g.StateManager.VideoID)
 	g.App.Logger.Infof("📁 工作目录: %s", g.StateManager.CurrentDir)
@@ -208,112 +267,38 @@ func (g *GenerateMetadata) executeWithAIManager(ctx map[string]interface{}) bool
 		return false // 返回false让调用者尝试备选服务
 	}
 
-	// 6. 验证标题长度（Bilibili限制80字符）
-	if len([]rune(metadata.Title)) > 80 {
-		runes := []rune(metadata.Title)
-		metadata.Title = string(runes[:77]) + "..."
-		g.App.Logger.Warnf("⚠️ 标题过长，已截断为80字符")
-	}
-
-	// 7. 保存到 context
-	ctx["video_title"] = metadata.Title
-	ctx["video_description"] = metadata.Description
-	ctx["video_tags"] = metadata.Tags
-
-	// 8. 保存到 meta.json 文件
-	g.App.Logger.Info("💾 保存元数据到 meta.json 文件...")
-	if err := g.saveMetadataToFile(metadata); err != nil {
-		g.App.Logger.Errorf("❌ 保存 meta.json 文件失败: %v", err)
-	} else {
-		g.App.Logger.Info("✅ meta.json 文件已保存")
-	}
-
-	// 9. 保存到数据库
-	g.App.Logger.Info("💾 保存生成的元数据到数据库...")
-	savedVideo, err := g.SavedVideoService.GetVideoByVideoID(g.StateManager.VideoID)
-	if err != nil {
-		g.App.Logger.Errorf("❌ 获取视频记录失败: %v", err)
-	} else {
-		savedVideo.GeneratedTitle = metadata.Title
-		savedVideo.GeneratedDesc = metadata.Description
-		if len(metadata.Tags) > 0 {
-			tagsJSON, _ := json.Marshal(metadata.Tags)
-			savedVideo.GeneratedTags = string(tagsJSON)
-		}
-		if err := g.SavedVideoService.UpdateVideo(savedVideo); err != nil {
-			g.App.Logger.Errorf("❌ 更新视频记录失败: %v", err)
-		} else {
-			g.App.Logger.Info("✅ 数据库记录已更新")
-		}
-	}
-
-	g.App.Logger.Infof("✓ 生成标题: %s", metadata.Title)
-	g.App.Logger.Infof("✓ 生成描述: %s", truncateString(metadata.Description, 100))
-	g.App.Logger.Infof("✓ 生成标签: %v", metadata.Tags)
-	g.App.Logger.Info("========================================")
-
-	return true
+	// 6. 保存结果
+	return g.saveMetadataResults(metadata, ctx)
 }
 
 // generateMetadataWithAIManager 使用AI服务管理器生成元数据
-func (g *GenerateMetadata) generateMetadataWithAIManager(subtitleText string) (*VideoMetadata, error) {
-	systemPrompt := `你是一个专业的视频内容分析师，擅长为Bilibili视频生成吸引人的标题和描述。
-
-请根据提供的字幕内容，生成：
-1. 标题：简洁有力，能吸引观众点击，不超过80个字符
-2. 描述：详细介绍视频内容，包含关键信息，适合SEO
-3. 标签：5-10个相关标签，用于视频分类和搜索
-
-请以JSON格式返回，格式如下：
-{
-  "title": "视频标题",
-  "description": "视频描述",
-  "tags": ["标签1", "标签2", "标签3"]
-}
-
-注意：
-- 标题要吸引人但不要标题党
-- 描述要详细但不要太长
-- 标签要相关且有搜索价值
-- 只返回JSON，不要添加其他内容`
-
-	userPrompt := fmt.Sprintf("请根据以下字幕内容生成视频元数据：\n\n%s", subtitleText)
-
-	// 使用AI服务管理器调用
-	response, provider, err := g.AIManager.ChatCompletion(systemPrompt, userPrompt)
-	if err != nil {
-		return nil, fmt.Errorf("AI服务调用失败: %v", err)
-	}
-
-	// 记录实际使用的提供商
-	if provider != g.LastProvider {
-		g.App.Logger.Infof("🔄 AI服务已切换: %s -> %s", g.LastProvider, provider)
-		g.LastProvider = provider
-	}
-
-	// 解析JSON响应
-	var metadata VideoMetadata
-	cleanResponse := strings.TrimSpace(response)
-	cleanResponse = strings.TrimPrefix(cleanResponse, "```json")
-	cleanResponse = strings.TrimPrefix(cleanResponse, "```")
-	cleanResponse = strings.TrimSuffix(cleanResponse, "```")
-	cleanResponse = strings.TrimSpace(cleanResponse)
-
-	if err := json.Unmarshal([]byte(cleanResponse), &metadata); err != nil {
-		return nil, fmt.Errorf("解析AI响应失败: %v, 原始响应: %s", err, response)
+func (g *GenerateMetadata) generateMetadataWithAIManager(subtitleText string) (*services.VideoMetadata, error) {
+	vars := g.metadataVars(subtitleText)
+	systemPrompt := g.prompts.Render(prompt.MetadataSystem, vars)
+	userPrompt := g.prompts.Render(prompt.MetadataUser, vars)
+
+	// 使用AI服务管理器调用，记录实际使用的提供商
+	chat := func(ctx context.Context, req *llm.Request) (*llm.Response, error) {
+		resp, provider, err := g.AIManager.Chat(ctx, "", req)
+		if err != nil {
+			return nil, fmt.Errorf("AI服务调用失败: %v", err)
+		}
+		if provider != g.LastProvider {
+			g.App.Logger.Infof("🔄 AI服务已切换: %s -> %s", g.LastProvider, provider)
+			g.LastProvider = provider
+		}
+		return resp, nil
 	}
-
-	return &metadata, nil
+	return g.generate(context.Background(), prompt.MetadataSystem, chat, &llm.Request{Messages: llm.Messages(systemPrompt, userPrompt)})
 }
 
 // executeWithDeepSeek 使用 DeepSeek 生成元数据
 func (g *GenerateMetadata) executeWithDeepSeek(context map[string]interface{}) bool {
 	g.App.Logger.Info("🔄 使用 DeepSeek 生成元数据...")
 
-	// 0. 动态获取最新的DeepSeek客户端
-	client, err := g.getCurrentDeepSeekClient()
-	if err != nil {
-		g.App.Logger.Errorf("❌ 获取 DeepSeek 客户端失败: %v", err)
+	// 0. 检查 DeepSeek 配置（使用最新配置）
+	if !g.AIManager.IsDeepSeekEnabled() {
+		g.App.Logger.Error("❌ DeepSeek 未启用或 API Key 未配置")
 		g.App.Logger.Warn("⚠️ 使用默认标题和描述")
 		// 使用默认值而不是失败
 		context["video_title"] = g.StateManager.VideoID
@@ -321,10 +306,6 @@ func (g *GenerateMetadata) executeWithDeepSeek(context map[string]interface{}) b
 		return true
 	}
 
-	g.App.Logger.Infof("🔑 DeepSeek 客户端创建成功")
-	// 更新当前使用的客户端
-	g.DeepSeekClient = client
-
 	// 1. 检查中文字幕文件是否存在
 	zhSRTPath := filepath.Join(g.StateManager.CurrentDir, "zh.srt")
 	g.App.Logger.Infof("🔍 检查中文字幕文件: %s", zhSRTPath)
@@ -375,55 +356,8 @@ func (g *GenerateMetadata) executeWithDeepSeek(context map[string]interface{}) b
 		return true // API调用失败不算整个任务失败
 	}
 
-	// 6. 验证标题长度（Bilibili限制80字符）
-	if len([]rune(metadata.Title)) > 80 {
-		runes := []rune(metadata.Title)
-		metadata.Title = string(runes[:77]) + "..."
-		g.App.Logger.Warnf("⚠️  标题过长，已截断为80字符")
-	}
-
-	// 7. 保存到 context
-	context["video_title"] = metadata.Title
-	context["video_description"] = metadata.Description
-	context["video_tags"] = metadata.Tags
-
-	// 8. 保存到 meta.json 文件
-	g.App.Logger.Info("💾 保存元数据到 meta.json 文件...")
-	if err := g.saveMetadataToFile(metadata); err != nil {
-		g.App.Logger.Errorf("❌ 保存 meta.json 文件失败: %v", err)
-		// 不影响任务继续执行
-	} else {
-		g.App.Logger.Info("✅ meta.json 文件已保存")
-	}
-
-	// 9. 保存到数据库
-	g.App.Logger.Info("💾 保存生成的元数据到数据库...")
-	savedVideo, err := g.SavedVideoService.GetVideoByVideoID(g.StateManager.VideoID)
-	if err != nil {
-		g.App.Logger.Errorf("❌ 获取视频记录失败: %v", err)
-		// 不影响任务继续执行
-	} else {
-		// 更新生成的元数据
-		savedVideo.GeneratedTitle = metadata.Title
-		savedVideo.GeneratedDesc = metadata.Description
-		savedVideo.GeneratedTags = strings.Join(metadata.Tags, ",")
-
-		if err := g.SavedVideoService.UpdateVideo(savedVideo); err != nil {
-			g.App.Logger.Errorf("❌ 保存元数据到数据库失败: %v", err)
-		} else {
-			g.App.Logger.Info("✅ 元数据已保存到数据库")
-		}
-	}
-
-	// 10. 输出生成结果
-	g.App.Logger.Info("========================================")
-	g.App.Logger.Info("✅ 视频元数据生成成功！")
-	g.App.Logger.Infof("📌 标题: %s", metadata.Title)
-	g.App.Logger.Infof("📝 描述: %s", g.truncateString(metadata.Description, 100))
-	g.App.Logger.Infof("🏷️  标签: %v", metadata.Tags)
-	g.App.Logger.Info("========================================")
-
-	return true
+	// 6. 保存结果
+	return g.saveMetadataResults(metadata, context)
 }
 
 // extractTextFromSRT 从SRT内容中提取纯文本
@@ -454,69 +388,31 @@ func isNumber(s string) bool {
 }
 
 // generateMetadataFromDeepSeek 调用 DeepSeek API 生成标题和描述
-func (g *GenerateMetadata) generateMetadataFromDeepSeek(subtitleText string) (*VideoMetadata, error) {
-	prompt := fmt.Sprintf(`请根据以下视频字幕内容，生成一个吸引人的视频标题、详细描述和3-5个相关标签。
-
-字幕内容：
-%s
-
-要求：
-1. 标题要简洁有力，严格控制在30个字以内（B站限制80字，但建议30字以内更易读），能够准确概括视频主题，吸引观众点击
-2. 描述要详细但不要过长，严格控制在600-800字以内，包含视频的主要内容和亮点（注意：B站简介限制2000字，需要预留约200字给原视频链接和分隔线）
-3. 标签要准确反映视频内容，3-5个即可
-4. 必须使用中文
-5. 输出格式必须是JSON，格式如下：
-{
-  "title": "视频标题",
-  "description": "视频描述",
-  "tags": ["标签1", "标签2", "标签3"]
-}
-
-请直接返回JSON格式的结果，不要包含任何其他说明文字。`, subtitleText)
-
-	// 使用 DeepSeekClient 调用 API
-	content, usage, err := g.DeepSeekClient.ChatCompletionWithUsage("你是一个专业的视频内容分析助手，擅长根据视频字幕生成吸引人的标题和描述。", prompt)
-	if err != nil {
-		return nil, fmt.Errorf("调用 DeepSeek API 失败: %v", err)
-	}
-
-	g.App.Logger.Debugf("DeepSeek 原始返回: %s", content)
-
-	// 提取JSON部分（可能包含在代码块中）
-	content = strings.TrimSpace(content)
-	if strings.HasPrefix(content, "```json") {
-		content = strings.TrimPrefix(content, "```json")
-		content = strings.TrimSuffix(content, "```")
-	} else if strings.HasPrefix(content, "```") {
-		content = strings.TrimPrefix(content, "```")
-		content = strings.TrimSuffix(content, "```")
-	}
-	content = strings.TrimSpace(content)
+func (g *GenerateMetadata) generateMetadataFromDeepSeek(subtitleText string) (*services.VideoMetadata, error) {
+	vars := g.metadataVars(subtitleText)
+	systemPrompt := g.prompts.Render(prompt.MetadataDeepSeekSystem, vars)
+	userPrompt := g.prompts.Render(prompt.MetadataDeepSeek, vars)
+
+	chat := func(ctx context.Context, req *llm.Request) (*llm.Response, error) {
+		resp, _, err := g.AIManager.Chat(ctx, services.AIProviderDeepSeek, req)
+		if err != nil {
+			return nil, fmt.Errorf("调用 DeepSeek API 失败: %v", err)
+		}
 
-	// 解析JSON
-	var metadata VideoMetadata
-	if err := json.Unmarshal([]byte(content), &metadata); err != nil {
-		return nil, fmt.Errorf("解析元数据JSON失败: %v, 内容: %s", err, content)
-	}
+		g.App.Logger.Debugf("DeepSeek 原始返回: %s", resp.Content)
 
-	// 验证必填字段
-	if metadata.Title == "" {
-		return nil, fmt.Errorf("生成的标题为空")
-	}
-
-	// Token使用情况
-	if usage != nil {
+		// Token使用情况
 		g.App.Logger.Infof("💰 Token使用: 输入=%d, 输出=%d, 总计=%d",
-			usage.PromptTokens,
-			usage.CompletionTokens,
-			usage.TotalTokens)
+			resp.Usage.PromptTokens,
+			resp.Usage.CompletionTokens,
+			resp.Usage.TotalTokens)
+		return resp, nil
 	}
-
-	return &metadata, nil
+	return g.generate(context.Background(), prompt.MetadataDeepSeek, chat, &llm.Request{Messages: llm.Messages(systemPrompt, userPrompt)})
 }
 
 // saveMetadataToFile 保存元数据到 meta.json 文件
-func (g *GenerateMetadata) saveMetadataToFile(metadata *VideoMetadata) error {
+func (g *GenerateMetadata) saveMetadataToFile(metadata *services.VideoMetadata) error {
 	// 构建文件路径
 	metaFilePath := filepath.Join(g.StateManager.CurrentDir, "meta.json")
 
@@ -526,6 +422,9 @@ func (g *GenerateMetadata) saveMetadataToFile(metadata *VideoMetadata) error {
 		"title":        metadata.Title,
 		"description":  metadata.Description,
 		"tags":         metadata.Tags,
+		"chapters":     metadata.Chapters,
+		"tid":          metadata.Tid,
+		"dynamic":      metadata.Dynamic,
 		"generated_at": time.Now().Format("2006-01-02 15:04:05"),
 	}
 
@@ -559,27 +458,12 @@ func (g *GenerateMetadata) executeWithGeminiVideo(taskContext map[string]interfa
 	g.App.Logger.Infof("📁 搜索视频文件目录: %s", g.StateManager.CurrentDir)
 
 	// 1. 创建 Gemini 客户端（使用轮询 API Key）
-	apiKey := g.App.Config.GeminiConfig.GetCurrentApiKey()
-	keyCount := g.App.Config.GeminiConfig.GetApiKeysCount()
-	keyIndex := g.App.Config.GeminiConfig.CurrentKeyIndex + 1
-	g.App.Logger.Infof("🔧 创建 Gemini 客户端 (API Key %d/%d)...", keyIndex, keyCount)
-
-	client, err := NewGeminiClient(
-		apiKey,
-		g.App.Config.GeminiConfig.Model,
-		g.App.Config.GeminiConfig.Timeout,
-		g.App.Config.GeminiConfig.MaxTokens,
-	)
+	provider, err := g.newGeminiProvider(context.Background())
 	if err != nil {
 		g.App.Logger.Errorf("❌ 创建 Gemini 客户端失败: %v", err)
-		// 尝试轮换到下一个 API Key
-		if keyCount > 1 {
-			g.App.Config.GeminiConfig.RotateApiKey()
-			g.App.Logger.Infof("🔄 轮换到下一个 API Key...")
-		}
 		return false
 	}
-	defer client.Close()
+	defer provider.Close()
 	g.App.Logger.Info("✓ Gemini 客户端创建成功")
 
 	// 2. 查找视频文件
@@ -600,54 +484,38 @@ func (g *GenerateMetadata) executeWithGeminiVideo(taskContext map[string]interfa
 		g.App.Logger.Infof("📹 找到视频文件: %s", filepath.Base(videoPath))
 	}
 
-	// 3. 上传视频到 Gemini
+	// 3. 上传视频并生成元数据（上传、等待处理和生成共用超时时间）
 	timeoutSeconds := g.App.Config.GeminiConfig.Timeout
 	g.App.Logger.Infof("⏱️ 设置超时时间: %d 秒", timeoutSeconds)
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeoutSeconds)*time.Second)
 	defer cancel()
 
-	g.App.Logger.Info("⏫ 开始上传视频到 Gemini...")
-	uploadStartTime := time.Now()
-	uploadedFile, err := client.UploadFile(ctx, videoPath, filepath.Base(videoPath))
-	if err != nil {
-		uploadDuration := time.Since(uploadStartTime)
-		g.App.Logger.Errorf("❌ 上传视频失败 (耗时 %.2f 秒): %v", uploadDuration.Seconds(), err)
-		if strings.Contains(err.Error(), "context deadline exceeded") {
-			g.App.Logger.Errorf("❌ 上传超时！当前超时设置为 %d 秒，建议增加 GeminiConfig.Timeout 配置值", timeoutSeconds)
+	g.App.Logger.Info("⏫ 上传视频到 Gemini 并生成元数据...")
+	startTime := time.Now()
+	var totalTokens int
+	chat := func(ctx context.Context, req *llm.Request) (*llm.Response, error) {
+		resp, err := provider.Chat(ctx, req)
+		if err == nil {
+			totalTokens += resp.Usage.TotalTokens
 		}
-		return false
-	}
-	uploadDuration := time.Since(uploadStartTime)
-	g.App.Logger.Infof("✓ 视频上传成功 (耗时 %.2f 秒): %s", uploadDuration.Seconds(), uploadedFile.Name)
-
-	// 4. 等待文件处理完成
-	g.App.Logger.Info("⏳ 等待 Gemini 处理视频...")
-	processStartTime := time.Now()
-	if err := client.WaitForFileProcessing(ctx, uploadedFile); err != nil {
-		processDuration := time.Since(processStartTime)
-		g.App.Logger.Errorf("❌ 视频处理失败 (耗时 %.2f 秒): %v", processDuration.Seconds(), err)
-		if strings.Contains(err.Error(), "context deadline exceeded") {
-			g.App.Logger.Errorf("❌ 处理超时！当前超时设置为 %d 秒，建议增加 GeminiConfig.Timeout 配置值", timeoutSeconds)
-		}
-		return false
-	}
-	processDuration := time.Since(processStartTime)
-	g.App.Logger.Infof("✓ 视频处理完成 (耗时 %.2f 秒)", processDuration.Seconds())
-
-	// 5. 生成元数据
-	g.App.Logger.Info("🤖 调用 Gemini 生成元数据...")
-	generateStartTime := time.Now()
-	metadata, err := client.GenerateMetadataFromVideo(ctx, uploadedFile)
+		return resp, err
+	}
+	metadata, err := g.generate(ctx, prompt.MetadataGeminiVideo, chat, &llm.Request{
+		Messages: []llm.Message{{
+			Role:    llm.RoleUser,
+			Content: g.prompts.Render(prompt.MetadataGeminiVideo, g.metadataVars("")),
+			Files:   []llm.File{{Path: videoPath}},
+		}},
+		Temperature: 0.7,
+	})
 	if err != nil {
-		generateDuration := time.Since(generateStartTime)
-		g.App.Logger.Errorf("❌ 生成元数据失败 (耗时 %.2f 秒): %v", generateDuration.Seconds(), err)
+		g.App.Logger.Errorf("❌ 生成元数据失败 (耗时 %.2f 秒): %v", time.Since(startTime).Seconds(), err)
 		if strings.Contains(err.Error(), "context deadline exceeded") {
-			g.App.Logger.Errorf("❌ 生成超时！当前超时设置为 %d 秒，建议增加 GeminiConfig.Timeout 配置值", timeoutSeconds)
+			g.App.Logger.Errorf("❌ 处理超时！当前超时设置为 %d 秒，建议增加 GeminiConfig.Timeout 配置值", timeoutSeconds)
 		}
 		return false
 	}
-	generateDuration := time.Since(generateStartTime)
-	g.App.Logger.Infof("✓ 元数据生成完成 (耗时 %.2f 秒)", generateDuration.Seconds())
+	g.App.Logger.Infof("✓ 元数据生成完成 (耗时 %.2f 秒, Token: %d)", time.Since(startTime).Seconds(), totalTokens)
 
 	// 6. 保存结果
 	return g.saveMetadataResults(metadata, taskContext)
@@ -689,34 +557,22 @@ func (g *GenerateMetadata) executeWithGeminiText(taskContext map[string]interfac
 	}
 
 	// 5. 创建 Gemini 客户端（使用轮询 API Key）
-	apiKey := g.App.Config.GeminiConfig.GetCurrentApiKey()
-	keyCount := g.App.Config.GeminiConfig.GetApiKeysCount()
-	keyIndex := g.App.Config.GeminiConfig.CurrentKeyIndex + 1
-	g.App.Logger.Infof("🔧 创建 Gemini 客户端 (API Key %d/%d)...", keyIndex, keyCount)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(g.App.Config.GeminiConfig.Timeout)*time.Second)
+	defer cancel()
 
-	client, err := NewGeminiClient(
-		apiKey,
-		g.App.Config.GeminiConfig.Model,
-		g.App.Config.GeminiConfig.Timeout,
-		g.App.Config.GeminiConfig.MaxTokens,
-	)
+	provider, err := g.newGeminiProvider(ctx)
 	if err != nil {
 		g.App.Logger.Errorf("❌ 创建 Gemini 客户端失败: %v", err)
-		// 尝试轮换到下一个 API Key
-		if keyCount > 1 {
-			g.App.Config.GeminiConfig.RotateApiKey()
-			g.App.Logger.Infof("🔄 轮换到下一个 API Key...")
-		}
 		return false
 	}
-	defer client.Close()
+	defer provider.Close()
 
 	// 6. 生成元数据
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(g.App.Config.GeminiConfig.Timeout)*time.Second)
-	defer cancel()
-
 	g.App.Logger.Info("🤖 调用 Gemini 生成元数据...")
-	metadata, err := client.GenerateMetadataFromText(ctx, subtitleText)
+	metadata, err := g.generate(ctx, prompt.MetadataGeminiText, provider.Chat, &llm.Request{
+		Messages:    llm.Messages("", g.prompts.Render(prompt.MetadataGeminiText, g.metadataVars(subtitleText))),
+		Temperature: 0.7,
+	})
 	if err != nil {
 		g.App.Logger.Errorf("❌ 生成元数据失败: %v", err)
 		return false
@@ -727,18 +583,20 @@ func (g *GenerateMetadata) executeWithGeminiText(taskContext map[string]interfac
 }
 
 // saveMetadataResults 保存元数据结果到context和数据库
-func (g *GenerateMetadata) saveMetadataResults(metadata *VideoMetadata, taskContext map[string]interface{}) bool {
-	// 1. 验证标题长度
-	if len([]rune(metadata.Title)) > 80 {
-		runes := []rune(metadata.Title)
-		metadata.Title = string(runes[:77]) + "..."
-		g.App.Logger.Warnf("⚠️ 标题过长，已截断为80字符")
+// 元数据在 generate 中已按B站限制校验和修正
+func (g *GenerateMetadata) saveMetadataResults(metadata *services.VideoMetadata, taskContext map[string]interface{}) bool {
+	// 1. 按策略选择标题候选
+	if len(metadata.Variants) > 0 {
+		g.selectVariant(metadata, taskContext)
 	}
 
 	// 2. 保存到 context
 	taskContext["video_title"] = metadata.Title
 	taskContext["video_description"] = metadata.Description
 	taskContext["video_tags"] = metadata.Tags
+	taskContext["video_chapters"] = metadata.Chapters
+	taskContext["video_tid"] = metadata.Tid
+	taskContext["video_dynamic"] = metadata.Dynamic
 
 	// 3. 保存到 meta.json 文件
 	g.App.Logger.Info("💾 保存元数据到 meta.json 文件...")
@@ -757,6 +615,15 @@ func (g *GenerateMetadata) saveMetadataResults(metadata *VideoMetadata, taskCont
 		savedVideo.GeneratedTitle = metadata.Title
 		savedVideo.GeneratedDesc = metadata.Description
 		savedVideo.GeneratedTags = strings.Join(metadata.Tags, ",")
+		savedVideo.GeneratedTid = metadata.Tid
+		savedVideo.GeneratedDynamic = metadata.Dynamic
+		savedVideo.GeneratedCoverText = metadata.CoverText
+		savedVideo.GeneratedChapters = ""
+		if len(metadata.Chapters) > 0 {
+			if data, err := json.Marshal(metadata.Chapters); err == nil {
+				savedVideo.GeneratedChapters = string(data)
+			}
+		}
 
 		if err := g.SavedVideoService.UpdateVideo(savedVideo); err != nil {
 			g.App.Logger.Errorf("❌ 保存元数据到数据库失败: %v", err)
@@ -771,11 +638,64 @@ func (g *GenerateMetadata) saveMetadataResults(metadata *VideoMetadata, taskCont
 	g.App.Logger.Infof("📌 标题: %s", metadata.Title)
 	g.App.Logger.Infof("📝 描述: %s", g.truncateString(metadata.Description, 100))
 	g.App.Logger.Infof("🏷️ 标签: %v", metadata.Tags)
+	if metadata.CoverText != "" {
+		g.App.Logger.Infof("🖼️ 封面文字: %s", metadata.CoverText)
+	}
+	if metadata.Tid > 0 {
+		g.App.Logger.Infof("📂 建议分区: %d", metadata.Tid)
+	}
+	if len(metadata.Chapters) > 0 {
+		g.App.Logger.Infof("📑 章节: %d 个", len(metadata.Chapters))
+	}
 	g.App.Logger.Info("========================================")
 
 	return true
 }
 
+// selectVariant 按 MetadataConfig.VariantStrategy 选择标题候选作为标题和封面文字，并保存全部候选
+func (g *GenerateMetadata) selectVariant(metadata *services.VideoMetadata, taskContext map[string]interface{}) {
+	strategy, exploreRate := services.VariantStrategyFirst, 0.0
+	if config := g.App.Config.MetadataConfig; config != nil && config.VariantStrategy != "" {
+		strategy, exploreRate = config.VariantStrategy, config.ExploreRate
+	}
+
+	// 按投稿时将使用的分区比较历史效果
+	tid := PartitionTid(g.App.Config, &model.SavedVideo{GeneratedTid: metadata.Tid})
+	index, err := g.Variants.Choose(metadata.Variants, tid, strategy, exploreRate)
+	if err != nil {
+		g.App.Logger.Warnf("⚠️ 读取标题候选历史效果失败，使用第一个候选: %v", err)
+	}
+	chosen := metadata.Variants[index]
+	metadata.Title = chosen.Title
+	metadata.CoverText = chosen.CoverText
+	g.App.Logger.Infof("🎯 按 %s 策略选择标题候选 %d/%d（%s）", strategy, index+1, len(metadata.Variants), chosen.Style)
+
+	ref := g.prompts.Used()[g.promptName]
+	rows := make([]model.MetadataVariant, len(metadata.Variants))
+	for i, variant := range metadata.Variants {
+		rows[i] = model.MetadataVariant{
+			Position:      i,
+			Title:         variant.Title,
+			CoverText:     variant.CoverText,
+			Style:         variant.Style,
+			PromptName:    g.promptName,
+			PromptVersion: ref.Version,
+			PromptChannel: ref.ChannelID,
+			Selected:      i == index,
+		}
+		if i == index {
+			rows[i].Strategy = strategy
+		}
+	}
+	if err := g.Variants.Save(g.StateManager.VideoID, rows); err != nil {
+		g.App.Logger.Errorf("❌ 保存标题候选失败: %v", err)
+	}
+
+	taskContext["title_variants"] = metadata.Variants
+	taskContext["title_variant"] = index
+	taskContext["video_cover_text"] = metadata.CoverText
+}
+
 // findVideoFiles 查找视频文件
 func (g *GenerateMetadata) findVideoFiles() []string {
 	var videoFiles []string
@@ -7,12 +7,14 @@ import (
 	"github.com/difyz9/ytb2bili/internal/core/services"
 	"github.com/difyz9/ytb2bili/pkg/cos"
 	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
+	"github.com/difyz9/ytb2bili/pkg/transcriber"
 	"github.com/difyz9/ytb2bili/pkg/utils"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 )
 
 type GenerateSubtitles struct {
@@ -33,35 +35,41 @@ func NewGenerateSubtitles(name string, app *core.AppServer, stateManager *manage
 	}
 }
 
-// formatTime 将秒数转换为 SRT 时间格式 (HH:MM:SS,mmm)
-func (t *GenerateSubtitles) formatTime(seconds float64) string {
-	hours := int(seconds / 3600)
-	minutes := int((seconds - float64(hours*3600)) / 60)
-	secs := int(seconds - float64(hours*3600) - float64(minutes*60))
-	milliseconds := int((seconds - float64(int(seconds))) * 1000)
-
-	return fmt.Sprintf("%02d:%02d:%02d,%03d", hours, minutes, secs, milliseconds)
-}
-
 // generateSRT 生成 SRT 格式字幕内容
 func (t *GenerateSubtitles) generateSRT(subtitles []model.SavedVideoSubtitle) string {
-	var srtContent strings.Builder
-
-	for i, subtitle := range subtitles {
-		// SRT 序号（从1开始）
-		srtContent.WriteString(fmt.Sprintf("%d\n", i+1))
-
-		// 时间轴
-		startTime := t.formatTime(subtitle.Offset)
-		endTime := t.formatTime(subtitle.Offset + subtitle.Duration)
-		srtContent.WriteString(fmt.Sprintf("%s --> %s\n", startTime, endTime))
+	return string(subtitle.MarshalSRT(subtitlesToCues(subtitles)))
+}
 
-		// 字幕文本
-		srtContent.WriteString(subtitle.Text)
-		srtContent.WriteString("\n\n")
+// subtitlesToCues 将数据库中的字幕转换为统一字幕条目
+func subtitlesToCues(subtitles []model.SavedVideoSubtitle) []subtitle.Cue {
+	cues := make([]subtitle.Cue, len(subtitles))
+	for i, s := range subtitles {
+		cues[i] = subtitle.Cue{
+			Index: i + 1,
+			Start: subtitle.Seconds(s.Offset),
+			End:   subtitle.Seconds(s.Offset + s.Duration),
+			Text:  s.Text,
+		}
 	}
+	return cues
+}
 
-	return srtContent.String()
+// cuesToSubtitles 将统一字幕条目转换为数据库字幕（文本合并为单行）
+func cuesToSubtitles(cues []subtitle.Cue, lang string) []model.SavedVideoSubtitle {
+	subtitles := make([]model.SavedVideoSubtitle, 0, len(cues))
+	for _, cue := range cues {
+		text := subtitle.NormalizeText(cue.Text)
+		if text == "" {
+			continue
+		}
+		subtitles = append(subtitles, model.SavedVideoSubtitle{
+			Text:     text,
+			Offset:   cue.Start.Seconds(),
+			Duration: cue.Duration().Seconds(),
+			Lang:     lang,
+		})
+	}
+	return subtitles
 }
 
 func (t *GenerateSubtitles) Execute(context map[string]interface{}) bool {
@@ -84,23 +92,26 @@ func (t *GenerateSubtitles) Execute(context map[string]interface{}) bool {
 		return false
 	}
 
-	// 2. 检查字幕数据是否存在
-	if savedVideo.Subtitles == "" || savedVideo.Subtitles == "null" {
-		t.App.Logger.Warn("⚠️  视频没有字幕数据，跳过字幕生成")
-		return true // 没有字幕不算错误，继续执行后续任务
-	}
-
-	// 3. 解析字幕 JSON 数据
-	var subtitles []model.SavedVideoSubtitle
-	if err := json.Unmarshal([]byte(savedVideo.Subtitles), &subtitles); err != nil {
+	// 2. 按 提交的字幕 → 人工字幕 → 自动字幕 的顺序获取字幕
+	subtitles, source, lang, err := t.acquireSubtitles(savedVideo)
+	if err != nil {
 		t.App.Logger.Errorf("❌ 解析字幕数据失败: %v", err)
 		context["error"] = fmt.Sprintf("解析字幕数据失败: %v", err)
 		return false
 	}
 
+	context["subtitle_source"] = source
+	context["subtitle_lang"] = lang
+
 	if len(subtitles) == 0 {
-		t.App.Logger.Warn("⚠️  字幕数据为空，跳过字幕生成")
-		return true
+		t.App.Logger.Warn("⚠️  视频没有提交字幕，YouTube 上也没有可用字幕，语音识别也未成功，跳过字幕生成")
+		t.saveSubtitleSource(savedVideo, nil, model.SubtitleSourceNone, "")
+		return true // 没有字幕不算错误，继续执行后续任务
+	}
+
+	t.App.Logger.Infof("📝 字幕来源: %s字幕, 语言: %s", captionSourceLabel(source), lang)
+	if savedVideo.SubtitleSource != source || savedVideo.SubtitleLang != lang {
+		t.saveSubtitleSource(savedVideo, subtitles, source, lang)
 	}
 
 	t.App.Logger.Infof("📝 找到 %d 条字幕", len(subtitles))
@@ -168,6 +179,137 @@ func (t *GenerateSubtitles) Execute(context map[string]interface{}) bool {
 	return true
 }
 
+// acquireSubtitles 获取视频字幕，返回字幕条目、来源和语言
+func (t *GenerateSubtitles) acquireSubtitles(savedVideo *model.SavedVideo) ([]model.SavedVideoSubtitle, string, string, error) {
+	// 1. 数据库中已有字幕（扩展提交的，或之前从 YouTube 获取并保存的）
+	if savedVideo.Subtitles != "" && savedVideo.Subtitles != "null" {
+		var subtitles []model.SavedVideoSubtitle
+		if err := json.Unmarshal([]byte(savedVideo.Subtitles), &subtitles); err != nil {
+			return nil, "", "", err
+		}
+		if len(subtitles) > 0 {
+			source := savedVideo.SubtitleSource
+			if source == "" || source == model.SubtitleSourceNone {
+				source = model.SubtitleSourceSubmitted
+			}
+			lang := savedVideo.SubtitleLang
+			if lang == "" {
+				lang = subtitles[0].Lang
+			}
+			return subtitles, source, lang, nil
+		}
+	}
+
+	// 2. 通过 yt-dlp 获取 YouTube 人工字幕 / 自动字幕
+	t.App.Logger.Info("🔍 视频没有提交字幕，尝试从 YouTube 获取字幕...")
+	var installDir string
+	if t.App.Config != nil && t.App.Config.YtDlpPath != "" {
+		installDir = t.App.Config.YtDlpPath
+	}
+	var fetcher *CaptionFetcher
+	ytdlp := utils.NewYtDlpManager(t.App.Logger, installDir)
+	if ytdlp.IsInstalled() {
+		fetcher = NewCaptionFetcher(t.App, ytdlp.GetBinaryPath(), t.StateManager.CurrentDir, savedVideo.URL, savedVideo.ChannelID)
+		defer fetcher.Close()
+		result, err := fetcher.Fetch(savedVideo.URL)
+		if fetcher.ChannelID != "" && savedVideo.ChannelID == "" {
+			savedVideo.ChannelID = fetcher.ChannelID
+			savedVideo.ChannelName = fetcher.ChannelName
+		}
+		if err == nil {
+			return result.Subtitles, result.Source, result.Lang, nil
+		}
+		t.App.Logger.Warnf("⚠️ 获取 YouTube 字幕失败: %v", err)
+	} else {
+		t.App.Logger.Warn("⚠️ 未找到 yt-dlp，无法获取 YouTube 字幕")
+	}
+
+	// 3. 语音识别
+	subtitles, lang, err := t.transcribeAudio(savedVideo.URL, fetcher)
+	if err != nil {
+		t.App.Logger.Warnf("⚠️ 语音识别失败: %v", err)
+		return nil, model.SubtitleSourceNone, "", nil
+	}
+
+	return subtitles, model.SubtitleSourceASR, lang, nil
+}
+
+// transcribeAudio 使用语音识别生成字幕（视频没有任何字幕时使用）
+func (t *GenerateSubtitles) transcribeAudio(videoURL string, fetcher *CaptionFetcher) ([]model.SavedVideoSubtitle, string, error) {
+	cfg := t.App.Config.TranscriberConfig
+	if cfg == nil || !cfg.Enabled {
+		return nil, "", fmt.Errorf("语音识别未启用")
+	}
+
+	asr, err := transcriber.NewTranscriber(t.App.Config)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// 优先使用已下载的视频，否则只下载音频
+	audioSource := t.StateManager.InputVideoPath
+	if _, err := os.Stat(audioSource); err != nil {
+		if fetcher == nil {
+			return nil, "", fmt.Errorf("视频文件不存在且无法下载音频")
+		}
+		t.App.Logger.Info("🎧 下载音频用于语音识别...")
+		if audioSource, err = fetcher.DownloadAudio(videoURL); err != nil {
+			return nil, "", err
+		}
+		defer os.Remove(audioSource)
+	}
+
+	wavPath := filepath.Join(t.StateManager.CurrentDir, t.StateManager.VideoID+".wav")
+	if err := utils.ExtractWaveAudio(audioSource, wavPath); err != nil {
+		return nil, "", err
+	}
+
+	t.App.Logger.Infof("🎙️ 开始语音识别 (provider: %s)...", asr.GetName())
+	result, err := transcriber.TranscribeLong(context.Background(), asr, &transcriber.TranscriptionRequest{
+		AudioPath: wavPath,
+		Language:  cfg.Language,
+	}, cfg.ChunkSeconds)
+	if err != nil {
+		return nil, "", err
+	}
+
+	cues := transcriber.BuildCues(result.Segments, transcriber.CueOptions{
+		MaxChars:    cfg.MaxCueChars,
+		MaxDuration: cfg.MaxCueDuration,
+	})
+
+	subtitles := make([]model.SavedVideoSubtitle, 0, len(cues))
+	for _, cue := range cues {
+		subtitles = append(subtitles, model.SavedVideoSubtitle{
+			Text:     cue.Text,
+			Offset:   cue.Start,
+			Duration: cue.End - cue.Start,
+			Lang:     result.Language,
+		})
+	}
+
+	t.App.Logger.Infof("✓ 语音识别完成，识别语言: %s，共 %d 条字幕", result.Language, len(subtitles))
+	return subtitles, result.Language, nil
+}
+
+// saveSubtitleSource 保存字幕来源；从 YouTube 获取的字幕同时写回数据库，重试时无需再次下载
+func (t *GenerateSubtitles) saveSubtitleSource(savedVideo *model.SavedVideo, subtitles []model.SavedVideoSubtitle, source, lang string) {
+	if source != model.SubtitleSourceSubmitted && len(subtitles) > 0 {
+		data, err := json.Marshal(subtitles)
+		if err != nil {
+			t.App.Logger.Warnf("⚠️ 序列化字幕失败: %v", err)
+			return
+		}
+		savedVideo.Subtitles = string(data)
+	}
+	savedVideo.SubtitleSource = source
+	savedVideo.SubtitleLang = lang
+
+	if err := t.SavedVideoService.UpdateVideo(savedVideo); err != nil {
+		t.App.Logger.Warnf("⚠️ 保存字幕来源失败: %v", err)
+	}
+}
+
 // truncateString 截断字符串，避免日志过长
 func truncateString(s string, maxLen int) string {
 	if len(s) <= maxLen {
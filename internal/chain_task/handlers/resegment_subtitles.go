@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/difyz9/ytb2bili/internal/chain_task/base"
+	"github.com/difyz9/ytb2bili/internal/chain_task/manager"
+	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/cos"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
+)
+
+// 字幕断句阶段
+const (
+	SegmentStageSource = "source" // 翻译前：处理原文字幕
+	SegmentStageUpload = "upload" // 上传前：处理译文字幕
+)
+
+// ResegmentSubtitles 字幕重新断句
+// source 阶段把滚动式碎片合并为整句并按阅读速度拆分，之后的翻译按新的时间轴逐条进行；
+// upload 阶段对各目标语言的译文按行长和阅读速度重新拆分并调整时间轴，写入 <语言>_segmented.srt 供上传使用，
+// 双语字幕随主语言的新时间轴重新生成
+type ResegmentSubtitles struct {
+	base.BaseTask
+	App   *core.AppServer
+	Stage string
+}
+
+func NewResegmentSubtitles(name string, app *core.AppServer, stateManager *manager.StateManager, client *cos.CosClient, stage string) *ResegmentSubtitles {
+	return &ResegmentSubtitles{
+		BaseTask: base.BaseTask{
+			Name:         name,
+			StateManager: stateManager,
+			Client:       client,
+		},
+		App:   app,
+		Stage: stage,
+	}
+}
+
+func (t *ResegmentSubtitles) Execute(context map[string]interface{}) bool {
+	config := t.segmentConfig()
+	if config == nil || !config.Enabled {
+		t.App.Logger.Debugf("字幕断句未启用 (%s)，跳过", t.Stage)
+		return true
+	}
+	options := segmentOptions(config)
+
+	if t.Stage == SegmentStageUpload {
+		return t.resegmentTranslations(context, options)
+	}
+
+	// GenerateSubtitles 生成的 <videoID>.srt 是翻译的输入，en.srt 是上传的英文字幕
+	inputPath := t.sourceSRTPath()
+	before, segmented, err := t.resegment(inputPath, []string{inputPath, t.StateManager.OriginalSRT}, options)
+	if err != nil {
+		t.App.Logger.Errorf("❌ %v", err)
+		context["error"] = err.Error()
+		return false
+	}
+	if segmented != nil {
+		context["segment_"+t.Stage+"_before"] = before
+		context["segment_"+t.Stage+"_after"] = len(segmented)
+	}
+	return true
+}
+
+// resegmentTranslations 上传阶段：每种目标语言的译文分别断句写入 <语言>_segmented.srt，
+// 并按主语言断句后的时间轴重新生成双语字幕
+func (t *ResegmentSubtitles) resegmentTranslations(context map[string]interface{}, options subtitle.SegmentOptions) bool {
+	before, after := 0, 0
+	for i, lang := range TargetLanguages(t.App.Config) {
+		count, segmented, err := t.resegment(t.StateManager.TranslatedSRTPath(lang), []string{t.StateManager.SegmentedSRTPath(lang)}, options)
+		if err != nil {
+			t.App.Logger.Errorf("❌ %v", err)
+			context["error"] = err.Error()
+			return false
+		}
+		if segmented == nil {
+			continue
+		}
+		before += count
+		after += len(segmented)
+
+		if i == 0 {
+			t.resegmentBilingual(segmented)
+		}
+	}
+
+	context["segment_"+t.Stage+"_before"] = before
+	context["segment_"+t.Stage+"_after"] = after
+	return true
+}
+
+// resegmentBilingual 双语字幕按主语言断句后的时间轴重新生成，与上传的译文字幕保持一致
+func (t *ResegmentSubtitles) resegmentBilingual(segmented []subtitle.Cue) {
+	if _, err := os.Stat(t.StateManager.BilingualSRT); err != nil {
+		return
+	}
+	original, err := subtitle.ReadFile(t.sourceSRTPath())
+	if err != nil {
+		t.App.Logger.Warnf("⚠️  读取原文字幕失败，双语字幕保持原时间轴: %v", err)
+		return
+	}
+	if _, err := WriteBilingualSubtitles(t.App.Config, t.StateManager, original, segmented); err != nil {
+		t.App.Logger.Warnf("⚠️  重新生成双语字幕失败: %v", err)
+	}
+}
+
+// resegment 读取字幕断句后写入 outputPaths，返回断句前的条数和断句结果；文件不存在或为空时跳过（返回 nil）
+func (t *ResegmentSubtitles) resegment(inputPath string, outputPaths []string, options subtitle.SegmentOptions) (int, []subtitle.Cue, error) {
+	if _, err := os.Stat(inputPath); os.IsNotExist(err) {
+		t.App.Logger.Warnf("⚠️  字幕文件不存在，跳过断句: %s", filepath.Base(inputPath))
+		return 0, nil, nil
+	}
+
+	cues, err := subtitle.ReadFile(inputPath)
+	if err != nil {
+		return 0, nil, fmt.Errorf("读取字幕文件失败: %v", err)
+	}
+	if len(cues) == 0 {
+		t.App.Logger.Warnf("⚠️  字幕内容为空，跳过断句: %s", filepath.Base(inputPath))
+		return 0, nil, nil
+	}
+
+	segmented := subtitle.Resegment(cues, options)
+	for _, path := range outputPaths {
+		if err := subtitle.WriteFile(path, segmented); err != nil {
+			return 0, nil, fmt.Errorf("写入断句后的字幕失败: %v", err)
+		}
+	}
+
+	t.App.Logger.Infof("✂️  字幕断句完成 (%s): %s %d 条 → %d 条", t.Stage, filepath.Base(inputPath), len(cues), len(segmented))
+	return len(cues), segmented, nil
+}
+
+// segmentConfig 当前阶段的断句配置
+func (t *ResegmentSubtitles) segmentConfig() *types.SegmentConfig {
+	if t.App.Config.SubtitleConfig == nil {
+		return nil
+	}
+	if t.Stage == SegmentStageUpload {
+		return &t.App.Config.SubtitleConfig.Upload
+	}
+	return &t.App.Config.SubtitleConfig.Source
+}
+
+// sourceSRTPath 原文字幕：GenerateSubtitles 生成的 <videoID>.srt
+func (t *ResegmentSubtitles) sourceSRTPath() string {
+	return filepath.Join(t.StateManager.CurrentDir, fmt.Sprintf("%s.srt", t.StateManager.VideoID))
+}
+
+// segmentOptions 将配置转换为断句规则
+func segmentOptions(config *types.SegmentConfig) subtitle.SegmentOptions {
+	return subtitle.SegmentOptions{
+		MergeSentences: config.MergeSentences,
+		MaxMergeGap:    subtitle.Seconds(config.MaxMergeGap),
+		MaxCPS:         config.MaxCPS,
+		MaxLineLength:  config.MaxLineLength,
+		MaxLines:       config.MaxLines,
+		MinDuration:    subtitle.Seconds(config.MinDuration),
+		MaxDuration:    subtitle.Seconds(config.MaxDuration),
+		MinGap:         subtitle.Seconds(config.MinGap),
+	}
+}
@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 
@@ -11,7 +13,13 @@ import (
 	"github.com/difyz9/ytb2bili/internal/chain_task/manager"
 	"github.com/difyz9/ytb2bili/internal/core"
 	"github.com/difyz9/ytb2bili/internal/core/services"
+	"github.com/difyz9/ytb2bili/internal/core/types"
 	"github.com/difyz9/ytb2bili/pkg/cos"
+	"github.com/difyz9/ytb2bili/pkg/llm"
+	"github.com/difyz9/ytb2bili/pkg/prompt"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
+	"github.com/difyz9/ytb2bili/pkg/translator"
 	"github.com/difyz9/ytb2bili/pkg/utils"
 	"gorm.io/gorm"
 )
@@ -22,14 +30,61 @@ type TranslateSubtitle struct {
 	DB           *gorm.DB
 	APIKey       string
 	GroupSize    int
+	ContextSize  int // 每组前后附带的上下文句数（只作参考，不翻译）
 	MaxWorkers   int // 最大并发数
 	AIManager    *services.AIServiceManager
-	LastProvider services.AIProvider // 记录最后使用的AI提供商
+	Translator   *translator.TranslatorManager       // 翻译器管理器（提供商链见 TranslatorConfig）
+	LastProvider string                              // 记录最后使用的翻译服务
+	Memory       *services.TranslationMemoryService  // 翻译记忆（TranslatorConfig.EnableCache 开启时生效）
+	Quality      *services.TranslationQualityService // 译文质量评审（TranslationQualityConfig.Enabled 开启时生效）
+	Usage        *services.AIUsageService            // AI用量统计（AIUsageConfig.Enabled 开启时生效）
+	Prompts      *services.PromptService             // 提示词模板（未启用自定义模板时使用内置默认）
+
+	prompts    *prompt.Set // 本次执行选用的提示词模板
+	sourceLang string      // 本次执行的原文字幕语言（翻译器语言代码）
+}
+
+// translationEngine 实际完成翻译的服务和模型
+type translationEngine struct {
+	Provider string
+	Model    string
+}
+
+// TranslationEngineUsage 单个翻译服务的用量
+type TranslationEngineUsage struct {
+	Provider string           `json:"provider"`        // 翻译服务
+	Model    string           `json:"model,omitempty"` // 模型
+	Lines    int              `json:"lines"`           // 译文句数（不含翻译记忆命中）
+	Usage    translator.Usage `json:"usage"`           // 用量
+}
+
+// TranslationRecord 翻译步骤结果中记录的服务、模型和用量
+type TranslationRecord struct {
+	Providers []string                 `json:"providers"`       // 配置的提供商链
+	Provider  string                   `json:"provider"`        // 主要使用的翻译服务（译文句数最多）
+	Model     string                   `json:"model,omitempty"` // 主要使用的模型
+	Usage     translator.Usage         `json:"usage"`           // 总用量
+	Engines   []TranslationEngineUsage `json:"engines"`         // 各翻译服务的用量
+}
+
+// LanguageTranslation 单个目标语言的翻译结果
+type LanguageTranslation struct {
+	Language           string                       `json:"language"`                      // 目标语言（如 zh-Hans、ja）
+	SRTPath            string                       `json:"srt_path"`                      // 译文字幕路径
+	VTTPath            string                       `json:"vtt_path,omitempty"`            // WebVTT 译文字幕路径
+	TranslatedCount    int                          `json:"translated_count"`              // 译文条数
+	Translation        *TranslationRecord           `json:"translation"`                   // 翻译服务、模型和用量
+	Memory             map[string]interface{}       `json:"translation_memory,omitempty"`  // 翻译记忆命中情况
+	GlossaryViolations []services.GlossaryViolation `json:"glossary_violations,omitempty"` // 仍未遵守术语表的句子
+	Validation         map[string]interface{}       `json:"validation_result,omitempty"`   // 字幕校验结果
+	Quality            *services.QualityReport      `json:"quality_report,omitempty"`      // 译文质量报告
 }
 
 func NewTranslateSubtitle(name string, app *core.AppServer, stateManager *manager.StateManager, client *cos.CosClient, db *gorm.DB, apiKey string) *TranslateSubtitle {
-	// 创建AI服务管理器
+	// 创建AI服务管理器，并作为 ai 提供商注册到翻译器管理器
 	aiManager := services.NewAIServiceManager(app.Config, app.Logger)
+	translatorManager := translator.NewTranslatorManager(app.Config)
+	translatorManager.RegisterTranslator(services.AITranslatorProvider, services.NewAITranslator(aiManager))
 
 	return &TranslateSubtitle{
 		BaseTask: base.BaseTask{
@@ -37,71 +92,129 @@ func NewTranslateSubtitle(name string, app *core.AppServer, stateManager *manage
 			StateManager: stateManager,
 			Client:       client,
 		},
-		App:        app,
-		DB:         db,
-		APIKey:     "", // 不再固化API Key，运行时动态获取
-		GroupSize:  25, // 每组25句，减少API调用次数
-		MaxWorkers: 3,  // 最多3个并发，避免API限制
-		AIManager:  aiManager,
+		App:         app,
+		DB:          db,
+		APIKey:      "", // 不再固化API Key，运行时动态获取
+		GroupSize:   25, // 每组25句，减少API调用次数
+		ContextSize: 3,  // 前后各附带3句上下文，避免跨组时代词和术语不一致
+		MaxWorkers:  3,  // 最多3个并发，避免API限制
+		AIManager:   aiManager,
+		Translator:  translatorManager,
+		Memory:      services.NewTranslationMemoryService(db, app.Config),
+		Quality:     services.NewTranslationQualityService(aiManager, app.Config),
+		Usage:       services.NewAIUsageService(db, app.Config),
+		Prompts:     services.NewPromptService(db),
 	}
 }
 
-// getCurrentAIProvider 获取当前可用的AI服务提供商
-func (t *TranslateSubtitle) getCurrentAIProvider() (services.AIProvider, error) {
-	// 刷新配置
-	t.AIManager.RefreshConfig(t.App.Config)
-
-	// 获取首选提供商
-	provider, err := t.AIManager.GetPreferredProvider()
-	if err != nil {
-		return "", fmt.Errorf("没有可用的AI服务: %v", err)
+// translationProviders 字幕翻译的提供商链：TranslatorConfig 中的默认和备选提供商，
+// 未配置时使用 ai（AI服务），其余已启用的翻译服务作为备选
+func (t *TranslateSubtitle) translationProviders() []string {
+	var providers []string
+	if cfg := t.App.Config.TranslatorConfig; cfg != nil && cfg.DefaultProvider != "" {
+		providers = append([]string{cfg.DefaultProvider}, cfg.FallbackProviders...)
+	} else {
+		providers = append([]string{services.AITranslatorProvider}, translator.NewTranslatorFactory(t.App.Config).GetEnabledProviders()...)
 	}
 
-	return provider, nil
-}
+	// 去重，并跳过未启用或无法创建的提供商
+	seen := make(map[string]bool)
+	var available []string
+	for _, provider := range providers {
+		provider = strings.TrimSpace(provider)
+		if provider == "" || seen[provider] {
+			continue
+		}
+		seen[provider] = true
 
-// getCurrentAPIKey 获取当前的API Key（兼容旧代码）
-func (t *TranslateSubtitle) getCurrentAPIKey() (string, error) {
-	// 优先使用OpenAI兼容API
-	if t.AIManager.IsOpenAICompatibleEnabled() {
-		cfg := t.AIManager.GetOpenAICompatibleConfig()
-		return cfg.ApiKey, nil
+		if provider == services.AITranslatorProvider {
+			if _, err := t.AIManager.GetPreferredProvider(); err != nil {
+				t.App.Logger.Debugf("跳过翻译服务 %s: %v", provider, err)
+				continue
+			}
+		} else if _, err := t.Translator.GetTranslator(provider); err != nil {
+			t.App.Logger.Debugf("跳过翻译服务 %s: %v", provider, err)
+			continue
+		}
+		available = append(available, provider)
 	}
+	return available
+}
 
-	// 备选：DeepSeek
-	if t.AIManager.IsDeepSeekEnabled() {
-		cfg := t.AIManager.GetDeepSeekConfig()
-		return cfg.ApiKey, nil
+// providerEngine 提供商链中某一项预期使用的服务和模型（用于查询翻译记忆）
+func (t *TranslateSubtitle) providerEngine(provider string) translationEngine {
+	switch provider {
+	case services.AITranslatorProvider:
+		aiProvider, err := t.AIManager.GetPreferredProvider()
+		if err != nil {
+			return translationEngine{Provider: provider}
+		}
+		engine := translationEngine{Provider: string(aiProvider)}
+		if status := t.AIManager.GetStatus(aiProvider); status != nil {
+			engine.Model = status.Model
+		}
+		return engine
+	case "deepseek":
+		if cfg := t.App.Config.DeepSeekTransConfig; cfg != nil {
+			model := cfg.Model
+			if model == "" {
+				model = "deepseek-chat"
+			}
+			return translationEngine{Provider: provider, Model: model}
+		}
+	case "ollama":
+		if cfg := t.App.Config.OllamaTransConfig; cfg != nil {
+			return translationEngine{Provider: provider, Model: cfg.Model}
+		}
 	}
-
-	return "", fmt.Errorf("没有可用的AI服务，请先配置AI服务")
+	return translationEngine{Provider: provider}
 }
 
-// SRTEntry SRT字幕条目
-type SRTEntry struct {
-	Index    int
-	TimeCode string
-	Text     string
+// validationProvider 字幕校验修复使用的AI服务（首选服务）
+func (t *TranslateSubtitle) validationProvider() (llm.Provider, error) {
+	provider, err := t.AIManager.GetPreferredProvider()
+	if err != nil {
+		return nil, err
+	}
+	client, err := t.AIManager.NewProvider(context.Background(), provider)
+	if err != nil {
+		return nil, err
+	}
+	return t.AIManager.Metered(provider, client), nil
 }
 
 func (t *TranslateSubtitle) Execute(context map[string]interface{}) bool {
+	t.prompts = t.loadPrompts()
+	t.sourceLang = t.detectSourceLang(context)
+	ok := t.execute(context)
+
+	// 记录使用的提示词模板版本，便于复现翻译结果
+	if used := t.prompts.Used(); len(used) > 0 {
+		context["prompt_versions"] = used
+	}
+	return ok
+}
+
+// execute 翻译字幕的主流程
+func (t *TranslateSubtitle) execute(context map[string]interface{}) bool {
 	t.App.Logger.Info("========================================")
 	t.App.Logger.Infof("开始翻译字幕: VideoID=%s", t.StateManager.VideoID)
 	t.App.Logger.Info("========================================")
 
-	// 0. 刷新AI服务管理器配置并获取首选AI服务
+	// 0. 刷新AI服务管理器配置并确定翻译服务提供商链
 	t.AIManager.RefreshConfig(t.App.Config)
-	provider, err := t.getCurrentAIProvider()
-	if err != nil {
-		t.App.Logger.Errorf("❌ %v", err)
-		context["error"] = t.getTranslationError(err)
+	t.AIManager.TrackUsage(t.Usage, t.StateManager.VideoID, t.Name)
+	providers := t.translationProviders()
+	if len(providers) == 0 {
+		t.App.Logger.Error("❌ 没有可用的翻译服务")
+		context["error"] = t.getTranslationError(fmt.Errorf("no translator provider enabled"), nil)
 		return false
 	}
 
-	// 记录使用的AI服务
-	t.LastProvider = provider
-	status := t.AIManager.GetStatus(provider)
-	t.App.Logger.Infof("🤖 使用AI服务: %s (模型: %s)", status.Name, status.Model)
+	// 记录使用的翻译服务
+	primary := t.providerEngine(providers[0])
+	t.LastProvider = primary.Provider
+	t.App.Logger.Infof("🤖 使用翻译服务: %s (模型: %s，提供商链: %s)", primary.Provider, primary.Model, strings.Join(providers, " → "))
 
 	// 1. 检查英文字幕文件是否存在（由 GenerateSubtitles 任务生成）
 	enSRTPath := filepath.Join(t.StateManager.CurrentDir, fmt.Sprintf("%s.srt", t.StateManager.VideoID))
@@ -118,7 +231,7 @@ func (t *TranslateSubtitle) Execute(context map[string]interface{}) bool {
 		return false
 	}
 
-	srtEntries, err := t.parseSRTContent(string(srtContent))
+	srtEntries, err := subtitle.ParseSRT(srtContent)
 	if err != nil {
 		t.App.Logger.Errorf("❌ 解析SRT文件失败: %v", err)
 		context["error"] = "字幕文件格式错误，无法解析SRT内容"
@@ -133,59 +246,148 @@ func (t *TranslateSubtitle) Execute(context map[string]interface{}) bool {
 	t.App.Logger.Infof("📝 找到 %d 条字幕", len(srtEntries))
 
 	// 3. 提取文本进行翻译
-	var texts []string
-	for _, entry := range srtEntries {
-		texts = append(texts, entry.Text)
+	texts := subtitle.Texts(srtEntries)
+
+	// 4. 加载频道术语表
+	glossary := t.loadGlossary()
+
+	// 5. 逐个目标语言翻译，第一个为主语言
+	languages := TargetLanguages(t.App.Config)
+	t.App.Logger.Infof("🌐 原文语言: %s，目标语言: %s", t.sourceLang, strings.Join(languages, ", "))
+
+	results := make([]*LanguageTranslation, 0, len(languages))
+	for i, lang := range languages {
+		result, cues, ok := t.translateLanguage(context, lang, srtEntries, enSRTPath, providers, glossary)
+		if !ok {
+			return false
+		}
+		results = append(results, result)
+
+		// 主语言的结果沿用原有字段，并用于生成双语字幕
+		if i == 0 {
+			context["translation"] = result.Translation
+			context["translated_count"] = result.TranslatedCount
+			if result.Memory != nil {
+				context["translation_memory"] = result.Memory
+			}
+			if !glossary.Empty() {
+				context["glossary_terms"] = len(glossary.Match(texts...))
+				context["glossary_violations"] = result.GlossaryViolations
+			}
+			if result.Validation != nil {
+				context["validation_result"] = result.Validation
+			}
+			if result.Quality != nil {
+				context["quality_report"] = result.Quality
+			}
+			if cues != nil {
+				t.generateBilingualSubtitles(srtEntries, cues, context)
+			}
+		}
+		if lang == subtitle.DefaultLanguage {
+			context["zh_srt_path"] = result.SRTPath
+			if result.VTTPath != "" {
+				context["zh_vtt_path"] = result.VTTPath
+			}
+		}
 	}
 
-	// 4. 执行并发翻译
-	totalGroups := (len(texts) + t.GroupSize - 1) / t.GroupSize
-	t.App.Logger.Infof("� 开始并发翻译，每组 %d 句，共 %d 组，并发数: %d", t.GroupSize, totalGroups, t.MaxWorkers)
+	// 6. 保存文件路径到 context
+	context["en_srt_path"] = enSRTPath
+	context["target_languages"] = languages
+	context["languages"] = results
 
-	translatedTexts, err := t.translateTextsInGroupsConcurrent(texts)
-	if err != nil {
-		t.App.Logger.Errorf("❌ 翻译失败: %v", err)
-		context["error"] = t.getTranslationError(err)
-		return false
+	t.App.Logger.Infof("✓ 翻译完成: %d 条字幕，%d 种语言", len(texts), len(languages))
+	t.App.Logger.Info("========================================")
+
+	return true
+}
+
+// translateLanguage 将字幕翻译成一种目标语言：翻译记忆 → 并发翻译 → 术语表校验 → 保存 → 校验修复 → 质量评审 → 写入翻译记忆 → 生成VTT
+// 返回该语言的结果和最终译文字幕（读取失败时为 nil）；失败时写入 context["error"] 并返回 false
+func (t *TranslateSubtitle) translateLanguage(context map[string]interface{}, lang string, srtEntries []subtitle.Cue, enSRTPath string, providers []string, glossary *services.Glossary) (*LanguageTranslation, []subtitle.Cue, bool) {
+	t.App.Logger.Infof("🌐 [%s] 开始翻译", lang)
+
+	texts := subtitle.Texts(srtEntries)
+	targetLang := translatorLanguage(lang)
+	result := &LanguageTranslation{Language: lang}
+
+	// 术语表的固定译法为中文，其他语言只保留不翻译的名称
+	if lang != subtitle.DefaultLanguage {
+		glossary = glossary.DoNotTranslateOnly()
+	}
+
+	// 1. 查询翻译记忆，命中的句子直接复用
+	primary := t.providerEngine(providers[0])
+	cached := t.Memory.Lookup(t.memoryKey(primary, glossary, targetLang), texts)
+	var pending []int
+	for i := range texts {
+		if _, ok := cached[i]; !ok {
+			pending = append(pending, i)
+		}
+	}
+	if t.Memory.Enabled() {
+		t.App.Logger.Infof("🧠 [%s] 翻译记忆命中 %d/%d 句", lang, len(cached), len(texts))
+		result.Memory = map[string]interface{}{
+			"hits":     len(cached),
+			"misses":   len(pending),
+			"hit_rate": services.HitRate(len(cached), len(texts)),
+		}
+	}
+
+	// 2. 并发翻译未命中的句子
+	translatedTexts := make([]string, len(texts))
+	engines := make([]translationEngine, len(texts))
+	usage := make(map[translationEngine]*translator.Usage)
+	for i, text := range cached {
+		translatedTexts[i] = text
+	}
+	if len(pending) > 0 {
+		totalGroups := (len(pending) + t.GroupSize - 1) / t.GroupSize
+		t.App.Logger.Infof("🚀 [%s] 开始并发翻译，每组 %d 句（前后各 %d 句上下文），共 %d 组，并发数: %d", lang, t.GroupSize, t.ContextSize, totalGroups, t.MaxWorkers)
+
+		if err := t.translateTextsInGroupsConcurrent(texts, pending, translatedTexts, engines, usage, providers, targetLang, glossary); err != nil {
+			t.App.Logger.Errorf("❌ [%s] 翻译失败: %v", lang, err)
+			context["error"] = t.getTranslationError(err, providers)
+			return nil, nil, false
+		}
 	}
 
-	// 5. 生成中文字幕SRT
-	translatedSRT := t.generateTranslatedSRTContent(srtEntries, translatedTexts)
+	// 3. 校验术语表译法，未遵守的句子重新翻译
+	if !glossary.Empty() {
+		result.GlossaryViolations = t.enforceGlossary(texts, translatedTexts, engines, usage, providers, targetLang, glossary)
+	}
+
+	// 记录实际使用的翻译服务、模型和用量
+	result.Translation = buildTranslationRecord(providers, engines, usage)
+	result.TranslatedCount = len(translatedTexts)
+	t.recordUsage(result.Translation)
+	if record := result.Translation; record.Provider != "" {
+		t.App.Logger.Infof("📊 [%s] 翻译服务: %s (模型: %s)，%d 字符，%d tokens，耗时 %dms",
+			lang, record.Provider, record.Model, record.Usage.Characters, record.Usage.TotalTokens, record.Usage.Duration)
+	}
 
-	// 6. 保存中文字幕文件
-	zhSRTPath := filepath.Join(t.StateManager.CurrentDir, "zh.srt")
-	if err := os.WriteFile(zhSRTPath, []byte(translatedSRT), 0644); err != nil {
-		t.App.Logger.Errorf("❌ 保存中文字幕失败: %v", err)
+	// 4. 生成并保存译文字幕（保持原时间轴）
+	result.SRTPath = t.StateManager.TranslatedSRTPath(lang)
+	if err := subtitle.WriteFile(result.SRTPath, subtitle.WithTexts(srtEntries, translatedTexts)); err != nil {
+		t.App.Logger.Errorf("❌ [%s] 保存译文字幕失败: %v", lang, err)
 		context["error"] = "保存翻译字幕文件失败，请检查磁盘空间和文件权限"
-		return false
+		return nil, nil, false
 	}
 
-	// 7. 字幕质量校验和优化
-	optimizedPath, validationResult, err := t.validateAndOptimizeSubtitles(enSRTPath, zhSRTPath)
+	// 5. 字幕质量校验和优化，修复结果直接写回译文字幕
+	applied, validationResult, err := t.validateAndOptimizeSubtitles(enSRTPath, result.SRTPath, lang)
 	if err != nil {
-		t.App.Logger.Warnf("⚠️  字幕校验失败，使用原始翻译: %v", err)
+		t.App.Logger.Warnf("⚠️  [%s] 字幕校验失败，使用原始翻译: %v", lang, err)
 	} else {
 		if validationResult.MissingEntries > 0 {
-			t.App.Logger.Infof("🔧 检测到 %d 个问题条目，已尝试修复 %d 个",
-				validationResult.MissingEntries, len(validationResult.FixedEntries))
-
-			if optimizedPath != "" {
-				// 使用优化后的文件替换原文件
-				if err := os.Rename(optimizedPath, zhSRTPath); err == nil {
-					t.App.Logger.Info("✨ 已应用字幕优化结果")
-				}
+			t.App.Logger.Infof("🔧 [%s] 检测到 %d 个问题条目，已尝试修复 %d 个",
+				lang, validationResult.MissingEntries, len(validationResult.FixedEntries))
+			if applied {
+				t.App.Logger.Infof("✨ [%s] 已应用字幕优化结果", lang)
 			}
 		}
-	}
-
-	// 8. 保存文件路径到 context
-	context["en_srt_path"] = enSRTPath
-	context["zh_srt_path"] = zhSRTPath
-	context["translated_count"] = len(translatedTexts)
-
-	// 添加校验结果信息
-	if validationResult != nil {
-		context["validation_result"] = map[string]interface{}{
+		result.Validation = map[string]interface{}{
 			"total_entries":   validationResult.TotalEntries,
 			"valid_entries":   validationResult.ValidEntries,
 			"missing_entries": validationResult.MissingEntries,
@@ -193,96 +395,394 @@ func (t *TranslateSubtitle) Execute(context map[string]interface{}) bool {
 		}
 	}
 
-	t.App.Logger.Infof("✓ 中文字幕已保存: %s", zhSRTPath)
-	t.App.Logger.Infof("✓ 翻译完成: %d/%d 条字幕", len(translatedTexts), len(texts))
-	t.App.Logger.Info("========================================")
+	// 6. 译文质量评审，低分条目用更强的模型重译
+	if t.Quality.Enabled() {
+		result.Quality = t.reviewQuality(lang, srtEntries, result.SRTPath, glossary)
+	}
 
-	return true
+	// 7. 校验和评审后的最终译文写入翻译记忆
+	finalCues, err := subtitle.ReadFile(result.SRTPath)
+	finalTexts := translatedTexts
+	if err == nil && len(finalCues) == len(texts) {
+		finalTexts = subtitle.Texts(finalCues)
+	}
+	t.storeMemory(texts, finalTexts, engines, primary, result.GlossaryViolations, result.Quality, glossary, targetLang)
+
+	// 8. 同时生成 WebVTT 格式的译文字幕（供网页播放器使用）
+	if err != nil {
+		t.App.Logger.Warnf("⚠️  [%s] 读取译文字幕失败，跳过生成VTT: %v", lang, err)
+	} else if vttPath := t.StateManager.TranslatedVTTPath(lang); subtitle.WriteFile(vttPath, finalCues) != nil {
+		t.App.Logger.Warnf("⚠️  [%s] 生成VTT字幕失败", lang)
+	} else {
+		result.VTTPath = vttPath
+	}
+
+	t.App.Logger.Infof("✓ [%s] 译文字幕已保存: %s", lang, result.SRTPath)
+	return result, finalCues, true
 }
 
-// parseSRTContent 解析SRT文件内容
-func (t *TranslateSubtitle) parseSRTContent(content string) ([]SRTEntry, error) {
-	lines := strings.Split(content, "\n")
-	var entries []SRTEntry
-	var currentEntry SRTEntry
-	var textLines []string
-	stage := 0 // 0=等待序号, 1=等待时间码, 2=读取文本
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-
-		if line == "" {
-			// 空行表示一个条目结束
-			if stage == 2 && len(textLines) > 0 {
-				currentEntry.Text = strings.Join(textLines, "\n")
-				entries = append(entries, currentEntry)
-				textLines = nil
-				stage = 0
-			}
+// reviewQuality 评审译文字幕质量，采用重译结果时写回字幕文件；评审失败时返回 nil，保留原译文
+func (t *TranslateSubtitle) reviewQuality(lang string, srtEntries []subtitle.Cue, srtPath string, glossary *services.Glossary) *services.QualityReport {
+	cues, err := subtitle.ReadFile(srtPath)
+	if err != nil || len(cues) != len(srtEntries) {
+		t.App.Logger.Warnf("⚠️  [%s] 译文与原文条数不一致，跳过质量评审", lang)
+		return nil
+	}
+
+	sources := subtitle.Texts(srtEntries)
+	t.App.Logger.Infof("🧐 [%s] 开始译文质量评审: %d 条", lang, len(cues))
+	report, translations, err := t.Quality.Review(&services.QualityReviewRequest{
+		Sources:      sources,
+		Translations: subtitle.Texts(cues),
+		SourceLang:   t.sourceLang,
+		TargetLang:   translatorLanguage(lang),
+		ContextSize:  t.ContextSize,
+		Instructions: func(index int) string {
+			return services.GlossaryPrompt(glossary.Match(sources[index]))
+		},
+		Prompts: t.prompts,
+	})
+	if err != nil {
+		t.App.Logger.Warnf("⚠️  [%s] 质量评审失败，保留原译文: %v", lang, err)
+		return nil
+	}
+	report.Language = lang
+
+	if report.Improved > 0 {
+		if err := subtitle.WriteFile(srtPath, subtitle.WithTexts(cues, translations)); err != nil {
+			t.App.Logger.Warnf("⚠️  [%s] 保存重译结果失败: %v", lang, err)
+		}
+	}
+	t.App.Logger.Infof("📈 [%s] 质量评审完成: 平均 %.1f 分，低分 %d 条，重译 %d 条，采用 %d 条，最终 %.1f 分",
+		lang, report.AverageScore, report.Flagged, report.Retranslated, report.Improved, report.FinalScore)
+	return report
+}
+
+// TargetLanguages 字幕翻译的目标语言（统一写法并去重），未配置时为简体中文；第一个为主语言
+func TargetLanguages(config *types.AppConfig) []string {
+	var configured []string
+	if config.TranslatorConfig != nil {
+		configured = config.TranslatorConfig.TargetLanguages
+	}
+
+	seen := make(map[string]bool)
+	var languages []string
+	for _, lang := range configured {
+		lang = subtitle.NormalizeLanguage(lang)
+		if lang == "" || seen[lang] {
 			continue
 		}
+		seen[lang] = true
+		languages = append(languages, lang)
+	}
+	if len(languages) == 0 {
+		return []string{subtitle.DefaultLanguage}
+	}
+	return languages
+}
 
-		switch stage {
-		case 0: // 读取序号
-			var index int
-			if _, err := fmt.Sscanf(line, "%d", &index); err == nil {
-				currentEntry = SRTEntry{Index: index}
-				stage = 1
+// translatorLanguage 目标语言对应的翻译器语言代码（翻译器和翻译记忆使用 zh、zh-tw 等写法）
+func translatorLanguage(lang string) string {
+	switch lang {
+	case subtitle.DefaultLanguage:
+		return "zh"
+	case "zh-Hant":
+		return "zh-tw"
+	}
+	return strings.ToLower(lang)
+}
+
+// detectSourceLang 原文字幕的语言：优先使用字幕步骤写入 context 的语言（YouTube 字幕轨道或语音识别结果），
+// 单独重试翻译步骤时读取视频记录中保存的语言
+func (t *TranslateSubtitle) detectSourceLang(context map[string]interface{}) string {
+	lang, _ := context["subtitle_lang"].(string)
+	if lang == "" {
+		if video := t.sourceVideo(); video != nil {
+			lang = video.SubtitleLang
+		}
+	}
+	return sourceLanguage(lang)
+}
+
+// sourceLanguage 原文字幕语言对应的翻译器语言代码（去掉 YouTube 原始轨道的 -orig 后缀），未知时按英文处理
+func sourceLanguage(lang string) string {
+	lang = strings.TrimSuffix(strings.TrimSpace(lang), "-orig")
+	if lang == "" || strings.EqualFold(lang, "auto") {
+		return "en"
+	}
+	return translatorLanguage(subtitle.NormalizeLanguage(lang))
+}
+
+// memoryKey 翻译记忆的查找条件
+func (t *TranslateSubtitle) memoryKey(engine translationEngine, glossary *services.Glossary, targetLang string) translator.MemoryKey {
+	return translator.MemoryKey{
+		SourceLang:      t.sourceLang,
+		TargetLang:      targetLang,
+		Provider:        engine.Provider,
+		Model:           engine.Model,
+		GlossaryVersion: glossary.Version(),
+	}
+}
+
+// storeMemory 将最终译文按实际使用的翻译服务写入翻译记忆：新翻译的句子，以及评审采用的重译结果（覆盖命中的旧记忆）；
+// 仍未遵守术语表的句子和评审低分且重译未被采用的句子不写入
+func (t *TranslateSubtitle) storeMemory(texts, translated []string, engines []translationEngine, primary translationEngine, violations []services.GlossaryViolation, report *services.QualityReport, glossary *services.Glossary, targetLang string) {
+	if !t.Memory.Enabled() {
+		return
+	}
+
+	skip := make(map[int]bool)
+	for _, violation := range violations {
+		skip[violation.Index] = true
+	}
+	accepted := make(map[int]bool)
+	if report != nil {
+		for _, cue := range report.Cues {
+			if cue.Accepted {
+				accepted[cue.Index] = true
+			} else {
+				skip[cue.Index] = true
 			}
-		case 1: // 读取时间码
-			if strings.Contains(line, "-->") {
-				currentEntry.TimeCode = line
-				stage = 2
+		}
+	}
+
+	sources := make(map[translationEngine][]string)
+	translations := make(map[translationEngine][]string)
+	for i, engine := range engines {
+		if skip[i] {
+			continue
+		}
+		if engine.Provider == "" {
+			// 命中翻译记忆的句子只在采用了重译结果时更新
+			if !accepted[i] {
+				continue
 			}
-		case 2: // 读取文本
-			textLines = append(textLines, line)
+			engine = primary
+		}
+		sources[engine] = append(sources[engine], texts[i])
+		translations[engine] = append(translations[engine], translated[i])
+	}
+	for engine := range sources {
+		t.Memory.Store(t.memoryKey(engine, glossary, targetLang), sources[engine], translations[engine])
+	}
+}
+
+// recordUsage 将各翻译服务的用量写入用量统计（AI翻译的调用不会由AI服务管理器重复记录）
+func (t *TranslateSubtitle) recordUsage(record *TranslationRecord) {
+	for _, engine := range record.Engines {
+		err := t.Usage.Record(&model.AIUsage{
+			VideoID:      t.StateManager.VideoID,
+			Step:         t.Name,
+			Provider:     engine.Provider,
+			Model:        engine.Model,
+			InputTokens:  engine.Usage.InputTokens,
+			OutputTokens: engine.Usage.OutputTokens,
+			TotalTokens:  engine.Usage.TotalTokens,
+			Characters:   engine.Usage.Characters,
+			Cost:         engine.Usage.Cost,
+			DurationMs:   engine.Usage.Duration,
+		})
+		if err != nil {
+			t.App.Logger.Warnf("⚠️ 记录翻译用量失败: %v", err)
+		}
+	}
+}
+
+// buildTranslationRecord 汇总各翻译服务的译文句数和用量
+func buildTranslationRecord(providers []string, engines []translationEngine, usage map[translationEngine]*translator.Usage) *TranslationRecord {
+	lines := make(map[translationEngine]int)
+	for _, engine := range engines {
+		if engine.Provider != "" {
+			lines[engine]++
+		}
+	}
+
+	record := &TranslationRecord{Providers: providers, Engines: []TranslationEngineUsage{}}
+	for engine, u := range usage {
+		record.Engines = append(record.Engines, TranslationEngineUsage{
+			Provider: engine.Provider,
+			Model:    engine.Model,
+			Lines:    lines[engine],
+			Usage:    *u,
+		})
+		addUsage(&record.Usage, u)
+	}
+	sort.Slice(record.Engines, func(i, j int) bool {
+		if record.Engines[i].Lines != record.Engines[j].Lines {
+			return record.Engines[i].Lines > record.Engines[j].Lines
 		}
+		return record.Engines[i].Provider < record.Engines[j].Provider
+	})
+	if len(record.Engines) > 0 {
+		record.Provider = record.Engines[0].Provider
+		record.Model = record.Engines[0].Model
+	}
+	return record
+}
+
+// addUsage 累加用量
+func addUsage(total *translator.Usage, u *translator.Usage) {
+	if u == nil {
+		return
+	}
+	total.InputTokens += u.InputTokens
+	total.OutputTokens += u.OutputTokens
+	total.TotalTokens += u.TotalTokens
+	total.Characters += u.Characters
+	total.Cost += u.Cost
+	total.Duration += u.Duration
+}
+
+// loadGlossary 加载视频所属频道的术语表（加载失败时不使用术语表）
+func (t *TranslateSubtitle) loadGlossary() *services.Glossary {
+	if t.DB == nil {
+		return nil
+	}
+
+	channelID := ""
+	if video := t.sourceVideo(); video != nil {
+		channelID = video.ChannelID
+	}
+
+	glossary, err := services.NewGlossaryService(t.DB).LoadGlossary(channelID)
+	if err != nil {
+		t.App.Logger.Warnf("⚠️  加载术语表失败，将不使用术语表: %v", err)
+		return nil
+	}
+	if !glossary.Empty() {
+		t.App.Logger.Infof("📖 已加载术语表: %d 条 (频道: %s)", len(glossary.Terms), channelID)
+	}
+	return glossary
+}
+
+// sourceVideo 当前任务的视频记录，查询失败时返回 nil
+func (t *TranslateSubtitle) sourceVideo() *model.SavedVideo {
+	if t.DB == nil {
+		return nil
 	}
+	video, err := services.NewSavedVideoService(t.DB).GetVideoByVideoID(t.StateManager.VideoID)
+	if err != nil {
+		return nil
+	}
+	return video
+}
+
+// loadPrompts 加载视频所属频道选用的提示词模板，并填入原视频标题、频道等公共变量
+func (t *TranslateSubtitle) loadPrompts() *prompt.Set {
+	vars := prompt.Vars{VideoID: t.StateManager.VideoID}
+	if video := t.sourceVideo(); video != nil {
+		vars.SourceTitle = video.Title
+		vars.SourceDescription = video.Description
+		vars.Channel = video.ChannelName
+		vars.ChannelID = video.ChannelID
+	}
+	return t.Prompts.Load(vars.ChannelID, vars)
+}
 
-	// 处理最后一个条目（如果文件末尾没有空行）
-	if stage == 2 && len(textLines) > 0 {
-		currentEntry.Text = strings.Join(textLines, "\n")
-		entries = append(entries, currentEntry)
+// generateBilingualSubtitles 生成双语字幕（SRT/ASS/BCC），上下顺序由 BilibiliConfig.BilingualOrder 决定
+func (t *TranslateSubtitle) generateBilingualSubtitles(original, translated []subtitle.Cue, context map[string]interface{}) {
+	written, err := WriteBilingualSubtitles(t.App.Config, t.StateManager, original, translated)
+	if err != nil {
+		t.App.Logger.Warnf("⚠️  生成双语字幕失败: %v", err)
+		return
+	}
+	if !written {
+		return
 	}
 
-	return entries, nil
+	t.App.Logger.Infof("✓ 双语字幕已保存: %s", t.StateManager.BilingualSRT)
+	context["bilingual_srt_path"] = t.StateManager.BilingualSRT
 }
 
-// generateTranslatedSRTContent 生成翻译后的SRT内容（保持原时间轴）
-func (t *TranslateSubtitle) generateTranslatedSRTContent(entries []SRTEntry, translatedTexts []string) string {
-	var builder strings.Builder
+// WriteBilingualSubtitles 按 BilibiliConfig 生成双语字幕（SRT/ASS/BCC），未开启双语字幕时返回 false
+// 翻译步骤和字幕编辑器修改主语言译文后都会调用
+func WriteBilingualSubtitles(appConfig *types.AppConfig, stateManager *manager.StateManager, original, translated []subtitle.Cue) (bool, error) {
+	config := appConfig.BilibiliConfig
+	if config == nil || !config.BilingualSubtitle {
+		return false, nil
+	}
 
-	for i, entry := range entries {
-		builder.WriteString(fmt.Sprintf("%d\n", entry.Index))
-		builder.WriteString(fmt.Sprintf("%s\n", entry.TimeCode))
+	var cues []subtitle.Cue
+	if config.BilingualOrder == types.BilingualOrderEnZh {
+		cues = subtitle.Bilingual(original, translated)
+	} else {
+		cues = subtitle.Bilingual(translated, original)
+	}
 
-		if i < len(translatedTexts) {
-			builder.WriteString(fmt.Sprintf("%s\n\n", translatedTexts[i]))
-		} else {
-			builder.WriteString(fmt.Sprintf("%s\n\n", entry.Text))
+	for _, path := range []string{stateManager.BilingualSRT, stateManager.BilingualBCC} {
+		if err := subtitle.WriteFile(path, cues); err != nil {
+			return false, err
 		}
 	}
+	ass := subtitle.MarshalBilingualASS(cues, subtitle.DefaultASSStyle(), subtitle.DefaultSecondaryASSStyle())
+	if err := os.WriteFile(stateManager.BilingualASS, ass, 0644); err != nil {
+		return false, fmt.Errorf("生成双语ASS字幕失败: %v", err)
+	}
+	return true, nil
+}
 
-	return builder.String()
+// translationGroup 一组待翻译字幕及其前后文
+type translationGroup struct {
+	index       int
+	indices     []int // 待翻译字幕的下标
+	texts       []string
+	prevContext []string
+	nextContext []string
 }
 
-// translateTextsInGroupsConcurrent 并发分组翻译文本
-func (t *TranslateSubtitle) translateTextsInGroupsConcurrent(texts []string) ([]string, error) {
-	totalGroups := (len(texts) + t.GroupSize - 1) / t.GroupSize
-	results := make([][]string, totalGroups)
+// splitTranslationGroups 将待翻译的下标按 GroupSize 分组，每组附带前后 ContextSize 句上下文（只作参考，不翻译）
+func (t *TranslateSubtitle) splitTranslationGroups(texts []string, pending []int) []translationGroup {
+	var groups []translationGroup
+	for i := 0; i < len(pending); i += t.GroupSize {
+		end := i + t.GroupSize
+		if end > len(pending) {
+			end = len(pending)
+		}
 
-	// 创建工作池
-	type translateTask struct {
-		groupIndex int
-		texts      []string
+		indices := pending[i:end]
+		groupTexts := make([]string, len(indices))
+		for j, index := range indices {
+			groupTexts[j] = texts[index]
+		}
+
+		first, last := indices[0], indices[len(indices)-1]
+		prevStart := first - t.ContextSize
+		if prevStart < 0 {
+			prevStart = 0
+		}
+		nextEnd := last + 1 + t.ContextSize
+		if nextEnd > len(texts) {
+			nextEnd = len(texts)
+		}
+
+		groups = append(groups, translationGroup{
+			index:       len(groups),
+			indices:     indices,
+			texts:       groupTexts,
+			prevContext: texts[prevStart:first],
+			nextContext: texts[last+1 : nextEnd],
+		})
 	}
+	return groups
+}
+
+// groupResult 一组翻译的结果
+type groupResult struct {
+	groupIndex int
+	result     []string
+	engine     translationEngine
+	usage      *translator.Usage
+	err        error
+}
+
+// translateTextsInGroupsConcurrent 并发分组翻译 pending 中的句子（滑动窗口上下文 + 术语表）
+// 译文和实际使用的翻译服务按下标写入 translated 和 engines，用量累加到 usage
+func (t *TranslateSubtitle) translateTextsInGroupsConcurrent(texts []string, pending []int, translated []string, engines []translationEngine, usage map[translationEngine]*translator.Usage, providers []string, targetLang string, glossary *services.Glossary) error {
+	groups := t.splitTranslationGroups(texts, pending)
+	totalGroups := len(groups)
 
-	taskChannel := make(chan translateTask, totalGroups)
-	resultChannel := make(chan struct {
-		groupIndex int
-		result     []string
-		err        error
-	}, totalGroups)
+	taskChannel := make(chan translationGroup, totalGroups)
+	resultChannel := make(chan groupResult, totalGroups)
 
 	// 启动工作者
 	var wg sync.WaitGroup
@@ -298,19 +798,15 @@ func (t *TranslateSubtitle) translateTextsInGroupsConcurrent(texts []string) ([]
 			t.App.Logger.Debugf("🔧 启动翻译工作者 %d", workerID)
 
 			for task := range taskChannel {
-				t.App.Logger.Infof("⏳ 工作者 %d 处理第 %d/%d 组 (%d句)",
-					workerID, task.groupIndex+1, totalGroups, len(task.texts))
-
-				// 使用简化的翻译方法
-				translated, err := t.translateGroupSimple(task.texts)
-
-				resultChannel <- struct {
-					groupIndex int
-					result     []string
-					err        error
-				}{
-					groupIndex: task.groupIndex,
-					result:     translated,
+				t.App.Logger.Infof("⏳ 工作者 %d 处理第 %d/%d 组 (上下文: 前%d句, 当前%d句, 后%d句)",
+					workerID, task.index+1, totalGroups, len(task.prevContext), len(task.texts), len(task.nextContext))
+
+				result, engine, groupUsage, err := t.translateGroupWithContext(task.texts, task.prevContext, task.nextContext, glossary.Match(task.texts...), providers, targetLang)
+				resultChannel <- groupResult{
+					groupIndex: task.index,
+					result:     result,
+					engine:     engine,
+					usage:      groupUsage,
 					err:        err,
 				}
 			}
@@ -319,16 +815,8 @@ func (t *TranslateSubtitle) translateTextsInGroupsConcurrent(texts []string) ([]
 
 	// 分发任务
 	go func() {
-		for i := 0; i < len(texts); i += t.GroupSize {
-			end := i + t.GroupSize
-			if end > len(texts) {
-				end = len(texts)
-			}
-
-			taskChannel <- translateTask{
-				groupIndex: i / t.GroupSize,
-				texts:      texts[i:end],
-			}
+		for _, group := range groups {
+			taskChannel <- group
 		}
 		close(taskChannel)
 	}()
@@ -347,226 +835,135 @@ func (t *TranslateSubtitle) translateTextsInGroupsConcurrent(texts []string) ([]
 			lastErr = result.err
 			continue
 		}
-		results[result.groupIndex] = result.result
-	}
-
-	if lastErr != nil {
-		return nil, lastErr
-	}
-
-	// 合并结果
-	var allTranslated []string
-	for _, groupResult := range results {
-		allTranslated = append(allTranslated, groupResult...)
+		// 按下标写回结果
+		for j, index := range groups[result.groupIndex].indices {
+			translated[index] = result.result[j]
+			engines[index] = result.engine
+		}
+		recordUsage(usage, result.engine, result.usage)
+		t.noteEngine(result.engine)
 	}
 
-	return allTranslated, nil
+	return lastErr
 }
 
-// translateGroupSimple 简化的组翻译（无上下文，更快速）
-func (t *TranslateSubtitle) translateGroupSimple(texts []string) ([]string, error) {
-	if len(texts) == 0 {
-		return []string{}, nil
-	}
-
-	// 直接组合文本
-	combinedText := strings.Join(texts, "\n###SENTENCE_BREAK###\n")
-
-	// 简化的系统提示
-	systemPrompt := fmt.Sprintf(`你是一个专业的视频字幕翻译专家。将给出的 %d 句英文字幕翻译成中文。
-
-翻译要求：
-1. 自然流畅：使用口语化表达，符合中文字幕习惯
-2. 准确传神：忠实原文含义，保持语气和情感
-3. 简洁明了：字幕需要快速阅读，避免冗长
-4. 数量严格：必须输出 %d 句翻译，不多不少
-5. 分隔符：每句翻译用"###SENTENCE_BREAK###"分隔
-
-输入格式：句子用"###SENTENCE_BREAK###"分隔
-输出格式：只返回中文翻译，用"###SENTENCE_BREAK###"分隔
-
-注意：只返回翻译的中文文本，不要添加序号、解释或其他内容。`, len(texts), len(texts))
-
-	translatedText, err := t.callDeepSeekAPI(systemPrompt, combinedText)
-	if err != nil {
-		return nil, err
-	}
-
-	translatedSentences := strings.Split(translatedText, "###SENTENCE_BREAK###")
-
-	// 清理和验证
-	for i := range translatedSentences {
-		translatedSentences[i] = strings.TrimSpace(translatedSentences[i])
+// recordUsage 累加某个翻译服务的用量
+func recordUsage(usage map[translationEngine]*translator.Usage, engine translationEngine, u *translator.Usage) {
+	if _, ok := usage[engine]; !ok {
+		usage[engine] = &translator.Usage{}
 	}
-
-	// 确保数量匹配
-	if len(translatedSentences) != len(texts) {
-		t.App.Logger.Warnf("⚠️  翻译结果数量不匹配: 期望%d句，实际%d句，正在修正...", len(texts), len(translatedSentences))
-		for len(translatedSentences) < len(texts) {
-			translatedSentences = append(translatedSentences, "[翻译缺失]")
-		}
-		if len(translatedSentences) > len(texts) {
-			translatedSentences = translatedSentences[:len(texts)]
-		}
-	}
-
-	return translatedSentences, nil
+	addUsage(usage[engine], u)
 }
 
-// translateTextsInGroups 分组翻译文本（带上下文）- 保留原方法作为备用
-func (t *TranslateSubtitle) translateTextsInGroups(texts []string) ([]string, error) {
-	var translatedTexts []string
-	totalGroups := (len(texts) + t.GroupSize - 1) / t.GroupSize
+// enforceGlossary 校验译文是否遵守术语表，对未遵守的句子带上下文重新翻译一次，返回仍未遵守的条目
+func (t *TranslateSubtitle) enforceGlossary(texts, translated []string, engines []translationEngine, usage map[translationEngine]*translator.Usage, providers []string, targetLang string, glossary *services.Glossary) []services.GlossaryViolation {
+	violations := glossary.Check(texts, translated)
+	if len(violations) == 0 {
+		return nil
+	}
+	t.App.Logger.Warnf("⚠️  %d 处译文未遵守术语表，正在重新翻译...", len(violations))
 
-	for i := 0; i < len(texts); i += t.GroupSize {
-		groupNum := (i / t.GroupSize) + 1
-		end := i + t.GroupSize
-		if end > len(texts) {
-			end = len(texts)
+	seen := make(map[int]bool)
+	for _, violation := range violations {
+		i := violation.Index
+		if seen[i] {
+			continue
 		}
+		seen[i] = true
 
-		currentGroup := texts[i:end]
-
-		// 准备上下文窗口
-		var prevContext, nextContext []string
-		contextSize := 2 // 前后各取2句作为上下文
-
-		// 获取前置上下文
-		if i > 0 {
-			prevStart := i - contextSize
-			if prevStart < 0 {
-				prevStart = 0
-			}
-			prevContext = texts[prevStart:i]
+		prevStart := i - t.ContextSize
+		if prevStart < 0 {
+			prevStart = 0
 		}
-
-		// 获取后置上下文
-		if end < len(texts) {
-			nextEnd := end + contextSize
-			if nextEnd > len(texts) {
-				nextEnd = len(texts)
-			}
-			nextContext = texts[end:nextEnd]
+		nextEnd := i + 1 + t.ContextSize
+		if nextEnd > len(texts) {
+			nextEnd = len(texts)
 		}
 
-		t.App.Logger.Infof("⏳ 翻译第 %d/%d 组 (上下文: 前%d句, 当前%d句, 后%d句)",
-			groupNum, totalGroups, len(prevContext), len(currentGroup), len(nextContext))
-
-		// 带上下文翻译
-		groupTranslated, err := t.translateGroupWithContext(currentGroup, prevContext, nextContext)
+		retried, engine, retryUsage, err := t.translateGroupWithContext(texts[i:i+1], texts[prevStart:i], texts[i+1:nextEnd], glossary.Match(texts[i]), providers, targetLang)
 		if err != nil {
-			return nil, fmt.Errorf("翻译第 %d 组失败: %v", groupNum, err)
+			t.App.Logger.Warnf("⚠️  第 %d 句重新翻译失败: %v", i+1, err)
+			continue
+		}
+		recordUsage(usage, engine, retryUsage)
+		t.noteEngine(engine)
+		if len(retried) == 1 && retried[0] != "" && retried[0] != "[翻译缺失]" {
+			translated[i] = retried[0]
+			engines[i] = engine
 		}
-
-		translatedTexts = append(translatedTexts, groupTranslated...)
-
-		// 移除组间延迟，改为根据需要动态调整
-		// 如果遇到API限制，可以在错误处理中添加重试和延迟
-	}
-
-	return translatedTexts, nil
-}
-
-// translateGroupWithContext 带上下文翻译一组文本
-func (t *TranslateSubtitle) translateGroupWithContext(texts []string, prevContext []string, nextContext []string) ([]string, error) {
-	// 构建包含上下文的完整文本
-	var fullTexts []string
-	targetStartIndex := 0
-
-	// 添加前置上下文
-	if len(prevContext) > 0 {
-		fullTexts = append(fullTexts, prevContext...)
-		targetStartIndex = len(fullTexts)
 	}
 
-	// 添加目标翻译文本
-	fullTexts = append(fullTexts, texts...)
-	targetEndIndex := len(fullTexts)
-
-	// 添加后置上下文
-	if len(nextContext) > 0 {
-		fullTexts = append(fullTexts, nextContext...)
+	remaining := glossary.Check(texts, translated)
+	for _, violation := range remaining {
+		t.App.Logger.Warnf("⚠️  第 %d 句未使用术语译法 %q: %s", violation.Index+1, violation.Expected, violation.Translation)
 	}
+	return remaining
+}
 
-	combinedText := strings.Join(fullTexts, "\n###SENTENCE_BREAK###\n")
-
-	// 构建系统提示
-	contextInfo := ""
-	if len(prevContext) > 0 || len(nextContext) > 0 {
-		contextInfo = fmt.Sprintf(`
-
-上下文信息：
-- 前置上下文：%d 句（仅供参考，不需要翻译）
-- 目标翻译：%d 句（位于第 %d-%d 句，需要全部翻译）
-- 后置上下文：%d 句（仅供参考，不需要翻译）
-
-请只翻译目标部分（第 %d-%d 句），但要充分考虑前后文的连贯性。`,
-			len(prevContext), len(texts), targetStartIndex+1, targetEndIndex,
-			len(nextContext), targetStartIndex+1, targetEndIndex)
+// translateGroupWithContext 通过翻译器管理器按提供商链将一组文本翻译成 targetLang，terms 为本组出现的术语
+// 前后文和术语表只有大模型翻译器会使用；返回译文、实际使用的翻译服务和用量
+func (t *TranslateSubtitle) translateGroupWithContext(texts []string, prevContext []string, nextContext []string, terms []model.GlossaryTerm, providers []string, targetLang string) ([]string, translationEngine, *translator.Usage, error) {
+	if len(texts) == 0 {
+		return []string{}, translationEngine{}, nil, nil
 	}
 
-	systemPrompt := fmt.Sprintf(`你是一个专业的视频字幕翻译专家。我将给你一段连续的英文字幕，其中包含 %d 句需要翻译的内容。%s
-
-翻译要求：
-1. 自然流畅：使用口语化表达，符合中文字幕习惯
-2. 上下文连贯：理解整体语境，确保翻译前后呼应
-3. 准确传神：忠实原文含义，保持语气和情感
-4. 简洁明了：字幕需要快速阅读，避免冗长
-5. 数量严格：必须输出 %d 句翻译，不多不少
-6. 分隔符：每句翻译用"###SENTENCE_BREAK###"分隔
-
-输入格式：句子用"###SENTENCE_BREAK###"分隔
-输出格式：只返回目标部分的中文翻译，用"###SENTENCE_BREAK###"分隔
-
-注意：只返回翻译的中文文本，不要添加序号、解释或其他内容。`, len(texts), contextInfo, len(texts))
-
-	translatedText, err := t.callDeepSeekAPI(systemPrompt, combinedText)
+	batch, err := t.Translator.BatchTranslateWithFallback(context.Background(), providers, &translator.BatchTranslationRequest{
+		Texts:        texts,
+		SourceLang:   t.sourceLang,
+		TargetLang:   targetLang,
+		TextType:     "subtitle",
+		PrevContext:  prevContext,
+		NextContext:  nextContext,
+		Instructions: services.GlossaryPrompt(terms),
+		Prompts:      t.prompts,
+	})
 	if err != nil {
-		return nil, err
-	}
-
-	translatedSentences := strings.Split(translatedText, "###SENTENCE_BREAK###")
-
-	// 清理和验证
-	for i := range translatedSentences {
-		translatedSentences[i] = strings.TrimSpace(translatedSentences[i])
+		return nil, translationEngine{}, nil, err
 	}
 
-	// 确保数量匹配
-	if len(translatedSentences) != len(texts) {
-		t.App.Logger.Warnf("⚠️  翻译结果数量不匹配: 期望%d句，实际%d句，正在修正...", len(texts), len(translatedSentences))
-		for len(translatedSentences) < len(texts) {
-			translatedSentences = append(translatedSentences, "[翻译缺失]")
-		}
-		if len(translatedSentences) > len(texts) {
-			translatedSentences = translatedSentences[:len(texts)]
+	engine := translationEngine{Provider: batch.Provider}
+	sentences := make([]string, len(batch.Results))
+	for i, result := range batch.Results {
+		sentences[i] = strings.TrimSpace(result.TranslatedText)
+		if engine.Model == "" {
+			engine.Model = result.Model
 		}
 	}
 
-	return translatedSentences, nil
+	return sentences, engine, batch.Usage, nil
 }
 
-// callDeepSeekAPI 调用AI API（使用AI服务管理器，支持自动故障转移）
-func (t *TranslateSubtitle) callDeepSeekAPI(systemPrompt, userPrompt string) (string, error) {
-	// 使用AI服务管理器进行调用（自动选择首选服务，失败时自动切换）
-	response, provider, err := t.AIManager.ChatCompletion(systemPrompt, userPrompt)
-	if err != nil {
-		return "", fmt.Errorf("AI服务调用失败: %v", err)
+// noteEngine 记录实际使用的翻译服务，发生切换时输出日志（只在收集结果的协程中调用）
+func (t *TranslateSubtitle) noteEngine(engine translationEngine) {
+	if engine.Provider != t.LastProvider {
+		t.App.Logger.Infof("🔄 翻译服务已切换: %s -> %s", t.LastProvider, engine.Provider)
+		t.LastProvider = engine.Provider
 	}
+}
 
-	// 记录实际使用的提供商
-	if provider != t.LastProvider {
-		t.App.Logger.Infof("🔄 AI服务已切换: %s -> %s", t.LastProvider, provider)
-		t.LastProvider = provider
+// failedProvider 从翻译服务链的错误（"all translators failed: deepseek: ...; openai: ..."）中找出包含 keyword 的翻译服务，
+// 找不到时翻译服务链只有一个服务则返回该服务，否则返回空字符串
+func failedProvider(errorStr, keyword string, providers []string) string {
+	for _, part := range strings.Split(strings.TrimPrefix(errorStr, "all translators failed: "), "; ") {
+		provider, detail, ok := strings.Cut(part, ": ")
+		if ok && !strings.Contains(provider, " ") && strings.Contains(detail, keyword) {
+			return provider
+		}
 	}
-
-	return response, nil
+	if len(providers) == 1 {
+		return providers[0]
+	}
+	return ""
 }
 
-// getTranslationError 将翻译错误转换为用户友好的错误信息
-func (t *TranslateSubtitle) getTranslationError(err error) string {
+// getTranslationError 将翻译错误转换为用户友好的错误信息，providers 为本次使用的翻译服务链
+func (t *TranslateSubtitle) getTranslationError(err error, providers []string) string {
 	errorStr := err.Error()
 
+	if strings.Contains(errorStr, "no translator provider enabled") {
+		return "翻译失败：没有可用的翻译服务，请在设置中配置AI服务，或启用百度/腾讯/微软/Google/Ollama等翻译服务"
+	}
+
 	if strings.Contains(errorStr, "没有可用的AI服务") {
 		return "翻译失败：没有可用的AI服务，请在设置中配置AI服务（首选OpenAI兼容API或DeepSeek）"
 	}
@@ -584,7 +981,10 @@ func (t *TranslateSubtitle) getTranslationError(err error) string {
 	}
 
 	if strings.Contains(errorStr, "insufficient_quota") || strings.Contains(errorStr, "quota") {
-		return "翻译失败：DeepSeek账户余额不足，请充值后重试"
+		if provider := failedProvider(errorStr, "quota", providers); provider != "" {
+			return fmt.Sprintf("翻译失败：%s 账户余额或额度不足，请充值后重试", provider)
+		}
+		return "翻译失败：翻译服务账户余额或额度不足，请充值后重试"
 	}
 
 	if strings.Contains(errorStr, "timeout") || strings.Contains(errorStr, "deadline exceeded") {
@@ -622,31 +1022,39 @@ func maskAPIKey(apiKey string) string {
 	return "***"
 }
 
-// validateAndOptimizeSubtitles 校验和优化字幕质量
-func (t *TranslateSubtitle) validateAndOptimizeSubtitles(originalPath, translatedPath string) (string, *utils.ValidationResult, error) {
-	// 获取当前API Key用于修复
-	apiKey, err := t.getCurrentAPIKey()
+// validateAndOptimizeSubtitles 校验和优化指定语言的字幕质量，有修复时写回译文字幕并返回 true
+func (t *TranslateSubtitle) validateAndOptimizeSubtitles(originalPath, translatedPath, lang string) (bool, *utils.ValidationResult, error) {
+	// 获取当前AI服务用于修复
+	provider, err := t.validationProvider()
 	if err != nil {
-		return "", nil, fmt.Errorf("无法获取API Key进行校验: %v", err)
+		return false, nil, fmt.Errorf("无法获取AI服务进行校验: %v", err)
 	}
+	defer provider.Close()
 
 	// 创建校验器
-	validator := utils.NewSubtitleValidator(t.App.Logger, apiKey)
+	validator := utils.NewSubtitleValidator(t.App.Logger, provider)
+	validator.SetTargetLanguage(lang, services.LanguageName(translatorLanguage(lang)))
+	validator.SetPrompts(t.prompts)
 
-	// 生成优化后的文件路径
-	optimizedPath := filepath.Join(t.StateManager.CurrentDir, "zh_optimized.srt")
+	// 校验器输出到临时的优化文件，不保留：<语言>.srt 始终是唯一的最终译文，字幕编辑、断句、上传和烧录都以它为准
+	optimizedPath := t.StateManager.OptimizedSRTPath(lang)
+	defer os.Remove(optimizedPath)
 
 	// 执行校验和修复
 	result, err := validator.ValidateAndFixSubtitles(originalPath, translatedPath, optimizedPath)
 	if err != nil {
-		return "", nil, err
+		return false, nil, err
 	}
 
-	// 如果有修复，返回优化文件路径
-	if len(result.FixedEntries) > 0 {
-		return optimizedPath, result, nil
+	// 没有问题或无法修复，保留原译文
+	if len(result.FixedEntries) == 0 {
+		return false, result, nil
 	}
 
-	// 没有问题或无法修复，返回空路径
-	return "", result, nil
+	// 使用优化后的文件替换原文件
+	if err := os.Rename(optimizedPath, translatedPath); err != nil {
+		t.App.Logger.Warnf("⚠️  [%s] 应用字幕优化结果失败: %v", lang, err)
+		return false, result, nil
+	}
+	return true, result, nil
 }
@@ -0,0 +1,39 @@
+package handlers
+
+import "testing"
+
+func TestFailedProvider(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       string
+		providers []string
+		want      string
+	}{
+		{
+			name:      "provider from chain error",
+			err:       "all translators failed: deepseek: status 500; openai: insufficient_quota: You exceeded your current quota",
+			providers: []string{"deepseek", "openai"},
+			want:      "openai",
+		},
+		{
+			name:      "single provider chain",
+			err:       "You exceeded your current quota",
+			providers: []string{"ai"},
+			want:      "ai",
+		},
+		{
+			name:      "unknown provider",
+			err:       "quota exceeded",
+			providers: []string{"deepseek", "openai"},
+			want:      "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := failedProvider(tt.err, "quota", tt.providers); got != tt.want {
+				t.Errorf("failedProvider() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -113,26 +113,32 @@ type SubtitleFileInfo struct {
 	Language string
 }
 
+// subtitleCandidate 待检查的字幕文件及其 Bilibili 字幕语言
+type subtitleCandidate struct {
+	filename string
+	language string
+}
+
 // findSubtitleFiles 查找字幕文件
 func (t *UploadSubtitleToBilibili) findSubtitleFiles() []SubtitleFileInfo {
 	var subtitleFiles []SubtitleFileInfo
 
-	// 检查常见的字幕文件
-	subtitleFilesToCheck := []struct {
-		filename string
-		language string
-	}{
-		{"zh_optimized.srt", "zh-Hans"}, // 中文简体
-		{"en.srt", "en"},                // 英文
-		//{"zh-cn.srt", "zh-Hans"}, // 中文简体
-		//{"zh-tw.srt", "zh-Hant"}, // 中文繁体
-		//{"ja.srt", "ja"},         // 日文
-		//{"ko.srt", "ko"},         // 韩文
+	// 各目标语言的最终译文字幕（如 zh.srt、zh-Hant.srt、ja.srt，有断句结果时为 <语言>_segmented.srt）
+	var subtitleFilesToCheck []subtitleCandidate
+	for _, lang := range TargetLanguages(t.App.Config) {
+		subtitleFilesToCheck = append(subtitleFilesToCheck, subtitleCandidate{filepath.Base(t.StateManager.FinalSRTPath(lang)), lang})
 	}
+	subtitleFilesToCheck = append(subtitleFilesToCheck, subtitleCandidate{"en.srt", "en"}) // 英文
 
+	// 同一语言只上传一个文件
+	found := make(map[string]bool)
 	for _, item := range subtitleFilesToCheck {
+		if found[item.language] {
+			continue
+		}
 		fullPath := filepath.Join(t.StateManager.CurrentDir, item.filename)
 		if _, err := os.Stat(fullPath); err == nil {
+			found[item.language] = true
 			subtitleFiles = append(subtitleFiles, SubtitleFileInfo{
 				Path:     fullPath,
 				Language: item.language,
@@ -141,5 +147,16 @@ func (t *UploadSubtitleToBilibili) findSubtitleFiles() []SubtitleFileInfo {
 		}
 	}
 
+	// 双语字幕作为单独的字幕轨道上传
+	if config := t.App.Config.BilibiliConfig; config != nil && config.BilingualSubtitle && config.BilingualLanguage != "" && !found[config.BilingualLanguage] {
+		if _, err := os.Stat(t.StateManager.BilingualSRT); err == nil {
+			subtitleFiles = append(subtitleFiles, SubtitleFileInfo{
+				Path:     t.StateManager.BilingualSRT,
+				Language: config.BilingualLanguage,
+			})
+			t.App.Logger.Infof("🎯 找到双语字幕文件: %s (%s)", filepath.Base(t.StateManager.BilingualSRT), config.BilingualLanguage)
+		}
+	}
+
 	return subtitleFiles
 }
@@ -16,6 +16,8 @@ import (
 	"github.com/difyz9/ytb2bili/internal/core/services"
 	"github.com/difyz9/ytb2bili/internal/storage"
 	"github.com/difyz9/ytb2bili/pkg/cos"
+	"github.com/difyz9/ytb2bili/pkg/proxypool"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
 	"github.com/difyz9/ytb2bili/pkg/utils"
 )
 
@@ -36,35 +38,56 @@ func (t *UploadToBilibili) fetchAndSaveMetadata(videoID string) error {
 
 	// 2. 构建命令
 	videoURL := fmt.Sprintf("https://www.youtube.com/watch?v=%s", videoID)
-	command := []string{
-		ytdlpPath,
-		"--dump-json",
-		"--no-download",
-		videoURL,
+	savedVideo, err := t.SavedVideoService.GetVideoByVideoID(videoID)
+	if err != nil {
+		return fmt.Errorf("获取视频记录失败: %v", err)
 	}
 
-	// 添加 cookies 支持
-	configDir := filepath.Dir(t.App.Config.Path)
-	cookiesPath := filepath.Join(configDir, "cookies.txt")
-	// 如果配置文件目录下的 cookies.txt 不存在，尝试当前目录
-	if _, err := os.Stat(cookiesPath); err != nil {
-		cookiesPath = "cookies.txt"
-	}
-	if _, err := os.Stat(cookiesPath); err == nil {
-		absPath, _ := filepath.Abs(cookiesPath)
-		command = append(command, "--cookies", absPath)
-	}
+	// 使用托管的 cookies 配置（没有配置时回退到 cookies.txt）
+	cookies := NewYtDlpCookieJar(t.App, videoURL, savedVideo.ChannelID)
+	defer cookies.Cleanup()
 
-	// 添加代理
-	if t.App.Config != nil && t.App.Config.ProxyConfig != nil && t.App.Config.ProxyConfig.UseProxy && t.App.Config.ProxyConfig.ProxyHost != "" {
-		command = append(command, "--proxy", t.App.Config.ProxyConfig.ProxyHost)
-	}
+	// 3. 执行命令（cookies 被限流时切换配置重试）
+	var output []byte
+	attempts := t.App.ProxyPool.Attempts(videoURL)
+	proxy := attempts[0]
+	for {
+		command := []string{
+			ytdlpPath,
+			"--dump-json",
+			"--no-download",
+		}
+		command = append(command, cookies.Args()...)
 
-	// 3. 执行命令
-	cmd := exec.Command(command[0], command[1:]...)
-	output, err := cmd.Output()
-	if err != nil {
-		return fmt.Errorf("执行 yt-dlp 失败: %v", err)
+		// 添加代理（按目标域名从代理池中选择）
+		if proxy != "" {
+			command = append(command, "--proxy", proxy)
+		} else {
+			t.App.ProxyPool.ReportDirect(videoURL)
+		}
+		command = append(command, videoURL)
+
+		cmd := exec.Command(command[0], command[1:]...)
+		output, err = cmd.Output()
+		if err == nil {
+			cookies.MarkSuccess()
+			t.App.ProxyPool.ReportSuccess(proxy)
+			break
+		}
+
+		stderr := ""
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			stderr = string(exitErr.Stderr)
+		}
+		if proxy != "" && t.App.ProxyPool.ReportFailure(proxy, stderr) && proxypool.IsBlockedError(stderr) && len(attempts) > 1 {
+			// 代理被限流/拒绝，换下一个代理重试
+			attempts = attempts[1:]
+			proxy = attempts[0]
+			continue
+		}
+		if !cookies.Rotate(stderr) {
+			return fmt.Errorf("执行 yt-dlp 失败: %v", err)
+		}
 	}
 
 	// 4. 解析 JSON
@@ -74,13 +97,12 @@ func (t *UploadToBilibili) fetchAndSaveMetadata(videoID string) error {
 	}
 
 	// 5. 更新数据库
-	savedVideo, err := t.SavedVideoService.GetVideoByVideoID(videoID)
-	if err != nil {
-		return fmt.Errorf("获取视频记录失败: %v", err)
-	}
-
 	savedVideo.Title = metadata.Title
 	savedVideo.Description = metadata.Description
+	if metadata.ChannelID != "" {
+		savedVideo.ChannelID = metadata.ChannelID
+		savedVideo.ChannelName = metadata.Channel
+	}
 	// 如果需要，也可以更新其他字段
 
 	if err := t.SavedVideoService.UpdateVideo(savedVideo); err != nil {
@@ -220,6 +242,16 @@ func (t *UploadToBilibili) Execute(context map[string]interface{}) bool {
 		}
 	}
 
+	// 记录投稿使用的标题候选，之后定时获取播放数据比较标题效果
+	if bvid, ok := context["bili_bvid"].(string); ok && bvid != "" {
+		variants := services.NewMetadataVariantService(t.App.DB)
+		if marked, err := variants.MarkUploaded(t.StateManager.VideoID, bvid, studio.Title, studio.Tid); err != nil {
+			t.App.Logger.Warnf("⚠️ 记录标题候选投稿信息失败: %v", err)
+		} else if marked {
+			t.App.Logger.Info("✓ 已记录投稿使用的标题候选")
+		}
+	}
+
 	// 10. 输出成功信息
 	t.App.Logger.Info("========================================")
 	t.App.Logger.Infof("✓ 视频投稿成功！")
@@ -233,14 +265,39 @@ func (t *UploadToBilibili) Execute(context map[string]interface{}) bool {
 }
 
 // findVideoFiles 查找下载目录中的视频文件
+// 当前分区启用硬字幕且已烧录时，烧录字幕的视频排在最前；否则不上传烧录字幕的视频
 func (t *UploadToBilibili) findVideoFiles() []string {
+	files, err := listVideoFiles(t.StateManager.CurrentDir)
+	if err != nil {
+		t.App.Logger.Errorf("读取目录失败: %v", err)
+		return nil
+	}
+
+	savedVideo, _ := t.SavedVideoService.GetVideoByVideoID(t.StateManager.VideoID)
+	useHardSub := t.App.Config.HardSubConfig.AppliesTo(PartitionTid(t.App.Config, savedVideo))
+
+	var videoFiles []string
+	for _, file := range files {
+		if file == t.StateManager.HardSubVideo {
+			if useHardSub {
+				videoFiles = append([]string{file}, videoFiles...)
+			}
+			continue
+		}
+		videoFiles = append(videoFiles, file)
+	}
+
+	return videoFiles
+}
+
+// listVideoFiles 列出目录中的视频文件
+func listVideoFiles(dir string) ([]string, error) {
 	var videoFiles []string
 	videoExtensions := []string{".mp4", ".flv", ".mkv", ".webm", ".avi", ".mov"}
 
-	files, err := os.ReadDir(t.StateManager.CurrentDir)
+	files, err := os.ReadDir(dir)
 	if err != nil {
-		t.App.Logger.Errorf("读取目录失败: %v", err)
-		return videoFiles
+		return nil, err
 	}
 
 	for _, file := range files {
@@ -251,14 +308,13 @@ func (t *UploadToBilibili) findVideoFiles() []string {
 		ext := strings.ToLower(filepath.Ext(file.Name()))
 		for _, videoExt := range videoExtensions {
 			if ext == videoExt {
-				fullPath := filepath.Join(t.StateManager.CurrentDir, file.Name())
-				videoFiles = append(videoFiles, fullPath)
+				videoFiles = append(videoFiles, filepath.Join(dir, file.Name()))
 				break
 			}
 		}
 	}
 
-	return videoFiles
+	return videoFiles, nil
 }
 
 // buildStudioInfo 构建投稿信息
@@ -356,12 +412,16 @@ func (t *UploadToBilibili) buildStudioInfo(video *bilibili.Video, context map[st
 			return true
 		}
 
+		// AI生成的章节列表
+		chapters := t.generatedChapters(savedVideo)
+
 		// 根据配置选择描述来源
 		if biliConfig != nil && biliConfig.CustomDescTemplate != "" {
 			// 使用自定义模板
 			desc = biliConfig.CustomDescTemplate
 			desc = strings.ReplaceAll(desc, "{original_desc}", savedVideo.Description)
 			desc = strings.ReplaceAll(desc, "{ai_desc}", savedVideo.GeneratedDesc)
+			desc = strings.ReplaceAll(desc, "{chapters}", chapters)
 			t.App.Logger.Infof("✓ 使用自定义描述模板")
 		} else if biliConfig != nil && biliConfig.UseOriginalDesc {
 			// 配置为使用原始描述
@@ -385,6 +445,10 @@ func (t *UploadToBilibili) buildStudioInfo(video *bilibili.Video, context map[st
 				aiIntro = savedVideo.GeneratedDesc
 				t.App.Logger.Infof("✓ AI生成的精炼介绍: %s", aiIntro)
 			}
+			if chapters != "" {
+				aiIntro = strings.TrimSpace(aiIntro + "\n\n章节：\n" + chapters)
+				t.App.Logger.Info("✓ 已添加AI生成的章节列表")
+			}
 
 			// 获取原视频简介
 			if isValidDescription(savedVideo.Description) {
@@ -530,6 +594,14 @@ func (t *UploadToBilibili) buildStudioInfo(video *bilibili.Video, context map[st
 		upCloseReward = t.App.Config.BilibiliConfig.UpCloseReward
 	}
 
+	// 使用AI建议的分区和生成的动态（MetadataConfig）
+	tid = PartitionTid(t.App.Config, savedVideo)
+	if metaConfig := t.App.Config.MetadataConfig; metaConfig != nil && metaConfig.UseGeneratedDynamic &&
+		savedVideo != nil && savedVideo.GeneratedDynamic != "" {
+		dynamic = savedVideo.GeneratedDynamic
+		t.App.Logger.Infof("✓ 使用AI生成的动态: %s", dynamic)
+	}
+
 	// 如果是转载且没有提供来源，使用视频URL作为来源
 	if copyright == 2 && source == "" {
 		if savedVideo != nil {
@@ -589,6 +661,20 @@ func (t *UploadToBilibili) buildStudioInfo(video *bilibili.Video, context map[st
 	return studio
 }
 
+// generatedChapters AI生成的章节列表文本，未启用 MetadataConfig.IncludeChapters 或没有章节时返回空
+func (t *UploadToBilibili) generatedChapters(savedVideo *model.SavedVideo) string {
+	metaConfig := t.App.Config.MetadataConfig
+	if metaConfig == nil || !metaConfig.IncludeChapters || savedVideo.GeneratedChapters == "" {
+		return ""
+	}
+	var chapters []services.MetadataChapter
+	if err := json.Unmarshal([]byte(savedVideo.GeneratedChapters), &chapters); err != nil {
+		t.App.Logger.Warnf("⚠️ 解析章节列表失败: %v", err)
+		return ""
+	}
+	return services.ChaptersText(chapters)
+}
+
 // truncateString 截断字符串用于日志显示
 func (t *UploadToBilibili) truncateString(s string, maxLen int) string {
 	runes := []rune(s)
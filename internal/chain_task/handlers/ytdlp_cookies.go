@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/services"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+)
+
+// YtDlpCookieJar 为 yt-dlp 提供 cookies 参数
+// 优先使用数据库中管理的 cookies 配置（按来源适配器/频道选择，限流时轮换）；
+// 没有配置任何 cookies 时回退到旧的 cookies.txt / 浏览器 cookies
+type YtDlpCookieJar struct {
+	App     *core.AppServer
+	Service *services.CookieProfileService
+	Adapter string
+	Channel string
+
+	profile  *model.CookieProfile
+	tempFile string
+	exclude  []uint
+}
+
+// NewYtDlpCookieJar 根据视频地址创建 cookies 管理器
+func NewYtDlpCookieJar(app *core.AppServer, videoURL, channel string) *YtDlpCookieJar {
+	jar := &YtDlpCookieJar{
+		App:     app,
+		Adapter: cookieAdapterFor(videoURL),
+		Channel: channel,
+	}
+	if app != nil && app.DB != nil {
+		jar.Service = services.NewCookieProfileService(app.DB, app.Config)
+	}
+	return jar
+}
+
+// cookieAdapterFor 根据视频地址判断来源适配器
+func cookieAdapterFor(videoURL string) string {
+	lower := strings.ToLower(videoURL)
+	switch {
+	case strings.Contains(lower, "bilibili.com"), strings.Contains(lower, "b23.tv"):
+		return "bilibili"
+	case strings.Contains(lower, "youtube.com"), strings.Contains(lower, "youtu.be"):
+		return "youtube"
+	case !strings.Contains(lower, "://"):
+		// 纯视频ID默认按 YouTube 处理
+		return "youtube"
+	default:
+		return "generic"
+	}
+}
+
+// Args 获取当前使用的 cookies 参数
+func (j *YtDlpCookieJar) Args() []string {
+	if j.tempFile != "" {
+		return []string{"--cookies", j.tempFile}
+	}
+
+	if j.Service != nil && j.Service.HasProfiles(j.Adapter) {
+		if j.acquire() {
+			return []string{"--cookies", j.tempFile}
+		}
+		j.App.Logger.Warnf("⚠️ %s 的 cookies 配置均不可用（冷却中或已过期），不使用 cookies", j.Adapter)
+		return nil
+	}
+
+	return legacyCookieArgs(j.App)
+}
+
+// acquire 选取一个可用的 cookies 配置并写入临时文件
+func (j *YtDlpCookieJar) acquire() bool {
+	profile, err := j.Service.AcquireProfile(j.Adapter, j.Channel, j.exclude)
+	if err != nil {
+		j.App.Logger.Errorf("❌ 获取 cookies 配置失败: %v", err)
+		return false
+	}
+	if profile == nil {
+		return false
+	}
+
+	content, err := j.Service.Decrypt(profile)
+	if err != nil {
+		j.App.Logger.Errorf("❌ 解密 cookies 配置 %s 失败: %v", profile.Name, err)
+		j.exclude = append(j.exclude, profile.ID)
+		return j.acquire()
+	}
+
+	file, err := os.CreateTemp("", "ytb2bili-cookies-*.txt")
+	if err != nil {
+		j.App.Logger.Errorf("❌ 创建 cookies 临时文件失败: %v", err)
+		return false
+	}
+	defer file.Close()
+
+	// yt-dlp 会回写 cookies 文件，必须是可写的普通文件，且只允许当前用户读取
+	if err := file.Chmod(0600); err != nil {
+		j.App.Logger.Warnf("⚠️ 设置 cookies 临时文件权限失败: %v", err)
+	}
+	if _, err := file.Write(content); err != nil {
+		j.App.Logger.Errorf("❌ 写入 cookies 临时文件失败: %v", err)
+		os.Remove(file.Name())
+		return false
+	}
+
+	j.profile = profile
+	j.tempFile = file.Name()
+	j.App.Logger.Infof("🍪 使用 Cookies 配置: %s (ID: %d)", profile.Name, profile.ID)
+	return true
+}
+
+// Rotate 根据 yt-dlp 的错误输出判断是否需要轮换 cookies
+// 被限流或年龄限制时将当前配置置为冷却状态并切换到下一个配置，返回是否可以重试
+func (j *YtDlpCookieJar) Rotate(output string) bool {
+	if j.profile == nil || j.Service == nil {
+		return false
+	}
+
+	status := services.ClassifyYtDlpError(output)
+	if status == "" {
+		return false
+	}
+
+	j.App.Logger.Warnf("⚠️ Cookies 配置 %s 遇到 %s，切换到下一个配置", j.profile.Name, status)
+	if err := j.Service.MarkFailure(j.profile.ID, status, lastLinesOf(output, 3)); err != nil {
+		j.App.Logger.Errorf("❌ 更新 cookies 配置状态失败: %v", err)
+	}
+
+	j.exclude = append(j.exclude, j.profile.ID)
+	j.release()
+	return j.acquire()
+}
+
+// MarkSuccess 记录当前 cookies 配置使用成功
+func (j *YtDlpCookieJar) MarkSuccess() {
+	if j.profile == nil || j.Service == nil || j.profile.Status == model.CookieStatusActive {
+		return
+	}
+	if err := j.Service.MarkSuccess(j.profile.ID); err != nil {
+		j.App.Logger.Errorf("❌ 更新 cookies 配置状态失败: %v", err)
+	}
+}
+
+// Cleanup 删除临时 cookies 文件
+func (j *YtDlpCookieJar) Cleanup() {
+	j.release()
+}
+
+// release 释放当前 cookies 配置
+func (j *YtDlpCookieJar) release() {
+	if j.tempFile != "" {
+		os.Remove(j.tempFile)
+	}
+	j.tempFile = ""
+	j.profile = nil
+}
+
+// legacyCookieArgs 旧的 cookies 查找逻辑：配置文件目录或当前目录下的 cookies.txt，否则读取 Chrome 浏览器 cookies
+func legacyCookieArgs(app *core.AppServer) []string {
+	cookiesPath := "cookies.txt"
+	if app != nil && app.Config != nil {
+		cookiesPath = filepath.Join(filepath.Dir(app.Config.Path), "cookies.txt")
+		if _, err := os.Stat(cookiesPath); err != nil {
+			cookiesPath = "cookies.txt"
+		}
+	}
+
+	if _, err := os.Stat(cookiesPath); err == nil {
+		absPath, _ := filepath.Abs(cookiesPath)
+		app.Logger.Infof("🍪 使用 Cookies 文件: %s", absPath)
+		return []string{"--cookies", absPath}
+	}
+
+	app.Logger.Warn("⚠️ 未配置 cookies，尝试从 Chrome 浏览器读取，可能会遇到 'Sign in to confirm you're not a bot' 错误")
+	return []string{"--cookies-from-browser", "chrome"}
+}
+
+// lastLinesOf 取输出的最后几行
+func lastLinesOf(output string, n int) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
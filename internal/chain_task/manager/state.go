@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/difyz9/ytb2bili/internal/core/models"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
 )
 
 // StateManager 任务状态管理器
@@ -31,6 +32,11 @@ type StateManager struct {
 	M3u8FileDir     string
 	TranslateSRT    string
 	TranslateVtt    string
+	BilingualSRT    string // 双语字幕
+	BilingualASS    string
+	BilingualBCC    string
+	HardSubASS      string // 烧录用的 ASS 字幕
+	HardSubVideo    string // 烧录字幕后的视频（原视频保留）
 	TranslateTXT    string
 	// 目录路径
 	AudioDir       string
@@ -67,6 +73,11 @@ func NewStateManager(Id uint, videoID, projectRoot string, createTim time.Time)
 		TranslateJSON:  filepath.Join(currentDir, "zh.json"),
 		TranslateSRT:   filepath.Join(currentDir, "zh.srt"),
 		TranslateVtt:   filepath.Join(currentDir, "zh.vtt"),
+		BilingualSRT:   filepath.Join(currentDir, "bilingual.srt"),
+		BilingualASS:   filepath.Join(currentDir, "bilingual.ass"),
+		BilingualBCC:   filepath.Join(currentDir, "bilingual.bcc"),
+		HardSubASS:     filepath.Join(currentDir, "hardsub.ass"),
+		HardSubVideo:   filepath.Join(currentDir, videoID+"_hardsub.mp4"),
 		TranslateTXT:   filepath.Join(currentDir, videoID+"_trans.txt"),
 		//AudioDir:       audioDir,
 		//M3u8FileDir:    m8u3Dir,
@@ -90,6 +101,34 @@ func (s *StateManager) SetCache(key string, value interface{}) {
 	s.cache[key] = value
 }
 
+// TranslatedSRTPath 指定语言的译文字幕路径（简体中文为 zh.srt，其余为 <语言>.srt）
+func (s *StateManager) TranslatedSRTPath(lang string) string {
+	return filepath.Join(s.CurrentDir, subtitle.LanguageFilePrefix(lang)+".srt")
+}
+
+// TranslatedVTTPath 指定语言的 WebVTT 译文字幕路径
+func (s *StateManager) TranslatedVTTPath(lang string) string {
+	return filepath.Join(s.CurrentDir, subtitle.LanguageFilePrefix(lang)+".vtt")
+}
+
+// OptimizedSRTPath 指定语言校验修复时的临时输出路径（修复结果会写回译文字幕）
+func (s *StateManager) OptimizedSRTPath(lang string) string {
+	return filepath.Join(s.CurrentDir, subtitle.LanguageFilePrefix(lang)+"_optimized.srt")
+}
+
+// SegmentedSRTPath 指定语言上传前断句后的译文字幕路径
+func (s *StateManager) SegmentedSRTPath(lang string) string {
+	return filepath.Join(s.CurrentDir, subtitle.LanguageFilePrefix(lang)+"_segmented.srt")
+}
+
+// FinalSRTPath 指定语言最终上传和烧录的译文字幕：有上传前断句的结果时使用断句后的文件，否则为译文字幕
+func (s *StateManager) FinalSRTPath(lang string) string {
+	if path := s.SegmentedSRTPath(lang); fileExists(path) {
+		return path
+	}
+	return s.TranslatedSRTPath(lang)
+}
+
 // UpdateTBVideo 更新TBVideo记录并通过MQTT通知
 func (s *StateManager) UpdateTBVideo(item *models.TbVideo) error {
 	// 使用 GORM 的 Updates 方法，仅更新非空字段
@@ -99,6 +138,12 @@ func (s *StateManager) UpdateTBVideo(item *models.TbVideo) error {
 	return nil
 }
 
+// fileExists 文件是否存在
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
 // GetCurrentDateYYYYMMDD 返回当前日期的yyyymmdd格式字符串
 func GetCurrentDateYYYYMMDD(time2 time.Time) string {
 	return time2.Format("2006-01-02")
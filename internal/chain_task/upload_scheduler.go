@@ -218,8 +218,18 @@ func (s *UploadScheduler) executeUploadTask(videoID, taskName string) error {
 	// 根据任务名称创建对应的任务
 	switch taskName {
 	case "上传到Bilibili":
+		// 准备阶段视频尚未下载，上传前按视频音频校正字幕、译文和章节的时间轴
+		chain.Context["subtitle_source"] = savedVideo.SubtitleSource
+		chain.AddTask(handlers.NewAlignSubtitles("字幕对齐", s.App, stateManager, s.App.CosClient, s.SavedVideoService))
+		// 启用硬字幕时，先对译文断句，再烧录到视频中（原视频保留）
+		if s.App.Config.HardSubConfig.AppliesTo(handlers.PartitionTid(s.App.Config, savedVideo)) {
+			chain.AddTask(handlers.NewResegmentSubtitles("字幕断句", s.App, stateManager, s.App.CosClient, handlers.SegmentStageUpload))
+			chain.AddTask(handlers.NewBurnSubtitles("烧录字幕", s.App, stateManager, s.App.CosClient))
+		}
 		task = handlers.NewUploadToBilibili("上传到Bilibili", s.App, stateManager, s.App.CosClient, s.SavedVideoService)
 	case "上传字幕到Bilibili":
+		// 上传前对译文字幕断句和断行
+		chain.AddTask(handlers.NewResegmentSubtitles("字幕断句", s.App, stateManager, s.App.CosClient, handlers.SegmentStageUpload))
 		task = handlers.NewUploadSubtitleToBilibili("上传字幕到Bilibili", s.App, stateManager, s.App.CosClient, s.SavedVideoService)
 	default:
 		return fmt.Errorf("未知的任务类型: %s", taskName)
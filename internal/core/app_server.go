@@ -9,6 +9,7 @@ import (
 
 	"github.com/difyz9/ytb2bili/internal/core/types"
 	"github.com/difyz9/ytb2bili/pkg/cos"
+	"github.com/difyz9/ytb2bili/pkg/proxypool"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -21,8 +22,8 @@ type AppServer struct {
 	Engine    *gin.Engine
 	Logger    *zap.SugaredLogger
 	DB        *gorm.DB
-	CosClient *cos.CosClient // COS客户端
-
+	CosClient *cos.CosClient  // COS客户端
+	ProxyPool *proxypool.Pool // 代理池
 }
 
 // NewServer 创建新的服务器实例
@@ -31,9 +32,10 @@ func NewServer(config *types.AppConfig, logger *zap.SugaredLogger) *AppServer {
 	gin.SetMode(gin.ReleaseMode)
 	gin.DefaultWriter = io.Discard
 	return &AppServer{
-		Config: config,
-		Engine: gin.Default(),
-		Logger: logger,
+		Config:    config,
+		Engine:    gin.Default(),
+		Logger:    logger,
+		ProxyPool: proxypool.NewPool(config.ProxyConfig, logger),
 	}
 }
 
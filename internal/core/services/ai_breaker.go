@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/llm"
+)
+
+// 熔断器状态
+const (
+	BreakerClosed   = "closed"    // 正常
+	BreakerOpen     = "open"      // 熔断中，请求直接跳过
+	BreakerHalfOpen = "half_open" // 冷却结束，放行一次探测请求
+)
+
+// latencyWindow 统计最近多少次成功调用的延迟
+const latencyWindow = 20
+
+// breakerPolicy 熔断规则
+type breakerPolicy struct {
+	threshold     int
+	openFor       time.Duration
+	rateLimitOpen time.Duration
+}
+
+// newBreakerPolicy 由配置得到熔断规则，未配置时使用默认值
+func newBreakerPolicy(config *types.AIFailoverConfig) breakerPolicy {
+	policy := breakerPolicy{threshold: 3, openFor: 60 * time.Second, rateLimitOpen: 120 * time.Second}
+	if config == nil {
+		return policy
+	}
+	if config.FailureThreshold > 0 {
+		policy.threshold = config.FailureThreshold
+	}
+	if config.OpenSeconds > 0 {
+		policy.openFor = time.Duration(config.OpenSeconds) * time.Second
+	}
+	if config.RateLimitOpenSeconds > 0 {
+		policy.rateLimitOpen = time.Duration(config.RateLimitOpenSeconds) * time.Second
+	}
+	return policy
+}
+
+// CircuitBreaker 单个AI服务的熔断器，同时记录最近的调用延迟
+type CircuitBreaker struct {
+	mu            sync.Mutex
+	state         string
+	failures      int // 连续失败次数
+	openedAt      time.Time
+	retryAt       time.Time // 熔断结束、可以探测的时间
+	probing       bool      // 半开状态下探测请求是否在进行中
+	lastError     string
+	lastSuccessAt time.Time
+	lastFailureAt time.Time
+	totalCalls    int64
+	totalFailures int64
+	latencies     []time.Duration
+	latencyNext   int
+	lastLatency   time.Duration
+}
+
+// BreakerStatus 熔断器状态快照
+type BreakerStatus struct {
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+	RetryAt             *time.Time `json:"retry_at,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+	LastSuccessAt       *time.Time `json:"last_success_at,omitempty"`
+	LastFailureAt       *time.Time `json:"last_failure_at,omitempty"`
+	TotalCalls          int64      `json:"total_calls"`
+	TotalFailures       int64      `json:"total_failures"`
+	LatencyLastMs       int64      `json:"latency_last_ms"`
+	LatencyAvgMs        int64      `json:"latency_avg_ms"`
+	LatencyP95Ms        int64      `json:"latency_p95_ms"`
+	LatencySamples      int        `json:"latency_samples"`
+}
+
+func newCircuitBreaker() *CircuitBreaker {
+	return &CircuitBreaker{state: BreakerClosed}
+}
+
+// Allow 请求前调用：熔断中返回 false；冷却结束后转为半开并只放行一个探测请求
+func (b *CircuitBreaker) Allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if now.Before(b.retryAt) {
+			return false
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+		return true
+	case BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// Available 是否可以发起请求（不占用半开状态的探测名额），用于选择首选服务
+func (b *CircuitBreaker) Available(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		return !now.Before(b.retryAt)
+	case BreakerHalfOpen:
+		return !b.probing
+	default:
+		return true
+	}
+}
+
+// Record 记录一次调用结果；取消的请求和请求本身不被支持（如文件类型）不计入失败
+func (b *CircuitBreaker) Record(err error, latency time.Duration, policy breakerPolicy, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err != nil && (errors.Is(err, context.Canceled) || errors.Is(err, llm.ErrFilesNotSupported)) {
+		b.probing = false
+		return
+	}
+
+	b.totalCalls++
+	b.probing = false
+	if err == nil {
+		b.state = BreakerClosed
+		b.failures = 0
+		b.lastSuccessAt = now
+		b.lastLatency = latency
+		if len(b.latencies) < latencyWindow {
+			b.latencies = append(b.latencies, latency)
+		} else {
+			b.latencies[b.latencyNext] = latency
+		}
+		b.latencyNext = (b.latencyNext + 1) % latencyWindow
+		return
+	}
+
+	b.totalFailures++
+	b.failures++
+	b.lastError = err.Error()
+	b.lastFailureAt = now
+
+	switch {
+	case llm.IsRateLimited(err):
+		b.open(now, policy.rateLimitOpen)
+	case b.state == BreakerHalfOpen || b.failures >= policy.threshold:
+		b.open(now, policy.openFor)
+	}
+}
+
+func (b *CircuitBreaker) open(now time.Time, duration time.Duration) {
+	b.state = BreakerOpen
+	b.openedAt = now
+	b.retryAt = now.Add(duration)
+}
+
+// Reset 恢复为正常状态（如更新了服务配置）
+func (b *CircuitBreaker) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state = BreakerClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// Status 状态快照；熔断时间已过但还没有探测时报告为半开
+func (b *CircuitBreaker) Status(now time.Time) BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := BreakerStatus{
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+		LastError:           b.lastError,
+		TotalCalls:          b.totalCalls,
+		TotalFailures:       b.totalFailures,
+		LatencyLastMs:       b.lastLatency.Milliseconds(),
+		LatencySamples:      len(b.latencies),
+	}
+	if b.state == BreakerOpen && !now.Before(b.retryAt) {
+		status.State = BreakerHalfOpen
+	}
+	if b.state != BreakerClosed {
+		openedAt, retryAt := b.openedAt, b.retryAt
+		status.OpenedAt, status.RetryAt = &openedAt, &retryAt
+	}
+	if !b.lastSuccessAt.IsZero() {
+		t := b.lastSuccessAt
+		status.LastSuccessAt = &t
+	}
+	if !b.lastFailureAt.IsZero() {
+		t := b.lastFailureAt
+		status.LastFailureAt = &t
+	}
+
+	if len(b.latencies) > 0 {
+		sorted := append([]time.Duration(nil), b.latencies...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		var total time.Duration
+		for _, latency := range sorted {
+			total += latency
+		}
+		status.LatencyAvgMs = (total / time.Duration(len(sorted))).Milliseconds()
+		status.LatencyP95Ms = sorted[(len(sorted)*95-1)/100].Milliseconds()
+	}
+	return status
+}
+
+// breakerRegistry 各AI服务的熔断器
+// 各任务步骤会各自创建 AIServiceManager，熔断状态需要在进程内共享
+type breakerRegistry struct {
+	mu       sync.Mutex
+	breakers map[AIProvider]*CircuitBreaker
+}
+
+func newBreakerRegistry() *breakerRegistry {
+	return &breakerRegistry{breakers: make(map[AIProvider]*CircuitBreaker)}
+}
+
+// sharedBreakers 进程内共享的熔断器
+var sharedBreakers = newBreakerRegistry()
+
+func (r *breakerRegistry) get(provider AIProvider) *CircuitBreaker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, ok := r.breakers[provider]
+	if !ok {
+		b = newCircuitBreaker()
+		r.breakers[provider] = b
+	}
+	return b
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/llm"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	policy := newBreakerPolicy(&types.AIFailoverConfig{FailureThreshold: 2, OpenSeconds: 10, RateLimitOpenSeconds: 60})
+	now := time.Now()
+	b := newCircuitBreaker()
+
+	// 连续失败达到阈值后熔断
+	b.Record(errors.New("timeout"), time.Second, policy, now)
+	if !b.Allow(now) {
+		t.Fatal("未达到阈值时不应熔断")
+	}
+	b.Record(errors.New("timeout"), time.Second, policy, now)
+	if b.Allow(now.Add(5*time.Second)) || b.Status(now).State != BreakerOpen {
+		t.Fatalf("达到阈值后应熔断: %+v", b.Status(now))
+	}
+
+	// 冷却结束后只放行一个探测请求
+	later := now.Add(10 * time.Second)
+	if b.Status(later).State != BreakerHalfOpen || !b.Allow(later) {
+		t.Fatal("冷却结束后应放行探测请求")
+	}
+	if b.Allow(later) || b.Available(later) {
+		t.Fatal("探测进行中不应放行其他请求")
+	}
+
+	// 探测失败立即重新熔断，成功则恢复
+	b.Record(errors.New("still down"), time.Second, policy, later)
+	if b.Allow(later.Add(time.Second)) {
+		t.Fatal("探测失败后应重新熔断")
+	}
+	again := later.Add(10 * time.Second)
+	if !b.Allow(again) {
+		t.Fatal("第二次冷却结束后应放行探测请求")
+	}
+	b.Record(nil, 200*time.Millisecond, policy, again)
+	status := b.Status(again)
+	if status.State != BreakerClosed || status.ConsecutiveFailures != 0 || status.LatencyLastMs != 200 || status.TotalFailures != 3 {
+		t.Errorf("探测成功后状态不正确: %+v", status)
+	}
+
+	// 限流错误直接熔断，使用更长的冷却时间
+	b.Record(&llm.APIError{Provider: "deepseek", StatusCode: 429, Message: "rate limit"}, time.Second, policy, again)
+	if b.Allow(again.Add(30*time.Second)) || !b.Available(again.Add(60*time.Second)) {
+		t.Errorf("限流熔断时间不正确: %+v", b.Status(again))
+	}
+}
+
+func TestCircuitBreakerIgnoresUnsupportedFiles(t *testing.T) {
+	policy := newBreakerPolicy(nil)
+	now := time.Now()
+	b := newCircuitBreaker()
+	for i := 0; i < 5; i++ {
+		b.Record(llm.ErrFilesNotSupported, 0, policy, now)
+	}
+	if status := b.Status(now); status.State != BreakerClosed || status.TotalCalls != 0 {
+		t.Errorf("不支持的请求不应计入失败: %+v", status)
+	}
+}
@@ -1,16 +1,15 @@
 package services
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
-	"net/http"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/llm"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
 	"go.uber.org/zap"
 )
 
@@ -43,6 +42,16 @@ type AIServiceManager struct {
 	mu         sync.RWMutex
 	statusMap  map[AIProvider]*AIServiceStatus
 	lastUpdate time.Time
+	breakers   *breakerRegistry
+
+	usage      *AIUsageService // 用量统计（为空时不记录）
+	usageScope UsageScope      // 用量记录所属的视频和步骤
+}
+
+// UsageScope AI调用所属的视频和任务步骤
+type UsageScope struct {
+	VideoID string
+	Step    string
 }
 
 // NewAIServiceManager 创建AI服务管理器
@@ -51,6 +60,7 @@ func NewAIServiceManager(config *types.AppConfig, log *zap.SugaredLogger) *AISer
 		config:    config,
 		logger:    log,
 		statusMap: make(map[AIProvider]*AIServiceStatus),
+		breakers:  sharedBreakers,
 	}
 	manager.initStatus()
 	return manager
@@ -140,34 +150,60 @@ func (m *AIServiceManager) RefreshConfig(config *types.AppConfig) {
 }
 
 // GetPreferredProvider 获取首选的AI服务提供商
-// 优先使用用户选择的首选服务，如果未设置则按默认优先级
+// 按故障转移顺序（默认用户选择的首选服务优先）返回第一个启用且未熔断的服务；全部熔断时返回第一个启用的服务
 func (m *AIServiceManager) GetPreferredProvider() (AIProvider, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	// 1. 首先检查用户选择的首选服务
-	if m.config.PrimaryAIService != "" {
-		provider := AIProvider(m.config.PrimaryAIService)
-		if status, ok := m.statusMap[provider]; ok && status.Enabled {
+	now := time.Now()
+	var fallback AIProvider
+	for _, provider := range m.priorityLocked() {
+		if status, ok := m.statusMap[provider]; !ok || !status.Enabled {
+			continue
+		}
+		if m.breakers.get(provider).Available(now) {
 			return provider, nil
 		}
-		// 用户选择的服务未启用，继续查找其他可用服务
+		if fallback == "" {
+			fallback = provider
+		}
 	}
-
-	// 2. 按默认优先级查找：OpenAI兼容API > DeepSeek > Gemini
-	if status := m.statusMap[AIProviderOpenAICompatible]; status.Enabled {
-		return AIProviderOpenAICompatible, nil
+	if fallback != "" {
+		return fallback, nil
 	}
 
-	if status := m.statusMap[AIProviderDeepSeek]; status.Enabled {
-		return AIProviderDeepSeek, nil
-	}
+	return "", fmt.Errorf("没有可用的AI服务，请先配置AI服务")
+}
 
-	if status := m.statusMap[AIProviderGemini]; status.Enabled {
-		return AIProviderGemini, nil
+// Priority 故障转移顺序：AIFailoverConfig.Priority，未配置时为 首选服务 > OpenAI兼容API > DeepSeek > Gemini
+func (m *AIServiceManager) Priority() []AIProvider {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.priorityLocked()
+}
+
+func (m *AIServiceManager) priorityLocked() []AIProvider {
+	var candidates []AIProvider
+	if cfg := m.config.AIFailoverConfig; cfg != nil && len(cfg.Priority) > 0 {
+		for _, provider := range cfg.Priority {
+			candidates = append(candidates, AIProvider(provider))
+		}
+	} else {
+		if m.config.PrimaryAIService != "" {
+			candidates = append(candidates, AIProvider(m.config.PrimaryAIService))
+		}
+		candidates = append(candidates, AIProviderOpenAICompatible, AIProviderDeepSeek, AIProviderGemini)
 	}
 
-	return "", fmt.Errorf("没有可用的AI服务，请先配置AI服务")
+	seen := make(map[AIProvider]bool)
+	var providers []AIProvider
+	for _, provider := range candidates {
+		if _, ok := m.statusMap[provider]; ok && !seen[provider] {
+			seen[provider] = true
+			providers = append(providers, provider)
+		}
+	}
+	return providers
 }
 
 // GetAvailableProvider 获取可用的AI服务提供商（带故障转移）
@@ -269,42 +305,84 @@ func (m *AIServiceManager) GetGeminiConfig() *types.GeminiConfig {
 // IsOpenAICompatibleEnabled 检查OpenAI兼容API是否启用
 func (m *AIServiceManager) IsOpenAICompatibleEnabled() bool {
 	cfg := m.GetOpenAICompatibleConfig()
-	return cfg != nil && cfg.Enabled && cfg.ApiKey != ""
+	return cfg != nil && cfg.Enabled && cfg.HasApiKey()
 }
 
 // IsDeepSeekEnabled 检查DeepSeek是否启用
 func (m *AIServiceManager) IsDeepSeekEnabled() bool {
 	cfg := m.GetDeepSeekConfig()
-	return cfg != nil && cfg.Enabled && cfg.ApiKey != ""
+	return cfg != nil && cfg.Enabled && cfg.HasApiKey()
 }
 
 // IsGeminiEnabled 检查Gemini是否启用
 func (m *AIServiceManager) IsGeminiEnabled() bool {
 	cfg := m.GetGeminiConfig()
-	return cfg != nil && cfg.Enabled && cfg.ApiKey != ""
+	return cfg != nil && cfg.Enabled && cfg.HasApiKey()
 }
 
 // ChatCompletion 执行对话补全（自动选择AI服务）
 // 优先使用首选服务，失败后自动切换到备选服务
 func (m *AIServiceManager) ChatCompletion(systemPrompt, userPrompt string) (string, AIProvider, error) {
-	providers := []AIProvider{
-		AIProviderOpenAICompatible,
-		AIProviderDeepSeek,
+	return m.ChatCompletionWithModel("", "", systemPrompt, userPrompt)
+}
+
+// ChatCompletionWithModel 使用指定服务和模型执行对话补全（如质量评审、低分重译需要特定模型）
+// provider 为空时按优先级自动选择并故障转移；model 为空时使用该服务配置的模型
+func (m *AIServiceManager) ChatCompletionWithModel(provider AIProvider, model, systemPrompt, userPrompt string) (string, AIProvider, error) {
+	resp, used, err := m.Chat(context.Background(), provider, &llm.Request{
+		Model:    model,
+		Messages: llm.Messages(systemPrompt, userPrompt),
+	})
+	if err != nil {
+		return "", "", err
 	}
+	return resp.Content, used, nil
+}
+
+// Chat 执行对话请求（支持 JSON 模式、文件输入，返回使用量）
+// provider 为空时按故障转移顺序依次尝试，跳过熔断中的服务；指定 provider 时只使用该服务
+func (m *AIServiceManager) Chat(ctx context.Context, provider AIProvider, req *llm.Request) (*llm.Response, AIProvider, error) {
+	return m.chat(ctx, provider, req, true)
+}
 
+// chat 执行对话请求，track 为 false 时不记录用量（由调用方汇总后记录，如字幕翻译）
+func (m *AIServiceManager) chat(ctx context.Context, provider AIProvider, req *llm.Request, track bool) (*llm.Response, AIProvider, error) {
+	providers := m.Priority()
+	if provider != "" {
+		if !m.isProviderEnabled(provider) {
+			return nil, "", fmt.Errorf("AI服务 %s 未启用", provider)
+		}
+		providers = []AIProvider{provider}
+	}
+
+	policy := newBreakerPolicy(m.config.AIFailoverConfig)
 	var lastErr error
+	var skipped []string
 	for _, provider := range providers {
 		if !m.isProviderEnabled(provider) {
 			continue
 		}
 
+		breaker := m.breakers.get(provider)
+		if !breaker.Allow(time.Now()) {
+			skipped = append(skipped, m.getProviderName(provider))
+			m.logger.Infof("⏭️  %s 熔断中，跳过", m.getProviderName(provider))
+			continue
+		}
+
 		m.logger.Infof("🤖 尝试使用 %s 进行AI对话...", m.getProviderName(provider))
 
-		result, err := m.chatWithProvider(provider, systemPrompt, userPrompt)
+		startTime := time.Now()
+		resp, err := m.chatWithProvider(ctx, provider, req)
+		latency := time.Since(startTime)
+		breaker.Record(err, latency, policy, time.Now())
 		if err == nil {
+			if track {
+				m.recordUsage(provider, resp, latency)
+			}
 			m.SetAvailable(provider, true, "")
 			m.logger.Infof("✅ %s 调用成功", m.getProviderName(provider))
-			return result, provider, nil
+			return resp, provider, nil
 		}
 
 		lastErr = err
@@ -312,75 +390,249 @@ func (m *AIServiceManager) ChatCompletion(systemPrompt, userPrompt string) (stri
 		m.logger.Warnf("⚠️ %s 调用失败: %v，尝试下一个服务...", m.getProviderName(provider), err)
 	}
 
-	return "", "", fmt.Errorf("所有AI服务都不可用: %v", lastErr)
+	if lastErr == nil {
+		if len(skipped) > 0 {
+			return nil, "", fmt.Errorf("AI服务均处于熔断状态: %s", strings.Join(skipped, ", "))
+		}
+		return nil, "", fmt.Errorf("没有可用的AI服务，请先配置AI服务")
+	}
+	return nil, "", fmt.Errorf("所有AI服务都不可用: %v", lastErr)
 }
 
-// chatWithProvider 使用指定提供商进行对话
-func (m *AIServiceManager) chatWithProvider(provider AIProvider, systemPrompt, userPrompt string) (string, error) {
-	switch provider {
-	case AIProviderOpenAICompatible:
-		return m.chatWithOpenAICompatible(systemPrompt, userPrompt)
-	case AIProviderDeepSeek:
-		return m.chatWithDeepSeek(systemPrompt, userPrompt)
-	default:
-		return "", fmt.Errorf("不支持的AI提供商: %s", provider)
+// TrackUsage 记录之后每次调用的用量，归属到指定视频和任务步骤
+func (m *AIServiceManager) TrackUsage(usage *AIUsageService, videoID, step string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.usage = usage
+	m.usageScope = UsageScope{VideoID: videoID, Step: step}
+}
+
+// recordUsage 保存一次调用的用量（失败只记日志，不影响调用结果）
+func (m *AIServiceManager) recordUsage(provider AIProvider, resp *llm.Response, latency time.Duration) {
+	m.mu.RLock()
+	usage, scope := m.usage, m.usageScope
+	modelName := ""
+	if status, ok := m.statusMap[provider]; ok {
+		modelName = status.Model
+	}
+	m.mu.RUnlock()
+
+	if !usage.Enabled() {
+		return
+	}
+	if resp.Model != "" {
+		modelName = resp.Model
+	}
+
+	record := &model.AIUsage{
+		VideoID:      scope.VideoID,
+		Step:         scope.Step,
+		Provider:     string(provider),
+		Model:        modelName,
+		InputTokens:  resp.Usage.PromptTokens,
+		OutputTokens: resp.Usage.CompletionTokens,
+		TotalTokens:  resp.Usage.TotalTokens,
+		DurationMs:   latency.Milliseconds(),
+	}
+	if err := usage.Record(record); err != nil {
+		m.logger.Warnf("⚠️ 记录AI用量失败: %v", err)
 	}
 }
 
-// chatWithOpenAICompatible 使用OpenAI兼容API进行对话
-func (m *AIServiceManager) chatWithOpenAICompatible(systemPrompt, userPrompt string) (string, error) {
-	cfg := m.GetOpenAICompatibleConfig()
-	if cfg == nil || !cfg.Enabled {
-		return "", fmt.Errorf("OpenAI兼容API未启用")
+// Metered 包装直接使用的 llm.Provider（如 Gemini 视频分析、字幕校验），成功的调用同样记录用量
+func (m *AIServiceManager) Metered(provider AIProvider, client llm.Provider) llm.Provider {
+	return &meteredProvider{Provider: client, manager: m, provider: provider}
+}
+
+// meteredProvider 记录用量的 llm.Provider
+type meteredProvider struct {
+	llm.Provider
+	manager  *AIServiceManager
+	provider AIProvider
+}
+
+func (p *meteredProvider) Chat(ctx context.Context, req *llm.Request) (*llm.Response, error) {
+	startTime := time.Now()
+	resp, err := p.Provider.Chat(ctx, req)
+	if err == nil {
+		p.manager.recordUsage(p.provider, resp, time.Since(startTime))
 	}
+	return resp, err
+}
 
-	client := m.createOpenAICompatibleClient(cfg)
-	return client.ChatCompletion(systemPrompt, userPrompt)
+func (p *meteredProvider) Stream(ctx context.Context, req *llm.Request, onDelta func(string) error) (*llm.Response, error) {
+	startTime := time.Now()
+	resp, err := p.Provider.Stream(ctx, req, onDelta)
+	if err == nil {
+		p.manager.recordUsage(p.provider, resp, time.Since(startTime))
+	}
+	return resp, err
 }
 
-// chatWithDeepSeek 使用DeepSeek进行对话
-func (m *AIServiceManager) chatWithDeepSeek(systemPrompt, userPrompt string) (string, error) {
-	cfg := m.GetDeepSeekConfig()
-	if cfg == nil || !cfg.Enabled {
-		return "", fmt.Errorf("DeepSeek未启用")
+// BreakerStatus 获取指定服务的熔断器状态和最近延迟
+func (m *AIServiceManager) BreakerStatus(provider AIProvider) BreakerStatus {
+	return m.breakers.get(provider).Status(time.Now())
+}
+
+// ResetBreaker 手动恢复指定服务的熔断器
+func (m *AIServiceManager) ResetBreaker(provider AIProvider) {
+	m.breakers.get(provider).Reset()
+	m.SetAvailable(provider, true, "")
+}
+
+// ProbeProviders 对熔断冷却结束的服务发起一次探测请求（定时任务调用），返回探测过的服务及结果
+func (m *AIServiceManager) ProbeProviders(ctx context.Context) map[AIProvider]error {
+	policy := newBreakerPolicy(m.config.AIFailoverConfig)
+	results := make(map[AIProvider]error)
+	for _, provider := range m.Priority() {
+		if !m.isProviderEnabled(provider) {
+			continue
+		}
+		breaker := m.breakers.get(provider)
+		if breaker.Status(time.Now()).State != BreakerHalfOpen || !breaker.Allow(time.Now()) {
+			continue
+		}
+
+		startTime := time.Now()
+		err := m.probe(ctx, provider)
+		breaker.Record(err, time.Since(startTime), policy, time.Now())
+		results[provider] = err
+		if err != nil {
+			m.SetAvailable(provider, false, err.Error())
+			m.logger.Warnf("⚠️ %s 探测失败，继续熔断: %v", m.getProviderName(provider), err)
+		} else {
+			m.SetAvailable(provider, true, "")
+			m.logger.Infof("✅ %s 探测成功，熔断已恢复", m.getProviderName(provider))
+		}
 	}
+	return results
+}
+
+// probe 用一次简短的对话探测服务是否恢复
+func (m *AIServiceManager) probe(ctx context.Context, provider AIProvider) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
 
-	// 使用OpenAI兼容客户端调用DeepSeek
-	client := m.createOpenAICompatibleClientFromDeepSeek(cfg)
-	return client.ChatCompletion(systemPrompt, userPrompt)
+	client, err := m.NewProvider(ctx, provider)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	return llm.Ping(ctx, client)
 }
 
-// createOpenAICompatibleClient 创建OpenAI兼容客户端
-func (m *AIServiceManager) createOpenAICompatibleClient(cfg *types.OpenAICompatibleConfig) *OpenAICompatibleClient {
-	return NewOpenAICompatibleClient(&OpenAIClientConfig{
-		APIKey:      cfg.ApiKey,
-		BaseURL:     cfg.BaseURL,
-		Model:       cfg.Model,
-		Timeout:     cfg.Timeout,
-		MaxRetries:  3,
-		Temperature: cfg.Temperature,
-		MaxTokens:   cfg.MaxTokens,
-	})
+// chatWithProvider 使用指定提供商进行对话
+func (m *AIServiceManager) chatWithProvider(ctx context.Context, provider AIProvider, req *llm.Request) (*llm.Response, error) {
+	client, err := m.NewProvider(ctx, provider)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	return client.Chat(ctx, req)
 }
 
-// createOpenAICompatibleClientFromDeepSeek 从DeepSeek配置创建OpenAI兼容客户端
-func (m *AIServiceManager) createOpenAICompatibleClientFromDeepSeek(cfg *types.DeepSeekTransConfig) *OpenAICompatibleClient {
-	baseURL := cfg.Endpoint
-	if baseURL == "" {
-		baseURL = "https://api.deepseek.com/v1"
+// NewProvider 按当前配置创建指定服务的 llm.Provider（调用方负责 Close）
+// 每次请求从该服务的共享密钥池中选择 API Key，被限流或密钥失效时换用下一个
+func (m *AIServiceManager) NewProvider(ctx context.Context, provider AIProvider) (llm.Provider, error) {
+	switch provider {
+	case AIProviderOpenAICompatible:
+		cfg := m.GetOpenAICompatibleConfig()
+		if cfg == nil || !cfg.Enabled {
+			return nil, fmt.Errorf("OpenAI兼容API未启用")
+		}
+		config := llm.Config{
+			BaseURL:     cfg.BaseURL,
+			Model:       cfg.Model,
+			Timeout:     llm.Seconds(cfg.Timeout),
+			MaxRetries:  llm.DefaultMaxRetries,
+			Temperature: cfg.Temperature,
+			MaxTokens:   cfg.MaxTokens,
+
+			StructuredOutput: cfg.StructuredOutput,
+		}
+		return m.pooled(provider, cfg.Keys(), llm.NewOpenAICompatible(config).Model(), config, func(ctx context.Context, config llm.Config) (llm.Provider, error) {
+			return llm.NewOpenAICompatible(config), nil
+		}), nil
+	case AIProviderDeepSeek:
+		cfg := m.GetDeepSeekConfig()
+		if cfg == nil || !cfg.Enabled {
+			return nil, fmt.Errorf("DeepSeek未启用")
+		}
+		config := llm.Config{
+			BaseURL:     cfg.Endpoint,
+			Model:       cfg.Model,
+			Timeout:     llm.Seconds(cfg.Timeout),
+			MaxRetries:  llm.DefaultMaxRetries,
+			Temperature: 0.3,
+			MaxTokens:   cfg.MaxTokens,
+		}
+		return m.pooled(provider, cfg.Keys(), llm.NewDeepSeek(config).Model(), config, func(ctx context.Context, config llm.Config) (llm.Provider, error) {
+			return llm.NewDeepSeek(config), nil
+		}), nil
+	case AIProviderGemini:
+		cfg := m.GetGeminiConfig()
+		if cfg == nil || !cfg.Enabled {
+			return nil, fmt.Errorf("Gemini未启用")
+		}
+		config := llm.Config{
+			Model:      cfg.Model,
+			Timeout:    llm.Seconds(cfg.Timeout),
+			MaxRetries: llm.DefaultMaxRetries,
+			MaxTokens:  cfg.MaxTokens,
+		}
+		model := cfg.Model
+		if model == "" {
+			model = llm.GeminiModel
+		}
+		return m.pooled(provider, cfg.Keys(), model, config, func(ctx context.Context, config llm.Config) (llm.Provider, error) {
+			return llm.NewGemini(ctx, config)
+		}), nil
+	default:
+		return nil, fmt.Errorf("不支持的AI提供商: %s", provider)
 	}
+}
 
-	return NewOpenAICompatibleClient(&OpenAIClientConfig{
-		APIKey:      cfg.ApiKey,
-		BaseURL:     baseURL,
-		Model:       cfg.Model,
-		Timeout:     cfg.Timeout,
-		MaxRetries:  3,
-		Temperature: 0.3,
-		MaxTokens:   cfg.MaxTokens,
+// pooled 创建使用共享密钥池的 Provider；有多个密钥时限流直接换用下一个密钥，不在同一个密钥上等待重试
+func (m *AIServiceManager) pooled(provider AIProvider, keys []string, model string, config llm.Config, create func(ctx context.Context, config llm.Config) (llm.Provider, error)) llm.Provider {
+	pool := m.KeyPool(provider, keys)
+	config.FailOnRateLimit = len(keys) > 1
+	return llm.NewPooled(string(provider), model, pool, func(ctx context.Context, apiKey string) (llm.Provider, error) {
+		keyConfig := config
+		keyConfig.APIKey = apiKey
+		return create(ctx, keyConfig)
 	})
 }
 
+// KeyPool 指定服务的共享密钥池（keys 为空时使用当前配置的密钥）
+func (m *AIServiceManager) KeyPool(provider AIProvider, keys []string) *llm.KeyPool {
+	m.mu.RLock()
+	config := m.config
+	m.mu.RUnlock()
+
+	if keys == nil {
+		switch provider {
+		case AIProviderOpenAICompatible:
+			if cfg := config.OpenAICompatibleConfig; cfg != nil {
+				keys = cfg.Keys()
+			}
+		case AIProviderDeepSeek:
+			if cfg := config.DeepSeekTransConfig; cfg != nil {
+				keys = cfg.Keys()
+			}
+		case AIProviderGemini:
+			if cfg := config.GeminiConfig; cfg != nil {
+				keys = cfg.Keys()
+			}
+		}
+	}
+	return llm.SharedKeyPool(string(provider), keys, llm.KeyPoolPolicy(config.AIKeyPoolConfig.Policy(string(provider))))
+}
+
+// KeyStatus 指定服务各 API Key 的状态
+func (m *AIServiceManager) KeyStatus(provider AIProvider) []llm.KeyStatus {
+	return m.KeyPool(provider, nil).Status(time.Now())
+}
+
 // isProviderEnabled 检查提供商是否启用
 func (m *AIServiceManager) isProviderEnabled(provider AIProvider) bool {
 	switch provider {
@@ -405,153 +657,3 @@ func (m *AIServiceManager) getProviderName(provider AIProvider) string {
 	}
 	return string(provider)
 }
-
-// OpenAICompatibleClient 引用handlers包中的客户端
-type OpenAICompatibleClient = openAICompatibleClientWrapper
-
-// openAICompatibleClientWrapper 包装器，避免循环引用
-type openAICompatibleClientWrapper struct {
-	apiKey      string
-	baseURL     string
-	model       string
-	timeout     int
-	maxRetries  int
-	temperature float64
-	maxTokens   int
-}
-
-// OpenAIClientConfig 客户端配置
-type OpenAIClientConfig struct {
-	APIKey      string
-	BaseURL     string
-	Model       string
-	Timeout     int
-	MaxRetries  int
-	Temperature float64
-	MaxTokens   int
-}
-
-// NewOpenAICompatibleClient 创建客户端
-func NewOpenAICompatibleClient(config *OpenAIClientConfig) *openAICompatibleClientWrapper {
-	return &openAICompatibleClientWrapper{
-		apiKey:      config.APIKey,
-		baseURL:     config.BaseURL,
-		model:       config.Model,
-		timeout:     config.Timeout,
-		maxRetries:  config.MaxRetries,
-		temperature: config.Temperature,
-		maxTokens:   config.MaxTokens,
-	}
-}
-
-// ChatCompletion 执行对话
-func (c *openAICompatibleClientWrapper) ChatCompletion(systemPrompt, userPrompt string) (string, error) {
-	// 构建请求
-	type Message struct {
-		Role    string `json:"role"`
-		Content string `json:"content"`
-	}
-	type Request struct {
-		Model       string    `json:"model"`
-		Messages    []Message `json:"messages"`
-		Stream      bool      `json:"stream"`
-		Temperature float64   `json:"temperature,omitempty"`
-		MaxTokens   int       `json:"max_tokens,omitempty"`
-	}
-	type Choice struct {
-		Message Message `json:"message"`
-	}
-	type Response struct {
-		Choices []Choice `json:"choices"`
-		Error   *struct {
-			Message string `json:"message"`
-			Type    string `json:"type"`
-		} `json:"error,omitempty"`
-	}
-
-	request := Request{
-		Model: c.model,
-		Messages: []Message{
-			{Role: "system", Content: systemPrompt},
-			{Role: "user", Content: userPrompt},
-		},
-		Stream:      false,
-		Temperature: c.temperature,
-		MaxTokens:   c.maxTokens,
-	}
-
-	jsonData, err := json.Marshal(request)
-	if err != nil {
-		return "", fmt.Errorf("序列化请求失败: %v", err)
-	}
-
-	// 构建API URL
-	apiURL := strings.TrimSuffix(c.baseURL, "/")
-	if !strings.Contains(apiURL, "/chat/completions") {
-		if !strings.HasSuffix(apiURL, "/v1") {
-			apiURL = apiURL + "/v1"
-		}
-		apiURL = apiURL + "/chat/completions"
-	}
-
-	// 创建HTTP客户端
-	client := &http.Client{
-		Timeout: time.Duration(c.timeout) * time.Second,
-	}
-
-	var lastErr error
-	for attempt := 0; attempt <= c.maxRetries; attempt++ {
-		if attempt > 0 {
-			time.Sleep(2 * time.Second * time.Duration(attempt))
-		}
-
-		req, err := http.NewRequest("POST", apiURL, bytes.NewBuffer(jsonData))
-		if err != nil {
-			return "", fmt.Errorf("创建请求失败: %v", err)
-		}
-
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
-
-		resp, err := client.Do(req)
-		if err != nil {
-			lastErr = fmt.Errorf("发送请求失败: %v", err)
-			continue
-		}
-
-		body, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		if err != nil {
-			lastErr = fmt.Errorf("读取响应失败: %v", err)
-			continue
-		}
-
-		var response Response
-		if err := json.Unmarshal(body, &response); err != nil {
-			lastErr = fmt.Errorf("解析响应失败: %v", err)
-			continue
-		}
-
-		if response.Error != nil {
-			lastErr = fmt.Errorf("API错误: %s", response.Error.Message)
-			if strings.Contains(response.Error.Message, "rate limit") {
-				time.Sleep(5 * time.Second * time.Duration(attempt+1))
-			}
-			continue
-		}
-
-		if resp.StatusCode != http.StatusOK {
-			lastErr = fmt.Errorf("API返回错误 (状态码: %d): %s", resp.StatusCode, string(body))
-			continue
-		}
-
-		if len(response.Choices) == 0 {
-			lastErr = fmt.Errorf("API响应中没有结果")
-			continue
-		}
-
-		return response.Choices[0].Message.Content, nil
-	}
-
-	return "", fmt.Errorf("重试 %d 次后仍然失败: %v", c.maxRetries, lastErr)
-}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/difyz9/ytb2bili/pkg/llm"
+	"github.com/difyz9/ytb2bili/pkg/prompt"
+	"github.com/difyz9/ytb2bili/pkg/translator"
+)
+
+// AITranslatorProvider 基于AI服务管理器的翻译器在提供商链中的名称
+const AITranslatorProvider = "ai"
+
+// sentenceBreak 批量翻译时句子之间的分隔符
+const sentenceBreak = "###SENTENCE_BREAK###"
+
+// AITranslator 使用AI服务管理器（OpenAI兼容API / DeepSeek，自动故障转移）实现 translator.Translator
+// 支持前后文和附加要求（术语表），结果中的 Provider 为实际使用的AI服务
+type AITranslator struct {
+	Manager *AIServiceManager
+}
+
+// NewAITranslator 创建AI翻译器
+func NewAITranslator(manager *AIServiceManager) *AITranslator {
+	return &AITranslator{
+		Manager: manager,
+	}
+}
+
+// Translate 翻译单条文本
+func (a *AITranslator) Translate(ctx context.Context, req *translator.TranslationRequest) (*translator.TranslationResult, error) {
+	batch, err := a.BatchTranslate(ctx, &translator.BatchTranslationRequest{
+		Texts:      []string{req.Text},
+		SourceLang: req.SourceLang,
+		TargetLang: req.TargetLang,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := batch.Results[0]
+	result.Usage = batch.Usage
+	return result, nil
+}
+
+// BatchTranslate 一次请求翻译一组文本，返回条数不足时用 [翻译缺失] 补齐
+func (a *AITranslator) BatchTranslate(ctx context.Context, req *translator.BatchTranslationRequest) (*translator.BatchTranslationResult, error) {
+	if len(req.Texts) == 0 {
+		return nil, fmt.Errorf("texts cannot be empty")
+	}
+
+	startTime := time.Now()
+	systemPrompt, userPrompt := BuildTranslationPrompt(req)
+	// 用量随翻译结果返回，由翻译步骤按服务汇总后记录
+	resp, provider, err := a.Manager.chat(ctx, "", &llm.Request{Messages: llm.Messages(systemPrompt, userPrompt)}, false)
+	if err != nil {
+		return nil, fmt.Errorf("AI服务调用失败: %v", err)
+	}
+
+	sentences := strings.Split(resp.Content, sentenceBreak)
+	for i := range sentences {
+		sentences[i] = strings.TrimSpace(sentences[i])
+	}
+	if len(sentences) != len(req.Texts) {
+		a.Manager.logger.Warnf("⚠️  翻译结果数量不匹配: 期望%d句，实际%d句，正在修正...", len(req.Texts), len(sentences))
+		for len(sentences) < len(req.Texts) {
+			sentences = append(sentences, "[翻译缺失]")
+		}
+		sentences = sentences[:len(req.Texts)]
+	}
+
+	modelName := ""
+	if status := a.Manager.GetStatus(provider); status != nil {
+		modelName = status.Model
+	}
+
+	results := make([]*translator.TranslationResult, len(req.Texts))
+	characters := 0
+	for i, text := range req.Texts {
+		results[i] = &translator.TranslationResult{
+			OriginalText:   text,
+			TranslatedText: sentences[i],
+			SourceLang:     req.SourceLang,
+			TargetLang:     req.TargetLang,
+			Provider:       string(provider),
+			Model:          modelName,
+		}
+		characters += len(text)
+	}
+
+	return &translator.BatchTranslationResult{
+		Results:  results,
+		Provider: string(provider),
+		Usage: &translator.Usage{
+			InputTokens:  resp.Usage.PromptTokens,
+			OutputTokens: resp.Usage.CompletionTokens,
+			TotalTokens:  resp.Usage.TotalTokens,
+			Characters:   characters,
+			Duration:     time.Since(startTime).Milliseconds(),
+		},
+	}, nil
+}
+
+// GetSupportedLanguages 获取支持的语言列表
+func (a *AITranslator) GetSupportedLanguages(ctx context.Context) ([]translator.LanguageInfo, error) {
+	var languages []translator.LanguageInfo
+	for _, code := range a.GetInfo().SupportedLanguages {
+		languages = append(languages, translator.LanguageInfo{
+			Code:        code,
+			Name:        LanguageName(code),
+			NativeName:  LanguageName(code),
+			Direction:   "ltr",
+			IsSupported: true,
+		})
+	}
+	return languages, nil
+}
+
+// DetectLanguage 检测语言
+func (a *AITranslator) DetectLanguage(ctx context.Context, text string) (string, float64, error) {
+	if text == "" {
+		return "", 0, fmt.Errorf("text cannot be empty")
+	}
+
+	systemPrompt := "你是一个语言检测专家。请检测给定文本的语言，并返回ISO 639-1语言代码（如'en'、'zh'、'ja'等）。只返回语言代码，不要其他说明。"
+	response, _, err := a.Manager.ChatCompletion(systemPrompt, text)
+	if err != nil {
+		return "", 0, fmt.Errorf("language detection failed: %v", err)
+	}
+
+	code := strings.Trim(strings.ToLower(strings.TrimSpace(response)), "'\".")
+	if len(code) < 2 || len(code) > 5 {
+		code = "auto"
+	}
+	return code, 0.9, nil
+}
+
+// GetInfo 获取翻译器信息
+func (a *AITranslator) GetInfo() *translator.TranslatorInfo {
+	return &translator.TranslatorInfo{
+		Name:               "AI Service Translator",
+		Provider:           AITranslatorProvider,
+		Version:            "1.0.0",
+		MaxTextLength:      32000,
+		SupportedLanguages: []string{"zh", "zh-tw", "en", "ja", "ko", "es", "fr", "de", "ru", "it", "pt", "ar", "hi", "th", "vi"},
+		Features:           []string{"translate", "batch_translate", "detect_language", "context", "glossary"},
+		IsOnline:           true,
+	}
+}
+
+// IsHealthy 健康检查：至少有一个可用的AI服务
+func (a *AITranslator) IsHealthy(ctx context.Context) error {
+	_, err := a.Manager.GetPreferredProvider()
+	return err
+}
+
+// BuildTranslationPrompt 构建字幕翻译提示词：上下文单独列出且不参与翻译，附加要求（术语表）注入系统提示
+// 系统提示词使用 req.Prompts 中的 translation.system 模板
+func BuildTranslationPrompt(req *translator.BatchTranslationRequest) (string, string) {
+	sourceName, targetName := LanguageName(req.SourceLang), LanguageName(req.TargetLang)
+	if req.SourceLang == "" || req.SourceLang == "auto" {
+		sourceName = "原文"
+	}
+
+	systemPrompt := req.Prompts.Render(prompt.TranslationSystem, prompt.Vars{
+		SourceLang:     sourceName,
+		TargetLang:     targetName,
+		SourceLangCode: req.SourceLang,
+		TargetLangCode: req.TargetLang,
+		Glossary:       strings.TrimSpace(req.Instructions),
+		Count:          len(req.Texts),
+		Separator:      sentenceBreak,
+	})
+
+	var sb strings.Builder
+	if len(req.PrevContext) > 0 {
+		sb.WriteString("【前文】\n")
+		sb.WriteString(strings.Join(req.PrevContext, "\n"))
+		sb.WriteString("\n\n")
+	}
+	sb.WriteString("【待翻译】\n")
+	sb.WriteString(strings.Join(req.Texts, "\n"+sentenceBreak+"\n"))
+	if len(req.NextContext) > 0 {
+		sb.WriteString("\n\n【后文】\n")
+		sb.WriteString(strings.Join(req.NextContext, "\n"))
+	}
+
+	return systemPrompt, sb.String()
+}
+
+// LanguageName 语言代码对应的中文名称，未知代码原样返回
+func LanguageName(code string) string {
+	names := map[string]string{
+		"zh":    "中文",
+		"zh-cn": "中文",
+		"zh-tw": "繁体中文",
+		"en":    "英文",
+		"ja":    "日文",
+		"ko":    "韩文",
+		"es":    "西班牙文",
+		"fr":    "法文",
+		"de":    "德文",
+		"ru":    "俄文",
+		"it":    "意大利文",
+		"pt":    "葡萄牙文",
+		"ar":    "阿拉伯文",
+		"hi":    "印地文",
+		"th":    "泰文",
+		"vi":    "越南文",
+	}
+	if name, ok := names[strings.ToLower(code)]; ok {
+		return name
+	}
+	return code
+}
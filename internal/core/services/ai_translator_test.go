@@ -0,0 +1,29 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/difyz9/ytb2bili/pkg/translator"
+)
+
+// TestBuildTranslationPrompt 测试上下文只作参考、附加要求进入系统提示
+func TestBuildTranslationPrompt(t *testing.T) {
+	system, user := BuildTranslationPrompt(&translator.BatchTranslationRequest{
+		Texts:        []string{"Hello", "World"},
+		SourceLang:   "en",
+		TargetLang:   "zh",
+		PrevContext:  []string{"Before"},
+		Instructions: "术语表（必须严格遵守）：\n- Rust → Rust",
+	})
+
+	if !strings.Contains(system, "2 句英文字幕翻译成中文") || !strings.Contains(system, "- Rust → Rust") {
+		t.Fatalf("系统提示不正确: %s", system)
+	}
+	if !strings.HasPrefix(user, "【前文】\nBefore\n\n【待翻译】\nHello\n"+sentenceBreak+"\nWorld") {
+		t.Fatalf("用户提示不正确: %q", user)
+	}
+	if strings.Contains(user, "【后文】") {
+		t.Fatalf("没有后文时不应输出后文: %q", user)
+	}
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+
+	"gorm.io/gorm"
+)
+
+// 用量汇总维度 → 字段
+var usageGroupColumns = map[string]string{
+	"video":    "video_id",
+	"day":      "day",
+	"user":     "user_id",
+	"step":     "step",
+	"provider": "provider",
+	"model":    "model",
+}
+
+// AIUsageService AI调用用量和费用统计服务
+// 由 AIUsageConfig.Enabled 开启，费用按 AIUsageConfig.Prices 估算
+type AIUsageService struct {
+	DB     *gorm.DB
+	Config *types.AppConfig
+}
+
+// NewAIUsageService 创建用量统计服务实例
+func NewAIUsageService(db *gorm.DB, config *types.AppConfig) *AIUsageService {
+	return &AIUsageService{
+		DB:     db,
+		Config: config,
+	}
+}
+
+// Enabled 是否记录用量
+func (s *AIUsageService) Enabled() bool {
+	return s != nil && s.DB != nil && s.Config != nil && s.Config.AIUsageConfig != nil && s.Config.AIUsageConfig.Enabled
+}
+
+// Currency 价格表的货币单位
+func (s *AIUsageService) Currency() string {
+	if s.Config == nil || s.Config.AIUsageConfig == nil || s.Config.AIUsageConfig.Currency == "" {
+		return "USD"
+	}
+	return s.Config.AIUsageConfig.Currency
+}
+
+// Price 查找服务和模型的价格：先精确匹配模型名，再按顺序匹配以 * 结尾的前缀，最后匹配只填写服务的条目
+func (s *AIUsageService) Price(provider, modelName string) (types.ModelPrice, bool) {
+	if s.Config == nil || s.Config.AIUsageConfig == nil {
+		return types.ModelPrice{}, false
+	}
+	prices := s.Config.AIUsageConfig.Prices
+	providerMatches := func(price types.ModelPrice) bool {
+		return price.Provider == "" || strings.EqualFold(price.Provider, provider)
+	}
+
+	for _, price := range prices {
+		if price.Model != "" && !strings.HasSuffix(price.Model, "*") && strings.EqualFold(price.Model, modelName) && providerMatches(price) {
+			return price, true
+		}
+	}
+	for _, price := range prices {
+		prefix, ok := strings.CutSuffix(price.Model, "*")
+		if ok && strings.HasPrefix(strings.ToLower(modelName), strings.ToLower(prefix)) && providerMatches(price) {
+			return price, true
+		}
+	}
+	for _, price := range prices {
+		if price.Model == "" && price.Provider != "" && strings.EqualFold(price.Provider, provider) {
+			return price, true
+		}
+	}
+	return types.ModelPrice{}, false
+}
+
+// EstimateCost 按价格表估算费用，没有匹配的价格时为 0
+func (s *AIUsageService) EstimateCost(usage *model.AIUsage) float64 {
+	price, ok := s.Price(usage.Provider, usage.Model)
+	if !ok {
+		return 0
+	}
+	return (float64(usage.InputTokens)*price.InputPerMillion +
+		float64(usage.OutputTokens)*price.OutputPerMillion +
+		float64(usage.Characters)*price.CharsPerMillion) / 1e6
+}
+
+// Record 保存一条用量记录：补全日期、费用（未提供时按价格表估算）和视频所属用户
+func (s *AIUsageService) Record(usage *model.AIUsage) error {
+	if !s.Enabled() {
+		return nil
+	}
+
+	if usage.TotalTokens == 0 {
+		usage.TotalTokens = usage.InputTokens + usage.OutputTokens
+	}
+	if usage.Day == "" {
+		usage.Day = time.Now().Format("2006-01-02")
+	}
+	if usage.Cost == 0 {
+		usage.Cost = s.EstimateCost(usage)
+	}
+	if usage.Currency == "" {
+		usage.Currency = s.Currency()
+	}
+	if usage.UserID == "" && usage.VideoID != "" {
+		var video model.SavedVideo
+		if err := s.DB.Select("user_id").Where("video_id = ?", usage.VideoID).First(&video).Error; err == nil {
+			usage.UserID = video.UserID
+		}
+	}
+
+	return s.DB.Create(usage).Error
+}
+
+// UsageFilter 用量查询条件（日期为 2006-01-02，包含首尾）
+type UsageFilter struct {
+	VideoID  string `form:"video_id"`
+	UserID   string `form:"user_id"`
+	Provider string `form:"provider"`
+	From     string `form:"from"`
+	To       string `form:"to"`
+}
+
+// UsageSummary 用量汇总
+type UsageSummary struct {
+	Key          string  `gorm:"column:group_key" json:"key"` // 汇总维度的值（视频ID、日期、用户ID等）
+	Records      int64   `json:"records"`                     // 记录数
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	TotalTokens  int64   `json:"total_tokens"`
+	Characters   int64   `json:"characters"`
+	Cost         float64 `json:"cost"`
+	Currency     string  `gorm:"-" json:"currency"`
+}
+
+// query 按条件筛选用量记录
+func (s *AIUsageService) query(filter UsageFilter) *gorm.DB {
+	query := s.DB.Model(&model.AIUsage{})
+	if filter.VideoID != "" {
+		query = query.Where("video_id = ?", filter.VideoID)
+	}
+	if filter.UserID != "" {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Provider != "" {
+		query = query.Where("provider = ?", filter.Provider)
+	}
+	if filter.From != "" {
+		query = query.Where("day >= ?", filter.From)
+	}
+	if filter.To != "" {
+		query = query.Where("day <= ?", filter.To)
+	}
+	return query
+}
+
+const usageSums = "COUNT(*) AS records, COALESCE(SUM(input_tokens), 0) AS input_tokens, COALESCE(SUM(output_tokens), 0) AS output_tokens, " +
+	"COALESCE(SUM(total_tokens), 0) AS total_tokens, COALESCE(SUM(characters), 0) AS characters, COALESCE(SUM(cost), 0) AS cost"
+
+// Summarize 按维度汇总用量（video、day、user、step、provider、model），按天汇总时按日期排序，其余按费用从高到低
+func (s *AIUsageService) Summarize(groupBy string, filter UsageFilter) ([]UsageSummary, error) {
+	column, ok := usageGroupColumns[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("不支持的汇总维度: %s", groupBy)
+	}
+
+	order := "cost DESC"
+	if groupBy == "day" {
+		order = "group_key"
+	}
+
+	var summaries []UsageSummary
+	err := s.query(filter).
+		Select(column + " AS group_key, " + usageSums).
+		Group(column).
+		Order(order).
+		Scan(&summaries).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range summaries {
+		summaries[i].Currency = s.Currency()
+	}
+	return summaries, nil
+}
+
+// Total 符合条件的用量合计
+func (s *AIUsageService) Total(filter UsageFilter) (*UsageSummary, error) {
+	var total UsageSummary
+	if err := s.query(filter).Select(usageSums).Scan(&total).Error; err != nil {
+		return nil, err
+	}
+	total.Currency = s.Currency()
+	return &total, nil
+}
+
+// ListRecords 分页查询用量记录（按时间倒序）
+func (s *AIUsageService) ListRecords(filter UsageFilter, page, pageSize int) ([]model.AIUsage, int64, error) {
+	var total int64
+	if err := s.query(filter).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var records []model.AIUsage
+	err := s.query(filter).
+		Order("id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&records).Error
+	return records, total, err
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+)
+
+func newTestUsageService(t *testing.T) *AIUsageService {
+	db := newTestDB(t, &model.AIUsage{}, &model.SavedVideo{})
+	config := types.NewDefaultConfig()
+	config.AIUsageConfig.Prices = append([]types.ModelPrice{
+		{Provider: "openai_compatible", Model: "gpt-4o-mini", InputPerMillion: 1, OutputPerMillion: 2},
+	}, config.AIUsageConfig.Prices...)
+	return NewAIUsageService(db, config)
+}
+
+func TestAIUsagePrice(t *testing.T) {
+	s := newTestUsageService(t)
+	tests := []struct {
+		provider, model string
+		input           float64
+		chars           float64
+	}{
+		{"openai_compatible", "gpt-4o-mini", 1, 0},          // 精确匹配优先
+		{"deepseek", "gpt-4o-mini", 0.15, 0},                // 服务不匹配时使用前缀
+		{"openai_compatible", "gpt-4o-2024-08-06", 2.50, 0}, // 前缀按顺序匹配
+		{"google", "", 0, 20},                               // 只填写服务的条目
+	}
+	for _, tt := range tests {
+		price, ok := s.Price(tt.provider, tt.model)
+		if !ok || price.InputPerMillion != tt.input || price.CharsPerMillion != tt.chars {
+			t.Errorf("Price(%s, %s) = %+v, %v", tt.provider, tt.model, price, ok)
+		}
+	}
+	if _, ok := s.Price("ollama", "llama3"); ok {
+		t.Error("未配置价格的模型不应匹配")
+	}
+}
+
+// TestAIUsageRecordAndSummarize 测试费用估算、用户归属和按维度汇总
+func TestAIUsageRecordAndSummarize(t *testing.T) {
+	s := newTestUsageService(t)
+	s.DB.Create(&model.SavedVideo{VideoID: "v1", URL: "https://youtu.be/v1", UserID: "42"})
+
+	records := []*model.AIUsage{
+		{Day: "2026-10-01", VideoID: "v1", Step: "翻译字幕", Provider: "deepseek", Model: "deepseek-chat", InputTokens: 1000000, OutputTokens: 500000},
+		{Day: "2026-10-01", VideoID: "v1", Step: "生成元数据", Provider: "gemini", Model: "gemini-1.5-pro-002", InputTokens: 200000, OutputTokens: 10000},
+		{Day: "2026-10-02", VideoID: "v2", Step: "翻译字幕", Provider: "google", Characters: 50000},
+	}
+	for _, record := range records {
+		if err := s.Record(record); err != nil {
+			t.Fatalf("记录用量失败: %v", err)
+		}
+	}
+
+	if records[0].UserID != "42" || records[0].TotalTokens != 1500000 || records[0].Currency != "USD" {
+		t.Errorf("记录未补全: %+v", records[0])
+	}
+	if math.Abs(records[0].Cost-0.82) > 1e-9 || math.Abs(records[2].Cost-1) > 1e-9 {
+		t.Errorf("费用估算不正确: %v %v", records[0].Cost, records[2].Cost)
+	}
+
+	byVideo, err := s.Summarize("video", UsageFilter{})
+	if err != nil || len(byVideo) != 2 || byVideo[0].Key != "v1" || byVideo[0].Records != 2 {
+		t.Fatalf("按视频汇总不正确: %+v %v", byVideo, err)
+	}
+	byDay, _ := s.Summarize("day", UsageFilter{From: "2026-10-02"})
+	if len(byDay) != 1 || byDay[0].Key != "2026-10-02" || byDay[0].Characters != 50000 {
+		t.Errorf("按天汇总不正确: %+v", byDay)
+	}
+	total, _ := s.Total(UsageFilter{UserID: "42"})
+	if total.Records != 2 || total.TotalTokens != 1710000 {
+		t.Errorf("按用户合计不正确: %+v", total)
+	}
+	if _, err := s.Summarize("channel", UsageFilter{}); err == nil {
+		t.Error("不支持的汇总维度应返回错误")
+	}
+}
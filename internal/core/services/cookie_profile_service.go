@@ -0,0 +1,276 @@
+package services
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+// CookieProfileService yt-dlp cookies 配置服务
+// cookies 内容使用 AES-256-GCM 加密存储（密钥由 CookieConfig.EncryptionKey 派生），按来源适配器/频道选择，被限流或年龄限制时轮换到下一个配置
+type CookieProfileService struct {
+	DB     *gorm.DB
+	Config *types.AppConfig
+}
+
+// NewCookieProfileService 创建 cookies 配置服务实例
+func NewCookieProfileService(db *gorm.DB, config *types.AppConfig) *CookieProfileService {
+	return &CookieProfileService{
+		DB:     db,
+		Config: config,
+	}
+}
+
+// ErrCookieKeyMissing 未配置 cookies 加密密钥，不能保存或读取 cookies
+var ErrCookieKeyMissing = errors.New("未配置 CookieConfig.encryption_key，不能保存 cookies")
+
+// encryptionKey 由 CookieConfig.EncryptionKey 派生 32 字节的 AES-256 密钥
+func (s *CookieProfileService) encryptionKey() ([]byte, error) {
+	if s.Config == nil || s.Config.CookieConfig == nil || strings.TrimSpace(s.Config.CookieConfig.EncryptionKey) == "" {
+		return nil, ErrCookieKeyMissing
+	}
+	key := sha256.Sum256([]byte("ytb2bili-cookies:" + s.Config.CookieConfig.EncryptionKey))
+	return key[:], nil
+}
+
+// cooldown 获取限流后的冷却时间
+func (s *CookieProfileService) cooldown() time.Duration {
+	minutes := 60
+	if s.Config != nil && s.Config.CookieConfig != nil && s.Config.CookieConfig.CooldownMinutes > 0 {
+		minutes = s.Config.CookieConfig.CooldownMinutes
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// CreateProfile 解析、校验并加密保存 cookies 文件
+func (s *CookieProfileService) CreateProfile(name, adapter, channel string, content []byte) (*model.CookieProfile, error) {
+	if adapter == "" {
+		adapter = "youtube"
+	}
+
+	profile := &model.CookieProfile{
+		Name:    name,
+		Adapter: adapter,
+		Channel: channel,
+		Enabled: true,
+	}
+	if err := s.applyContent(profile, content); err != nil {
+		return nil, err
+	}
+	if profile.Status == model.CookieStatusExpired {
+		return nil, fmt.Errorf("cookies 已于 %s 过期，请重新导出", profile.ExpiresAt.Format("2006-01-02 15:04:05"))
+	}
+
+	if err := s.DB.Create(profile).Error; err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// ReplaceContent 替换 cookies 内容（重新导出后更新）
+func (s *CookieProfileService) ReplaceContent(profile *model.CookieProfile, content []byte) error {
+	if err := s.applyContent(profile, content); err != nil {
+		return err
+	}
+	profile.FailureCount = 0
+	profile.CooldownUntil = nil
+	profile.LastError = ""
+	return s.DB.Save(profile).Error
+}
+
+// applyContent 解析 cookies 内容并填充加密内容、有效期等字段
+func (s *CookieProfileService) applyContent(profile *model.CookieProfile, content []byte) error {
+	key, err := s.encryptionKey()
+	if err != nil {
+		return err
+	}
+
+	cookies, err := utils.ParseNetscapeCookies(content)
+	if err != nil {
+		return fmt.Errorf("cookies 文件格式无效: %v", err)
+	}
+
+	encrypted, err := utils.AesGcmEncrypt(key, content)
+	if err != nil {
+		return fmt.Errorf("加密 cookies 失败: %v", err)
+	}
+
+	profile.EncryptedContent = encrypted
+	profile.CookieCount = len(cookies)
+	profile.Domains = strings.Join(utils.CookiesDomains(cookies), ",")
+	profile.ExpiresAt = utils.CookiesExpiry(cookies)
+	profile.Status = model.CookieStatusActive
+	if profile.ExpiresAt != nil && profile.ExpiresAt.Before(time.Now()) {
+		profile.Status = model.CookieStatusExpired
+	}
+	return nil
+}
+
+// Decrypt 解密 cookies 内容
+func (s *CookieProfileService) Decrypt(profile *model.CookieProfile) ([]byte, error) {
+	key, err := s.encryptionKey()
+	if err != nil {
+		return nil, err
+	}
+	content, err := utils.AesGcmDecrypt(key, profile.EncryptedContent)
+	if err != nil {
+		return nil, fmt.Errorf("解密 cookies 失败（加密密钥已更换或为旧版本数据，请重新上传）: %v", err)
+	}
+	return content, nil
+}
+
+// ListProfiles 获取所有 cookies 配置
+func (s *CookieProfileService) ListProfiles() ([]model.CookieProfile, error) {
+	var profiles []model.CookieProfile
+	err := s.DB.Order("adapter ASC, channel ASC, id ASC").Find(&profiles).Error
+	return profiles, err
+}
+
+// GetProfile 根据ID获取 cookies 配置
+func (s *CookieProfileService) GetProfile(id uint) (*model.CookieProfile, error) {
+	var profile model.CookieProfile
+	if err := s.DB.Where("id = ?", id).First(&profile).Error; err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// UpdateProfile 更新 cookies 配置
+func (s *CookieProfileService) UpdateProfile(profile *model.CookieProfile) error {
+	return s.DB.Save(profile).Error
+}
+
+// DeleteProfile 删除 cookies 配置
+func (s *CookieProfileService) DeleteProfile(id uint) error {
+	return s.DB.Delete(&model.CookieProfile{}, id).Error
+}
+
+// ValidateProfile 重新校验 cookies 有效期，未过期时清除限流状态
+func (s *CookieProfileService) ValidateProfile(profile *model.CookieProfile) error {
+	content, err := s.Decrypt(profile)
+	if err != nil {
+		return err
+	}
+	if err := s.applyContent(profile, content); err != nil {
+		return err
+	}
+	if profile.Status == model.CookieStatusActive {
+		profile.CooldownUntil = nil
+		profile.FailureCount = 0
+	}
+	return s.DB.Save(profile).Error
+}
+
+// HasProfiles 判断来源适配器是否配置了 cookies
+func (s *CookieProfileService) HasProfiles(adapter string) bool {
+	var count int64
+	s.DB.Model(&model.CookieProfile{}).Where("adapter = ? AND enabled = ?", adapter, true).Count(&count)
+	return count > 0
+}
+
+// AcquireProfile 选择一个可用的 cookies 配置
+// 绑定到频道的配置优先于通用配置；同等条件下选择最久未使用的，实现轮换
+// exclude 为本次任务中已经失败过的配置
+func (s *CookieProfileService) AcquireProfile(adapter, channel string, exclude []uint) (*model.CookieProfile, error) {
+	now := time.Now()
+
+	query := s.DB.Where("adapter = ? AND enabled = ?", adapter, true).
+		Where("status <> ?", model.CookieStatusExpired).
+		Where("cooldown_until IS NULL OR cooldown_until <= ?", now).
+		Where("expires_at IS NULL OR expires_at > ?", now)
+
+	if channel != "" {
+		query = query.Where("channel = '' OR channel IS NULL OR channel = ?", channel)
+	} else {
+		query = query.Where("channel = '' OR channel IS NULL")
+	}
+	if len(exclude) > 0 {
+		query = query.Where("id NOT IN ?", exclude)
+	}
+
+	var profile model.CookieProfile
+	err := query.
+		Order("channel DESC").
+		Order("CASE WHEN last_used_at IS NULL THEN 0 ELSE 1 END").
+		Order("last_used_at ASC").
+		First(&profile).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	profile.LastUsedAt = &now
+	profile.UseCount++
+	if err := s.DB.Model(&profile).Updates(map[string]interface{}{
+		"last_used_at": profile.LastUsedAt,
+		"use_count":    profile.UseCount,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	return &profile, nil
+}
+
+// MarkSuccess 记录 cookies 使用成功
+func (s *CookieProfileService) MarkSuccess(id uint) error {
+	return s.DB.Model(&model.CookieProfile{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":         model.CookieStatusActive,
+		"failure_count":  0,
+		"cooldown_until": nil,
+	}).Error
+}
+
+// MarkFailure 记录 cookies 被限流/年龄限制，在冷却时间内不再使用
+func (s *CookieProfileService) MarkFailure(id uint, status, errMsg string) error {
+	cooldownUntil := time.Now().Add(s.cooldown())
+	return s.DB.Model(&model.CookieProfile{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":         status,
+		"failure_count":  gorm.Expr("failure_count + 1"),
+		"cooldown_until": cooldownUntil,
+		"last_error":     errMsg,
+	}).Error
+}
+
+// ClassifyYtDlpError 根据 yt-dlp 的错误输出判断是否需要轮换 cookies
+// 返回 CookieStatusRateLimited / CookieStatusAgeGated，无关 cookies 的错误返回空字符串
+func ClassifyYtDlpError(output string) string {
+	lower := strings.ToLower(output)
+
+	ageGatedMarkers := []string{
+		"sign in to confirm your age",
+		"age-restricted",
+		"age restricted",
+		"inappropriate for some users",
+	}
+	for _, marker := range ageGatedMarkers {
+		if strings.Contains(lower, marker) {
+			return model.CookieStatusAgeGated
+		}
+	}
+
+	rateLimitedMarkers := []string{
+		"http error 429",
+		"too many requests",
+		"rate-limited",
+		"rate limited",
+		"not a bot",
+		"sign in to confirm",
+	}
+	for _, marker := range rateLimitedMarkers {
+		if strings.Contains(lower, marker) {
+			return model.CookieStatusRateLimited
+		}
+	}
+
+	return ""
+}
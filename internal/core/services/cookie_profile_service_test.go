@@ -0,0 +1,64 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+)
+
+const testCookieContent = "# Netscape HTTP Cookie File\n.youtube.com\tTRUE\t/\tTRUE\t4102444800\tSID\tsecret-value\n"
+
+// TestCookieProfileEncryption 测试 cookies 加密保存：必须配置密钥，相同内容每次密文不同，更换密钥后无法解密
+func TestCookieProfileEncryption(t *testing.T) {
+	s := NewCookieProfileService(nil, &types.AppConfig{CookieConfig: &types.CookieConfig{}})
+	if err := s.applyContent(&model.CookieProfile{}, []byte(testCookieContent)); !errors.Is(err, ErrCookieKeyMissing) {
+		t.Fatalf("未配置密钥时应拒绝保存，得到 %v", err)
+	}
+
+	s.Config.CookieConfig.EncryptionKey = "test-key"
+	first, second := &model.CookieProfile{}, &model.CookieProfile{}
+	if err := s.applyContent(first, []byte(testCookieContent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.applyContent(second, []byte(testCookieContent)); err != nil {
+		t.Fatal(err)
+	}
+	if first.EncryptedContent == second.EncryptedContent {
+		t.Error("每次加密应使用随机 nonce")
+	}
+	if first.CookieCount != 1 || first.Domains != "youtube.com" || first.Status != model.CookieStatusActive {
+		t.Errorf("cookies 信息不正确: %+v", first)
+	}
+
+	content, err := s.Decrypt(first)
+	if err != nil || string(content) != testCookieContent {
+		t.Fatalf("Decrypt = %q, %v", content, err)
+	}
+
+	s.Config.CookieConfig.EncryptionKey = "other-key"
+	if _, err := s.Decrypt(first); err == nil {
+		t.Error("更换密钥后不应能解密")
+	}
+}
+
+// TestClassifyYtDlpError 测试按 yt-dlp 输出判断是否需要轮换 cookies
+func TestClassifyYtDlpError(t *testing.T) {
+	cases := []struct {
+		output string
+		want   string
+	}{
+		{"ERROR: [youtube] abc: Sign in to confirm your age. This video may be inappropriate for some users.", model.CookieStatusAgeGated},
+		{"ERROR: [youtube] abc: This video is age-restricted", model.CookieStatusAgeGated},
+		{"ERROR: unable to download video data: HTTP Error 429: Too Many Requests", model.CookieStatusRateLimited},
+		{"ERROR: [youtube] abc: Sign in to confirm you're not a bot", model.CookieStatusRateLimited},
+		{"ERROR: [youtube] abc: Video unavailable", ""},
+		{"", ""},
+	}
+	for _, c := range cases {
+		if got := ClassifyYtDlpError(c.output); got != c.want {
+			t.Errorf("ClassifyYtDlpError(%q) = %q, 期望 %q", c.output, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+// GlossaryService 翻译术语表服务
+// 术语按频道维护，频道术语覆盖同名的全局术语（ChannelID 为空）
+type GlossaryService struct {
+	DB *gorm.DB
+}
+
+// NewGlossaryService 创建术语表服务实例
+func NewGlossaryService(db *gorm.DB) *GlossaryService {
+	return &GlossaryService{
+		DB: db,
+	}
+}
+
+// ListTerms 获取术语列表，channelID 为 nil 时返回全部术语
+func (s *GlossaryService) ListTerms(channelID *string) ([]model.GlossaryTerm, error) {
+	var terms []model.GlossaryTerm
+	query := s.DB.Order("channel_id ASC, source ASC")
+	if channelID != nil {
+		query = query.Where("channel_id = ?", *channelID)
+	}
+	err := query.Find(&terms).Error
+	return terms, err
+}
+
+// GetTerm 获取术语
+func (s *GlossaryService) GetTerm(id uint) (*model.GlossaryTerm, error) {
+	var term model.GlossaryTerm
+	if err := s.DB.First(&term, id).Error; err != nil {
+		return nil, err
+	}
+	return &term, nil
+}
+
+// CreateTerm 添加术语（同一频道内原文不能重复）
+func (s *GlossaryService) CreateTerm(term *model.GlossaryTerm) error {
+	if err := s.validate(term); err != nil {
+		return err
+	}
+	return s.DB.Create(term).Error
+}
+
+// UpdateTerm 更新术语
+func (s *GlossaryService) UpdateTerm(term *model.GlossaryTerm) error {
+	if err := s.validate(term); err != nil {
+		return err
+	}
+	return s.DB.Save(term).Error
+}
+
+// DeleteTerm 删除术语
+func (s *GlossaryService) DeleteTerm(id uint) error {
+	return s.DB.Delete(&model.GlossaryTerm{}, id).Error
+}
+
+// validate 校验术语内容
+func (s *GlossaryService) validate(term *model.GlossaryTerm) error {
+	term.ChannelID = strings.TrimSpace(term.ChannelID)
+	term.Source = strings.TrimSpace(term.Source)
+	term.Target = strings.TrimSpace(term.Target)
+	if term.Source == "" {
+		return fmt.Errorf("原文术语不能为空")
+	}
+	if !term.DoNotTranslate && term.Target == "" {
+		return fmt.Errorf("请填写固定译法，或设置为不翻译")
+	}
+
+	var count int64
+	if err := s.DB.Model(&model.GlossaryTerm{}).
+		Where("channel_id = ? AND LOWER(source) = ? AND id <> ?", term.ChannelID, strings.ToLower(term.Source), term.ID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("术语 %q 已存在", term.Source)
+	}
+	return nil
+}
+
+// LoadGlossary 加载频道生效的术语表（全局术语 + 频道术语）
+func (s *GlossaryService) LoadGlossary(channelID string) (*Glossary, error) {
+	var terms []model.GlossaryTerm
+	query := s.DB.Where("enabled = ?", true)
+	if channelID != "" {
+		query = query.Where("channel_id = ? OR channel_id = ''", channelID)
+	} else {
+		query = query.Where("channel_id = ''")
+	}
+	if err := query.Find(&terms).Error; err != nil {
+		return nil, err
+	}
+
+	// 频道术语覆盖同名的全局术语
+	merged := make(map[string]model.GlossaryTerm)
+	for _, term := range terms {
+		key := strings.ToLower(term.Source)
+		if existing, ok := merged[key]; ok && existing.ChannelID != "" {
+			continue
+		}
+		merged[key] = term
+	}
+	terms = terms[:0]
+	for _, term := range merged {
+		terms = append(terms, term)
+	}
+	return NewGlossary(terms), nil
+}
+
+// Glossary 已加载的术语表，用于匹配原文、生成提示词和校验译文
+type Glossary struct {
+	Terms    []model.GlossaryTerm
+	patterns []*regexp.Regexp
+}
+
+// NewGlossary 创建术语表，较长的术语优先匹配
+func NewGlossary(terms []model.GlossaryTerm) *Glossary {
+	sorted := append([]model.GlossaryTerm(nil), terms...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if len(sorted[i].Source) != len(sorted[j].Source) {
+			return len(sorted[i].Source) > len(sorted[j].Source)
+		}
+		return sorted[i].Source < sorted[j].Source
+	})
+
+	g := &Glossary{}
+	for _, term := range sorted {
+		if strings.TrimSpace(term.Source) == "" {
+			continue
+		}
+		g.Terms = append(g.Terms, term)
+		g.patterns = append(g.patterns, termPattern(term))
+	}
+	return g
+}
+
+// termPattern 术语的匹配规则：英文术语按整词匹配，默认不区分大小写
+func termPattern(term model.GlossaryTerm) *regexp.Regexp {
+	pattern := regexp.QuoteMeta(term.Source)
+	first, _ := utf8.DecodeRuneInString(term.Source)
+	last, _ := utf8.DecodeLastRuneInString(term.Source)
+	if isWordRune(first) {
+		pattern = `\b` + pattern
+	}
+	if isWordRune(last) {
+		pattern += `\b`
+	}
+	if !term.CaseSensitive {
+		pattern = `(?i)` + pattern
+	}
+	return regexp.MustCompile(pattern)
+}
+
+// isWordRune 是否为 \b 能识别的单词字符
+func isWordRune(r rune) bool {
+	return r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_')
+}
+
+// DoNotTranslateOnly 只保留不翻译的术语：固定译法为中文，翻译成其他语言时不适用
+func (g *Glossary) DoNotTranslateOnly() *Glossary {
+	if g.Empty() {
+		return g
+	}
+	var terms []model.GlossaryTerm
+	for _, term := range g.Terms {
+		if term.DoNotTranslate || term.Target == "" {
+			terms = append(terms, term)
+		}
+	}
+	return NewGlossary(terms)
+}
+
+// Empty 术语表是否为空
+func (g *Glossary) Empty() bool {
+	return g == nil || len(g.Terms) == 0
+}
+
+// Version 术语表版本（内容摘要），术语变化后翻译记忆中的旧译文不再命中；空术语表返回空字符串
+func (g *Glossary) Version() string {
+	if g.Empty() {
+		return ""
+	}
+	lines := make([]string, 0, len(g.Terms))
+	for _, term := range g.Terms {
+		lines = append(lines, fmt.Sprintf("%s\t%s\t%t\t%t", term.Source, term.Expected(), term.DoNotTranslate, term.CaseSensitive))
+	}
+	sort.Strings(lines)
+	return utils.Sha256(strings.Join(lines, "\n"))[:16]
+}
+
+// Match 返回在文本中出现的术语
+func (g *Glossary) Match(texts ...string) []model.GlossaryTerm {
+	if g.Empty() {
+		return nil
+	}
+	var matched []model.GlossaryTerm
+	for i, term := range g.Terms {
+		for _, text := range texts {
+			if g.patterns[i].MatchString(text) {
+				matched = append(matched, term)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// GlossaryPrompt 生成注入提示词的术语说明
+func GlossaryPrompt(terms []model.GlossaryTerm) string {
+	if len(terms) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("术语表（必须严格遵守）：\n")
+	for _, term := range terms {
+		if term.DoNotTranslate || term.Target == "" {
+			fmt.Fprintf(&sb, "- %s：保持原文，不要翻译或音译", term.Source)
+		} else {
+			fmt.Fprintf(&sb, "- %s → %s", term.Source, term.Target)
+		}
+		if term.Note != "" {
+			fmt.Fprintf(&sb, "（%s）", term.Note)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// GlossaryViolation 译文未遵守术语表的条目
+type GlossaryViolation struct {
+	Index       int    `json:"index"`       // 字幕序号（从 0 开始）
+	Term        string `json:"term"`        // 原文术语
+	Expected    string `json:"expected"`    // 应出现的译法
+	Translation string `json:"translation"` // 实际译文
+}
+
+// Check 校验译文是否使用了术语表中的译法，sources 与 translations 按下标一一对应
+func (g *Glossary) Check(sources, translations []string) []GlossaryViolation {
+	if g.Empty() {
+		return nil
+	}
+	var violations []GlossaryViolation
+	for i, source := range sources {
+		if i >= len(translations) {
+			break
+		}
+		lower := strings.ToLower(translations[i])
+		for j, term := range g.Terms {
+			if !g.patterns[j].MatchString(source) {
+				continue
+			}
+			expected := term.Expected()
+			if !strings.Contains(lower, strings.ToLower(expected)) {
+				violations = append(violations, GlossaryViolation{
+					Index:       i,
+					Term:        term.Source,
+					Expected:    expected,
+					Translation: translations[i],
+				})
+			}
+		}
+	}
+	return violations
+}
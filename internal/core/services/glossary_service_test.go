@@ -0,0 +1,74 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+)
+
+func testGlossary() *Glossary {
+	return NewGlossary([]model.GlossaryTerm{
+		{Source: "AI", Target: "人工智能"},
+		{Source: "Linus", DoNotTranslate: true},
+		{Source: "C++", Target: "C++"},
+		{Source: "Go", Target: "Go 语言", CaseSensitive: true},
+	})
+}
+
+// TestGlossaryMatch 测试术语匹配（整词、大小写）
+func TestGlossaryMatch(t *testing.T) {
+	g := testGlossary()
+
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"He said it was fine", nil},
+		{"ai is everywhere", []string{"AI"}},
+		{"Linus wrote C++ once", []string{"Linus", "C++"}},
+		{"let's go", nil},
+		{"Written in Go.", []string{"Go"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, term := range g.Match(tt.text) {
+			got = append(got, term.Source)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("Match(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+// TestGlossaryCheck 测试译文术语校验
+func TestGlossaryCheck(t *testing.T) {
+	g := testGlossary()
+
+	sources := []string{"AI is the future", "Thanks, Linus!", "nothing here"}
+	translations := []string{"人工智能是未来", "谢谢你，莱纳斯！", "这里什么都没有"}
+
+	violations := g.Check(sources, translations)
+	if len(violations) != 1 {
+		t.Fatalf("期望 1 个违规，实际 %d: %+v", len(violations), violations)
+	}
+	if violations[0].Index != 1 || violations[0].Expected != "Linus" {
+		t.Errorf("违规信息不正确: %+v", violations[0])
+	}
+}
+
+// TestGlossaryPrompt 测试术语提示词
+func TestGlossaryPrompt(t *testing.T) {
+	prompt := GlossaryPrompt([]model.GlossaryTerm{
+		{Source: "AI", Target: "人工智能", Note: "缩写"},
+		{Source: "Linus", DoNotTranslate: true},
+	})
+	for _, want := range []string{"AI → 人工智能（缩写）", "Linus：保持原文"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("提示词缺少 %q:\n%s", want, prompt)
+		}
+	}
+	if GlossaryPrompt(nil) != "" {
+		t.Error("空术语表应返回空字符串")
+	}
+}
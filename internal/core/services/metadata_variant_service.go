@@ -0,0 +1,303 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/difyz9/bilibili-go-sdk/bilibili"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+
+	"gorm.io/gorm"
+)
+
+// 标题候选选择策略
+const (
+	VariantStrategyFirst         = "first"          // 使用模型推荐的第一个候选
+	VariantStrategyRandom        = "random"         // 随机选择
+	VariantStrategyBest          = "best"           // 选择该分区平均播放最高的风格
+	VariantStrategyEpsilonGreedy = "epsilon_greedy" // 按概率随机探索，否则同 best
+	VariantStrategyManual        = "manual"         // 手动选择
+)
+
+// minStyleSamples 风格至少有多少个有数据的投稿才参与 best 策略的比较
+const minStyleSamples = 3
+
+// 效果报告维度 → 字段
+var variantReportColumns = map[string]string{
+	"style":  "style",
+	"prompt": "prompt_name, prompt_version, prompt_channel",
+}
+
+// MetadataVariantService 标题/封面文字候选服务
+// 保存元数据生成步骤的全部候选，按策略选择，投稿后按 BVID 获取播放数据，按分区比较标题风格和提示词模板的效果
+type MetadataVariantService struct {
+	DB  *gorm.DB
+	rng *rand.Rand // 为 nil 时使用全局随机数
+}
+
+// NewMetadataVariantService 创建标题候选服务实例
+func NewMetadataVariantService(db *gorm.DB) *MetadataVariantService {
+	return &MetadataVariantService{
+		DB: db,
+	}
+}
+
+// intn 随机整数 [0, n)
+func (s *MetadataVariantService) intn(n int) int {
+	if s.rng != nil {
+		return s.rng.Intn(n)
+	}
+	return rand.Intn(n)
+}
+
+// chance 随机小数 [0, 1)
+func (s *MetadataVariantService) chance() float64 {
+	if s.rng != nil {
+		return s.rng.Float64()
+	}
+	return rand.Float64()
+}
+
+// Choose 按策略选择候选，返回下标；best 和 epsilon_greedy 按分区 tid 的历史数据选择，数据不足时选第一个
+func (s *MetadataVariantService) Choose(variants []TitleVariant, tid int, strategy string, exploreRate float64) (int, error) {
+	if len(variants) < 2 {
+		return 0, nil
+	}
+	switch strategy {
+	case VariantStrategyRandom:
+		return s.intn(len(variants)), nil
+	case VariantStrategyEpsilonGreedy:
+		if s.chance() < exploreRate {
+			return s.intn(len(variants)), nil
+		}
+		return s.best(variants, tid)
+	case VariantStrategyBest:
+		return s.best(variants, tid)
+	default:
+		return 0, nil
+	}
+}
+
+// best 选择分区内平均播放最高的风格对应的候选
+func (s *MetadataVariantService) best(variants []TitleVariant, tid int) (int, error) {
+	report, err := s.Report("style", tid)
+	if err != nil {
+		return 0, err
+	}
+	scores := make(map[string]float64)
+	for _, row := range report {
+		if row.Videos >= minStyleSamples {
+			scores[row.Style] = row.AvgViews
+		}
+	}
+
+	selected, bestScore := 0, -1.0
+	for i, variant := range variants {
+		if score, ok := scores[variant.Style]; ok && score > bestScore {
+			selected, bestScore = i, score
+		}
+	}
+	return selected, nil
+}
+
+// Save 保存视频的全部候选（替换之前生成的候选）
+func (s *MetadataVariantService) Save(videoID string, variants []model.MetadataVariant) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("video_id = ?", videoID).Delete(&model.MetadataVariant{}).Error; err != nil {
+			return err
+		}
+		if len(variants) == 0 {
+			return nil
+		}
+		for i := range variants {
+			variants[i].ID = 0
+			variants[i].VideoID = videoID
+		}
+		return tx.Create(&variants).Error
+	})
+}
+
+// ListByVideo 获取视频的全部候选
+func (s *MetadataVariantService) ListByVideo(videoID string) ([]model.MetadataVariant, error) {
+	var variants []model.MetadataVariant
+	err := s.DB.Where("video_id = ?", videoID).Order("position ASC").Find(&variants).Error
+	return variants, err
+}
+
+// Select 手动选中候选，同时更新视频的AI标题和封面文字；视频已投稿时不能更换
+func (s *MetadataVariantService) Select(id uint) (*model.MetadataVariant, error) {
+	var variant model.MetadataVariant
+	if err := s.DB.First(&variant, id).Error; err != nil {
+		return nil, err
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var uploaded int64
+		if err := tx.Model(&model.MetadataVariant{}).
+			Where("video_id = ? AND bvid <> ''", variant.VideoID).
+			Count(&uploaded).Error; err != nil {
+			return err
+		}
+		if uploaded > 0 {
+			return fmt.Errorf("视频已投稿，不能更换标题")
+		}
+
+		if err := tx.Model(&model.MetadataVariant{}).
+			Where("video_id = ?", variant.VideoID).
+			Update("selected", false).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&variant).Updates(map[string]interface{}{
+			"selected": true,
+			"strategy": VariantStrategyManual,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.SavedVideo{}).
+			Where("video_id = ?", variant.VideoID).
+			Updates(map[string]interface{}{
+				"generated_title":      variant.Title,
+				"generated_cover_text": variant.CoverText,
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	variant.Selected = true
+	variant.Strategy = VariantStrategyManual
+	return &variant, nil
+}
+
+// MarkUploaded 投稿后记录选中候选的 BVID 和分区
+// 投稿标题没有使用选中的候选（如配置为使用原视频标题）时不记录，返回 false，避免干扰效果比较
+func (s *MetadataVariantService) MarkUploaded(videoID, bvid, title string, tid int) (bool, error) {
+	var variant model.MetadataVariant
+	err := s.DB.Where("video_id = ? AND selected = ?", videoID, true).First(&variant).Error
+	if err == gorm.ErrRecordNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if variant.Title == "" || !strings.Contains(title, variant.Title) {
+		return false, nil
+	}
+
+	now := time.Now()
+	err = s.DB.Model(&variant).Updates(map[string]interface{}{
+		"bvid":        bvid,
+		"tid":         tid,
+		"uploaded_at": &now,
+	}).Error
+	return err == nil, err
+}
+
+// RefreshStats 获取已投稿候选的播放数据，只更新投稿后 windowDays 天内的投稿（首次获取不受限制），返回更新的数量
+func (s *MetadataVariantService) RefreshStats(fetch func(bvid string) (*bilibili.VideoStat, error), windowDays int) (int, error) {
+	if windowDays <= 0 {
+		windowDays = 7
+	}
+	cutoff := time.Now().AddDate(0, 0, -windowDays)
+
+	var variants []model.MetadataVariant
+	err := s.DB.Where("selected = ? AND bvid <> ''", true).
+		Where("stats_at IS NULL OR uploaded_at >= ?", cutoff).
+		Find(&variants).Error
+	if err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	var errs []error
+	for _, variant := range variants {
+		stat, err := fetch(variant.BVID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", variant.BVID, err))
+			continue
+		}
+		now := time.Now()
+		if err := s.DB.Model(&variant).Updates(map[string]interface{}{
+			"views":     stat.View,
+			"likes":     stat.Like,
+			"coins":     stat.Coin,
+			"favorites": stat.Favorite,
+			"shares":    stat.Share,
+			"replies":   stat.Reply,
+			"danmaku":   stat.Danmaku,
+			"stats_at":  &now,
+		}).Error; err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", variant.BVID, err))
+			continue
+		}
+		updated++
+	}
+	return updated, errors.Join(errs...)
+}
+
+// VariantPerformance 一个分区内某标题风格或提示词模板版本的投稿效果
+type VariantPerformance struct {
+	Tid            int     `json:"tid"`
+	Style          string  `json:"style,omitempty"`          // 按风格汇总时
+	PromptName     string  `json:"prompt_name,omitempty"`    // 按提示词模板汇总时
+	PromptVersion  int     `json:"prompt_version"`           // 按提示词模板汇总时
+	PromptChannel  string  `json:"prompt_channel,omitempty"` // 按提示词模板汇总时
+	Videos         int64   `json:"videos"`                   // 有数据的投稿数
+	Views          int64   `json:"views"`
+	Likes          int64   `json:"likes"`
+	Coins          int64   `json:"coins"`
+	Favorites      int64   `json:"favorites"`
+	Shares         int64   `json:"shares"`
+	Replies        int64   `json:"replies"`
+	AvgViews       float64 `gorm:"-" json:"avg_views"`       // 平均播放数
+	LikeRate       float64 `gorm:"-" json:"like_rate"`       // 点赞数 / 播放数
+	EngagementRate float64 `gorm:"-" json:"engagement_rate"` // （点赞+投币+收藏+分享+评论）/ 播放数
+}
+
+const variantSums = "COUNT(*) AS videos, COALESCE(SUM(views), 0) AS views, COALESCE(SUM(likes), 0) AS likes, " +
+	"COALESCE(SUM(coins), 0) AS coins, COALESCE(SUM(favorites), 0) AS favorites, COALESCE(SUM(shares), 0) AS shares, " +
+	"COALESCE(SUM(replies), 0) AS replies"
+
+// Report 按分区汇总已投稿候选的效果（style 按标题风格，prompt 按提示词模板版本），tid 为 0 表示所有分区
+// 结果按分区排序，同一分区内按平均播放从高到低排序
+func (s *MetadataVariantService) Report(groupBy string, tid int) ([]VariantPerformance, error) {
+	columns, ok := variantReportColumns[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("不支持的汇总维度: %s", groupBy)
+	}
+
+	query := s.DB.Model(&model.MetadataVariant{}).
+		Where("selected = ? AND bvid <> '' AND stats_at IS NOT NULL", true)
+	if tid > 0 {
+		query = query.Where("tid = ?", tid)
+	}
+
+	var report []VariantPerformance
+	err := query.Select("tid, " + columns + ", " + variantSums).
+		Group("tid, " + columns).
+		Scan(&report).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range report {
+		row := &report[i]
+		if row.Videos > 0 {
+			row.AvgViews = float64(row.Views) / float64(row.Videos)
+		}
+		if row.Views > 0 {
+			row.LikeRate = float64(row.Likes) / float64(row.Views)
+			row.EngagementRate = float64(row.Likes+row.Coins+row.Favorites+row.Shares+row.Replies) / float64(row.Views)
+		}
+	}
+	sort.SliceStable(report, func(i, j int) bool {
+		if report[i].Tid != report[j].Tid {
+			return report[i].Tid < report[j].Tid
+		}
+		return report[i].AvgViews > report[j].AvgViews
+	})
+	return report, nil
+}
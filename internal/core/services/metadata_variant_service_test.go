@@ -0,0 +1,119 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/difyz9/bilibili-go-sdk/bilibili"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+)
+
+func newTestMetadataVariantService(t *testing.T) *MetadataVariantService {
+	db := newTestDB(t, &model.MetadataVariant{}, &model.SavedVideo{})
+	return NewMetadataVariantService(db)
+}
+
+// uploadTestVariant 保存一个已投稿、选中指定风格的视频
+func uploadTestVariant(t *testing.T, s *MetadataVariantService, videoID, style string, tid int) {
+	variants := []model.MetadataVariant{
+		{Position: 0, Title: videoID + " 标题", Style: style, Selected: true},
+		{Position: 1, Title: videoID + " 备选", Style: "直述"},
+	}
+	if err := s.Save(videoID, variants); err != nil {
+		t.Fatalf("保存候选失败: %v", err)
+	}
+	ok, err := s.MarkUploaded(videoID, "BV"+videoID, videoID+" 标题", tid)
+	if err != nil || !ok {
+		t.Fatalf("记录投稿失败: %v, %v", ok, err)
+	}
+}
+
+// TestMetadataVariantStatsAndReport 测试投稿记录、获取播放数据、按分区汇总和 best 策略
+func TestMetadataVariantStatsAndReport(t *testing.T) {
+	s := newTestMetadataVariantService(t)
+
+	views := map[string]int{}
+	for i := 0; i < 3; i++ {
+		suspense := fmt.Sprintf("s%d", i)
+		question := fmt.Sprintf("q%d", i)
+		uploadTestVariant(t, s, suspense, "悬念", 201)
+		uploadTestVariant(t, s, question, "提问", 201)
+		views["BV"+suspense] = 1000
+		views["BV"+question] = 100
+	}
+	uploadTestVariant(t, s, "other", "提问", 122)
+	views["BVother"] = 5000
+
+	// 投稿标题未使用选中的候选时不记录
+	if err := s.Save("orig", []model.MetadataVariant{{Title: "AI标题", Selected: true}}); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := s.MarkUploaded("orig", "BVorig", "原视频标题", 201); ok {
+		t.Error("投稿标题不是选中候选时不应记录")
+	}
+
+	updated, err := s.RefreshStats(func(bvid string) (*bilibili.VideoStat, error) {
+		return &bilibili.VideoStat{BVid: bvid, View: views[bvid], Like: views[bvid] / 10}, nil
+	}, 7)
+	if err != nil || updated != 7 {
+		t.Fatalf("RefreshStats = %d, %v, 期望 7", updated, err)
+	}
+
+	report, err := s.Report("style", 201)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report) != 2 || report[0].Style != "悬念" || report[0].Videos != 3 || report[0].AvgViews != 1000 {
+		t.Fatalf("报告不正确: %+v", report)
+	}
+	if report[0].LikeRate != 0.1 {
+		t.Errorf("点赞率 = %v, 期望 0.1", report[0].LikeRate)
+	}
+
+	variants := []TitleVariant{{Title: "a", Style: "提问"}, {Title: "b", Style: "悬念"}}
+	if index, err := s.Choose(variants, 201, VariantStrategyBest, 0); err != nil || index != 1 {
+		t.Errorf("best 应选择悬念风格，得到 %d, %v", index, err)
+	}
+	// 分区 122 数据不足，选择第一个
+	if index, _ := s.Choose(variants, 122, VariantStrategyBest, 0); index != 0 {
+		t.Errorf("数据不足时应选择第一个，得到 %d", index)
+	}
+	if index, _ := s.Choose(variants, 201, VariantStrategyFirst, 0); index != 0 {
+		t.Errorf("first 应选择第一个，得到 %d", index)
+	}
+
+	// 已投稿的视频不能更换标题
+	list, _ := s.ListByVideo("s0")
+	if _, err := s.Select(list[1].ID); err == nil {
+		t.Error("已投稿的视频不应允许更换标题")
+	}
+}
+
+// TestMetadataVariantSelect 测试手动选择候选同步更新视频标题
+func TestMetadataVariantSelect(t *testing.T) {
+	s := newTestMetadataVariantService(t)
+	if err := s.DB.Create(&model.SavedVideo{VideoID: "v1", URL: "https://youtu.be/v1", GeneratedTitle: "标题一"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save("v1", []model.MetadataVariant{
+		{Position: 0, Title: "标题一", Selected: true},
+		{Position: 1, Title: "标题二", CoverText: "封面二"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	list, _ := s.ListByVideo("v1")
+	if _, err := s.Select(list[1].ID); err != nil {
+		t.Fatalf("选择候选失败: %v", err)
+	}
+
+	list, _ = s.ListByVideo("v1")
+	if list[0].Selected || !list[1].Selected || list[1].Strategy != VariantStrategyManual {
+		t.Errorf("选中状态不正确: %+v", list)
+	}
+	var video model.SavedVideo
+	s.DB.Where("video_id = ?", "v1").First(&video)
+	if video.GeneratedTitle != "标题二" || video.GeneratedCoverText != "封面二" {
+		t.Errorf("视频标题未更新: %q %q", video.GeneratedTitle, video.GeneratedCoverText)
+	}
+}
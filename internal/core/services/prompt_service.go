@@ -0,0 +1,163 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/difyz9/ytb2bili/pkg/prompt"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+
+	"gorm.io/gorm"
+)
+
+// PromptService 提示词模板服务
+// 模板按名称和频道保存版本，频道模板覆盖全局模板（ChannelID 为空），都未启用时使用内置默认模板
+type PromptService struct {
+	DB *gorm.DB
+}
+
+// NewPromptService 创建提示词模板服务实例
+func NewPromptService(db *gorm.DB) *PromptService {
+	return &PromptService{
+		DB: db,
+	}
+}
+
+// List 获取模板版本列表，name、channelID 为 nil 时不过滤
+func (s *PromptService) List(name, channelID *string) ([]model.PromptTemplate, error) {
+	var templates []model.PromptTemplate
+	query := s.DB.Order("name ASC, channel_id ASC, version DESC")
+	if name != nil {
+		query = query.Where("name = ?", *name)
+	}
+	if channelID != nil {
+		query = query.Where("channel_id = ?", *channelID)
+	}
+	err := query.Find(&templates).Error
+	return templates, err
+}
+
+// Get 获取模板版本
+func (s *PromptService) Get(id uint) (*model.PromptTemplate, error) {
+	var t model.PromptTemplate
+	if err := s.DB.First(&t, id).Error; err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Active 获取启用的模板版本，没有时返回 nil
+func (s *PromptService) Active(name, channelID string) (*model.PromptTemplate, error) {
+	var t model.PromptTemplate
+	err := s.DB.Where("name = ? AND channel_id = ? AND active = ?", name, channelID, true).First(&t).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Create 保存模板的新版本，activate 为 true 时同时启用（停用该名称、频道的其他版本）
+func (s *PromptService) Create(t *model.PromptTemplate, activate bool) error {
+	t.Name = strings.TrimSpace(t.Name)
+	t.ChannelID = strings.TrimSpace(t.ChannelID)
+	if _, ok := prompt.Lookup(t.Name); !ok {
+		return fmt.Errorf("未知的模板名称: %s", t.Name)
+	}
+	if strings.TrimSpace(t.Content) == "" {
+		return fmt.Errorf("模板内容不能为空")
+	}
+	if _, err := prompt.Parse(t.Name, t.Content); err != nil {
+		return err
+	}
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		var latest int
+		if err := tx.Model(&model.PromptTemplate{}).Unscoped().
+			Where("name = ? AND channel_id = ?", t.Name, t.ChannelID).
+			Select("COALESCE(MAX(version), 0)").Scan(&latest).Error; err != nil {
+			return err
+		}
+		t.ID = 0
+		t.Version = latest + 1
+		t.Active = activate
+		if activate {
+			if err := deactivatePrompts(tx, t.Name, t.ChannelID); err != nil {
+				return err
+			}
+		}
+		return tx.Create(t).Error
+	})
+}
+
+// Activate 启用模板版本（停用同一名称、频道的其他版本），可用于回滚到旧版本
+func (s *PromptService) Activate(id uint) (*model.PromptTemplate, error) {
+	t, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := deactivatePrompts(tx, t.Name, t.ChannelID); err != nil {
+			return err
+		}
+		return tx.Model(t).Update("active", true).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	t.Active = true
+	return t, nil
+}
+
+// Deactivate 停用模板，恢复为全局模板或内置默认模板
+func (s *PromptService) Deactivate(name, channelID string) error {
+	return deactivatePrompts(s.DB, name, channelID)
+}
+
+// deactivatePrompts 停用某名称、频道的所有版本
+func deactivatePrompts(db *gorm.DB, name, channelID string) error {
+	return db.Model(&model.PromptTemplate{}).
+		Where("name = ? AND channel_id = ? AND active = ?", name, channelID, true).
+		Update("active", false).Error
+}
+
+// Load 加载频道生效的模板集合（频道模板 > 全局模板 > 内置默认）
+// 加载失败或模板无法解析时使用内置默认模板，s 为 nil 时返回只包含内置默认模板的集合
+func (s *PromptService) Load(channelID string, vars prompt.Vars) *prompt.Set {
+	if s == nil || s.DB == nil {
+		return prompt.NewSet(vars)
+	}
+
+	var rows []model.PromptTemplate
+	query := s.DB.Where("active = ?", true)
+	if channelID != "" {
+		query = query.Where("channel_id = ? OR channel_id = ''", channelID)
+	} else {
+		query = query.Where("channel_id = ''")
+	}
+	if err := query.Find(&rows).Error; err != nil {
+		return prompt.NewSet(vars)
+	}
+
+	selected := make(map[string]model.PromptTemplate)
+	for _, row := range rows {
+		if existing, ok := selected[row.Name]; ok && existing.ChannelID != "" {
+			continue
+		}
+		selected[row.Name] = row
+	}
+
+	var templates []*prompt.Template
+	for _, row := range selected {
+		t, err := prompt.Parse(row.Name, row.Content)
+		if err != nil {
+			continue
+		}
+		t.Version = row.Version
+		t.ChannelID = row.ChannelID
+		templates = append(templates, t)
+	}
+	return prompt.NewSet(vars, templates...)
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/difyz9/ytb2bili/pkg/prompt"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+)
+
+func newTestPromptService(t *testing.T) *PromptService {
+	db := newTestDB(t, &model.PromptTemplate{})
+	return NewPromptService(db)
+}
+
+// TestPromptServiceVersions 测试版本递增、启用切换和非法模板
+func TestPromptServiceVersions(t *testing.T) {
+	s := newTestPromptService(t)
+
+	if err := s.Create(&model.PromptTemplate{Name: "unknown", Content: "x"}, true); err == nil {
+		t.Error("未知模板名称应返回错误")
+	}
+	if err := s.Create(&model.PromptTemplate{Name: prompt.MetadataUser, Content: "{{.NoSuchVar}}"}, true); err == nil {
+		t.Error("引用不存在的变量应返回错误")
+	}
+
+	v1 := &model.PromptTemplate{Name: prompt.MetadataUser, Content: "v1 {{.Subtitles}}"}
+	v2 := &model.PromptTemplate{Name: prompt.MetadataUser, Content: "v2 {{.Subtitles}}"}
+	if err := s.Create(v1, true); err != nil {
+		t.Fatalf("创建失败: %v", err)
+	}
+	if err := s.Create(v2, true); err != nil {
+		t.Fatalf("创建失败: %v", err)
+	}
+	if v1.Version != 1 || v2.Version != 2 {
+		t.Errorf("版本号 = %d, %d", v1.Version, v2.Version)
+	}
+
+	active, _ := s.Active(prompt.MetadataUser, "")
+	if active == nil || active.Version != 2 {
+		t.Fatalf("应启用 v2: %+v", active)
+	}
+	if _, err := s.Activate(v1.ID); err != nil {
+		t.Fatalf("回滚失败: %v", err)
+	}
+	active, _ = s.Active(prompt.MetadataUser, "")
+	if active == nil || active.Version != 1 {
+		t.Errorf("回滚后应启用 v1: %+v", active)
+	}
+}
+
+// TestPromptServiceLoad 测试频道模板覆盖全局模板，并记录使用的版本
+func TestPromptServiceLoad(t *testing.T) {
+	s := newTestPromptService(t)
+	s.Create(&model.PromptTemplate{Name: prompt.MetadataUser, Content: "global {{.Subtitles}}"}, true)
+	s.Create(&model.PromptTemplate{Name: prompt.MetadataUser, ChannelID: "UC1", Content: "{{.Channel}}: {{.Subtitles}}"}, true)
+
+	set := s.Load("UC1", prompt.Vars{Channel: "Tech"})
+	if got := set.Render(prompt.MetadataUser, prompt.Vars{Subtitles: "hello"}); got != "Tech: hello" {
+		t.Errorf("频道模板渲染 = %q", got)
+	}
+	if got := s.Load("UC2", prompt.Vars{}).Render(prompt.MetadataUser, prompt.Vars{Subtitles: "hello"}); got != "global hello" {
+		t.Errorf("其他频道应使用全局模板, 得到 %q", got)
+	}
+
+	set.Render(prompt.MetadataSystem, prompt.Vars{})
+	used := set.Used()
+	if used[prompt.MetadataUser] != (prompt.Ref{Version: 1, ChannelID: "UC1"}) || used[prompt.MetadataSystem].Version != 0 {
+		t.Errorf("记录的模板版本不正确: %+v", used)
+	}
+
+	s.Deactivate(prompt.MetadataUser, "")
+	s.Deactivate(prompt.MetadataUser, "UC1")
+	got := s.Load("UC1", prompt.Vars{}).Render(prompt.MetadataUser, prompt.Vars{Subtitles: "hello"})
+	if !strings.HasPrefix(got, "请根据以下字幕内容生成视频元数据") {
+		t.Errorf("停用后应使用内置默认模板, 得到 %q", got)
+	}
+}
@@ -17,10 +17,11 @@ func NewSavedVideoService(db *gorm.DB) *SavedVideoService {
 	}
 }
 
-// GetPendingVideos 获取待处理的视频列表（状态为 001 且 subtitles 不为空）
+// GetPendingVideos 获取待处理的视频列表（状态为 001）
+// 没有提交字幕的视频也会被处理，字幕由"生成字幕"步骤从 YouTube 获取
 func (s *SavedVideoService) GetPendingVideos(limit int) ([]model.SavedVideo, error) {
 	var videos []model.SavedVideo
-	err := s.DB.Where("status = ? AND subtitles IS NOT NULL AND subtitles != ''", "001").
+	err := s.DB.Where("status = ?", "001").
 		Order("created_at ASC").
 		Limit(limit).
 		Find(&videos).Error
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
+
+	"gorm.io/gorm"
+)
+
+// 视频状态：准备阶段完成后等待人工审核字幕（SubtitleConfig.RequireReview 开启时）
+const VideoStatusAwaitingReview = "100"
+
+var (
+	// ErrInvalidCuePatch 字幕条目修改不合法
+	ErrInvalidCuePatch = errors.New("invalid cue patch")
+	// ErrNotAwaitingReview 视频不处于等待字幕审核状态
+	ErrNotAwaitingReview = errors.New("video is not awaiting subtitle review")
+)
+
+// AlignedCue 原文与译文对齐的字幕条目
+type AlignedCue struct {
+	Index       int    `json:"index"`       // 译文条目下标（从 0 开始）
+	StartMs     int64  `json:"start_ms"`    // 开始时间（毫秒）
+	EndMs       int64  `json:"end_ms"`      // 结束时间（毫秒）
+	Start       string `json:"start"`       // 开始时间（SRT 格式）
+	End         string `json:"end"`         // 结束时间（SRT 格式）
+	Original    string `json:"original"`    // 时间上对应的原文
+	Translation string `json:"translation"` // 译文
+}
+
+// CuePatch 字幕条目修改，未提供的字段保持不变
+type CuePatch struct {
+	Text    *string `json:"text,omitempty"`     // 译文
+	StartMs *int64  `json:"start_ms,omitempty"` // 开始时间（毫秒）
+	EndMs   *int64  `json:"end_ms,omitempty"`   // 结束时间（毫秒）
+	Note    string  `json:"note,omitempty"`     // 备注（记录到编辑历史）
+}
+
+// SubtitleEditService 字幕人工编辑和审核服务
+type SubtitleEditService struct {
+	DB     *gorm.DB
+	Config *types.AppConfig
+}
+
+// NewSubtitleEditService 创建字幕编辑服务实例
+func NewSubtitleEditService(db *gorm.DB, config *types.AppConfig) *SubtitleEditService {
+	return &SubtitleEditService{
+		DB:     db,
+		Config: config,
+	}
+}
+
+// RequireReview 准备阶段完成后是否需要人工审核字幕
+func (s *SubtitleEditService) RequireReview() bool {
+	return s.Config != nil && s.Config.SubtitleConfig != nil && s.Config.SubtitleConfig.RequireReview
+}
+
+// AlignCues 按时间轴将原文对齐到译文条目：条数相同时一一对应，否则取时间重叠的原文
+func AlignCues(original, translated []subtitle.Cue) []AlignedCue {
+	aligned := make([]AlignedCue, len(translated))
+	j := 0
+	for i, cue := range translated {
+		aligned[i] = AlignedCue{
+			Index:       i,
+			StartMs:     cue.Start.Milliseconds(),
+			EndMs:       cue.End.Milliseconds(),
+			Start:       subtitle.FormatSRTTime(cue.Start),
+			End:         subtitle.FormatSRTTime(cue.End),
+			Translation: cue.Text,
+		}
+
+		if len(original) == len(translated) {
+			aligned[i].Original = original[i].Text
+			continue
+		}
+
+		// 原文已按时间排序，跳过完全在当前条目之前的部分
+		for j < len(original) && original[j].End <= cue.Start {
+			j++
+		}
+		var texts []string
+		for k := j; k < len(original) && original[k].Start < cue.End; k++ {
+			texts = append(texts, original[k].Text)
+		}
+		aligned[i].Original = strings.Join(texts, " ")
+	}
+	return aligned
+}
+
+// PatchCue 修改译文字幕文件中的一条字幕（文本和时间轴）并记录编辑历史，返回修改后的全部字幕
+func (s *SubtitleEditService) PatchCue(videoID, language, path string, index int, patch *CuePatch, editor string) ([]subtitle.Cue, *model.SubtitleEdit, error) {
+	cues, err := subtitle.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if index < 0 || index >= len(cues) {
+		return nil, nil, fmt.Errorf("%w: 字幕条目 %d 不存在（共 %d 条）", ErrInvalidCuePatch, index, len(cues))
+	}
+
+	cue := cues[index]
+	edit := &model.SubtitleEdit{
+		VideoID:    videoID,
+		Language:   language,
+		Action:     model.SubtitleEditActionEdit,
+		CueIndex:   index,
+		OldText:    cue.Text,
+		OldStartMs: cue.Start.Milliseconds(),
+		OldEndMs:   cue.End.Milliseconds(),
+		Editor:     editor,
+		Note:       patch.Note,
+	}
+
+	if patch.Text != nil {
+		text := subtitle.NormalizeText(*patch.Text)
+		if text == "" {
+			return nil, nil, fmt.Errorf("%w: 字幕文本不能为空", ErrInvalidCuePatch)
+		}
+		cue.Text = text
+	}
+	if patch.StartMs != nil {
+		cue.Start = time.Duration(*patch.StartMs) * time.Millisecond
+	}
+	if patch.EndMs != nil {
+		cue.End = time.Duration(*patch.EndMs) * time.Millisecond
+	}
+
+	// 时间轴必须有效，且不能越过相邻条目的开始时间（保持字幕顺序）
+	if cue.Start < 0 || cue.End <= cue.Start {
+		return nil, nil, fmt.Errorf("%w: 结束时间必须晚于开始时间", ErrInvalidCuePatch)
+	}
+	if index > 0 && cue.Start < cues[index-1].Start {
+		return nil, nil, fmt.Errorf("%w: 开始时间早于上一条字幕", ErrInvalidCuePatch)
+	}
+	if index < len(cues)-1 && cue.Start > cues[index+1].Start {
+		return nil, nil, fmt.Errorf("%w: 开始时间晚于下一条字幕", ErrInvalidCuePatch)
+	}
+
+	cues[index] = cue
+	edit.NewText = cue.Text
+	edit.NewStartMs = cue.Start.Milliseconds()
+	edit.NewEndMs = cue.End.Milliseconds()
+	if edit.NewText == edit.OldText && edit.NewStartMs == edit.OldStartMs && edit.NewEndMs == edit.OldEndMs {
+		return cues, nil, nil
+	}
+
+	if err := subtitle.WriteFile(path, cues); err != nil {
+		return nil, nil, err
+	}
+	if err := s.DB.Create(edit).Error; err != nil {
+		return nil, nil, err
+	}
+	return cues, edit, nil
+}
+
+// ListEdits 获取视频的编辑历史（最新的在前），language 为空时返回所有语言和审核记录
+func (s *SubtitleEditService) ListEdits(videoID, language string) ([]model.SubtitleEdit, error) {
+	var edits []model.SubtitleEdit
+	query := s.DB.Where("video_id = ?", videoID)
+	if language != "" {
+		query = query.Where("language = ? OR action = ?", language, model.SubtitleEditActionApprove)
+	}
+	err := query.Order("id DESC").Find(&edits).Error
+	return edits, err
+}
+
+// ListAwaitingReview 获取等待字幕审核的视频
+func (s *SubtitleEditService) ListAwaitingReview() ([]model.SavedVideo, error) {
+	var videos []model.SavedVideo
+	err := s.DB.Where("status = ?", VideoStatusAwaitingReview).Order("updated_at ASC").Find(&videos).Error
+	return videos, err
+}
+
+// Approve 审核通过：视频进入准备就绪状态（200），由上传调度器上传
+func (s *SubtitleEditService) Approve(video *model.SavedVideo, editor, note string) error {
+	if video.Status != VideoStatusAwaitingReview {
+		return ErrNotAwaitingReview
+	}
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.SavedVideo{}).
+			Where("id = ? AND status = ?", video.ID, VideoStatusAwaitingReview).
+			Update("status", "200")
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotAwaitingReview
+		}
+
+		video.Status = "200"
+		return tx.Create(&model.SubtitleEdit{
+			VideoID: video.VideoID,
+			Action:  model.SubtitleEditActionApprove,
+			Editor:  editor,
+			Note:    note,
+		}).Error
+	})
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
+)
+
+// TestSubtitleEditPatchAndApprove 测试字幕条目修改、编辑历史和审核状态流转
+func TestSubtitleEditPatchAndApprove(t *testing.T) {
+	db := newTestDB(t, &model.SavedVideo{}, &model.SubtitleEdit{})
+	s := NewSubtitleEditService(db, &types.AppConfig{SubtitleConfig: &types.SubtitleConfig{RequireReview: true}})
+
+	path := filepath.Join(t.TempDir(), "zh.srt")
+	cues := []subtitle.Cue{
+		{Start: 0, End: 2 * time.Second, Text: "你好"},
+		{Start: 2 * time.Second, End: 4 * time.Second, Text: "错误译文"},
+		{Start: 4 * time.Second, End: 6 * time.Second, Text: "谢谢"},
+	}
+	if err := subtitle.WriteFile(path, cues); err != nil {
+		t.Fatalf("写入字幕失败: %v", err)
+	}
+
+	text := "早上好"
+	endMs := int64(3500)
+	updated, edit, err := s.PatchCue("vid", "zh-Hans", path, 1, &CuePatch{Text: &text, EndMs: &endMs}, "alice")
+	if err != nil || edit == nil {
+		t.Fatalf("修改字幕失败: %v", err)
+	}
+	if updated[1].Text != "早上好" || updated[1].End != 3500*time.Millisecond {
+		t.Errorf("修改结果不正确: %+v", updated[1])
+	}
+	if saved, _ := subtitle.ReadFile(path); saved[1].Text != "早上好" {
+		t.Errorf("修改未写入字幕文件: %+v", saved)
+	}
+
+	// 开始时间越过下一条字幕时拒绝修改
+	startMs := int64(4500)
+	if _, _, err := s.PatchCue("vid", "zh-Hans", path, 1, &CuePatch{StartMs: &startMs}, "alice"); !errors.Is(err, ErrInvalidCuePatch) {
+		t.Errorf("应拒绝越过下一条字幕的修改: %v", err)
+	}
+
+	video := &model.SavedVideo{VideoID: "vid", Status: "200"}
+	db.Create(video)
+	if err := s.Approve(video, "bob", ""); !errors.Is(err, ErrNotAwaitingReview) {
+		t.Errorf("非待审核状态不应审核通过: %v", err)
+	}
+
+	db.Model(video).Update("status", VideoStatusAwaitingReview)
+	video.Status = VideoStatusAwaitingReview
+	if err := s.Approve(video, "bob", "ok"); err != nil {
+		t.Fatalf("审核失败: %v", err)
+	}
+	var stored model.SavedVideo
+	db.First(&stored, video.ID)
+	if stored.Status != "200" {
+		t.Errorf("审核后状态应为 200: %s", stored.Status)
+	}
+
+	edits, err := s.ListEdits("vid", "zh-Hans")
+	if err != nil || len(edits) != 2 || edits[0].Action != model.SubtitleEditActionApprove || edits[1].OldText != "错误译文" {
+		t.Errorf("编辑历史不正确: %+v (%v)", edits, err)
+	}
+}
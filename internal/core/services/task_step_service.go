@@ -35,8 +35,9 @@ func (s *TaskStepService) InitTaskSteps(videoID string) error {
 		{"生成字幕", 2, true},
 		{"翻译字幕", 3, true},
 		{"生成元数据", 4, true},
-		{"上传到Bilibili", 5, true},
-		// {"上传字幕到Bilibili", 6, true},
+		{"生成章节", 5, true},
+		{"上传到Bilibili", 6, true},
+		// {"上传字幕到Bilibili", 7, true},
 	}
 
 	// 检查是否已经初始化过
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/translator"
+	"github.com/difyz9/ytb2bili/pkg/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// translationMemoryBatchSize 单次查询/写入的条目数
+const translationMemoryBatchSize = 200
+
+// 本次运行的命中统计（所有任务共享）
+var (
+	translationMemoryHits   atomic.Int64
+	translationMemoryMisses atomic.Int64
+)
+
+// TranslationMemoryService 翻译记忆服务
+// 由 TranslatorConfig.EnableCache 开启，CacheExpiry 为条目有效期（秒，0 表示永不过期）
+type TranslationMemoryService struct {
+	DB     *gorm.DB
+	Config *types.AppConfig
+}
+
+// NewTranslationMemoryService 创建翻译记忆服务实例
+func NewTranslationMemoryService(db *gorm.DB, config *types.AppConfig) *TranslationMemoryService {
+	return &TranslationMemoryService{
+		DB:     db,
+		Config: config,
+	}
+}
+
+// Enabled 是否启用翻译记忆
+func (s *TranslationMemoryService) Enabled() bool {
+	return s != nil && s.DB != nil && s.Config != nil && s.Config.TranslatorConfig != nil && s.Config.TranslatorConfig.EnableCache
+}
+
+// expiry 条目有效期，0 表示永不过期
+func (s *TranslationMemoryService) expiry() time.Duration {
+	if s.Config == nil || s.Config.TranslatorConfig == nil || s.Config.TranslatorConfig.CacheExpiry <= 0 {
+		return 0
+	}
+	return time.Duration(s.Config.TranslatorConfig.CacheExpiry) * time.Second
+}
+
+// memoryHash 原文哈希（去掉首尾空白）
+func memoryHash(text string) string {
+	return utils.Sha256(strings.TrimSpace(text))
+}
+
+// Lookup 查询译文，返回命中条目的 下标 → 译文
+func (s *TranslationMemoryService) Lookup(key translator.MemoryKey, texts []string) map[int]string {
+	found := make(map[int]string)
+	if !s.Enabled() || len(texts) == 0 {
+		return found
+	}
+
+	// 同一句原文可能出现多次
+	indexes := make(map[string][]int)
+	var hashes []string
+	for i, text := range texts {
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		hash := memoryHash(text)
+		if _, ok := indexes[hash]; !ok {
+			hashes = append(hashes, hash)
+		}
+		indexes[hash] = append(indexes[hash], i)
+	}
+
+	var hitIDs []uint
+	expiry := s.expiry()
+	for start := 0; start < len(hashes); start += translationMemoryBatchSize {
+		end := start + translationMemoryBatchSize
+		if end > len(hashes) {
+			end = len(hashes)
+		}
+
+		var entries []model.TranslationMemory
+		if err := s.keyScope(key).Where("source_hash IN ?", hashes[start:end]).Find(&entries).Error; err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if expiry > 0 && time.Since(entry.UpdatedAt) > expiry {
+				continue
+			}
+			for _, i := range indexes[entry.SourceHash] {
+				found[i] = entry.TranslatedText
+			}
+			hitIDs = append(hitIDs, entry.ID)
+		}
+	}
+
+	if len(hitIDs) > 0 {
+		s.DB.Model(&model.TranslationMemory{}).Where("id IN ?", hitIDs).Updates(map[string]interface{}{
+			"hit_count":   gorm.Expr("hit_count + ?", 1),
+			"last_hit_at": time.Now(),
+		})
+	}
+
+	total := 0
+	for _, list := range indexes {
+		total += len(list)
+	}
+	translationMemoryHits.Add(int64(len(found)))
+	translationMemoryMisses.Add(int64(total - len(found)))
+	return found
+}
+
+// Store 保存译文（已存在时覆盖），空译文和翻译缺失的条目不保存
+func (s *TranslationMemoryService) Store(key translator.MemoryKey, sources, translations []string) {
+	if !s.Enabled() {
+		return
+	}
+
+	seen := make(map[string]bool)
+	var entries []model.TranslationMemory
+	for i, source := range sources {
+		if i >= len(translations) {
+			break
+		}
+		source = strings.TrimSpace(source)
+		translation := strings.TrimSpace(translations[i])
+		if source == "" || translation == "" || translation == "[翻译缺失]" {
+			continue
+		}
+		hash := memoryHash(source)
+		if seen[hash] {
+			continue
+		}
+		seen[hash] = true
+
+		entries = append(entries, model.TranslationMemory{
+			SourceHash:      hash,
+			SourceLang:      key.SourceLang,
+			TargetLang:      key.TargetLang,
+			Provider:        key.Provider,
+			Model:           key.Model,
+			GlossaryVersion: key.GlossaryVersion,
+			SourceText:      source,
+			TranslatedText:  translation,
+		})
+	}
+	if len(entries) == 0 {
+		return
+	}
+
+	s.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{
+			{Name: "source_hash"}, {Name: "source_lang"}, {Name: "target_lang"},
+			{Name: "provider"}, {Name: "model"}, {Name: "glossary_version"},
+		},
+		DoUpdates: clause.AssignmentColumns([]string{"source_text", "translated_text", "updated_at", "deleted_at"}),
+	}).CreateInBatches(entries, translationMemoryBatchSize)
+}
+
+// keyScope 按查找条件筛选
+func (s *TranslationMemoryService) keyScope(key translator.MemoryKey) *gorm.DB {
+	return s.DB.Where("source_lang = ? AND target_lang = ? AND provider = ? AND model = ? AND glossary_version = ?",
+		key.SourceLang, key.TargetLang, key.Provider, key.Model, key.GlossaryVersion)
+}
+
+// TranslationMemoryStats 翻译记忆统计
+type TranslationMemoryStats struct {
+	Enabled   bool    `json:"enabled"`    // 是否启用
+	Entries   int64   `json:"entries"`    // 记忆条目数
+	TotalHits int64   `json:"total_hits"` // 累计命中次数
+	Hits      int64   `json:"hits"`       // 本次运行命中句数
+	Misses    int64   `json:"misses"`     // 本次运行未命中句数
+	HitRate   float64 `json:"hit_rate"`   // 本次运行命中率
+}
+
+// Stats 获取翻译记忆统计
+func (s *TranslationMemoryService) Stats() (*TranslationMemoryStats, error) {
+	stats := &TranslationMemoryStats{
+		Enabled: s.Enabled(),
+		Hits:    translationMemoryHits.Load(),
+		Misses:  translationMemoryMisses.Load(),
+	}
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(total)
+	}
+
+	if err := s.DB.Model(&model.TranslationMemory{}).Count(&stats.Entries).Error; err != nil {
+		return nil, err
+	}
+	if err := s.DB.Model(&model.TranslationMemory{}).Select("COALESCE(SUM(hit_count), 0)").Scan(&stats.TotalHits).Error; err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// PurgeExpired 删除过期的条目，返回删除数量
+func (s *TranslationMemoryService) PurgeExpired() (int64, error) {
+	expiry := s.expiry()
+	if expiry <= 0 {
+		return 0, nil
+	}
+	result := s.DB.Unscoped().Where("updated_at < ?", time.Now().Add(-expiry)).Delete(&model.TranslationMemory{})
+	return result.RowsAffected, result.Error
+}
+
+// Clear 清空翻译记忆，返回删除数量
+func (s *TranslationMemoryService) Clear() (int64, error) {
+	result := s.DB.Unscoped().Where("1 = 1").Delete(&model.TranslationMemory{})
+	return result.RowsAffected, result.Error
+}
+
+// HitRate 计算命中率
+func HitRate(hits, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(hits) / float64(total)
+}
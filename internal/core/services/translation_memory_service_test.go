@@ -0,0 +1,62 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/translator"
+)
+
+func newTestMemoryService(t *testing.T) *TranslationMemoryService {
+	db := newTestDB(t, &model.TranslationMemory{})
+	return NewTranslationMemoryService(db, &types.AppConfig{
+		TranslatorConfig: &types.TranslatorConfig{EnableCache: true},
+	})
+}
+
+// TestTranslationMemory 测试翻译记忆的写入、命中和键隔离
+func TestTranslationMemory(t *testing.T) {
+	s := newTestMemoryService(t)
+	key := translator.MemoryKey{SourceLang: "en", TargetLang: "zh", Provider: "deepseek", Model: "deepseek-chat"}
+
+	s.Store(key, []string{"Hello", "Subscribe!", "broken", "Hello"}, []string{"你好", "订阅！", "[翻译缺失]", "你好"})
+
+	found := s.Lookup(key, []string{"Subscribe!", "new line", " Hello ", "broken"})
+	if len(found) != 2 || found[0] != "订阅！" || found[2] != "你好" {
+		t.Fatalf("命中结果不正确: %v", found)
+	}
+
+	// 模型或术语表版本不同时不命中
+	other := key
+	other.GlossaryVersion = "v2"
+	if found := s.Lookup(other, []string{"Hello"}); len(found) != 0 {
+		t.Fatalf("不同术语表版本不应命中: %v", found)
+	}
+
+	// 重新翻译后覆盖旧译文
+	s.Store(key, []string{"Hello"}, []string{"哈喽"})
+	if found := s.Lookup(key, []string{"Hello"}); found[0] != "哈喽" {
+		t.Fatalf("译文未更新: %v", found)
+	}
+
+	stats, err := s.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Entries != 2 || stats.TotalHits != 3 {
+		t.Fatalf("统计不正确: %+v", stats)
+	}
+}
+
+// TestTranslationMemoryDisabled 测试未启用时不读写
+func TestTranslationMemoryDisabled(t *testing.T) {
+	s := newTestMemoryService(t)
+	s.Config.TranslatorConfig.EnableCache = false
+	key := translator.MemoryKey{SourceLang: "en", TargetLang: "zh", Provider: "deepseek"}
+
+	s.Store(key, []string{"Hello"}, []string{"你好"})
+	if found := s.Lookup(key, []string{"Hello"}); len(found) != 0 {
+		t.Fatalf("未启用时不应命中: %v", found)
+	}
+}
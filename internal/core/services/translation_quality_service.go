@@ -0,0 +1,415 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/prompt"
+	"github.com/difyz9/ytb2bili/pkg/translator"
+)
+
+// QualityScores 单条字幕各维度的得分（0-10，越高越好）
+type QualityScores struct {
+	Fluency  float64 `json:"fluency"`  // 流畅度
+	Omission float64 `json:"omission"` // 完整性（10 表示没有漏译）
+	Accuracy float64 `json:"accuracy"` // 准确性（10 表示没有误译）
+	Length   float64 `json:"length"`   // 长度是否适合字幕阅读
+}
+
+// Average 综合得分
+func (s QualityScores) Average() float64 {
+	return roundScore((s.Fluency + s.Omission + s.Accuracy + s.Length) / 4)
+}
+
+// CueQuality 单条字幕的评审结果
+type CueQuality struct {
+	Index            int           `json:"index"`                       // 字幕下标（从 0 开始）
+	Source           string        `json:"source"`                      // 原文
+	Translation      string        `json:"translation"`                 // 评审时的译文
+	Scores           QualityScores `json:"scores"`                      // 各维度得分
+	Score            float64       `json:"score"`                       // 综合得分
+	LengthRatio      float64       `json:"length_ratio"`                // 译文与原文的字符数之比
+	Issue            string        `json:"issue,omitempty"`             // 评审意见
+	Retranslation    string        `json:"retranslation,omitempty"`     // 重译结果
+	RetranslateScore float64       `json:"retranslate_score,omitempty"` // 重译结果的综合得分
+	Accepted         bool          `json:"accepted"`                    // 是否采用了重译结果
+}
+
+// QualityReport 译文质量报告，保存在翻译步骤结果中
+type QualityReport struct {
+	Language            string        `json:"language"`                       // 目标语言
+	JudgeProvider       string        `json:"judge_provider"`                 // 评审使用的AI服务
+	JudgeModel          string        `json:"judge_model,omitempty"`          // 评审模型
+	RetranslateProvider string        `json:"retranslate_provider,omitempty"` // 重译使用的AI服务
+	RetranslateModel    string        `json:"retranslate_model,omitempty"`    // 重译模型
+	MinScore            float64       `json:"min_score"`                      // 低于该分数的条目重译
+	TotalCues           int           `json:"total_cues"`                     // 字幕总条数
+	ScoredCues          int           `json:"scored_cues"`                    // 评审成功的条数
+	AverageScore        float64       `json:"average_score"`                  // 重译前的平均综合得分
+	FinalScore          float64       `json:"final_score"`                    // 采用重译结果后的平均综合得分
+	Dimensions          QualityScores `json:"dimensions"`                     // 重译前各维度的平均分
+	Flagged             int           `json:"flagged"`                        // 低分条数
+	Retranslated        int           `json:"retranslated"`                   // 重译条数
+	Improved            int           `json:"improved"`                       // 采用重译结果的条数
+	Cues                []CueQuality  `json:"cues"`                           // 低分条目明细
+	Duration            int64         `json:"duration"`                       // 耗时（毫秒）
+}
+
+// QualityReviewRequest 质量评审请求
+type QualityReviewRequest struct {
+	Sources      []string               // 原文
+	Translations []string               // 译文，与原文一一对应
+	SourceLang   string                 // 原文语言
+	TargetLang   string                 // 译文语言（翻译器语言代码，如 zh、ja）
+	ContextSize  int                    // 重译时前后附带的上下文句数
+	Instructions func(index int) string // 重译某条字幕时的附加要求（如本句涉及的术语），可为空
+	Prompts      *prompt.Set            // 选用的提示词模板，为空时使用内置默认
+}
+
+// TranslationQualityService 译文质量评审服务
+// 评审模型逐条打分（流畅度、漏译、误译、长度），低分条目带上下文交给更强的模型重译，重译结果再次评审后择优采用
+type TranslationQualityService struct {
+	Manager *AIServiceManager
+	Config  *types.AppConfig
+
+	// chat 执行对话补全，默认为 Manager.ChatCompletionWithModel
+	chat func(provider AIProvider, model, systemPrompt, userPrompt string) (string, AIProvider, error)
+}
+
+// NewTranslationQualityService 创建译文质量评审服务
+func NewTranslationQualityService(manager *AIServiceManager, config *types.AppConfig) *TranslationQualityService {
+	return &TranslationQualityService{
+		Manager: manager,
+		Config:  config,
+		chat:    manager.ChatCompletionWithModel,
+	}
+}
+
+// Enabled 是否启用质量评审
+func (s *TranslationQualityService) Enabled() bool {
+	return s != nil && s.Config != nil && s.Config.TranslationQualityConfig != nil && s.Config.TranslationQualityConfig.Enabled
+}
+
+// settings 评审配置（补齐默认值）
+func (s *TranslationQualityService) settings() types.TranslationQualityConfig {
+	cfg := types.TranslationQualityConfig{}
+	if s.Config != nil && s.Config.TranslationQualityConfig != nil {
+		cfg = *s.Config.TranslationQualityConfig
+	}
+	if cfg.MinScore <= 0 {
+		cfg.MinScore = 6
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 20
+	}
+	return cfg
+}
+
+// Review 评审译文并重译低分条目，返回报告和更新后的译文；评审全部失败时返回错误
+func (s *TranslationQualityService) Review(req *QualityReviewRequest) (*QualityReport, []string, error) {
+	if len(req.Sources) != len(req.Translations) {
+		return nil, nil, fmt.Errorf("原文 %d 条，译文 %d 条，数量不一致", len(req.Sources), len(req.Translations))
+	}
+
+	startTime := time.Now()
+	cfg := s.settings()
+	report := &QualityReport{
+		Language:  req.TargetLang,
+		MinScore:  cfg.MinScore,
+		TotalCues: len(req.Sources),
+		Cues:      []CueQuality{},
+	}
+	translations := append([]string(nil), req.Translations...)
+
+	// 1. 分批评审全部译文
+	indices := make([]int, len(req.Sources))
+	for i := range indices {
+		indices[i] = i
+	}
+	scores, provider, err := s.score(cfg, req, indices, translations)
+	if err != nil {
+		return nil, nil, err
+	}
+	report.JudgeProvider, report.JudgeModel = string(provider), s.modelName(provider, cfg.JudgeModel)
+	report.ScoredCues = len(scores)
+	if len(scores) == 0 {
+		report.Duration = time.Since(startTime).Milliseconds()
+		return report, translations, nil
+	}
+
+	var flagged []CueQuality
+	for _, cue := range scores {
+		report.AverageScore += cue.Score
+		report.Dimensions.Fluency += cue.Scores.Fluency
+		report.Dimensions.Omission += cue.Scores.Omission
+		report.Dimensions.Accuracy += cue.Scores.Accuracy
+		report.Dimensions.Length += cue.Scores.Length
+		if cue.Score < cfg.MinScore {
+			flagged = append(flagged, cue)
+		}
+	}
+	n := float64(len(scores))
+	report.Dimensions = QualityScores{
+		Fluency:  roundScore(report.Dimensions.Fluency / n),
+		Omission: roundScore(report.Dimensions.Omission / n),
+		Accuracy: roundScore(report.Dimensions.Accuracy / n),
+		Length:   roundScore(report.Dimensions.Length / n),
+	}
+	report.Flagged = len(flagged)
+
+	// 2. 低分条目从最低分开始重译，并再次评审，得分更高时采用
+	sort.Slice(flagged, func(i, j int) bool { return flagged[i].Score < flagged[j].Score })
+	if cfg.MaxRetranslate > 0 && len(flagged) > cfg.MaxRetranslate {
+		flagged = flagged[:cfg.MaxRetranslate]
+	}
+	var retranslated []int
+	candidates := make([]string, len(translations))
+	copy(candidates, translations)
+	for i := range flagged {
+		cue := &flagged[i]
+		text, provider, err := s.retranslate(cfg, req, cue)
+		if err != nil {
+			s.Manager.logger.Warnf("⚠️  第 %d 句重译失败: %v", cue.Index+1, err)
+			continue
+		}
+		report.RetranslateProvider, report.RetranslateModel = string(provider), s.modelName(provider, cfg.RetranslateModel)
+		cue.Retranslation = text
+		candidates[cue.Index] = text
+		retranslated = append(retranslated, cue.Index)
+	}
+	report.Retranslated = len(retranslated)
+
+	if len(retranslated) > 0 {
+		rescored, _, err := s.score(cfg, req, retranslated, candidates)
+		if err != nil {
+			s.Manager.logger.Warnf("⚠️  重译结果评审失败: %v", err)
+		}
+		for i := range flagged {
+			cue := &flagged[i]
+			if cue.Retranslation == "" {
+				continue
+			}
+			if again, ok := rescored[cue.Index]; ok {
+				cue.RetranslateScore = again.Score
+				cue.Accepted = again.Score > cue.Score
+				if cue.Accepted {
+					scores[cue.Index] = again
+				}
+			} else if err != nil {
+				// 无法再次评审时仍采用更强模型的结果
+				cue.Accepted = true
+			}
+			if cue.Accepted {
+				translations[cue.Index] = cue.Retranslation
+				report.Improved++
+			}
+		}
+	}
+
+	for _, cue := range scores {
+		report.FinalScore += cue.Score
+	}
+	report.AverageScore = roundScore(report.AverageScore / n)
+	report.FinalScore = roundScore(report.FinalScore / n)
+	report.Cues = append(report.Cues, flagged...)
+	report.Duration = time.Since(startTime).Milliseconds()
+	return report, translations, nil
+}
+
+// modelName 实际使用的模型：配置了模型时为配置值，否则为该服务配置的模型
+func (s *TranslationQualityService) modelName(provider AIProvider, model string) string {
+	if model != "" {
+		return model
+	}
+	if status := s.Manager.GetStatus(provider); status != nil {
+		return status.Model
+	}
+	return ""
+}
+
+// score 分批评审 indices 对应的译文，返回 下标 → 评审结果；单批失败时跳过，全部失败时返回错误
+func (s *TranslationQualityService) score(cfg types.TranslationQualityConfig, req *QualityReviewRequest, indices []int, translations []string) (map[int]CueQuality, AIProvider, error) {
+	results := make(map[int]CueQuality)
+	var provider AIProvider
+	var lastErr error
+	for start := 0; start < len(indices); start += cfg.BatchSize {
+		end := start + cfg.BatchSize
+		if end > len(indices) {
+			end = len(indices)
+		}
+
+		batch, used, err := s.scoreBatch(cfg, req, indices[start:end], translations)
+		if err != nil {
+			s.Manager.logger.Warnf("⚠️  第 %d-%d 句质量评审失败: %v", indices[start]+1, indices[end-1]+1, err)
+			lastErr = err
+			continue
+		}
+		provider = used
+		for index, cue := range batch {
+			results[index] = cue
+		}
+	}
+
+	if len(results) == 0 && lastErr != nil {
+		return nil, "", lastErr
+	}
+	return results, provider, nil
+}
+
+// judgeItem 发送给评审模型的字幕条目
+type judgeItem struct {
+	ID          int    `json:"id"`
+	Source      string `json:"source"`
+	Translation string `json:"translation"`
+}
+
+// judgeResponse 评审模型返回的得分
+type judgeResponse struct {
+	Scores []struct {
+		ID       int     `json:"id"`
+		Fluency  float64 `json:"fluency"`
+		Omission float64 `json:"omission"`
+		Accuracy float64 `json:"accuracy"`
+		Length   float64 `json:"length"`
+		Issue    string  `json:"issue"`
+	} `json:"scores"`
+}
+
+// scoreBatch 评审一批译文
+func (s *TranslationQualityService) scoreBatch(cfg types.TranslationQualityConfig, req *QualityReviewRequest, indices []int, translations []string) (map[int]CueQuality, AIProvider, error) {
+	items := make([]judgeItem, len(indices))
+	for i, index := range indices {
+		items[i] = judgeItem{ID: index + 1, Source: req.Sources[index], Translation: translations[index]}
+	}
+	payload, err := json.Marshal(items)
+	if err != nil {
+		return nil, "", err
+	}
+
+	systemPrompt := req.Prompts.Render(prompt.ValidationJudge, prompt.Vars{
+		SourceLang:     LanguageName(req.SourceLang),
+		TargetLang:     LanguageName(req.TargetLang),
+		SourceLangCode: req.SourceLang,
+		TargetLangCode: req.TargetLang,
+	})
+	response, provider, err := s.chat(AIProvider(cfg.JudgeProvider), cfg.JudgeModel, systemPrompt, string(payload))
+	if err != nil {
+		return nil, "", err
+	}
+
+	var parsed judgeResponse
+	if err := json.Unmarshal([]byte(extractJSONObject(response)), &parsed); err != nil {
+		return nil, "", fmt.Errorf("解析评审结果失败: %v", err)
+	}
+
+	wanted := make(map[int]bool, len(indices))
+	for _, index := range indices {
+		wanted[index] = true
+	}
+	results := make(map[int]CueQuality)
+	for _, item := range parsed.Scores {
+		index := item.ID - 1
+		if !wanted[index] {
+			continue
+		}
+		scores := QualityScores{
+			Fluency:  clampScore(item.Fluency),
+			Omission: clampScore(item.Omission),
+			Accuracy: clampScore(item.Accuracy),
+			Length:   clampScore(item.Length),
+		}
+		results[index] = CueQuality{
+			Index:       index,
+			Source:      req.Sources[index],
+			Translation: translations[index],
+			Scores:      scores,
+			Score:       scores.Average(),
+			LengthRatio: lengthRatio(req.Sources[index], translations[index]),
+			Issue:       strings.TrimSpace(item.Issue),
+		}
+	}
+	if len(results) == 0 {
+		return nil, "", fmt.Errorf("评审结果为空")
+	}
+	return results, provider, nil
+}
+
+// retranslate 带上下文和评审意见重译一条低分字幕
+func (s *TranslationQualityService) retranslate(cfg types.TranslationQualityConfig, req *QualityReviewRequest, cue *CueQuality) (string, AIProvider, error) {
+	i := cue.Index
+	prevStart := i - req.ContextSize
+	if prevStart < 0 {
+		prevStart = 0
+	}
+	nextEnd := i + 1 + req.ContextSize
+	if nextEnd > len(req.Sources) {
+		nextEnd = len(req.Sources)
+	}
+
+	var instructions []string
+	if req.Instructions != nil {
+		if extra := strings.TrimSpace(req.Instructions(i)); extra != "" {
+			instructions = append(instructions, extra)
+		}
+	}
+	feedback := fmt.Sprintf("上一版译文：%s\n评审得分：%.1f/10", cue.Translation, cue.Score)
+	if cue.Issue != "" {
+		feedback += "\n评审意见：" + cue.Issue
+	}
+	instructions = append(instructions, feedback+"\n请避免上述问题，给出更准确流畅的译文。")
+
+	systemPrompt, userPrompt := BuildTranslationPrompt(&translator.BatchTranslationRequest{
+		Texts:        []string{req.Sources[i]},
+		SourceLang:   req.SourceLang,
+		TargetLang:   req.TargetLang,
+		PrevContext:  req.Sources[prevStart:i],
+		NextContext:  req.Sources[i+1 : nextEnd],
+		Instructions: strings.Join(instructions, "\n\n"),
+		Prompts:      req.Prompts,
+	})
+	response, provider, err := s.chat(AIProvider(cfg.RetranslateProvider), cfg.RetranslateModel, systemPrompt, userPrompt)
+	if err != nil {
+		return "", "", err
+	}
+
+	text := strings.TrimSpace(strings.Split(response, sentenceBreak)[0])
+	if text == "" || text == "[翻译缺失]" {
+		return "", "", fmt.Errorf("重译结果为空")
+	}
+	return text, provider, nil
+}
+
+// extractJSONObject 截取模型回复中的 JSON 对象（去掉 ```json 代码块等多余内容）
+func extractJSONObject(content string) string {
+	start := strings.Index(content, "{")
+	end := strings.LastIndex(content, "}")
+	if start < 0 || end < start {
+		return content
+	}
+	return content[start : end+1]
+}
+
+// lengthRatio 译文与原文的字符数之比
+func lengthRatio(source, translation string) float64 {
+	sourceLen := utf8.RuneCountInString(strings.TrimSpace(source))
+	if sourceLen == 0 {
+		return 0
+	}
+	return math.Round(float64(utf8.RuneCountInString(strings.TrimSpace(translation)))/float64(sourceLen)*100) / 100
+}
+
+// clampScore 将得分限制在 0-10
+func clampScore(score float64) float64 {
+	return math.Max(0, math.Min(10, score))
+}
+
+// roundScore 得分保留一位小数
+func roundScore(score float64) float64 {
+	return math.Round(score*10) / 10
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"go.uber.org/zap"
+)
+
+// TestTranslationQualityReview 测试评审打分、低分重译和择优采用
+func TestTranslationQualityReview(t *testing.T) {
+	config := &types.AppConfig{
+		TranslationQualityConfig: &types.TranslationQualityConfig{Enabled: true, MinScore: 6, BatchSize: 2, RetranslateModel: "strong-model"},
+	}
+	s := NewTranslationQualityService(NewAIServiceManager(config, zap.NewNop().Sugar()), config)
+
+	var retranslateModels []string
+	s.chat = func(provider AIProvider, model, systemPrompt, userPrompt string) (string, AIProvider, error) {
+		if strings.Contains(systemPrompt, "质量评审") {
+			var items []judgeItem
+			if err := json.Unmarshal([]byte(userPrompt), &items); err != nil {
+				t.Fatalf("评审输入不是 JSON: %v", err)
+			}
+			var scores []string
+			for _, item := range items {
+				score := "9"
+				issue := ""
+				if strings.Contains(item.Translation, "错") {
+					score, issue = "2", "误译"
+				}
+				scores = append(scores, `{"id":`+strconv.Itoa(item.ID)+`,"fluency":`+score+`,"omission":`+score+`,"accuracy":`+score+`,"length":`+score+`,"issue":"`+issue+`"}`)
+			}
+			return "```json\n{\"scores\":[" + strings.Join(scores, ",") + "]}\n```", AIProviderDeepSeek, nil
+		}
+
+		retranslateModels = append(retranslateModels, model)
+		if !strings.Contains(systemPrompt, "评审意见：误译") {
+			t.Errorf("重译提示词缺少评审意见: %s", systemPrompt)
+		}
+		return "正确的译文", AIProviderDeepSeek, nil
+	}
+
+	report, translations, err := s.Review(&QualityReviewRequest{
+		Sources:      []string{"Hello", "Good morning", "Thank you"},
+		Translations: []string{"你好", "错误译文", "谢谢"},
+		SourceLang:   "en",
+		TargetLang:   "zh",
+		ContextSize:  1,
+	})
+	if err != nil {
+		t.Fatalf("评审失败: %v", err)
+	}
+
+	if report.ScoredCues != 3 || report.Flagged != 1 || report.Retranslated != 1 || report.Improved != 1 {
+		t.Fatalf("报告统计不正确: %+v", report)
+	}
+	if translations[1] != "正确的译文" || translations[0] != "你好" {
+		t.Errorf("译文未按重译结果更新: %v", translations)
+	}
+	if len(retranslateModels) != 1 || retranslateModels[0] != "strong-model" {
+		t.Errorf("重译应使用配置的模型: %v", retranslateModels)
+	}
+	if report.AverageScore >= report.FinalScore {
+		t.Errorf("重译后得分应提高: %.1f -> %.1f", report.AverageScore, report.FinalScore)
+	}
+	if len(report.Cues) != 1 || report.Cues[0].Index != 1 || !report.Cues[0].Accepted {
+		t.Errorf("低分条目明细不正确: %+v", report.Cues)
+	}
+}
@@ -0,0 +1,332 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/llm"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
+)
+
+// chapterTranscriptWindows 字幕超出字数限制时，依次尝试按这些时长合并相邻字幕
+var chapterTranscriptWindows = []time.Duration{0, 15 * time.Second, 30 * time.Second, time.Minute, 2 * time.Minute, 5 * time.Minute}
+
+// ChapterRules 分段章节校验规则
+type ChapterRules struct {
+	MaxChapters    int
+	MinLength      time.Duration // 每个章节最短时长
+	MaxTitleLength int
+	BannedWords    []string
+	RepairAttempts int // 校验不通过时重新生成的次数
+}
+
+// NewChapterRules 从配置创建章节校验规则，违禁词和重新生成次数使用 MetadataConfig 的设置
+func NewChapterRules(config *types.ChapterConfig, metadata *types.MetadataConfig) ChapterRules {
+	rules := ChapterRules{
+		MaxChapters:    12,
+		MinLength:      time.Minute,
+		MaxTitleLength: 20,
+		RepairAttempts: 2,
+	}
+	if config != nil {
+		if config.MaxChapters > 0 {
+			rules.MaxChapters = config.MaxChapters
+		}
+		if config.MinChapterSeconds > 0 {
+			rules.MinLength = time.Duration(config.MinChapterSeconds) * time.Second
+		}
+		if config.MaxTitleLength > 0 {
+			rules.MaxTitleLength = config.MaxTitleLength
+		}
+	}
+	if metadata != nil {
+		rules.BannedWords = metadata.BannedWords
+		rules.RepairAttempts = metadata.RepairAttempts
+	}
+	return rules
+}
+
+// Schema 章节列表的 JSON Schema，用于服务支持的结构化输出
+func (r ChapterRules) Schema() *llm.Schema {
+	return &llm.Schema{
+		Type: "object",
+		Properties: map[string]*llm.Schema{
+			"chapters": {
+				Type:        "array",
+				Description: fmt.Sprintf("章节列表，最多 %d 个，按时间顺序，第一个章节从 00:00 开始", r.MaxChapters),
+				Items: &llm.Schema{
+					Type: "object",
+					Properties: map[string]*llm.Schema{
+						"time":  {Type: "string", Description: "开始时间，mm:ss 或 h:mm:ss，使用字幕中出现的时间"},
+						"title": {Type: "string", Description: fmt.Sprintf("章节标题，不超过 %d 字", r.MaxTitleLength)},
+					},
+					Required: []string{"time", "title"},
+				},
+			},
+		},
+		Required: []string{"chapters"},
+	}
+}
+
+// ParseChapters 解析模型返回的章节 JSON（容忍代码块包裹）
+func ParseChapters(content string) ([]MetadataChapter, error) {
+	var raw struct {
+		Chapters []MetadataChapter `json:"chapters"`
+	}
+	if err := json.Unmarshal([]byte(extractJSONObject(llm.TrimCodeFence(content))), &raw); err != nil {
+		return nil, fmt.Errorf("解析章节JSON失败: %v", err)
+	}
+
+	chapters := raw.Chapters[:0]
+	for _, chapter := range raw.Chapters {
+		chapter.Time = strings.TrimSpace(chapter.Time)
+		chapter.Title = strings.TrimSpace(chapter.Title)
+		if chapter.Title != "" {
+			chapters = append(chapters, chapter)
+		}
+	}
+	return chapters, nil
+}
+
+// FormatChapterTime 简介中的章节时间，不足1小时为 mm:ss，否则为 h:mm:ss
+func FormatChapterTime(d time.Duration) string {
+	seconds := int(d / time.Second)
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%02d:%02d", seconds/60, seconds%60)
+}
+
+// ParseChapterTime 解析 mm:ss 或 h:mm:ss 格式的章节时间
+func ParseChapterTime(value string) (time.Duration, bool) {
+	if !chapterTimePattern.MatchString(value) {
+		return 0, false
+	}
+	var seconds int
+	for _, part := range strings.Split(value, ":") {
+		n, _ := strconv.Atoi(part)
+		seconds = seconds*60 + n
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+// ChapterTranscript 发送给模型的带时间字幕，每行一个“[mm:ss] 文本”
+// 超过 maxChars 字时逐步扩大时间窗口合并相邻字幕，仍超出时截断每行的文本
+func ChapterTranscript(cues []subtitle.Cue, maxChars int) string {
+	var lines []string
+	for _, window := range chapterTranscriptWindows {
+		lines = transcriptLines(cues, window)
+		if maxChars <= 0 || utf8.RuneCountInString(strings.Join(lines, "\n")) <= maxChars {
+			return strings.Join(lines, "\n")
+		}
+	}
+
+	perLine := maxChars/len(lines) - 1
+	for i, line := range lines {
+		lines[i] = truncateRunes(line, max(perLine, 12))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// transcriptLines 按时间窗口合并字幕，每个窗口一行，以窗口内第一条字幕的开始时间标记
+func transcriptLines(cues []subtitle.Cue, window time.Duration) []string {
+	var lines []string
+	var start time.Duration
+	var texts []string
+	flush := func() {
+		if len(texts) > 0 {
+			lines = append(lines, "["+FormatChapterTime(start)+"] "+strings.Join(texts, " "))
+			texts = nil
+		}
+	}
+	for _, cue := range cues {
+		text := strings.Join(strings.Fields(cue.Text), " ")
+		if text == "" {
+			continue
+		}
+		if len(texts) > 0 && cue.Start-start >= window {
+			flush()
+		}
+		if len(texts) == 0 {
+			start = cue.Start
+		}
+		texts = append(texts, text)
+	}
+	flush()
+	return lines
+}
+
+// Validate 按规则校验章节，duration 为视频时长，返回需要修正的问题（为空表示通过）
+func (r ChapterRules) Validate(chapters []MetadataChapter, duration time.Duration) []string {
+	if len(chapters) == 0 {
+		return []string{"没有章节"}
+	}
+
+	var issues []string
+	if len(chapters) > r.MaxChapters {
+		issues = append(issues, fmt.Sprintf("章节有 %d 个，超过 %d 个的限制", len(chapters), r.MaxChapters))
+	}
+	previous := time.Duration(-1)
+	for i, chapter := range chapters {
+		if n := utf8.RuneCountInString(chapter.Title); n > r.MaxTitleLength {
+			issues = append(issues, fmt.Sprintf("章节标题“%s”有 %d 字，超过 %d 字的限制", chapter.Title, n, r.MaxTitleLength))
+		}
+		start, ok := ParseChapterTime(chapter.Time)
+		if !ok {
+			issues = append(issues, fmt.Sprintf("章节“%s”的时间“%s”不是 mm:ss 或 h:mm:ss 格式", chapter.Title, chapter.Time))
+			continue
+		}
+		switch {
+		case i == 0 && start != 0:
+			issues = append(issues, "第一个章节必须从 00:00 开始")
+		case duration > 0 && start >= duration:
+			issues = append(issues, fmt.Sprintf("章节“%s”的时间 %s 超出视频时长 %s", chapter.Title, chapter.Time, FormatChapterTime(duration)))
+		case previous >= 0 && start <= previous:
+			issues = append(issues, fmt.Sprintf("章节“%s”的时间 %s 不在上一个章节之后", chapter.Title, chapter.Time))
+		case previous >= 0 && start-previous < r.MinLength:
+			issues = append(issues, fmt.Sprintf("章节“%s”距上一个章节只有 %d 秒，每个章节至少 %d 秒", chapter.Title, int((start-previous)/time.Second), int(r.MinLength/time.Second)))
+		}
+		previous = start
+	}
+	for _, word := range r.BannedWords {
+		for _, chapter := range chapters {
+			if word = strings.TrimSpace(word); word != "" && strings.Contains(chapter.Title, word) {
+				issues = append(issues, fmt.Sprintf("章节标题“%s”包含违禁词“%s”", chapter.Title, word))
+			}
+		}
+	}
+	return issues
+}
+
+// Sanitize 自动修正仍未通过校验的章节：删除违禁词、格式错误和超出时长的章节，按时间排序，
+// 开始时间对齐到最近的字幕，第一个章节改为 00:00，合并过短的章节，截断过长的标题和多余的章节
+func (r ChapterRules) Sanitize(chapters []MetadataChapter, cues []subtitle.Cue, duration time.Duration) []MetadataChapter {
+	type point struct {
+		start time.Duration
+		title string
+	}
+	var points []point
+	for _, chapter := range chapters {
+		title := chapter.Title
+		for _, word := range r.BannedWords {
+			if word = strings.TrimSpace(word); word != "" {
+				title = strings.ReplaceAll(title, word, "")
+			}
+		}
+		title = strings.TrimSpace(title)
+		start, ok := ParseChapterTime(chapter.Time)
+		if !ok || title == "" || (duration > 0 && start >= duration) {
+			continue
+		}
+		points = append(points, point{start: snapToCue(start, cues), title: truncateRunes(title, r.MaxTitleLength)})
+	}
+	sort.SliceStable(points, func(i, j int) bool { return points[i].start < points[j].start })
+
+	result := make([]MetadataChapter, 0, len(points))
+	last := time.Duration(-1)
+	for i, p := range points {
+		if i == 0 {
+			p.start = 0
+		} else if p.start-last < r.MinLength {
+			continue
+		}
+		if len(result) >= r.MaxChapters {
+			break
+		}
+		result = append(result, MetadataChapter{Time: FormatChapterTime(p.start), Title: p.title})
+		last = p.start
+	}
+	return result
+}
+
+// snapToCue 章节开始时间对齐到开始时间最接近的字幕
+func snapToCue(start time.Duration, cues []subtitle.Cue) time.Duration {
+	snapped, best := start, time.Duration(-1)
+	for _, cue := range cues {
+		diff := cue.Start - start
+		if diff < 0 {
+			diff = -diff
+		}
+		if best < 0 || diff < best {
+			snapped, best = cue.Start.Truncate(time.Second), diff
+		}
+	}
+	return snapped
+}
+
+// GenerateChapters 请求模型按 JSON Schema 根据带时间的字幕划分章节并按规则校验；
+// 不通过时把问题（由 repairPrompt 生成提示词）反馈给模型重新生成，次数用完后自动修正剩余问题
+func GenerateChapters(ctx context.Context, chat func(ctx context.Context, req *llm.Request) (*llm.Response, error), req *llm.Request, rules ChapterRules, cues []subtitle.Cue, repairPrompt func(issues []string) string) ([]MetadataChapter, *MetadataValidation, error) {
+	request := *req
+	request.JSON = true
+	request.Schema = rules.Schema()
+	duration := SubtitleDuration(cues)
+
+	var chapters []MetadataChapter
+	parsed := false
+	validation, issues, err := generateValidated(ctx, chat, request, rules.RepairAttempts, func(content string) []string {
+		result, err := ParseChapters(content)
+		if err != nil {
+			return []string{"输出不是合法的 JSON：" + err.Error()}
+		}
+		chapters, parsed = result, true
+		return rules.Validate(chapters, duration)
+	}, repairPrompt)
+	if err != nil && !parsed {
+		return nil, validation, err
+	}
+	if len(issues) == 0 {
+		return chapters, validation, nil
+	}
+
+	// 重新生成失败或次数用完时，修正上一次的结果
+	if !parsed {
+		return nil, validation, fmt.Errorf("生成的章节无法解析: %s", strings.Join(issues, "；"))
+	}
+	validation.Issues = issues
+	validation.Sanitized = true
+	chapters = rules.Sanitize(chapters, cues, duration)
+	if len(chapters) < 2 {
+		return nil, validation, fmt.Errorf("修正后有效章节不足 2 个")
+	}
+	return chapters, validation, nil
+}
+
+// AlignChapters 对章节时间应用与字幕相同的时间轴校正，第一个章节保持 00:00，
+// 校正后不在上一个章节之后或无法解析时间的章节被删除
+func AlignChapters(chapters []MetadataChapter, alignment subtitle.Alignment) []MetadataChapter {
+	result := make([]MetadataChapter, 0, len(chapters))
+	last := time.Duration(-1)
+	for _, chapter := range chapters {
+		start, ok := ParseChapterTime(chapter.Time)
+		if !ok {
+			continue
+		}
+		start = alignment.Apply(start).Truncate(time.Second)
+		if len(result) == 0 {
+			start = 0
+		}
+		if start <= last {
+			continue
+		}
+		result = append(result, MetadataChapter{Time: FormatChapterTime(start), Title: chapter.Title})
+		last = start
+	}
+	return result
+}
+
+// SubtitleDuration 字幕覆盖的时长（最后一条字幕的结束时间）
+func SubtitleDuration(cues []subtitle.Cue) time.Duration {
+	var end time.Duration
+	for _, cue := range cues {
+		end = max(end, cue.End)
+	}
+	return end
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/difyz9/ytb2bili/pkg/llm"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
+)
+
+// chapterTestCues 20 分钟视频，每 10 秒一条字幕
+func chapterTestCues() []subtitle.Cue {
+	var cues []subtitle.Cue
+	for i := 0; i < 120; i++ {
+		start := time.Duration(i) * 10 * time.Second
+		cues = append(cues, subtitle.Cue{Index: i + 1, Start: start, End: start + 9*time.Second, Text: "第" + FormatChapterTime(start) + "句字幕"})
+	}
+	return cues
+}
+
+// TestChapterTranscript 测试字幕超出字数限制时合并相邻字幕
+func TestChapterTranscript(t *testing.T) {
+	cues := chapterTestCues()
+	full := ChapterTranscript(cues, 0)
+	if lines := strings.Split(full, "\n"); len(lines) != 120 || lines[1] != "[00:10] 第00:10句字幕" {
+		t.Fatalf("未限制字数时应每条字幕一行: %q", lines[:2])
+	}
+
+	merged := ChapterTranscript(cues, 2000)
+	lines := strings.Split(merged, "\n")
+	if len([]rune(merged)) > 2000 || len(lines) >= 120 || !strings.HasPrefix(lines[1], "[00:") {
+		t.Errorf("合并后的字幕不正确: %d 行, %d 字", len(lines), len([]rune(merged)))
+	}
+}
+
+// TestChapterValidateSanitize 测试章节校验和自动修正
+func TestChapterValidateSanitize(t *testing.T) {
+	rules := ChapterRules{MaxChapters: 3, MinLength: time.Minute, MaxTitleLength: 8, BannedWords: []string{"最强"}}
+	cues := chapterTestCues()
+	duration := SubtitleDuration(cues)
+
+	valid := []MetadataChapter{{Time: "00:00", Title: "开场"}, {Time: "05:00", Title: "安装"}, {Time: "12:30", Title: "总结"}}
+	if issues := rules.Validate(valid, duration); len(issues) != 0 {
+		t.Fatalf("有效章节不应有问题: %v", issues)
+	}
+
+	chapters := []MetadataChapter{
+		{Time: "00:15", Title: "开场"},
+		{Time: "00:40", Title: "太短"},
+		{Time: "1:02", Title: "最强配置方法讲解"},
+		{Time: "8:03", Title: "总结"},
+		{Time: "10:00", Title: "多余"},
+		{Time: "25:00", Title: "超出时长"},
+		{Time: "abc", Title: "格式错误"},
+	}
+	if issues := rules.Validate(chapters, duration); len(issues) < 6 {
+		t.Errorf("应发现多个问题，得到: %v", issues)
+	}
+
+	got := rules.Sanitize(chapters, cues, duration)
+	want := []MetadataChapter{{Time: "00:00", Title: "开场"}, {Time: "01:00", Title: "配置方法讲解"}, {Time: "08:00", Title: "总结"}}
+	if len(got) != len(want) {
+		t.Fatalf("Sanitize = %+v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("第 %d 个章节 = %+v, 期望 %+v", i, got[i], want[i])
+		}
+	}
+	if issues := rules.Validate(got, duration); len(issues) != 0 {
+		t.Errorf("修正后仍有问题: %v", issues)
+	}
+
+	if d, ok := ParseChapterTime("1:02:03"); !ok || d != time.Hour+2*time.Minute+3*time.Second || FormatChapterTime(d) != "1:02:03" {
+		t.Errorf("ParseChapterTime/FormatChapterTime = %v, %v", d, ok)
+	}
+}
+
+// TestGenerateChaptersRepair 测试章节不通过校验时带上问题重新生成
+func TestGenerateChaptersRepair(t *testing.T) {
+	rules := ChapterRules{MaxChapters: 5, MinLength: time.Minute, MaxTitleLength: 10, RepairAttempts: 1}
+	replies := []string{
+		`{"chapters": [{"time": "00:30", "title": "开场"}, {"time": "05:00", "title": "正文"}]}`,
+		"```json\n{\"chapters\": [{\"time\": \"00:00\", \"title\": \"开场\"}, {\"time\": \"05:00\", \"title\": \"正文\"}]}\n```",
+	}
+	var requests []*llm.Request
+	chat := func(ctx context.Context, req *llm.Request) (*llm.Response, error) {
+		requests = append(requests, req)
+		return &llm.Response{Content: replies[len(requests)-1]}, nil
+	}
+
+	var repairIssues []string
+	chapters, validation, err := GenerateChapters(context.Background(), chat, &llm.Request{Messages: llm.Messages("sys", "user")}, rules, chapterTestCues(), func(issues []string) string {
+		repairIssues = issues
+		return "请修正"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chapters) != 2 || chapters[0].Time != "00:00" || validation.Attempts != 2 || validation.Sanitized {
+		t.Errorf("chapters = %+v, validation = %+v", chapters, validation)
+	}
+	if len(repairIssues) != 1 || !strings.Contains(repairIssues[0], "00:00") {
+		t.Errorf("修正提示问题 = %v", repairIssues)
+	}
+	if !requests[0].JSON || requests[0].Schema == nil || len(requests[1].Messages) != 4 {
+		t.Errorf("请求不正确: %+v", requests[1])
+	}
+}
+
+// TestAlignChapters 测试章节时间应用字幕时间轴校正
+func TestAlignChapters(t *testing.T) {
+	chapters := []MetadataChapter{{Time: "00:00", Title: "开场"}, {Time: "00:05", Title: "片头"}, {Time: "05:00", Title: "安装"}, {Time: "1:00:00", Title: "总结"}}
+
+	shifted := AlignChapters(chapters, subtitle.Alignment{Method: subtitle.AlignMethodShift, Scale: 1, Offset: -8 * time.Second})
+	want := []MetadataChapter{{Time: "00:00", Title: "开场"}, {Time: "04:52", Title: "安装"}, {Time: "59:52", Title: "总结"}}
+	if len(shifted) != len(want) {
+		t.Fatalf("AlignChapters = %+v", shifted)
+	}
+	for i := range want {
+		if shifted[i] != want[i] {
+			t.Errorf("第 %d 个章节 = %+v, 期望 %+v", i, shifted[i], want[i])
+		}
+	}
+
+	stretched := AlignChapters(chapters, subtitle.Alignment{Method: subtitle.AlignMethodStretch, Scale: 1.001, Offset: 2 * time.Second})
+	if len(stretched) != 4 || stretched[0].Time != "00:00" || stretched[2].Time != "05:02" || stretched[3].Time != "1:00:05" {
+		t.Errorf("AlignChapters = %+v", stretched)
+	}
+	if got := AlignChapters(chapters, subtitle.Alignment{Method: subtitle.AlignMethodNone}); len(got) != 4 || got[1] != chapters[1] {
+		t.Errorf("不校正时章节应保持不变: %+v", got)
+	}
+}
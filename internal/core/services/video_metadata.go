@@ -0,0 +1,548 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/llm"
+)
+
+// B站投稿限制
+const (
+	BiliMaxTitleLength   = 80
+	BiliMaxDescLength    = 2000
+	BiliMaxTags          = 12
+	BiliMaxTagLength     = 20
+	BiliMaxDynamicLength = 233
+)
+
+const (
+	MaxCoverTextLength = 20 // 封面文字最多字数（需在封面上清晰可读）
+	MaxTitleVariants   = 5  // 最多生成的标题候选数
+)
+
+// TitleStyles 标题候选可选的风格，用于比较不同风格的效果
+var TitleStyles = []string{"直述", "悬念", "提问", "数字", "干货", "情绪", "对比"}
+
+// VideoMetadata AI生成的视频元数据
+type VideoMetadata struct {
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	Tags        []string          `json:"tags"`
+	Chapters    []MetadataChapter `json:"chapters,omitempty"` // 章节列表
+	Tid         int               `json:"tid,omitempty"`      // 建议的投稿分区
+	Dynamic     string            `json:"dynamic,omitempty"`  // 投稿动态文字
+	Variants    []TitleVariant    `json:"variants,omitempty"` // 标题/封面文字候选（MetadataConfig.TitleVariants > 1 时生成）
+	CoverText   string            `json:"cover_text,omitempty"`
+}
+
+// TitleVariant 标题/封面文字候选
+type TitleVariant struct {
+	Title     string `json:"title"`
+	CoverText string `json:"cover_text"` // 封面文字
+	Style     string `json:"style"`      // 标题风格，见 TitleStyles
+}
+
+// MetadataChapter 视频章节
+type MetadataChapter struct {
+	Time  string `json:"time"`  // 开始时间（mm:ss 或 hh:mm:ss）
+	Title string `json:"title"` // 章节标题
+}
+
+// Partition B站投稿分区
+type Partition struct {
+	Tid  int    `json:"tid"`
+	Name string `json:"name"`
+}
+
+// BiliPartitions 模型可以建议的常用投稿分区
+var BiliPartitions = []Partition{
+	{Tid: 201, Name: "知识 - 科学科普"},
+	{Tid: 124, Name: "知识 - 社科·法律·心理"},
+	{Tid: 228, Name: "知识 - 人文历史"},
+	{Tid: 207, Name: "知识 - 财经商业"},
+	{Tid: 208, Name: "知识 - 校园学习"},
+	{Tid: 209, Name: "知识 - 职业职场"},
+	{Tid: 229, Name: "知识 - 设计·创意"},
+	{Tid: 122, Name: "知识 - 野生技术协会"},
+	{Tid: 95, Name: "科技 - 数码"},
+	{Tid: 230, Name: "科技 - 软件应用"},
+	{Tid: 231, Name: "科技 - 计算机技术"},
+	{Tid: 232, Name: "科技 - 科工机械"},
+	{Tid: 233, Name: "科技 - 极客DIY"},
+	{Tid: 21, Name: "生活 - 日常"},
+	{Tid: 138, Name: "生活 - 搞笑"},
+	{Tid: 250, Name: "生活 - 出行"},
+	{Tid: 76, Name: "美食 - 美食制作"},
+	{Tid: 182, Name: "影视 - 影视杂谈"},
+	{Tid: 17, Name: "游戏 - 单机游戏"},
+	{Tid: 171, Name: "游戏 - 电子竞技"},
+	{Tid: 130, Name: "音乐 - 音乐综合"},
+	{Tid: 164, Name: "运动 - 健身"},
+	{Tid: 236, Name: "运动 - 竞技体育"},
+	{Tid: 258, Name: "汽车 - 汽车知识科普"},
+	{Tid: 75, Name: "动物圈 - 动物综合"},
+	{Tid: 203, Name: "资讯 - 热点"},
+	{Tid: 204, Name: "资讯 - 环球"},
+}
+
+// MetadataRules 元数据校验规则（MetadataConfig 未填写的限制使用B站限制）
+type MetadataRules struct {
+	MaxTitleLength   int
+	MaxDescLength    int
+	MaxTags          int
+	MaxTagLength     int
+	MaxDynamicLength int
+	BannedWords      []string
+	RepairAttempts   int         // 校验不通过时重新生成的次数
+	Partitions       []Partition // 允许建议的分区
+	Variants         int         // 标题候选数（小于 2 表示不生成候选）
+}
+
+// NewMetadataRules 从配置创建校验规则
+func NewMetadataRules(config *types.MetadataConfig) MetadataRules {
+	rules := MetadataRules{
+		MaxTitleLength:   BiliMaxTitleLength,
+		MaxDescLength:    BiliMaxDescLength,
+		MaxTags:          BiliMaxTags,
+		MaxTagLength:     BiliMaxTagLength,
+		MaxDynamicLength: BiliMaxDynamicLength,
+		RepairAttempts:   2,
+		Partitions:       BiliPartitions,
+	}
+	if config == nil {
+		return rules
+	}
+
+	limit := func(value, max int) int {
+		if value > 0 && value < max {
+			return value
+		}
+		return max
+	}
+	rules.MaxTitleLength = limit(config.MaxTitleLength, BiliMaxTitleLength)
+	rules.MaxDescLength = limit(config.MaxDescLength, BiliMaxDescLength)
+	rules.MaxTags = limit(config.MaxTags, BiliMaxTags)
+	rules.MaxTagLength = limit(config.MaxTagLength, BiliMaxTagLength)
+	rules.MaxDynamicLength = limit(config.MaxDynamicLength, BiliMaxDynamicLength)
+	rules.BannedWords = config.BannedWords
+	rules.RepairAttempts = config.RepairAttempts
+	if config.TitleVariants > 1 {
+		rules.Variants = min(config.TitleVariants, MaxTitleVariants)
+	}
+
+	if len(config.Tids) > 0 {
+		allowed := make(map[int]bool, len(config.Tids))
+		for _, tid := range config.Tids {
+			allowed[tid] = true
+		}
+		rules.Partitions = nil
+		for _, partition := range BiliPartitions {
+			if allowed[partition.Tid] {
+				rules.Partitions = append(rules.Partitions, partition)
+				delete(allowed, partition.Tid)
+			}
+		}
+		// 不在内置分区表中的分区也允许使用
+		for _, tid := range config.Tids {
+			if allowed[tid] {
+				rules.Partitions = append(rules.Partitions, Partition{Tid: tid, Name: fmt.Sprintf("分区 %d", tid)})
+			}
+		}
+	}
+	return rules
+}
+
+// PartitionList 提示词中的分区列表，每行一个“分区ID 名称”
+func (r MetadataRules) PartitionList() string {
+	lines := make([]string, len(r.Partitions))
+	for i, partition := range r.Partitions {
+		lines[i] = fmt.Sprintf("%d %s", partition.Tid, partition.Name)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// StyleList 提示词中的标题风格列表，未生成标题候选时为空
+func (r MetadataRules) StyleList() string {
+	if r.Variants < 2 {
+		return ""
+	}
+	return strings.Join(TitleStyles, "、")
+}
+
+// allowsTid 分区是否允许使用
+func (r MetadataRules) allowsTid(tid int) bool {
+	for _, partition := range r.Partitions {
+		if partition.Tid == tid {
+			return true
+		}
+	}
+	return false
+}
+
+// Schema 元数据的 JSON Schema，用于服务支持的结构化输出
+func (r MetadataRules) Schema() *llm.Schema {
+	schema := &llm.Schema{
+		Type: "object",
+		Properties: map[string]*llm.Schema{
+			"title":       {Type: "string", Description: fmt.Sprintf("视频标题，不超过 %d 字", r.MaxTitleLength)},
+			"description": {Type: "string", Description: fmt.Sprintf("视频简介，不超过 %d 字", r.MaxDescLength)},
+			"tags": {
+				Type:        "array",
+				Description: fmt.Sprintf("标签，最多 %d 个，每个不超过 %d 字", r.MaxTags, r.MaxTagLength),
+				Items:       &llm.Schema{Type: "string"},
+			},
+			"chapters": {
+				Type:        "array",
+				Description: "章节列表，第一个章节从 00:00 开始",
+				Items: &llm.Schema{
+					Type: "object",
+					Properties: map[string]*llm.Schema{
+						"time":  {Type: "string", Description: "开始时间，mm:ss 或 hh:mm:ss"},
+						"title": {Type: "string", Description: "章节标题"},
+					},
+					Required: []string{"time", "title"},
+				},
+			},
+			"tid":     {Type: "integer", Description: "建议的投稿分区ID"},
+			"dynamic": {Type: "string", Description: fmt.Sprintf("投稿动态文字，不超过 %d 字", r.MaxDynamicLength)},
+		},
+		Required: []string{"title", "description", "tags"},
+	}
+	if r.Variants > 1 {
+		schema.Properties["variants"] = &llm.Schema{
+			Type:        "array",
+			Description: fmt.Sprintf("%d 个风格不同的标题候选（第一个与 title 相同）", r.Variants),
+			Items: &llm.Schema{
+				Type: "object",
+				Properties: map[string]*llm.Schema{
+					"title":      {Type: "string", Description: fmt.Sprintf("标题，不超过 %d 字", r.MaxTitleLength)},
+					"cover_text": {Type: "string", Description: fmt.Sprintf("封面文字，不超过 %d 字", MaxCoverTextLength)},
+					"style":      {Type: "string", Description: "标题风格", Enum: TitleStyles},
+				},
+				Required: []string{"title", "cover_text", "style"},
+			},
+		}
+		schema.Required = append(schema.Required, "variants")
+	}
+	return schema
+}
+
+// ParseVideoMetadata 解析模型返回的元数据 JSON（容忍代码块包裹、tid 为字符串等情况）
+func ParseVideoMetadata(content string) (*VideoMetadata, error) {
+	var raw struct {
+		VideoMetadata
+		Tid interface{} `json:"tid"`
+	}
+	if err := json.Unmarshal([]byte(extractJSONObject(llm.TrimCodeFence(content))), &raw); err != nil {
+		return nil, fmt.Errorf("解析元数据JSON失败: %v", err)
+	}
+
+	metadata := raw.VideoMetadata
+	switch tid := raw.Tid.(type) {
+	case float64:
+		metadata.Tid = int(tid)
+	case string:
+		metadata.Tid, _ = strconv.Atoi(strings.TrimSpace(tid))
+	}
+	return &metadata, nil
+}
+
+// chapterTimePattern 章节时间（mm:ss 或 hh:mm:ss）
+var chapterTimePattern = regexp.MustCompile(`^(\d{1,2}:)?\d{1,2}:\d{2}$`)
+
+// Normalize 整理元数据：去除首尾空白和标签中的 #，删除空标签和重复标签、空章节
+func (m *VideoMetadata) Normalize() {
+	m.Title = strings.TrimSpace(m.Title)
+	m.Description = strings.TrimSpace(m.Description)
+	m.Dynamic = strings.TrimSpace(m.Dynamic)
+
+	seen := make(map[string]bool)
+	tags := m.Tags[:0]
+	for _, tag := range m.Tags {
+		tag = strings.TrimSpace(strings.Trim(strings.TrimSpace(tag), "#"))
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		tags = append(tags, tag)
+	}
+	m.Tags = tags
+
+	chapters := m.Chapters[:0]
+	for _, chapter := range m.Chapters {
+		chapter.Time = strings.TrimSpace(chapter.Time)
+		chapter.Title = strings.TrimSpace(chapter.Title)
+		if chapter.Title != "" {
+			chapters = append(chapters, chapter)
+		}
+	}
+	m.Chapters = chapters
+
+	seen = make(map[string]bool)
+	variants := m.Variants[:0]
+	for _, variant := range m.Variants {
+		variant.Title = strings.TrimSpace(variant.Title)
+		variant.CoverText = strings.TrimSpace(variant.CoverText)
+		variant.Style = strings.TrimSpace(variant.Style)
+		if variant.Title == "" || seen[variant.Title] {
+			continue
+		}
+		seen[variant.Title] = true
+		variants = append(variants, variant)
+	}
+	m.Variants = variants
+}
+
+// Validate 按规则校验元数据，返回需要修正的问题（为空表示通过）
+func (r MetadataRules) Validate(m *VideoMetadata) []string {
+	var issues []string
+	if m.Title == "" {
+		issues = append(issues, "标题为空")
+	} else if n := utf8.RuneCountInString(m.Title); n > r.MaxTitleLength {
+		issues = append(issues, fmt.Sprintf("标题有 %d 字，超过 %d 字的限制", n, r.MaxTitleLength))
+	}
+	if n := utf8.RuneCountInString(m.Description); n > r.MaxDescLength {
+		issues = append(issues, fmt.Sprintf("简介有 %d 字，超过 %d 字的限制", n, r.MaxDescLength))
+	}
+	if len(m.Tags) == 0 {
+		issues = append(issues, "没有标签")
+	} else if len(m.Tags) > r.MaxTags {
+		issues = append(issues, fmt.Sprintf("标签有 %d 个，超过 %d 个的限制", len(m.Tags), r.MaxTags))
+	}
+	for _, tag := range m.Tags {
+		if utf8.RuneCountInString(tag) > r.MaxTagLength {
+			issues = append(issues, fmt.Sprintf("标签“%s”超过 %d 字", tag, r.MaxTagLength))
+		}
+	}
+	if n := utf8.RuneCountInString(m.Dynamic); n > r.MaxDynamicLength {
+		issues = append(issues, fmt.Sprintf("动态有 %d 字，超过 %d 字的限制", n, r.MaxDynamicLength))
+	}
+	for _, chapter := range m.Chapters {
+		if !chapterTimePattern.MatchString(chapter.Time) {
+			issues = append(issues, fmt.Sprintf("章节“%s”的时间“%s”不是 mm:ss 或 hh:mm:ss 格式", chapter.Title, chapter.Time))
+		}
+	}
+	if m.Tid != 0 && !r.allowsTid(m.Tid) {
+		issues = append(issues, fmt.Sprintf("分区 %d 不在可选分区中", m.Tid))
+	}
+	if r.Variants > 1 && len(m.Variants) < 2 {
+		issues = append(issues, fmt.Sprintf("需要 %d 个标题候选（variants），只有 %d 个", r.Variants, len(m.Variants)))
+	}
+	for _, variant := range m.Variants {
+		if n := utf8.RuneCountInString(variant.Title); n > r.MaxTitleLength {
+			issues = append(issues, fmt.Sprintf("标题候选“%s”有 %d 字，超过 %d 字的限制", variant.Title, n, r.MaxTitleLength))
+		}
+		if n := utf8.RuneCountInString(variant.CoverText); n > MaxCoverTextLength {
+			issues = append(issues, fmt.Sprintf("封面文字“%s”有 %d 字，超过 %d 字的限制", variant.CoverText, n, MaxCoverTextLength))
+		}
+		if variant.Style != "" && !isTitleStyle(variant.Style) {
+			issues = append(issues, fmt.Sprintf("标题候选“%s”的风格“%s”不在可选风格中", variant.Title, variant.Style))
+		}
+	}
+	for _, word := range r.BannedWords {
+		if word = strings.TrimSpace(word); word != "" && strings.Contains(m.text(), word) {
+			issues = append(issues, fmt.Sprintf("包含违禁词“%s”", word))
+		}
+	}
+	return issues
+}
+
+// text 所有会公开显示的文本，用于检查违禁词
+func (m *VideoMetadata) text() string {
+	parts := []string{m.Title, m.Description, m.Dynamic}
+	parts = append(parts, m.Tags...)
+	for _, chapter := range m.Chapters {
+		parts = append(parts, chapter.Title)
+	}
+	for _, variant := range m.Variants {
+		parts = append(parts, variant.Title, variant.CoverText)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// isTitleStyle 是否为可选的标题风格
+func isTitleStyle(style string) bool {
+	for _, s := range TitleStyles {
+		if s == style {
+			return true
+		}
+	}
+	return false
+}
+
+// Sanitize 自动修正仍未通过校验的内容：删除违禁词，截断过长的文本，删除过长和多余的标签、格式错误的章节，清除不允许的分区
+func (r MetadataRules) Sanitize(m *VideoMetadata) {
+	for _, word := range r.BannedWords {
+		if word = strings.TrimSpace(word); word == "" {
+			continue
+		}
+		m.Title = strings.ReplaceAll(m.Title, word, "")
+		m.Description = strings.ReplaceAll(m.Description, word, "")
+		m.Dynamic = strings.ReplaceAll(m.Dynamic, word, "")
+		for i := range m.Tags {
+			m.Tags[i] = strings.ReplaceAll(m.Tags[i], word, "")
+		}
+		for i := range m.Chapters {
+			m.Chapters[i].Title = strings.ReplaceAll(m.Chapters[i].Title, word, "")
+		}
+		for i := range m.Variants {
+			m.Variants[i].Title = strings.ReplaceAll(m.Variants[i].Title, word, "")
+			m.Variants[i].CoverText = strings.ReplaceAll(m.Variants[i].CoverText, word, "")
+		}
+	}
+
+	tags := m.Tags[:0]
+	for _, tag := range m.Tags {
+		if utf8.RuneCountInString(tag) <= r.MaxTagLength {
+			tags = append(tags, tag)
+		}
+	}
+	m.Tags = tags
+	m.Normalize()
+	if len(m.Tags) > r.MaxTags {
+		m.Tags = m.Tags[:r.MaxTags]
+	}
+
+	m.Title = truncateRunes(m.Title, r.MaxTitleLength)
+	m.Description = truncateRunes(m.Description, r.MaxDescLength)
+	m.Dynamic = truncateRunes(m.Dynamic, r.MaxDynamicLength)
+
+	chapters := m.Chapters[:0]
+	for _, chapter := range m.Chapters {
+		if chapterTimePattern.MatchString(chapter.Time) {
+			chapters = append(chapters, chapter)
+		}
+	}
+	m.Chapters = chapters
+
+	if m.Tid != 0 && !r.allowsTid(m.Tid) {
+		m.Tid = 0
+	}
+
+	// 候选不足时不做比较
+	if len(m.Variants) < 2 {
+		m.Variants = nil
+	}
+	for i := range m.Variants {
+		m.Variants[i].Title = truncateRunes(m.Variants[i].Title, r.MaxTitleLength)
+		m.Variants[i].CoverText = truncateRunes(m.Variants[i].CoverText, MaxCoverTextLength)
+		if !isTitleStyle(m.Variants[i].Style) {
+			m.Variants[i].Style = ""
+		}
+	}
+}
+
+// truncateRunes 截断到 max 个字符，超出时以 ... 结尾
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	if max <= 3 {
+		return string(runes[:max])
+	}
+	return string(runes[:max-3]) + "..."
+}
+
+// MetadataValidation 元数据校验结果，保存在元数据生成步骤结果中
+type MetadataValidation struct {
+	Attempts  int      `json:"attempts"`          // 调用模型的次数（含重新生成）
+	History   []string `json:"history,omitempty"` // 每次重新生成前发现的问题
+	Issues    []string `json:"issues,omitempty"`  // 重新生成后仍存在、已自动修正的问题
+	Sanitized bool     `json:"sanitized"`         // 是否自动截断、删除了违规内容
+}
+
+// GenerateVideoMetadata 请求模型按 JSON Schema 生成元数据并按规则校验；
+// 不通过时把问题（由 repairPrompt 生成提示词）反馈给模型重新生成，次数用完后自动修正剩余问题
+func GenerateVideoMetadata(ctx context.Context, chat func(ctx context.Context, req *llm.Request) (*llm.Response, error), req *llm.Request, rules MetadataRules, repairPrompt func(issues []string) string) (*VideoMetadata, *MetadataValidation, error) {
+	request := *req
+	request.JSON = true
+	request.Schema = rules.Schema()
+
+	var metadata *VideoMetadata
+	validation, issues, err := generateValidated(ctx, chat, request, rules.RepairAttempts, func(content string) []string {
+		parsed, err := ParseVideoMetadata(content)
+		if err != nil {
+			return []string{"输出不是合法的 JSON：" + err.Error()}
+		}
+		parsed.Normalize()
+		metadata = parsed
+		return rules.Validate(metadata)
+	}, repairPrompt)
+	if err != nil && metadata == nil {
+		return nil, validation, err
+	}
+	if len(issues) == 0 {
+		return metadata, validation, nil
+	}
+
+	// 重新生成失败或次数用完时，修正上一次的结果
+	if metadata == nil {
+		return nil, validation, fmt.Errorf("生成的元数据无法解析: %s", strings.Join(issues, "；"))
+	}
+	validation.Issues = issues
+	validation.Sanitized = true
+	rules.Sanitize(metadata)
+	if metadata.Title == "" {
+		return nil, validation, fmt.Errorf("生成的标题为空")
+	}
+	return metadata, validation, nil
+}
+
+// generateValidated 调用模型并用 check 解析、校验输出（返回问题列表，为空表示通过），
+// 不通过时把问题反馈给模型重新生成，最多 repairAttempts 次；返回最后一次校验的问题。
+// 重新生成时调用失败也返回错误和上一次的问题，调用方可以修正上一次解析成功的结果
+func generateValidated(ctx context.Context, chat func(ctx context.Context, req *llm.Request) (*llm.Response, error), request llm.Request, repairAttempts int, check func(content string) []string, repairPrompt func(issues []string) string) (*MetadataValidation, []string, error) {
+	messages := append([]llm.Message(nil), request.Messages...)
+	validation := &MetadataValidation{}
+	var issues []string
+	for attempt := 0; ; attempt++ {
+		request.Messages = messages
+		validation.Attempts++
+		resp, err := chat(ctx, &request)
+		if err != nil {
+			return validation, issues, err
+		}
+
+		issues = check(resp.Content)
+		if len(issues) == 0 || attempt >= repairAttempts {
+			return validation, issues, nil
+		}
+		validation.History = append(validation.History, strings.Join(issues, "；"))
+
+		// 重新生成时不再重复上传文件，上一次的回答已包含对文件的理解
+		for i := range messages {
+			messages[i].Files = nil
+		}
+		messages = append(messages,
+			llm.Message{Role: llm.RoleAssistant, Content: resp.Content},
+			llm.Message{Role: llm.RoleUser, Content: repairPrompt(issues)},
+		)
+	}
+}
+
+// MetadataIssueList 提示词中的问题列表，每行一个
+func MetadataIssueList(issues []string) string {
+	lines := make([]string, len(issues))
+	for i, issue := range issues {
+		lines[i] = "- " + issue
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ChaptersText 简介中的章节列表，每行一个“时间 标题”
+func ChaptersText(chapters []MetadataChapter) string {
+	lines := make([]string, 0, len(chapters))
+	for _, chapter := range chapters {
+		lines = append(lines, chapter.Time+" "+chapter.Title)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/llm"
+)
+
+// TestParseVideoMetadata 测试代码块包裹、字符串 tid 和标签整理
+func TestParseVideoMetadata(t *testing.T) {
+	m, err := ParseVideoMetadata("```json\n{\"title\":\" 标题 \",\"description\":\"简介\",\"tags\":[\"#Go\",\"go\",\" \",\"编程\"],\"tid\":\"231\",\"chapters\":[{\"time\":\"00:00\",\"title\":\"开场\"}]}\n```")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Normalize()
+	if m.Title != "标题" || m.Tid != 231 || strings.Join(m.Tags, ",") != "Go,编程" || len(m.Chapters) != 1 {
+		t.Errorf("解析结果不正确: %+v", m)
+	}
+}
+
+// TestMetadataRulesValidate 测试B站限制校验和自动修正
+func TestMetadataRulesValidate(t *testing.T) {
+	rules := NewMetadataRules(&types.MetadataConfig{MaxTags: 3, BannedWords: []string{"最强"}, Tids: []int{231}})
+	m := &VideoMetadata{
+		Title:    "史上最强" + strings.Repeat("长", 80),
+		Tags:     []string{"a", "b", "c", "d", strings.Repeat("超", 21)},
+		Chapters: []MetadataChapter{{Time: "1分钟", Title: "错误"}, {Time: "01:02:03", Title: "正确"}},
+		Tid:      122,
+	}
+	issues := rules.Validate(m)
+	for _, want := range []string{"标题有", "标签有 5 个", "超过 20 字", "1分钟", "分区 122", "违禁词“最强”"} {
+		if !strings.Contains(strings.Join(issues, "\n"), want) {
+			t.Errorf("缺少问题 %q: %v", want, issues)
+		}
+	}
+
+	rules.Sanitize(m)
+	if issues := rules.Validate(m); len(issues) != 0 {
+		t.Errorf("自动修正后仍有问题: %v", issues)
+	}
+	if strings.Contains(m.Title, "最强") || len(m.Tags) != 3 || m.Tid != 0 || len(m.Chapters) != 1 {
+		t.Errorf("自动修正结果不正确: %+v", m)
+	}
+}
+
+// TestGenerateVideoMetadata 测试校验不通过时带上问题重新生成
+func TestGenerateVideoMetadata(t *testing.T) {
+	rules := NewMetadataRules(&types.MetadataConfig{RepairAttempts: 1})
+	replies := []string{
+		`{"title":"` + strings.Repeat("长", 90) + `","description":"简介","tags":["标签"]}`,
+		`{"title":"短标题","description":"简介","tags":["标签"],"dynamic":"发布了"}`,
+	}
+	var requests []*llm.Request
+	chat := func(ctx context.Context, req *llm.Request) (*llm.Response, error) {
+		requests = append(requests, req)
+		return &llm.Response{Content: replies[len(requests)-1]}, nil
+	}
+
+	m, validation, err := GenerateVideoMetadata(context.Background(), chat, &llm.Request{Messages: llm.Messages("", "生成")}, rules, MetadataIssueList)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Title != "短标题" || validation.Attempts != 2 || validation.Sanitized {
+		t.Errorf("结果不正确: %+v %+v", m, validation)
+	}
+	last := requests[1]
+	if last.Schema == nil || len(last.Messages) != 3 || !strings.Contains(last.Messages[2].Content, "标题有 90 字") {
+		t.Errorf("重新生成的请求不正确: %+v", last.Messages)
+	}
+}
+
+// TestMetadataRulesVariants 测试标题候选的 Schema、校验和自动修正
+func TestMetadataRulesVariants(t *testing.T) {
+	rules := NewMetadataRules(&types.MetadataConfig{TitleVariants: 9})
+	if rules.Variants != MaxTitleVariants || rules.Schema().Properties["variants"] == nil {
+		t.Fatalf("候选数应限制为 %d 并加入 Schema: %d", MaxTitleVariants, rules.Variants)
+	}
+
+	m, err := ParseVideoMetadata(`{"title":"标题","description":"简介","tags":["标签"],"variants":[
+		{"title":"标题","cover_text":"封面","style":"悬念"},
+		{"title":"标题","cover_text":"重复","style":"提问"},
+		{"title":"另一个标题","cover_text":"` + strings.Repeat("字", 30) + `","style":"夸张"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Normalize()
+	if len(m.Variants) != 2 {
+		t.Fatalf("应删除重复的候选: %+v", m.Variants)
+	}
+	if issues := rules.Validate(m); len(issues) != 2 {
+		t.Errorf("应发现封面文字过长和风格不可选两个问题: %v", issues)
+	}
+
+	rules.Sanitize(m)
+	if issues := rules.Validate(m); len(issues) != 0 || m.Variants[1].Style != "" {
+		t.Errorf("自动修正后仍有问题（不可选的风格应清空）: %v %+v", issues, m.Variants)
+	}
+}
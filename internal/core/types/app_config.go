@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/BurntSushi/toml"
 )
@@ -20,16 +21,31 @@ type AppConfig struct {
 	FileUpDir   string        `toml:"fileUpDir"`
 	YtDlpPath   string        `toml:"yt_dlp_path"` // yt-dlp 安装路径
 
-	TenCosConfig           *TencentCosConfig       `toml:"TenCosConfig"`           // 腾讯云 COS 存储配置
-	BaiduTransConfig       *BaiduTransConfig       `toml:"BaiduTransConfig"`       // 百度翻译服务配置
-	DeepSeekTransConfig    *DeepSeekTransConfig    `toml:"DeepSeekTransConfig"`    // DeepSeek翻译服务配置
-	GeminiConfig           *GeminiConfig           `toml:"GeminiConfig"`           // Gemini多模态服务配置
-	OpenAICompatibleConfig *OpenAICompatibleConfig `toml:"OpenAICompatibleConfig"` // OpenAI兼容API配置
-	TranslatorConfig       *TranslatorConfig       `toml:"TranslatorConfig"`       // 翻译器总配置
-	ProxyConfig            *ProxyConfig            `toml:"ProxyConfig"`            // 代理配置
-	AnalyticsConfig        *AnalyticsConfig        `toml:"AnalyticsConfig"`        // 数据分析配置
-	BilibiliConfig         *BilibiliConfig         `toml:"BilibiliConfig"`         // Bilibili上传配置
-	MembershipConfig       *MembershipConfig       `toml:"MembershipConfig"`       // 会员系统配置
+	TenCosConfig             *TencentCosConfig         `toml:"TenCosConfig"`             // 腾讯云 COS 存储配置
+	BaiduTransConfig         *BaiduTransConfig         `toml:"BaiduTransConfig"`         // 百度翻译服务配置
+	DeepSeekTransConfig      *DeepSeekTransConfig      `toml:"DeepSeekTransConfig"`      // DeepSeek翻译服务配置
+	OllamaTransConfig        *OllamaTransConfig        `toml:"OllamaTransConfig"`        // Ollama本地模型翻译配置
+	GoogleTransConfig        *GoogleTransConfig        `toml:"GoogleTransConfig"`        // Google翻译配置
+	MicrosoftTransConfig     *MicrosoftTransConfig     `toml:"MicrosoftTransConfig"`     // 微软翻译配置
+	TencentTransConfig       *TencentTransConfig       `toml:"TencentTransConfig"`       // 腾讯云机器翻译配置
+	GeminiConfig             *GeminiConfig             `toml:"GeminiConfig"`             // Gemini多模态服务配置
+	OpenAICompatibleConfig   *OpenAICompatibleConfig   `toml:"OpenAICompatibleConfig"`   // OpenAI兼容API配置
+	TranslatorConfig         *TranslatorConfig         `toml:"TranslatorConfig"`         // 翻译器总配置
+	TranslationQualityConfig *TranslationQualityConfig `toml:"TranslationQualityConfig"` // 译文质量评审配置
+	AIFailoverConfig         *AIFailoverConfig         `toml:"AIFailoverConfig"`         // AI服务故障转移和熔断配置
+	AIUsageConfig            *AIUsageConfig            `toml:"AIUsageConfig"`            // AI调用用量和费用统计配置
+	AIKeyPoolConfig          *AIKeyPoolConfig          `toml:"AIKeyPoolConfig"`          // AI服务多密钥轮换配置
+	MetadataConfig           *MetadataConfig           `toml:"MetadataConfig"`           // 元数据生成和校验配置
+	ChapterConfig            *ChapterConfig            `toml:"ChapterConfig"`            // 分段章节生成配置
+	ProxyConfig              *ProxyConfig              `toml:"ProxyConfig"`              // 代理配置
+	AnalyticsConfig          *AnalyticsConfig          `toml:"AnalyticsConfig"`          // 数据分析配置
+	BilibiliConfig           *BilibiliConfig           `toml:"BilibiliConfig"`           // Bilibili上传配置
+	MembershipConfig         *MembershipConfig         `toml:"MembershipConfig"`         // 会员系统配置
+	TranscriberConfig        *TranscriberConfig        `toml:"TranscriberConfig"`        // 语音识别配置
+	CookieConfig             *CookieConfig             `toml:"CookieConfig"`             // yt-dlp cookies 管理配置
+	YtDlpConfig              *YtDlpConfig              `toml:"YtDlpConfig"`              // yt-dlp 版本管理配置
+	SubtitleConfig           *SubtitleConfig           `toml:"SubtitleConfig"`           // 字幕断句配置
+	HardSubConfig            *HardSubConfig            `toml:"HardSubConfig"`            // 硬字幕烧录配置
 
 	// AI服务选择配置
 	PrimaryAIService string `toml:"primary_ai_service"` // 用户选择的首选AI服务: openai_compatible, deepseek, gemini
@@ -56,7 +72,7 @@ type BilibiliConfig struct {
 	UseOriginalTitle    bool   `toml:"use_original_title"`    // true=使用原视频标题, false=使用AI生成标题
 	UseOriginalDesc     bool   `toml:"use_original_desc"`     // true=使用原视频描述, false=使用AI生成描述
 	CustomTitleTemplate string `toml:"custom_title_template"` // 自定义标题模板，支持变量: {original_title}, {ai_title}
-	CustomDescTemplate  string `toml:"custom_desc_template"`  // 自定义描述模板，支持变量: {original_desc}, {ai_desc}
+	CustomDescTemplate  string `toml:"custom_desc_template"`  // 自定义描述模板，支持变量: {original_desc}, {ai_desc}, {chapters}
 
 	// 新增配置项
 	Tid              int    `toml:"tid"`                // 分区ID（默认122，可自定义）
@@ -66,8 +82,19 @@ type BilibiliConfig struct {
 	UpSelectionReply int    `toml:"up_selection_reply"` // 是否展示推荐评论 0=关闭, 1=开启
 	UpCloseReply     int    `toml:"up_close_reply"`     // 是否关闭评论 0=开启评论, 1=关闭评论
 	UpCloseReward    int    `toml:"up_close_reward"`    // 是否关闭打赏 0=开启, 1=关闭
+
+	// 双语字幕
+	BilingualSubtitle bool   `toml:"bilingual_subtitle"` // 是否生成并上传双语字幕（原文+译文）
+	BilingualOrder    string `toml:"bilingual_order"`    // 双语字幕上下顺序: zh_en=中文在上, en_zh=英文在上
+	BilingualLanguage string `toml:"bilingual_language"` // 双语字幕在B站使用的语言代码（需与中文、英文字幕不同）
 }
 
+// 双语字幕上下顺序
+const (
+	BilingualOrderZhEn = "zh_en" // 中文在上，英文在下
+	BilingualOrderEnZh = "en_zh" // 英文在上，中文在下
+)
+
 type TencentCosConfig struct {
 	Enabled      bool // 是否启用腾讯云 COS 存储
 	CosBucketURL string
@@ -143,12 +170,73 @@ type BaiduTransConfig struct {
 
 // DeepSeekTransConfig DeepSeek翻译服务配置
 type DeepSeekTransConfig struct {
+	Enabled   bool     `toml:"enabled"`    // 是否启用翻译服务
+	ApiKey    string   `toml:"api_key"`    // DeepSeek API密钥
+	ApiKeys   []string `toml:"api_keys"`   // 多个API密钥，按 AIKeyPoolConfig 轮换（优先使用）
+	Model     string   `toml:"models"`     // 使用的模型，默认为 deepseek-chat
+	Endpoint  string   `toml:"endpoint"`   // API端点，默认为 https://api.deepseek.com
+	Timeout   int      `toml:"timeout"`    // 超时时间（秒）
+	MaxTokens int      `toml:"max_tokens"` // 最大token数
+}
+
+// Keys 所有API密钥（配置了 ApiKeys 时使用 ApiKeys，否则为 ApiKey）
+func (c *DeepSeekTransConfig) Keys() []string {
+	return apiKeys(c.ApiKey, c.ApiKeys)
+}
+
+// HasApiKey 是否配置了API密钥
+func (c *DeepSeekTransConfig) HasApiKey() bool {
+	return len(c.Keys()) > 0
+}
+
+// apiKeys 合并单个密钥和多个密钥的配置，去掉空值
+func apiKeys(key string, keys []string) []string {
+	if len(keys) == 0 {
+		keys = []string{key}
+	}
+	var result []string
+	for _, k := range keys {
+		if k = strings.TrimSpace(k); k != "" {
+			result = append(result, k)
+		}
+	}
+	return result
+}
+
+// OllamaTransConfig Ollama本地模型翻译配置
+type OllamaTransConfig struct {
+	Enabled  bool   `toml:"enabled"`  // 是否启用翻译服务
+	Endpoint string `toml:"endpoint"` // Ollama服务地址，默认为 http://localhost:11434
+	Model    string `toml:"model"`    // 使用的模型，如 qwen2.5:7b
+	Timeout  int    `toml:"timeout"`  // 超时时间（秒）
+}
+
+// GoogleTransConfig Google翻译（Cloud Translation v2）配置
+type GoogleTransConfig struct {
+	Enabled  bool   `toml:"enabled"`  // 是否启用翻译服务
+	ApiKey   string `toml:"api_key"`  // Google Cloud API密钥
+	Endpoint string `toml:"endpoint"` // API端点，默认为 https://translation.googleapis.com
+	Timeout  int    `toml:"timeout"`  // 超时时间（秒）
+}
+
+// MicrosoftTransConfig 微软翻译（Azure Translator v3）配置
+type MicrosoftTransConfig struct {
+	Enabled  bool   `toml:"enabled"`  // 是否启用翻译服务
+	ApiKey   string `toml:"api_key"`  // Azure Translator 订阅密钥
+	Region   string `toml:"region"`   // 资源所在区域，如 eastasia（全局资源可留空）
+	Endpoint string `toml:"endpoint"` // API端点，默认为 https://api.cognitive.microsofttranslator.com
+	Timeout  int    `toml:"timeout"`  // 超时时间（秒）
+}
+
+// TencentTransConfig 腾讯云机器翻译（TMT）配置
+type TencentTransConfig struct {
 	Enabled   bool   `toml:"enabled"`    // 是否启用翻译服务
-	ApiKey    string `toml:"api_key"`    // DeepSeek API密钥
-	Model     string `toml:"models"`     // 使用的模型，默认为 deepseek-chat
-	Endpoint  string `toml:"endpoint"`   // API端点，默认为 https://api.deepseek.com
+	SecretId  string `toml:"secret_id"`  // 腾讯云 SecretId
+	SecretKey string `toml:"secret_key"` // 腾讯云 SecretKey
+	Region    string `toml:"region"`     // 地域，默认为 ap-guangzhou
+	ProjectId int64  `toml:"project_id"` // 项目ID，默认为 0
+	Endpoint  string `toml:"endpoint"`   // API端点，默认为 https://tmt.tencentcloudapi.com
 	Timeout   int    `toml:"timeout"`    // 超时时间（秒）
-	MaxTokens int    `toml:"max_tokens"` // 最大token数
 }
 
 // GeminiConfig Gemini多模态服务配置
@@ -156,7 +244,6 @@ type GeminiConfig struct {
 	Enabled           bool     `toml:"enabled"`             // 是否启用Gemini服务
 	ApiKey            string   `toml:"api_key"`             // Google AI API密钥（主密钥，兼容旧配置）
 	ApiKeys           []string `toml:"api_keys"`            // 多个API密钥，用于轮询（优先使用）
-	CurrentKeyIndex   int      `toml:"-"`                   // 当前使用的密钥索引（运行时状态，不保存）
 	Model             string   `toml:"model"`               // 使用的模型，默认为 gemini-1.5-pro
 	Timeout           int      `toml:"timeout"`             // 超时时间（秒）
 	MaxTokens         int      `toml:"max_tokens"`          // 最大输出token数
@@ -165,23 +252,14 @@ type GeminiConfig struct {
 	VideoSampleFrames int      `toml:"video_sample_frames"` // 视频采样帧数（0=上传完整视频）
 }
 
-// GetCurrentApiKey 获取当前使用的API密钥
-func (g *GeminiConfig) GetCurrentApiKey() string {
-	// 优先使用 ApiKeys 数组
-	if len(g.ApiKeys) > 0 {
-		index := g.CurrentKeyIndex % len(g.ApiKeys)
-		return g.ApiKeys[index]
-	}
-	// 兼容旧配置，使用单个 ApiKey
-	return g.ApiKey
+// Keys 所有API密钥（配置了 ApiKeys 时使用 ApiKeys，否则为 ApiKey）
+func (g *GeminiConfig) Keys() []string {
+	return apiKeys(g.ApiKey, g.ApiKeys)
 }
 
-// RotateApiKey 轮换到下一个API密钥
-func (g *GeminiConfig) RotateApiKey() string {
-	if len(g.ApiKeys) > 1 {
-		g.CurrentKeyIndex = (g.CurrentKeyIndex + 1) % len(g.ApiKeys)
-	}
-	return g.GetCurrentApiKey()
+// HasApiKey 是否配置了API密钥
+func (g *GeminiConfig) HasApiKey() bool {
+	return len(g.Keys()) > 0
 }
 
 // GetApiKeysCount 获取API密钥数量
@@ -198,30 +276,293 @@ func (g *GeminiConfig) GetApiKeysCount() int {
 // OpenAICompatibleConfig OpenAI兼容API配置
 // 支持任何兼容OpenAI API格式的服务
 type OpenAICompatibleConfig struct {
-	Enabled     bool    `toml:"enabled"`     // 是否启用
-	Provider    string  `toml:"provider"`    // 提供商标识: openai, deepseek, qwen, zhipu, gemini, custom
-	ApiKey      string  `toml:"api_key"`     // API密钥
-	BaseURL     string  `toml:"base_url"`    // API基础URL
-	Model       string  `toml:"model"`       // 使用的模型
-	Timeout     int     `toml:"timeout"`     // 超时时间（秒）
-	MaxTokens   int     `toml:"max_tokens"`  // 最大token数
-	Temperature float64 `toml:"temperature"` // 温度参数 (0-2)
+	Enabled     bool     `toml:"enabled"`     // 是否启用
+	Provider    string   `toml:"provider"`    // 提供商标识: openai, deepseek, qwen, zhipu, gemini, custom
+	ApiKey      string   `toml:"api_key"`     // API密钥
+	ApiKeys     []string `toml:"api_keys"`    // 多个API密钥，按 AIKeyPoolConfig 轮换（优先使用）
+	BaseURL     string   `toml:"base_url"`    // API基础URL
+	Model       string   `toml:"model"`       // 使用的模型
+	Timeout     int      `toml:"timeout"`     // 超时时间（秒）
+	MaxTokens   int      `toml:"max_tokens"`  // 最大token数
+	Temperature float64  `toml:"temperature"` // 温度参数 (0-2)
+
+	StructuredOutput bool `toml:"structured_output"` // 模型支持 JSON Schema 结构化输出（如 OpenAI gpt-4o），否则只使用 JSON 模式
+}
+
+// Keys 所有API密钥（配置了 ApiKeys 时使用 ApiKeys，否则为 ApiKey）
+func (c *OpenAICompatibleConfig) Keys() []string {
+	return apiKeys(c.ApiKey, c.ApiKeys)
+}
+
+// HasApiKey 是否配置了API密钥
+func (c *OpenAICompatibleConfig) HasApiKey() bool {
+	return len(c.Keys()) > 0
 }
 
 // TranslatorConfig 翻译器总配置
 type TranslatorConfig struct {
-	DefaultProvider   string   `toml:"default_provider"`   // 默认翻译提供商
-	FallbackProviders []string `toml:"fallback_providers"` // 备选翻译提供商
+	DefaultProvider   string   `toml:"default_provider"`   // 默认翻译提供商，留空时字幕翻译使用 ai（AI服务），其他场景使用第一个已启用的提供商
+	FallbackProviders []string `toml:"fallback_providers"` // 备选翻译提供商，留空时使用已启用的提供商
+	TargetLanguages   []string `toml:"target_languages"`   // 字幕翻译的目标语言（如 zh-Hans、zh-Hant、ja），每种语言生成一个译文文件，留空时为 zh-Hans
 	MaxRetries        int      `toml:"max_retries"`        // 最大重试次数
 	Timeout           int      `toml:"timeout"`            // 超时时间（秒）
-	EnableCache       bool     `toml:"enable_cache"`       // 是否启用缓存
-	CacheExpiry       int      `toml:"cache_expiry"`       // 缓存过期时间（秒）
+	EnableCache       bool     `toml:"enable_cache"`       // 是否启用翻译记忆（跨视频共享的持久化译文缓存）
+	CacheExpiry       int      `toml:"cache_expiry"`       // 翻译记忆有效期（秒），0 表示永不过期
+}
+
+// TranslationQualityConfig 译文质量评审配置：评审模型逐条打分，低分条目用更强的模型重译
+type TranslationQualityConfig struct {
+	Enabled             bool    `toml:"enabled"`              // 是否启用质量评审
+	JudgeProvider       string  `toml:"judge_provider"`       // 评审使用的AI服务: openai_compatible, deepseek，留空时自动选择
+	JudgeModel          string  `toml:"judge_model"`          // 评审模型，留空时使用该服务配置的模型
+	RetranslateProvider string  `toml:"retranslate_provider"` // 重译使用的AI服务，留空时自动选择
+	RetranslateModel    string  `toml:"retranslate_model"`    // 重译模型（建议比翻译模型更强），留空时使用该服务配置的模型
+	MinScore            float64 `toml:"min_score"`            // 综合得分（0-10）低于该值的条目重译，默认 6
+	BatchSize           int     `toml:"batch_size"`           // 每次评审的字幕条数，默认 20
+	MaxRetranslate      int     `toml:"max_retranslate"`      // 单个语言最多重译的条数，0 表示不限制
+}
+
+// AIFailoverConfig AI服务故障转移配置：每个服务一个熔断器，连续失败（或被限流）后熔断，
+// 冷却结束后进入半开状态，放行一次探测请求，成功则恢复
+type AIFailoverConfig struct {
+	Priority             []string `toml:"priority"`                // 故障转移顺序，留空时为 首选服务 > openai_compatible > deepseek > gemini
+	FailureThreshold     int      `toml:"failure_threshold"`       // 连续失败多少次后熔断，默认 3
+	OpenSeconds          int      `toml:"open_seconds"`            // 熔断持续时间（秒），默认 60
+	RateLimitOpenSeconds int      `toml:"rate_limit_open_seconds"` // 被限流（429）时立即熔断的持续时间（秒），默认 120
+	ProbeCron            string   `toml:"probe_cron"`              // 主动探测熔断中服务的定时任务，默认每 30 秒，设为 "-" 关闭
+}
+
+// AIKeyPoolConfig 多个API密钥的轮换规则（OpenAI兼容API、DeepSeek、Gemini 的 api_keys），
+// 限额针对单个密钥，0 表示不限制；轮换状态在进程内共享，不修改配置
+type AIKeyPoolConfig struct {
+	Strategy          string                   `toml:"strategy"`            // round_robin（默认）、least_used（今日请求最少）、failover（固定使用一个，被限流后切换）
+	RequestsPerMinute int                      `toml:"requests_per_minute"` // 每个密钥每分钟最多请求数
+	DailyRequests     int                      `toml:"daily_requests"`      // 每个密钥每天最多请求数
+	DailyTokens       int                      `toml:"daily_tokens"`        // 每个密钥每天最多 token 数
+	CooldownSeconds   int                      `toml:"cooldown_seconds"`    // 密钥被限流（429）后暂停使用的时间（秒），默认 60
+	Providers         map[string]KeyPoolPolicy `toml:"providers"`           // 按服务覆盖（openai_compatible、deepseek、gemini）
+}
+
+// MetadataConfig 元数据生成配置：模型按 JSON Schema 输出，按B站限制校验，不通过时带上问题重新生成
+type MetadataConfig struct {
+	MaxTitleLength      int      `toml:"max_title_length"`      // 标题最多字数（B站限制 80）
+	MaxDescLength       int      `toml:"max_desc_length"`       // 简介最多字数（B站限制 2000，需为原视频简介和链接留出空间）
+	MaxTags             int      `toml:"max_tags"`              // 标签最多个数（B站限制 12）
+	MaxTagLength        int      `toml:"max_tag_length"`        // 单个标签最多字数（B站限制 20）
+	MaxDynamicLength    int      `toml:"max_dynamic_length"`    // 动态最多字数（B站限制 233）
+	BannedWords         []string `toml:"banned_words"`          // 违禁词，不能出现在标题、简介、标签、动态和章节中
+	RepairAttempts      int      `toml:"repair_attempts"`       // 校验不通过时带上问题重新生成的次数（0 表示不重新生成），仍不通过时自动截断、删除
+	Tids                []int    `toml:"tids"`                  // 允许模型建议的分区，为空表示内置分区表中的全部分区
+	UseSuggestedTid     bool     `toml:"use_suggested_tid"`     // 投稿时使用模型建议的分区（否则使用 BilibiliConfig.tid）
+	UseGeneratedDynamic bool     `toml:"use_generated_dynamic"` // 投稿时使用生成的动态文本（否则使用 BilibiliConfig.dynamic）
+	IncludeChapters     bool     `toml:"include_chapters"`      // 在简介中附加生成的章节列表
+
+	// 标题 A/B 测试：生成多个标题/封面文字候选，按策略选中一个，投稿后定时获取播放数据比较效果
+	TitleVariants   int     `toml:"title_variants"`    // 标题候选数（最多 5，小于 2 表示不生成候选）
+	VariantStrategy string  `toml:"variant_strategy"`  // 选择策略：first（模型推荐）、random、best（该分区表现最好的风格）、epsilon_greedy
+	ExploreRate     float64 `toml:"explore_rate"`      // epsilon_greedy 随机选择的概率
+	StatsCron       string  `toml:"stats_cron"`        // 获取投稿播放数据的定时任务，设为 "-" 关闭
+	StatsWindowDays int     `toml:"stats_window_days"` // 投稿后持续更新播放数据的天数，之后数据不再变化，便于不同视频之间比较
+}
+
+// ChapterConfig 分段章节生成配置：根据带时间的译文字幕由模型划分章节，写入投稿简介
+// 违禁词和重新生成次数使用 MetadataConfig 的设置
+type ChapterConfig struct {
+	Enabled            bool `toml:"enabled"`              // 是否生成章节
+	MinVideoMinutes    int  `toml:"min_video_minutes"`    // 视频至少多少分钟才生成章节
+	MaxChapters        int  `toml:"max_chapters"`         // 最多章节数
+	MinChapterSeconds  int  `toml:"min_chapter_seconds"`  // 每个章节最短秒数
+	MaxTitleLength     int  `toml:"max_title_length"`     // 章节标题最多字数
+	MaxTranscriptChars int  `toml:"max_transcript_chars"` // 发送给模型的字幕最多字数，超出时合并相邻字幕并截断
+}
+
+// KeyPoolPolicy 单个服务的密钥轮换规则，未设置的字段使用 AIKeyPoolConfig 的值
+type KeyPoolPolicy struct {
+	Strategy          string `toml:"strategy"`
+	RequestsPerMinute int    `toml:"requests_per_minute"`
+	DailyRequests     int    `toml:"daily_requests"`
+	DailyTokens       int    `toml:"daily_tokens"`
+	CooldownSeconds   int    `toml:"cooldown_seconds"`
+}
+
+// Policy 指定服务的密钥轮换规则
+func (c *AIKeyPoolConfig) Policy(provider string) KeyPoolPolicy {
+	if c == nil {
+		return KeyPoolPolicy{}
+	}
+	policy := KeyPoolPolicy{
+		Strategy:          c.Strategy,
+		RequestsPerMinute: c.RequestsPerMinute,
+		DailyRequests:     c.DailyRequests,
+		DailyTokens:       c.DailyTokens,
+		CooldownSeconds:   c.CooldownSeconds,
+	}
+	override, ok := c.Providers[provider]
+	if !ok {
+		return policy
+	}
+	if override.Strategy != "" {
+		policy.Strategy = override.Strategy
+	}
+	if override.RequestsPerMinute > 0 {
+		policy.RequestsPerMinute = override.RequestsPerMinute
+	}
+	if override.DailyRequests > 0 {
+		policy.DailyRequests = override.DailyRequests
+	}
+	if override.DailyTokens > 0 {
+		policy.DailyTokens = override.DailyTokens
+	}
+	if override.CooldownSeconds > 0 {
+		policy.CooldownSeconds = override.CooldownSeconds
+	}
+	return policy
+}
+
+// AIUsageConfig AI调用用量统计：每次调用记录服务、模型、token 数和按价格表估算的费用
+type AIUsageConfig struct {
+	Enabled  bool         `toml:"enabled"`  // 是否记录用量
+	Currency string       `toml:"currency"` // 价格表的货币单位，默认 USD
+	Prices   []ModelPrice `toml:"prices"`   // 价格表
+}
+
+// ModelPrice 模型价格（每百万 token / 字符）
+type ModelPrice struct {
+	Provider         string  `toml:"provider"`           // 服务（openai_compatible、deepseek、gemini、google 等），为空匹配所有服务
+	Model            string  `toml:"model"`              // 模型名，以 * 结尾按前缀匹配，为空匹配该服务的所有模型
+	InputPerMillion  float64 `toml:"input_per_million"`  // 每百万输入 token 价格
+	OutputPerMillion float64 `toml:"output_per_million"` // 每百万输出 token 价格
+	CharsPerMillion  float64 `toml:"chars_per_million"`  // 每百万字符价格（按字符计费的机器翻译）
+}
+
+// TranscriberConfig 语音识别配置（视频没有任何字幕时使用）
+type TranscriberConfig struct {
+	Enabled        bool    `toml:"enabled"`          // 是否启用语音识别
+	Provider       string  `toml:"provider"`         // 识别后端: whisper_cpp, openai
+	WhisperCppPath string  `toml:"whisper_cpp_path"` // whisper.cpp 可执行文件路径（默认 whisper-cli）
+	ModelPath      string  `toml:"model_path"`       // whisper.cpp ggml 模型路径
+	Threads        int     `toml:"threads"`          // whisper.cpp 线程数
+	ApiKey         string  `toml:"api_key"`          // OpenAI 兼容接口密钥（为空时复用 OpenAICompatibleConfig）
+	BaseURL        string  `toml:"base_url"`         // OpenAI 兼容接口地址
+	Model          string  `toml:"model"`            // OpenAI 兼容接口模型，默认 whisper-1
+	Language       string  `toml:"language"`         // 识别语言，auto 为自动检测
+	ChunkSeconds   int     `toml:"chunk_seconds"`    // 长音频分片时长（秒）
+	Timeout        int     `toml:"timeout"`          // 单个分片超时时间（秒）
+	MaxCueChars    int     `toml:"max_cue_chars"`    // 每条字幕最大字符数
+	MaxCueDuration float64 `toml:"max_cue_duration"` // 每条字幕最长时长（秒）
+}
+
+// CookieConfig yt-dlp cookies 管理配置
+type CookieConfig struct {
+	EncryptionKey   string `toml:"encryption_key"`   // cookies 加密密钥（必填，未配置时不能上传 cookies；修改后需重新上传）
+	CooldownMinutes int    `toml:"cooldown_minutes"` // 被限流/年龄限制后暂停使用的时间（分钟）
+}
+
+// YtDlpConfig yt-dlp 版本管理配置
+type YtDlpConfig struct {
+	AutoUpdate     bool   `toml:"auto_update"`     // 是否定时检查更新
+	UpdateCron     string `toml:"update_cron"`     // 检查更新的 cron 表达式（带秒）
+	PinnedVersion  string `toml:"pinned_version"`  // 固定版本（例如 2024.08.06），为空表示跟随最新版本
+	ReleaseAPIURL  string `toml:"release_api_url"` // GitHub Release API 地址（可替换为镜像或测试服务器）
+	VerifyChecksum bool   `toml:"verify_checksum"` // 是否校验 SHA2-256SUMS
+	SmokeTestURL   string `toml:"smoke_test_url"`  // 更新后用于冒烟测试的视频地址（为空时只检查 --version）
+}
+
+// SubtitleConfig 字幕后处理配置
+type SubtitleConfig struct {
+	Source        SegmentConfig `toml:"source"`         // 翻译前对原文字幕重新断句（准备阶段）
+	Upload        SegmentConfig `toml:"upload"`         // 上传前对译文字幕断句和断行（上传阶段）
+	Align         AlignConfig   `toml:"align"`          // 按视频音频校正字幕时间轴（整体偏移或线性拉伸）
+	RequireReview bool          `toml:"require_review"` // 准备阶段完成后等待人工审核字幕（状态 100），审核通过后才进入上传队列
+
+	// 视频原始语言未知时可以使用的字幕语言（按顺序），只有一条字幕时直接使用该字幕
+	CaptionLanguages []string `toml:"caption_languages"`
+}
+
+// HardSubConfig 硬字幕（烧录字幕）配置
+type HardSubConfig struct {
+	Enabled       bool    `toml:"enabled"`        // 是否在上传前将字幕烧录到视频画面中（原视频保留）
+	Tids          []int   `toml:"tids"`           // 只对这些分区烧录字幕，为空表示全部分区
+	Source        string  `toml:"source"`         // 烧录的字幕: translated=译文, bilingual=双语
+	FontName      string  `toml:"font_name"`      // 字体名称（需已安装或位于 fonts_dir 中）
+	FontsDir      string  `toml:"fonts_dir"`      // 额外的字体目录（可选）
+	FontSize      int     `toml:"font_size"`      // 字号（以 1080p 画面为基准）
+	PrimaryColour string  `toml:"primary_colour"` // 文字颜色 (&HAABBGGRR)
+	OutlineColour string  `toml:"outline_colour"` // 描边颜色 (&HAABBGGRR)
+	Outline       float64 `toml:"outline"`        // 描边宽度
+	Bold          bool    `toml:"bold"`           // 是否加粗
+	Position      string  `toml:"position"`       // 字幕位置: bottom, top, middle
+	MarginV       int     `toml:"margin_v"`       // 垂直边距
+	Preset        string  `toml:"preset"`         // x264 编码预设
+	CRF           int     `toml:"crf"`            // x264 质量参数（越小质量越高）
+}
+
+// 硬字幕位置
+const (
+	HardSubPositionBottom = "bottom"
+	HardSubPositionTop    = "top"
+	HardSubPositionMiddle = "middle"
+)
+
+// 硬字幕内容
+const (
+	HardSubSourceTranslated = "translated"
+	HardSubSourceBilingual  = "bilingual"
+)
+
+// AppliesTo 是否对指定分区烧录字幕
+func (c *HardSubConfig) AppliesTo(tid int) bool {
+	if c == nil || !c.Enabled {
+		return false
+	}
+	if len(c.Tids) == 0 {
+		return true
+	}
+	for _, t := range c.Tids {
+		if t == tid {
+			return true
+		}
+	}
+	return false
+}
+
+// SegmentConfig 字幕断句规则
+type SegmentConfig struct {
+	Enabled        bool    `toml:"enabled"`         // 是否启用
+	MergeSentences bool    `toml:"merge_sentences"` // 是否将碎片合并为整句（滚动式自动字幕）
+	MaxMergeGap    float64 `toml:"max_merge_gap"`   // 合并时允许的最大间隔（秒）
+	MaxCPS         float64 `toml:"max_cps"`         // 每秒最多字符数（阅读速度）
+	MaxLineLength  int     `toml:"max_line_length"` // 每行最大字符数
+	MaxLines       int     `toml:"max_lines"`       // 每条字幕最多行数
+	MinDuration    float64 `toml:"min_duration"`    // 最短显示时长（秒）
+	MaxDuration    float64 `toml:"max_duration"`    // 最长显示时长（秒）
+	MinGap         float64 `toml:"min_gap"`         // 相邻字幕最小间隔（秒）
+}
+
+// AlignConfig 字幕时间轴校正配置
+// 用 ffmpeg silencedetect 检测视频中的有声区间，将字幕开始时间与语音起点对齐，估计整体偏移或线性拉伸
+type AlignConfig struct {
+	Enabled       bool    `toml:"enabled"`         // 是否启用（需要视频文件已下载）
+	NoiseDB       float64 `toml:"noise_db"`        // 静音阈值（dB）
+	MinSilence    float64 `toml:"min_silence"`     // 最短静音时长（秒）
+	MaxShift      float64 `toml:"max_shift"`       // 允许的最大偏移（秒）
+	MaxStretch    float64 `toml:"max_stretch"`     // 允许的最大拉伸比例（0.05 表示 ±5%），0 表示只做整体偏移
+	MinShift      float64 `toml:"min_shift"`       // 偏移小于该值（秒）时不校正
+	Tolerance     float64 `toml:"tolerance"`       // 字幕开始时间与语音起点的匹配容差（秒）
+	MinMatchRatio float64 `toml:"min_match_ratio"` // 校正后匹配字幕的最低比例，低于该值认为对齐不可靠，不校正
 }
 
 // ProxyConfig 代理配置
 type ProxyConfig struct {
-	UseProxy  bool   `toml:"use_proxy"`  // 是否使用代理
-	ProxyHost string `toml:"proxy_host"` // 代理地址 (例如: http://127.0.0.1:7890)
+	UseProxy            bool     `toml:"use_proxy"`             // 是否使用代理
+	ProxyHost           string   `toml:"proxy_host"`            // 代理地址 (例如: http://127.0.0.1:7890)，未配置 proxies 时使用
+	Proxies             []string `toml:"proxies"`               // 代理池，支持 http:// 和 socks5://，按顺序轮换
+	ProxyDomains        []string `toml:"proxy_domains"`         // 走代理的域名（为空表示除直连域名外全部走代理）
+	DirectDomains       []string `toml:"direct_domains"`        // 直连的域名（优先于 proxy_domains，未配置时为 Bilibili 和 COS）
+	HealthCheckURL      string   `toml:"health_check_url"`      // 健康检查地址
+	HealthCheckInterval int      `toml:"health_check_interval"` // 健康检查间隔（秒）
+	CooldownSeconds     int      `toml:"cooldown_seconds"`      // 遇到 429/403 后暂停使用的时间（秒）
+	DirectFallback      bool     `toml:"direct_fallback"`       // 代理全部失败后是否直连重试（会暴露服务器 IP，默认关闭）
 }
 
 // AnalyticsConfig 数据分析配置
@@ -269,6 +610,14 @@ func NewDefaultConfig() *AppConfig {
 			CosUrL:       "",
 		},
 
+		// 百度翻译配置（默认值，可被 config.toml 覆盖）
+		BaiduTransConfig: &BaiduTransConfig{
+			Enabled:   false,
+			AppId:     "",
+			SecretKey: "",
+			Endpoint:  "https://fanyi-api.baidu.com/api/trans/vip/translate",
+		},
+
 		// DeepSeek 翻译配置（默认值，可被 config.toml 覆盖）
 		DeepSeekTransConfig: &DeepSeekTransConfig{
 			Enabled:   false,
@@ -279,6 +628,42 @@ func NewDefaultConfig() *AppConfig {
 			MaxTokens: 4000,
 		},
 
+		// Ollama 本地翻译配置（默认值，可被 config.toml 覆盖）
+		OllamaTransConfig: &OllamaTransConfig{
+			Enabled:  false,
+			Endpoint: "http://localhost:11434",
+			Model:    "qwen2.5:7b",
+			Timeout:  120,
+		},
+
+		// Google 翻译配置（默认值，可被 config.toml 覆盖）
+		GoogleTransConfig: &GoogleTransConfig{
+			Enabled:  false,
+			ApiKey:   "",
+			Endpoint: "https://translation.googleapis.com",
+			Timeout:  30,
+		},
+
+		// 微软翻译配置（默认值，可被 config.toml 覆盖）
+		MicrosoftTransConfig: &MicrosoftTransConfig{
+			Enabled:  false,
+			ApiKey:   "",
+			Region:   "",
+			Endpoint: "https://api.cognitive.microsofttranslator.com",
+			Timeout:  30,
+		},
+
+		// 腾讯云机器翻译配置（默认值，可被 config.toml 覆盖）
+		TencentTransConfig: &TencentTransConfig{
+			Enabled:   false,
+			SecretId:  "",
+			SecretKey: "",
+			Region:    "ap-guangzhou",
+			ProjectId: 0,
+			Endpoint:  "https://tmt.tencentcloudapi.com",
+			Timeout:   30,
+		},
+
 		// Gemini 多模态配置（默认值，可被 config.toml 覆盖）
 		GeminiConfig: &GeminiConfig{
 			Enabled:           false,
@@ -305,8 +690,11 @@ func NewDefaultConfig() *AppConfig {
 
 		// 代理配置（默认值，可被 config.toml 覆盖）
 		ProxyConfig: &ProxyConfig{
-			UseProxy:  false,
-			ProxyHost: "",
+			UseProxy:            false,
+			ProxyHost:           "",
+			HealthCheckInterval: 300,
+			CooldownSeconds:     600,
+			DirectFallback:      false,
 		},
 
 		// 数据分析配置（默认值，可被 config.toml 覆盖）
@@ -333,6 +721,166 @@ func NewDefaultConfig() *AppConfig {
 			UpSelectionReply:   0,         // 默认不展示推荐评论
 			UpCloseReply:       0,         // 默认开启评论
 			UpCloseReward:      0,         // 默认开启打赏
+			BilingualSubtitle:  false,
+			BilingualOrder:     BilingualOrderZhEn,
+			BilingualLanguage:  "zh-CN",
+		},
+
+		// 语音识别配置（默认值，可被 config.toml 覆盖）
+		TranscriberConfig: &TranscriberConfig{
+			Enabled:        false,
+			Provider:       "whisper_cpp",
+			WhisperCppPath: "whisper-cli",
+			ModelPath:      "",
+			Threads:        4,
+			Model:          "whisper-1",
+			Language:       "auto",
+			ChunkSeconds:   600,
+			Timeout:        300,
+			MaxCueChars:    80,
+			MaxCueDuration: 7,
+		},
+
+		// cookies 管理配置（默认值，可被 config.toml 覆盖）
+		CookieConfig: &CookieConfig{
+			EncryptionKey:   "",
+			CooldownMinutes: 60,
+		},
+
+		// yt-dlp 版本管理配置
+		YtDlpConfig: &YtDlpConfig{
+			AutoUpdate:     false,
+			UpdateCron:     "0 30 4 * * *",
+			PinnedVersion:  "",
+			ReleaseAPIURL:  "https://api.github.com/repos/yt-dlp/yt-dlp",
+			VerifyChecksum: true,
+			SmokeTestURL:   "",
+		},
+
+		// 字幕后处理配置
+		SubtitleConfig: &SubtitleConfig{
+			Source: SegmentConfig{
+				Enabled:        true,
+				MergeSentences: true,
+				MaxMergeGap:    1.0,
+				MaxCPS:         20,
+				MaxLineLength:  42,
+				MaxLines:       2,
+				MinDuration:    1.0,
+				MaxDuration:    7.0,
+				MinGap:         0.08,
+			},
+			Upload: SegmentConfig{
+				Enabled:       true,
+				MaxCPS:        9,
+				MaxLineLength: 18,
+				MaxLines:      2,
+				MinDuration:   1.0,
+				MaxDuration:   7.0,
+				MinGap:        0.08,
+			},
+			Align: AlignConfig{
+				Enabled:       true,
+				NoiseDB:       -30,
+				MinSilence:    0.3,
+				MaxShift:      30,
+				MaxStretch:    0.05,
+				MinShift:      0.2,
+				Tolerance:     0.3,
+				MinMatchRatio: 0.15,
+			},
+			CaptionLanguages: []string{"en"},
+		},
+
+		// 硬字幕配置（默认关闭）
+		HardSubConfig: &HardSubConfig{
+			Enabled:       false,
+			Tids:          []int{},
+			Source:        HardSubSourceTranslated,
+			FontName:      "Microsoft YaHei",
+			FontSize:      56,
+			PrimaryColour: "&H00FFFFFF",
+			OutlineColour: "&H00000000",
+			Outline:       2.5,
+			Position:      HardSubPositionBottom,
+			MarginV:       50,
+			Preset:        "veryfast",
+			CRF:           20,
+		},
+
+		// 翻译器配置（翻译记忆默认开启，有效期 30 天）
+		TranslatorConfig: &TranslatorConfig{
+			TargetLanguages: []string{"zh-Hans"},
+			MaxRetries:      3,
+			Timeout:         30,
+			EnableCache:     true,
+			CacheExpiry:     30 * 24 * 3600,
+		},
+
+		// 译文质量评审配置（默认关闭）
+		TranslationQualityConfig: &TranslationQualityConfig{
+			MinScore:       6,
+			BatchSize:      20,
+			MaxRetranslate: 50,
+		},
+
+		// AI服务故障转移配置
+		AIFailoverConfig: &AIFailoverConfig{
+			FailureThreshold:     3,
+			OpenSeconds:          60,
+			RateLimitOpenSeconds: 120,
+			ProbeCron:            "@every 30s",
+		},
+
+		// AI服务多密钥轮换配置
+		AIKeyPoolConfig: &AIKeyPoolConfig{
+			Strategy:        "round_robin",
+			CooldownSeconds: 60,
+		},
+
+		// 元数据生成和校验配置
+		MetadataConfig: &MetadataConfig{
+			MaxTitleLength:   80,
+			MaxDescLength:    1000,
+			MaxTags:          12,
+			MaxTagLength:     20,
+			MaxDynamicLength: 233,
+			BannedWords:      []string{},
+			RepairAttempts:   2,
+			Tids:             []int{},
+			IncludeChapters:  false,
+			TitleVariants:    0,
+			VariantStrategy:  "epsilon_greedy",
+			ExploreRate:      0.2,
+			StatsCron:        "-",
+			StatsWindowDays:  7,
+		},
+
+		// 分段章节生成配置（默认关闭）
+		ChapterConfig: &ChapterConfig{
+			Enabled:            false,
+			MinVideoMinutes:    10,
+			MaxChapters:        12,
+			MinChapterSeconds:  60,
+			MaxTitleLength:     20,
+			MaxTranscriptChars: 12000,
+		},
+
+		// AI调用用量统计配置（价格为各服务公开的标准价格，可在 config.toml 中覆盖）
+		AIUsageConfig: &AIUsageConfig{
+			Enabled:  true,
+			Currency: "USD",
+			Prices: []ModelPrice{
+				{Model: "deepseek-chat", InputPerMillion: 0.27, OutputPerMillion: 1.10},
+				{Model: "deepseek-reasoner", InputPerMillion: 0.55, OutputPerMillion: 2.19},
+				{Model: "gpt-4o-mini*", InputPerMillion: 0.15, OutputPerMillion: 0.60},
+				{Model: "gpt-4o*", InputPerMillion: 2.50, OutputPerMillion: 10.00},
+				{Model: "gpt-3.5-turbo*", InputPerMillion: 0.50, OutputPerMillion: 1.50},
+				{Model: "gemini-1.5-flash*", InputPerMillion: 0.075, OutputPerMillion: 0.30},
+				{Model: "gemini-1.5-pro*", InputPerMillion: 1.25, OutputPerMillion: 5.00},
+				{Provider: "google", CharsPerMillion: 20},
+				{Provider: "microsoft", CharsPerMillion: 10},
+			},
 		},
 
 		// 会员系统配置（默认值，可被 config.toml 覆盖）
@@ -362,21 +910,38 @@ func LoadConfig(configFile string) (*AppConfig, error) {
 
 	// 创建临时结构体用于读取 config.toml（只包含可配置字段）
 	var fileConfig struct {
-		Listen                 string                  `toml:"listen"`
-		Environment            string                  `toml:"environment"`
-		Debug                  bool                    `toml:"debug"`
-		Database               Database                `toml:"database"`
-		Auth                   AuthConfig              `toml:"auth"`
-		FileUpDir              string                  `toml:"fileUpDir"`
-		YtDlpPath              string                  `toml:"yt_dlp_path"`
-		TenCosConfig           *TencentCosConfig       `toml:"TenCosConfig"`
-		DeepSeekTransConfig    *DeepSeekTransConfig    `toml:"DeepSeekTransConfig"`
-		GeminiConfig           *GeminiConfig           `toml:"GeminiConfig"`
-		OpenAICompatibleConfig *OpenAICompatibleConfig `toml:"OpenAICompatibleConfig"`
-		ProxyConfig            *ProxyConfig            `toml:"ProxyConfig"`
-		AnalyticsConfig        *AnalyticsConfig        `toml:"AnalyticsConfig"`
-		BilibiliConfig         *BilibiliConfig         `toml:"BilibiliConfig"`
-		MembershipConfig       *MembershipConfig       `toml:"MembershipConfig"`
+		Listen                   string                    `toml:"listen"`
+		Environment              string                    `toml:"environment"`
+		Debug                    bool                      `toml:"debug"`
+		Database                 Database                  `toml:"database"`
+		Auth                     AuthConfig                `toml:"auth"`
+		FileUpDir                string                    `toml:"fileUpDir"`
+		YtDlpPath                string                    `toml:"yt_dlp_path"`
+		TenCosConfig             *TencentCosConfig         `toml:"TenCosConfig"`
+		BaiduTransConfig         *BaiduTransConfig         `toml:"BaiduTransConfig"`
+		DeepSeekTransConfig      *DeepSeekTransConfig      `toml:"DeepSeekTransConfig"`
+		OllamaTransConfig        *OllamaTransConfig        `toml:"OllamaTransConfig"`
+		GoogleTransConfig        *GoogleTransConfig        `toml:"GoogleTransConfig"`
+		MicrosoftTransConfig     *MicrosoftTransConfig     `toml:"MicrosoftTransConfig"`
+		TencentTransConfig       *TencentTransConfig       `toml:"TencentTransConfig"`
+		GeminiConfig             *GeminiConfig             `toml:"GeminiConfig"`
+		OpenAICompatibleConfig   *OpenAICompatibleConfig   `toml:"OpenAICompatibleConfig"`
+		ProxyConfig              *ProxyConfig              `toml:"ProxyConfig"`
+		AnalyticsConfig          *AnalyticsConfig          `toml:"AnalyticsConfig"`
+		BilibiliConfig           *BilibiliConfig           `toml:"BilibiliConfig"`
+		MembershipConfig         *MembershipConfig         `toml:"MembershipConfig"`
+		TranscriberConfig        *TranscriberConfig        `toml:"TranscriberConfig"`
+		CookieConfig             *CookieConfig             `toml:"CookieConfig"`
+		YtDlpConfig              *YtDlpConfig              `toml:"YtDlpConfig"`
+		SubtitleConfig           *SubtitleConfig           `toml:"SubtitleConfig"`
+		HardSubConfig            *HardSubConfig            `toml:"HardSubConfig"`
+		TranslatorConfig         *TranslatorConfig         `toml:"TranslatorConfig"`
+		TranslationQualityConfig *TranslationQualityConfig `toml:"TranslationQualityConfig"`
+		AIFailoverConfig         *AIFailoverConfig         `toml:"AIFailoverConfig"`
+		AIUsageConfig            *AIUsageConfig            `toml:"AIUsageConfig"`
+		AIKeyPoolConfig          *AIKeyPoolConfig          `toml:"AIKeyPoolConfig"`
+		MetadataConfig           *MetadataConfig           `toml:"MetadataConfig"`
+		ChapterConfig            *ChapterConfig            `toml:"ChapterConfig"`
 	}
 
 	// 解码TOML配置文件
@@ -396,9 +961,24 @@ func LoadConfig(configFile string) (*AppConfig, error) {
 	if fileConfig.TenCosConfig != nil {
 		config.TenCosConfig = fileConfig.TenCosConfig
 	}
+	if fileConfig.BaiduTransConfig != nil {
+		config.BaiduTransConfig = fileConfig.BaiduTransConfig
+	}
 	if fileConfig.DeepSeekTransConfig != nil {
 		config.DeepSeekTransConfig = fileConfig.DeepSeekTransConfig
 	}
+	if fileConfig.OllamaTransConfig != nil {
+		config.OllamaTransConfig = fileConfig.OllamaTransConfig
+	}
+	if fileConfig.GoogleTransConfig != nil {
+		config.GoogleTransConfig = fileConfig.GoogleTransConfig
+	}
+	if fileConfig.MicrosoftTransConfig != nil {
+		config.MicrosoftTransConfig = fileConfig.MicrosoftTransConfig
+	}
+	if fileConfig.TencentTransConfig != nil {
+		config.TencentTransConfig = fileConfig.TencentTransConfig
+	}
 	if fileConfig.GeminiConfig != nil {
 		config.GeminiConfig = fileConfig.GeminiConfig
 	}
@@ -417,6 +997,42 @@ func LoadConfig(configFile string) (*AppConfig, error) {
 	if fileConfig.MembershipConfig != nil {
 		config.MembershipConfig = fileConfig.MembershipConfig
 	}
+	if fileConfig.TranscriberConfig != nil {
+		config.TranscriberConfig = fileConfig.TranscriberConfig
+	}
+	if fileConfig.CookieConfig != nil {
+		config.CookieConfig = fileConfig.CookieConfig
+	}
+	if fileConfig.YtDlpConfig != nil {
+		config.YtDlpConfig = fileConfig.YtDlpConfig
+	}
+	if fileConfig.SubtitleConfig != nil {
+		config.SubtitleConfig = fileConfig.SubtitleConfig
+	}
+	if fileConfig.HardSubConfig != nil {
+		config.HardSubConfig = fileConfig.HardSubConfig
+	}
+	if fileConfig.TranslatorConfig != nil {
+		config.TranslatorConfig = fileConfig.TranslatorConfig
+	}
+	if fileConfig.TranslationQualityConfig != nil {
+		config.TranslationQualityConfig = fileConfig.TranslationQualityConfig
+	}
+	if fileConfig.AIFailoverConfig != nil {
+		config.AIFailoverConfig = fileConfig.AIFailoverConfig
+	}
+	if fileConfig.AIUsageConfig != nil {
+		config.AIUsageConfig = fileConfig.AIUsageConfig
+	}
+	if fileConfig.AIKeyPoolConfig != nil {
+		config.AIKeyPoolConfig = fileConfig.AIKeyPoolConfig
+	}
+	if fileConfig.MetadataConfig != nil {
+		config.MetadataConfig = fileConfig.MetadataConfig
+	}
+	if fileConfig.ChapterConfig != nil {
+		config.ChapterConfig = fileConfig.ChapterConfig
+	}
 
 	return config, nil
 }
@@ -425,37 +1041,71 @@ func LoadConfig(configFile string) (*AppConfig, error) {
 func SaveConfig(config *AppConfig) error {
 	// 只保存用户可配置的字段
 	fileConfig := struct {
-		Listen                 string                  `toml:"listen"`
-		Environment            string                  `toml:"environment"`
-		Debug                  bool                    `toml:"debug"`
-		Database               Database                `toml:"database"`
-		Auth                   AuthConfig              `toml:"auth"`
-		FileUpDir              string                  `toml:"fileUpDir"`
-		YtDlpPath              string                  `toml:"yt_dlp_path"`
-		TenCosConfig           *TencentCosConfig       `toml:"TenCosConfig"`
-		DeepSeekTransConfig    *DeepSeekTransConfig    `toml:"DeepSeekTransConfig"`
-		GeminiConfig           *GeminiConfig           `toml:"GeminiConfig"`
-		OpenAICompatibleConfig *OpenAICompatibleConfig `toml:"OpenAICompatibleConfig"`
-		ProxyConfig            *ProxyConfig            `toml:"ProxyConfig"`
-		AnalyticsConfig        *AnalyticsConfig        `toml:"AnalyticsConfig"`
-		BilibiliConfig         *BilibiliConfig         `toml:"BilibiliConfig"`
-		MembershipConfig       *MembershipConfig       `toml:"MembershipConfig"`
+		Listen                   string                    `toml:"listen"`
+		Environment              string                    `toml:"environment"`
+		Debug                    bool                      `toml:"debug"`
+		Database                 Database                  `toml:"database"`
+		Auth                     AuthConfig                `toml:"auth"`
+		FileUpDir                string                    `toml:"fileUpDir"`
+		YtDlpPath                string                    `toml:"yt_dlp_path"`
+		TenCosConfig             *TencentCosConfig         `toml:"TenCosConfig"`
+		BaiduTransConfig         *BaiduTransConfig         `toml:"BaiduTransConfig"`
+		DeepSeekTransConfig      *DeepSeekTransConfig      `toml:"DeepSeekTransConfig"`
+		OllamaTransConfig        *OllamaTransConfig        `toml:"OllamaTransConfig"`
+		GoogleTransConfig        *GoogleTransConfig        `toml:"GoogleTransConfig"`
+		MicrosoftTransConfig     *MicrosoftTransConfig     `toml:"MicrosoftTransConfig"`
+		TencentTransConfig       *TencentTransConfig       `toml:"TencentTransConfig"`
+		GeminiConfig             *GeminiConfig             `toml:"GeminiConfig"`
+		OpenAICompatibleConfig   *OpenAICompatibleConfig   `toml:"OpenAICompatibleConfig"`
+		ProxyConfig              *ProxyConfig              `toml:"ProxyConfig"`
+		AnalyticsConfig          *AnalyticsConfig          `toml:"AnalyticsConfig"`
+		BilibiliConfig           *BilibiliConfig           `toml:"BilibiliConfig"`
+		MembershipConfig         *MembershipConfig         `toml:"MembershipConfig"`
+		TranscriberConfig        *TranscriberConfig        `toml:"TranscriberConfig"`
+		CookieConfig             *CookieConfig             `toml:"CookieConfig"`
+		YtDlpConfig              *YtDlpConfig              `toml:"YtDlpConfig"`
+		SubtitleConfig           *SubtitleConfig           `toml:"SubtitleConfig"`
+		HardSubConfig            *HardSubConfig            `toml:"HardSubConfig"`
+		TranslatorConfig         *TranslatorConfig         `toml:"TranslatorConfig"`
+		TranslationQualityConfig *TranslationQualityConfig `toml:"TranslationQualityConfig"`
+		AIFailoverConfig         *AIFailoverConfig         `toml:"AIFailoverConfig"`
+		AIUsageConfig            *AIUsageConfig            `toml:"AIUsageConfig"`
+		AIKeyPoolConfig          *AIKeyPoolConfig          `toml:"AIKeyPoolConfig"`
+		MetadataConfig           *MetadataConfig           `toml:"MetadataConfig"`
+		ChapterConfig            *ChapterConfig            `toml:"ChapterConfig"`
 	}{
-		Listen:                 config.Listen,
-		Environment:            config.Environment,
-		Debug:                  config.Debug,
-		Database:               config.Database,
-		Auth:                   config.Auth,
-		FileUpDir:              config.FileUpDir,
-		YtDlpPath:              config.YtDlpPath,
-		TenCosConfig:           config.TenCosConfig,
-		DeepSeekTransConfig:    config.DeepSeekTransConfig,
-		GeminiConfig:           config.GeminiConfig,
-		OpenAICompatibleConfig: config.OpenAICompatibleConfig,
-		ProxyConfig:            config.ProxyConfig,
-		AnalyticsConfig:        config.AnalyticsConfig,
-		BilibiliConfig:         config.BilibiliConfig,
-		MembershipConfig:       config.MembershipConfig,
+		Listen:                   config.Listen,
+		Environment:              config.Environment,
+		Debug:                    config.Debug,
+		Database:                 config.Database,
+		Auth:                     config.Auth,
+		FileUpDir:                config.FileUpDir,
+		YtDlpPath:                config.YtDlpPath,
+		TenCosConfig:             config.TenCosConfig,
+		BaiduTransConfig:         config.BaiduTransConfig,
+		DeepSeekTransConfig:      config.DeepSeekTransConfig,
+		OllamaTransConfig:        config.OllamaTransConfig,
+		GoogleTransConfig:        config.GoogleTransConfig,
+		MicrosoftTransConfig:     config.MicrosoftTransConfig,
+		TencentTransConfig:       config.TencentTransConfig,
+		GeminiConfig:             config.GeminiConfig,
+		OpenAICompatibleConfig:   config.OpenAICompatibleConfig,
+		ProxyConfig:              config.ProxyConfig,
+		AnalyticsConfig:          config.AnalyticsConfig,
+		BilibiliConfig:           config.BilibiliConfig,
+		MembershipConfig:         config.MembershipConfig,
+		TranscriberConfig:        config.TranscriberConfig,
+		CookieConfig:             config.CookieConfig,
+		YtDlpConfig:              config.YtDlpConfig,
+		SubtitleConfig:           config.SubtitleConfig,
+		HardSubConfig:            config.HardSubConfig,
+		TranslatorConfig:         config.TranslatorConfig,
+		TranslationQualityConfig: config.TranslationQualityConfig,
+		AIFailoverConfig:         config.AIFailoverConfig,
+		AIUsageConfig:            config.AIUsageConfig,
+		AIKeyPoolConfig:          config.AIKeyPoolConfig,
+		MetadataConfig:           config.MetadataConfig,
+		ChapterConfig:            config.ChapterConfig,
 	}
 
 	buf := new(bytes.Buffer)
@@ -463,7 +1113,6 @@ func SaveConfig(config *AppConfig) error {
 	// 写入注释说明
 	buf.WriteString("# Bilibili 视频上传后端 - 配置文件\n\n")
 	buf.WriteString("# 注意：以下配置已硬编码在代码中，无需在此配置：\n")
-	buf.WriteString("# - BaiduTransConfig (百度翻译)\n")
 	buf.WriteString("# - app_auth (应用认证)\n")
 	buf.WriteString("# \n")
 	buf.WriteString("# 所有配置都可以通过 config.toml 或 API 接口动态配置\n\n")
@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/services"
+	"github.com/difyz9/ytb2bili/internal/membership"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AIUsageHandler struct {
+	BaseHandler
+	UsageService *services.AIUsageService
+	Quota        *membership.QuotaService
+}
+
+func NewAIUsageHandler(app *core.AppServer, usageService *services.AIUsageService, quota *membership.QuotaService) *AIUsageHandler {
+	return &AIUsageHandler{
+		BaseHandler:  BaseHandler{App: app},
+		UsageService: usageService,
+		Quota:        quota,
+	}
+}
+
+// RegisterRoutes 注册AI用量统计路由
+func (h *AIUsageHandler) RegisterRoutes(server *core.AppServer) {
+	api := server.Engine.Group("/api/v1")
+
+	usage := api.Group("/ai-usage")
+	{
+		usage.GET("/records", h.listRecords)
+		usage.GET("/summary", h.getSummary)
+		usage.GET("/videos/:videoId", h.getVideoUsage)
+		usage.GET("/users/:userId", h.getUserUsage)
+	}
+}
+
+// parseFilter 解析查询条件，日期格式为 2006-01-02
+func (h *AIUsageHandler) parseFilter(c *gin.Context) (services.UsageFilter, bool) {
+	var filter services.UsageFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid query parameters: " + err.Error(),
+		})
+		return filter, false
+	}
+	for _, day := range []string{filter.From, filter.To} {
+		if day == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", day); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    400,
+				"message": "日期格式应为 YYYY-MM-DD: " + day,
+			})
+			return filter, false
+		}
+	}
+	return filter, true
+}
+
+// listRecords 分页查询用量记录，可按 video_id、user_id、provider、from、to 筛选
+func (h *AIUsageHandler) listRecords(c *gin.Context) {
+	filter, ok := h.parseFilter(c)
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 200 {
+		pageSize = 20
+	}
+
+	records, total, err := h.UsageService.ListRecords(filter, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "获取用量记录失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data": gin.H{
+			"records":   records,
+			"total":     total,
+			"page":      page,
+			"page_size": pageSize,
+		},
+	})
+}
+
+// getSummary 按 group_by（video、day、user、step、provider、model，默认 day）汇总用量
+func (h *AIUsageHandler) getSummary(c *gin.Context) {
+	filter, ok := h.parseFilter(c)
+	if !ok {
+		return
+	}
+	groupBy := c.DefaultQuery("group_by", "day")
+
+	summaries, err := h.UsageService.Summarize(groupBy, filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": err.Error(),
+		})
+		return
+	}
+	total, err := h.UsageService.Total(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "汇总用量失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data": gin.H{
+			"group_by": groupBy,
+			"items":    summaries,
+			"total":    total,
+		},
+	})
+}
+
+// getVideoUsage 单个视频的用量（合计和按步骤、服务拆分）
+func (h *AIUsageHandler) getVideoUsage(c *gin.Context) {
+	filter := services.UsageFilter{VideoID: c.Param("videoId")}
+
+	total, err := h.UsageService.Total(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "获取视频用量失败: " + err.Error(),
+		})
+		return
+	}
+	bySteps, _ := h.UsageService.Summarize("step", filter)
+	byProviders, _ := h.UsageService.Summarize("provider", filter)
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data": gin.H{
+			"video_id":    filter.VideoID,
+			"total":       total,
+			"by_step":     bySteps,
+			"by_provider": byProviders,
+		},
+	})
+}
+
+// getUserUsage 用户今日和本月的用量，以及会员配额（视频处理配额）
+func (h *AIUsageHandler) getUserUsage(c *gin.Context) {
+	userID := c.Param("userId")
+	now := time.Now()
+	today := now.Format("2006-01-02")
+	monthStart := now.Format("2006-01") + "-01"
+
+	todayUsage, err := h.UsageService.Total(services.UsageFilter{UserID: userID, From: today, To: today})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "获取用户用量失败: " + err.Error(),
+		})
+		return
+	}
+	monthUsage, _ := h.UsageService.Total(services.UsageFilter{UserID: userID, From: monthStart, To: today})
+	byDays, _ := h.UsageService.Summarize("day", services.UsageFilter{UserID: userID, From: monthStart, To: today})
+
+	data := gin.H{
+		"user_id": userID,
+		"today":   todayUsage,
+		"month":   monthUsage,
+		"by_day":  byDays,
+	}
+	if h.Quota != nil {
+		if quota, err := h.Quota.GetQuotaInfo(c.Request.Context(), userID); err == nil {
+			data["quota"] = quota
+			// 今日每个已处理视频的平均AI费用，便于评估会员配额的成本
+			if quota.DailyUsed > 0 {
+				data["cost_per_video"] = todayUsage.Cost / float64(quota.DailyUsed)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data":    data,
+	})
+}
@@ -1,18 +1,18 @@
 package handler
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/services"
 	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/llm"
+	"github.com/difyz9/ytb2bili/pkg/proxypool"
 	"github.com/google/generative-ai-go/genai"
 	"google.golang.org/api/option"
 
@@ -39,6 +39,8 @@ func (h *ConfigHandler) RegisterRoutes(server *core.AppServer) {
 		config.PUT("/deepseek", h.updateDeepSeekConfig)
 		config.GET("/proxy", h.getProxyConfig)
 		config.PUT("/proxy", h.updateProxyConfig)
+		config.GET("/proxy/stats", h.getProxyStats)
+		config.POST("/proxy/check", h.checkProxies)
 
 		// OpenAI兼容API配置
 		config.GET("/openai-compatible", h.getOpenAICompatibleConfig)
@@ -49,6 +51,9 @@ func (h *ConfigHandler) RegisterRoutes(server *core.AppServer) {
 		// AI服务状态
 		config.GET("/ai-services/status", h.getAIServicesStatus)
 		config.PUT("/ai-services/primary", h.setPrimaryAIService)
+		config.POST("/ai-services/probe", h.probeAIServices)
+		config.POST("/ai-services/:provider/reset", h.resetAIServiceBreaker)
+		config.GET("/ai-services/keys", h.getAIServiceKeys)
 
 		// Gemini原生配置（用于元数据生成）
 		config.GET("/gemini", h.getGeminiConfig)
@@ -60,34 +65,83 @@ func (h *ConfigHandler) RegisterRoutes(server *core.AppServer) {
 
 // DeepSeekConfigRequest DeepSeek配置请求
 type DeepSeekConfigRequest struct {
-	Enabled   *bool   `json:"enabled,omitempty"`    // 是否启用（可选）
-	ApiKey    *string `json:"api_key,omitempty"`    // API Key（可选）
-	Model     *string `json:"model,omitempty"`      // 模型（可选）
-	Endpoint  *string `json:"endpoint,omitempty"`   // 端点（可选）
-	Timeout   *int    `json:"timeout,omitempty"`    // 超时时间（可选）
-	MaxTokens *int    `json:"max_tokens,omitempty"` // 最大Token数（可选）
+	Enabled      *bool    `json:"enabled,omitempty"`        // 是否启用（可选）
+	ApiKey       *string  `json:"api_key,omitempty"`        // API Key（可选）
+	ApiKeys      []string `json:"api_keys,omitempty"`       // 多个 API Key（用于轮换）
+	ClearApiKeys *bool    `json:"clear_api_keys,omitempty"` // 是否清空所有 API Keys
+	Model        *string  `json:"model,omitempty"`          // 模型（可选）
+	Endpoint     *string  `json:"endpoint,omitempty"`       // 端点（可选）
+	Timeout      *int     `json:"timeout,omitempty"`        // 超时时间（可选）
+	MaxTokens    *int     `json:"max_tokens,omitempty"`     // 最大Token数（可选）
 }
 
 // DeepSeekConfigResponse DeepSeek配置响应
 type DeepSeekConfigResponse struct {
-	Enabled   bool   `json:"enabled"`
-	ApiKey    string `json:"api_key"` // 为了安全只返回部分字符
-	Model     string `json:"model"`
-	Endpoint  string `json:"endpoint"`
-	Timeout   int    `json:"timeout"`
-	MaxTokens int    `json:"max_tokens"`
+	Enabled      bool     `json:"enabled"`
+	ApiKey       string   `json:"api_key"`        // 为了安全只返回部分字符
+	ApiKeys      []string `json:"api_keys"`       // 多个 API Key（脱敏显示）
+	ApiKeysCount int      `json:"api_keys_count"` // API Key 数量
+	Model        string   `json:"model"`
+	Endpoint     string   `json:"endpoint"`
+	Timeout      int      `json:"timeout"`
+	MaxTokens    int      `json:"max_tokens"`
 }
 
 // ProxyConfigRequest 代理配置请求
 type ProxyConfigRequest struct {
-	UseProxy  *bool   `json:"useProxy,omitempty"`  // 是否使用代理（可选）
-	ProxyHost *string `json:"proxyHost,omitempty"` // 代理地址（可选）
+	UseProxy            *bool     `json:"useProxy,omitempty"`            // 是否使用代理（可选）
+	ProxyHost           *string   `json:"proxyHost,omitempty"`           // 代理地址（可选）
+	Proxies             *[]string `json:"proxies,omitempty"`             // 代理池（可选）
+	ProxyDomains        *[]string `json:"proxyDomains,omitempty"`        // 走代理的域名（可选）
+	DirectDomains       *[]string `json:"directDomains,omitempty"`       // 直连的域名（可选）
+	HealthCheckURL      *string   `json:"healthCheckUrl,omitempty"`      // 健康检查地址（可选）
+	HealthCheckInterval *int      `json:"healthCheckInterval,omitempty"` // 健康检查间隔，秒（可选）
+	CooldownSeconds     *int      `json:"cooldownSeconds,omitempty"`     // 429/403 后的冷却时间，秒（可选）
+	DirectFallback      *bool     `json:"directFallback,omitempty"`      // 代理全部失败后是否直连重试（可选）
 }
 
 // ProxyConfigResponse 代理配置响应
 type ProxyConfigResponse struct {
-	UseProxy  bool   `json:"useProxy"`  // 是否使用代理
-	ProxyHost string `json:"proxyHost"` // 代理地址
+	UseProxy            bool                   `json:"useProxy"`            // 是否使用代理
+	ProxyHost           string                 `json:"proxyHost"`           // 代理地址
+	Proxies             []string               `json:"proxies"`             // 代理池
+	ProxyDomains        []string               `json:"proxyDomains"`        // 走代理的域名
+	DirectDomains       []string               `json:"directDomains"`       // 直连的域名
+	HealthCheckURL      string                 `json:"healthCheckUrl"`      // 健康检查地址
+	HealthCheckInterval int                    `json:"healthCheckInterval"` // 健康检查间隔（秒）
+	CooldownSeconds     int                    `json:"cooldownSeconds"`     // 429/403 后的冷却时间（秒）
+	DirectFallback      bool                   `json:"directFallback"`      // 代理全部失败后是否直连重试
+	Stats               []proxypool.EntryStats `json:"stats"`               // 代理池统计
+}
+
+// newProxyConfigResponse 构建代理配置响应
+func (h *ConfigHandler) newProxyConfigResponse(config *types.ProxyConfig) ProxyConfigResponse {
+	resp := ProxyConfigResponse{
+		Proxies:       []string{},
+		ProxyDomains:  []string{},
+		DirectDomains: proxypool.DefaultDirectDomains,
+		Stats:         h.App.ProxyPool.Stats(),
+	}
+	if config == nil {
+		return resp
+	}
+
+	resp.UseProxy = config.UseProxy
+	resp.ProxyHost = config.ProxyHost
+	resp.HealthCheckURL = config.HealthCheckURL
+	resp.HealthCheckInterval = config.HealthCheckInterval
+	resp.CooldownSeconds = config.CooldownSeconds
+	resp.DirectFallback = config.DirectFallback
+	if config.Proxies != nil {
+		resp.Proxies = config.Proxies
+	}
+	if config.ProxyDomains != nil {
+		resp.ProxyDomains = config.ProxyDomains
+	}
+	if config.DirectDomains != nil {
+		resp.DirectDomains = config.DirectDomains
+	}
+	return resp
 }
 
 // getDeepSeekConfig 获取DeepSeek配置
@@ -123,12 +177,14 @@ func (h *ConfigHandler) getDeepSeekConfig(c *gin.Context) {
 		"code":    200,
 		"message": "success",
 		"data": DeepSeekConfigResponse{
-			Enabled:   config.Enabled,
-			ApiKey:    apiKeyMasked,
-			Model:     config.Model,
-			Endpoint:  config.Endpoint,
-			Timeout:   config.Timeout,
-			MaxTokens: config.MaxTokens,
+			Enabled:      config.Enabled,
+			ApiKey:       apiKeyMasked,
+			ApiKeys:      maskApiKeys(config.ApiKeys),
+			ApiKeysCount: len(config.Keys()),
+			Model:        config.Model,
+			Endpoint:     config.Endpoint,
+			Timeout:      config.Timeout,
+			MaxTokens:    config.MaxTokens,
 		},
 	})
 }
@@ -169,6 +225,14 @@ func (h *ConfigHandler) updateDeepSeekConfig(c *gin.Context) {
 		h.App.Logger.Infof("Updated DeepSeek API Key: %s", maskApiKey(*req.ApiKey))
 	}
 
+	if req.ClearApiKeys != nil && *req.ClearApiKeys {
+		config.ApiKeys = nil
+		h.App.Logger.Info("Cleared DeepSeek API Keys")
+	} else if len(req.ApiKeys) > 0 {
+		config.ApiKeys = req.ApiKeys
+		h.App.Logger.Infof("Updated DeepSeek API Keys: %d keys", len(req.ApiKeys))
+	}
+
 	if req.Model != nil {
 		config.Model = *req.Model
 		h.App.Logger.Infof("Updated DeepSeek model: %s", config.Model)
@@ -208,39 +272,43 @@ func (h *ConfigHandler) updateDeepSeekConfig(c *gin.Context) {
 		"code":    200,
 		"message": "Configuration updated and applied successfully (no restart required)",
 		"data": DeepSeekConfigResponse{
-			Enabled:   config.Enabled,
-			ApiKey:    maskApiKey(config.ApiKey),
-			Model:     config.Model,
-			Endpoint:  config.Endpoint,
-			Timeout:   config.Timeout,
-			MaxTokens: config.MaxTokens,
+			Enabled:      config.Enabled,
+			ApiKey:       maskApiKey(config.ApiKey),
+			ApiKeys:      maskApiKeys(config.ApiKeys),
+			ApiKeysCount: len(config.Keys()),
+			Model:        config.Model,
+			Endpoint:     config.Endpoint,
+			Timeout:      config.Timeout,
+			MaxTokens:    config.MaxTokens,
 		},
 	})
 }
 
 // getProxyConfig 获取代理配置
 func (h *ConfigHandler) getProxyConfig(c *gin.Context) {
-	// 检查配置中是否有代理配置
-	proxyConfig := h.App.Config.ProxyConfig
-	if proxyConfig == nil {
-		c.JSON(http.StatusOK, gin.H{
-			"code":    200,
-			"message": "success",
-			"data": ProxyConfigResponse{
-				UseProxy:  false,
-				ProxyHost: "",
-			},
-		})
-		return
-	}
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data":    h.newProxyConfigResponse(h.App.Config.ProxyConfig),
+	})
+}
 
+// getProxyStats 获取代理池统计信息
+func (h *ConfigHandler) getProxyStats(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"code":    200,
 		"message": "success",
-		"data": ProxyConfigResponse{
-			UseProxy:  proxyConfig.UseProxy,
-			ProxyHost: proxyConfig.ProxyHost,
-		},
+		"data":    h.App.ProxyPool.Stats(),
+	})
+}
+
+// checkProxies 立即对代理池执行健康检查
+func (h *ConfigHandler) checkProxies(c *gin.Context) {
+	h.App.ProxyPool.CheckAll()
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data":    h.App.ProxyPool.Stats(),
 	})
 }
 
@@ -276,6 +344,36 @@ func (h *ConfigHandler) updateProxyConfig(c *gin.Context) {
 		h.App.Logger.Infof("Updated proxy host: %s", config.ProxyHost)
 	}
 
+	if req.Proxies != nil {
+		config.Proxies = *req.Proxies
+		h.App.Logger.Infof("Updated proxy pool: %d proxies", len(config.Proxies))
+	}
+
+	if req.ProxyDomains != nil {
+		config.ProxyDomains = *req.ProxyDomains
+	}
+
+	if req.DirectDomains != nil {
+		config.DirectDomains = *req.DirectDomains
+	}
+
+	if req.HealthCheckURL != nil {
+		config.HealthCheckURL = *req.HealthCheckURL
+	}
+
+	if req.HealthCheckInterval != nil {
+		config.HealthCheckInterval = *req.HealthCheckInterval
+	}
+
+	if req.CooldownSeconds != nil {
+		config.CooldownSeconds = *req.CooldownSeconds
+	}
+
+	if req.DirectFallback != nil {
+		config.DirectFallback = *req.DirectFallback
+		h.App.Logger.Infof("Updated proxy direct fallback: %v", config.DirectFallback)
+	}
+
 	// 保存配置到文件
 	if err := types.SaveConfig(h.App.Config); err != nil {
 		h.App.Logger.Errorf("Failed to save config: %v", err)
@@ -288,16 +386,17 @@ func (h *ConfigHandler) updateProxyConfig(c *gin.Context) {
 
 	// 实时更新应用服务器的配置（不需要重启）
 	h.App.Config.ProxyConfig = config
+	h.App.ProxyPool.Reload(config)
+	if h.App.ProxyPool.Enabled() {
+		h.App.ProxyPool.Start()
+	}
 	h.App.Logger.Info("✅ Proxy configuration updated and applied successfully (no restart required)")
 
 	// 返回成功响应
 	c.JSON(http.StatusOK, gin.H{
 		"code":    200,
 		"message": "Configuration updated and applied successfully (no restart required)",
-		"data": ProxyConfigResponse{
-			UseProxy:  config.UseProxy,
-			ProxyHost: config.ProxyHost,
-		},
+		"data":    h.newProxyConfigResponse(config),
 	})
 }
 
@@ -312,30 +411,47 @@ func maskApiKey(apiKey string) string {
 	return "***"
 }
 
+// maskApiKeys 隐藏多个API Key的敏感信息
+func maskApiKeys(apiKeys []string) []string {
+	masked := make([]string, len(apiKeys))
+	for i, key := range apiKeys {
+		masked[i] = maskApiKey(key)
+	}
+	return masked
+}
+
 // ========== OpenAI兼容API配置 ==========
 
 // OpenAICompatibleConfigRequest OpenAI兼容API配置请求
 type OpenAICompatibleConfigRequest struct {
-	Enabled     *bool    `json:"enabled,omitempty"`
-	Provider    *string  `json:"provider,omitempty"`
-	ApiKey      *string  `json:"api_key,omitempty"`
-	BaseURL     *string  `json:"base_url,omitempty"`
-	Model       *string  `json:"model,omitempty"`
-	Timeout     *int     `json:"timeout,omitempty"`
-	MaxTokens   *int     `json:"max_tokens,omitempty"`
-	Temperature *float64 `json:"temperature,omitempty"`
+	Enabled      *bool    `json:"enabled,omitempty"`
+	Provider     *string  `json:"provider,omitempty"`
+	ApiKey       *string  `json:"api_key,omitempty"`
+	ApiKeys      []string `json:"api_keys,omitempty"`       // 多个 API Key（用于轮换）
+	ClearApiKeys *bool    `json:"clear_api_keys,omitempty"` // 是否清空所有 API Keys
+	BaseURL      *string  `json:"base_url,omitempty"`
+	Model        *string  `json:"model,omitempty"`
+	Timeout      *int     `json:"timeout,omitempty"`
+	MaxTokens    *int     `json:"max_tokens,omitempty"`
+	Temperature  *float64 `json:"temperature,omitempty"`
+
+	StructuredOutput *bool `json:"structured_output,omitempty"` // 模型是否支持 JSON Schema 结构化输出
 }
 
 // OpenAICompatibleConfigResponse OpenAI兼容API配置响应
 type OpenAICompatibleConfigResponse struct {
-	Enabled     bool    `json:"enabled"`
-	Provider    string  `json:"provider"`
-	ApiKey      string  `json:"api_key"` // 脱敏显示
-	BaseURL     string  `json:"base_url"`
-	Model       string  `json:"model"`
-	Timeout     int     `json:"timeout"`
-	MaxTokens   int     `json:"max_tokens"`
-	Temperature float64 `json:"temperature"`
+	Enabled      bool     `json:"enabled"`
+	Provider     string   `json:"provider"`
+	ApiKey       string   `json:"api_key"`        // 脱敏显示
+	ApiKeys      []string `json:"api_keys"`       // 多个 API Key（脱敏显示）
+	ApiKeysCount int      `json:"api_keys_count"` // API Key 数量
+	BaseURL      string   `json:"base_url"`
+	Model        string   `json:"model"`
+	Timeout      int      `json:"timeout"`
+	MaxTokens    int      `json:"max_tokens"`
+	Temperature  float64  `json:"temperature"`
+
+	StructuredOutput bool `json:"structured_output"`
 }
 
 // OpenAIProviderInfo 提供商信息
@@ -390,14 +506,18 @@ func (h *ConfigHandler) getOpenAICompatibleConfig(c *gin.Context) {
 		"code":    200,
 		"message": "success",
 		"data": OpenAICompatibleConfigResponse{
-			Enabled:     config.Enabled,
-			Provider:    config.Provider,
-			ApiKey:      maskApiKey(config.ApiKey),
-			BaseURL:     config.BaseURL,
-			Model:       config.Model,
-			Timeout:     config.Timeout,
-			MaxTokens:   config.MaxTokens,
-			Temperature: config.Temperature,
+			Enabled:      config.Enabled,
+			Provider:     config.Provider,
+			ApiKey:       maskApiKey(config.ApiKey),
+			ApiKeys:      maskApiKeys(config.ApiKeys),
+			ApiKeysCount: len(config.Keys()),
+			BaseURL:      config.BaseURL,
+			Model:        config.Model,
+			Timeout:      config.Timeout,
+			MaxTokens:    config.MaxTokens,
+			Temperature:  config.Temperature,
+
+			StructuredOutput: config.StructuredOutput,
 		},
 	})
 }
@@ -442,6 +562,13 @@ func (h *ConfigHandler) updateOpenAICompatibleConfig(c *gin.Context) {
 		config.ApiKey = *req.ApiKey
 		h.App.Logger.Infof("Updated OpenAI Compatible API Key: %s", maskApiKey(*req.ApiKey))
 	}
+	if req.ClearApiKeys != nil && *req.ClearApiKeys {
+		config.ApiKeys = nil
+		h.App.Logger.Info("Cleared OpenAI Compatible API Keys")
+	} else if len(req.ApiKeys) > 0 {
+		config.ApiKeys = req.ApiKeys
+		h.App.Logger.Infof("Updated OpenAI Compatible API Keys: %d keys", len(req.ApiKeys))
+	}
 	if req.BaseURL != nil {
 		config.BaseURL = *req.BaseURL
 		h.App.Logger.Infof("Updated OpenAI Compatible base URL: %s", config.BaseURL)
@@ -462,6 +589,10 @@ func (h *ConfigHandler) updateOpenAICompatibleConfig(c *gin.Context) {
 		config.Temperature = *req.Temperature
 		h.App.Logger.Infof("Updated OpenAI Compatible temperature: %f", config.Temperature)
 	}
+	if req.StructuredOutput != nil {
+		config.StructuredOutput = *req.StructuredOutput
+		h.App.Logger.Infof("Updated OpenAI Compatible structured_output: %v", config.StructuredOutput)
+	}
 
 	// 保存配置到文件
 	if err := types.SaveConfig(h.App.Config); err != nil {
@@ -480,14 +611,18 @@ func (h *ConfigHandler) updateOpenAICompatibleConfig(c *gin.Context) {
 		"code":    200,
 		"message": "Configuration updated successfully",
 		"data": OpenAICompatibleConfigResponse{
-			Enabled:     config.Enabled,
-			Provider:    config.Provider,
-			ApiKey:      maskApiKey(config.ApiKey),
-			BaseURL:     config.BaseURL,
-			Model:       config.Model,
-			Timeout:     config.Timeout,
-			MaxTokens:   config.MaxTokens,
-			Temperature: config.Temperature,
+			Enabled:      config.Enabled,
+			Provider:     config.Provider,
+			ApiKey:       maskApiKey(config.ApiKey),
+			ApiKeys:      maskApiKeys(config.ApiKeys),
+			ApiKeysCount: len(config.Keys()),
+			BaseURL:      config.BaseURL,
+			Model:        config.Model,
+			Timeout:      config.Timeout,
+			MaxTokens:    config.MaxTokens,
+			Temperature:  config.Temperature,
+
+			StructuredOutput: config.StructuredOutput,
 		},
 	})
 }
@@ -547,113 +682,27 @@ func (h *ConfigHandler) testOpenAICompatibleAPI(c *gin.Context) {
 func (h *ConfigHandler) doTestOpenAIAPI(req TestAPIRequest) TestAPIResponse {
 	startTime := time.Now()
 
-	// 构建请求
-	requestBody := map[string]interface{}{
-		"model": req.Model,
-		"messages": []map[string]string{
-			{"role": "system", "content": "You are a helpful assistant."},
-			{"role": "user", "content": "Say 'OK' if you can hear me."},
-		},
-		"max_tokens":  50,
-		"temperature": req.Temperature,
-	}
-
-	jsonData, err := json.Marshal(requestBody)
-	if err != nil {
-		return TestAPIResponse{
-			Success: false,
-			Message: "Failed to create request: " + err.Error(),
-		}
-	}
-
-	// 构建URL
-	apiURL := req.BaseURL
-	if !strings.HasSuffix(apiURL, "/v1") && !strings.Contains(apiURL, "/chat/completions") {
-		apiURL = strings.TrimSuffix(apiURL, "/") + "/v1"
-	}
-	if !strings.Contains(apiURL, "/chat/completions") {
-		apiURL = strings.TrimSuffix(apiURL, "/") + "/chat/completions"
-	}
-
-	httpReq, err := http.NewRequest("POST", apiURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return TestAPIResponse{
-			Success: false,
-			Message: "Failed to create HTTP request: " + err.Error(),
-		}
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+req.ApiKey)
-
-	client := &http.Client{
-		Timeout: time.Duration(req.Timeout) * time.Second,
-	}
-
-	resp, err := client.Do(httpReq)
+	provider := llm.NewOpenAICompatible(llm.Config{
+		APIKey:      req.ApiKey,
+		BaseURL:     req.BaseURL,
+		Model:       req.Model,
+		Timeout:     llm.Seconds(req.Timeout),
+		Temperature: req.Temperature,
+	})
+	resp, err := llm.Complete(context.Background(), provider, "You are a helpful assistant.", "Say 'OK' if you can hear me.")
+	latency := time.Since(startTime).Milliseconds()
 	if err != nil {
 		return TestAPIResponse{
 			Success: false,
 			Message: "API request failed: " + err.Error(),
-		}
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return TestAPIResponse{
-			Success: false,
-			Message: "Failed to read response: " + err.Error(),
-		}
-	}
-
-	latency := time.Since(startTime).Milliseconds()
-
-	if resp.StatusCode != http.StatusOK {
-		return TestAPIResponse{
-			Success: false,
-			Message: fmt.Sprintf("API returned error (status %d): %s", resp.StatusCode, string(body)),
-			Latency: latency,
-		}
-	}
-
-	// 解析响应
-	var apiResp struct {
-		Choices []struct {
-			Message struct {
-				Content string `json:"content"`
-			} `json:"message"`
-		} `json:"choices"`
-		Error *struct {
-			Message string `json:"message"`
-		} `json:"error"`
-	}
-
-	if err := json.Unmarshal(body, &apiResp); err != nil {
-		return TestAPIResponse{
-			Success: false,
-			Message: "Failed to parse response: " + err.Error(),
 			Latency: latency,
 		}
 	}
 
-	if apiResp.Error != nil {
-		return TestAPIResponse{
-			Success: false,
-			Message: "API error: " + apiResp.Error.Message,
-			Latency: latency,
-		}
-	}
-
-	responseText := ""
-	if len(apiResp.Choices) > 0 {
-		responseText = apiResp.Choices[0].Message.Content
-	}
-
 	return TestAPIResponse{
 		Success:  true,
 		Message:  "API connection successful",
-		Response: responseText,
+		Response: resp.Content,
 		Latency:  latency,
 	}
 }
@@ -724,6 +773,9 @@ type AIServiceStatusResponse struct {
 	BaseURL   string `json:"base_url,omitempty"`
 	IsPrimary bool   `json:"is_primary"`
 	LastError string `json:"last_error,omitempty"`
+
+	Breaker *services.BreakerStatus `json:"breaker,omitempty"` // 熔断器状态和最近延迟
+	Keys    []llm.KeyStatus         `json:"keys,omitempty"`    // 各 API Key 的用量、限额和冷却状态
 }
 
 // getAIServicesStatus 获取所有AI服务状态
@@ -735,7 +787,7 @@ func (h *ConfigHandler) getAIServicesStatus(c *gin.Context) {
 
 	// 1. OpenAI兼容API
 	openaiConfig := h.App.Config.OpenAICompatibleConfig
-	openaiEnabled := openaiConfig != nil && openaiConfig.Enabled && openaiConfig.ApiKey != ""
+	openaiEnabled := openaiConfig != nil && openaiConfig.Enabled && openaiConfig.HasApiKey()
 	openaiService := AIServiceStatusResponse{
 		Provider:  "openai_compatible",
 		Name:      "自定义API",
@@ -766,7 +818,7 @@ func (h *ConfigHandler) getAIServicesStatus(c *gin.Context) {
 
 	// 2. DeepSeek
 	deepseekConfig := h.App.Config.DeepSeekTransConfig
-	deepseekEnabled := deepseekConfig != nil && deepseekConfig.Enabled && deepseekConfig.ApiKey != ""
+	deepseekEnabled := deepseekConfig != nil && deepseekConfig.Enabled && deepseekConfig.HasApiKey()
 	deepseekService := AIServiceStatusResponse{
 		Provider:  "deepseek",
 		Name:      "DeepSeek",
@@ -782,7 +834,7 @@ func (h *ConfigHandler) getAIServicesStatus(c *gin.Context) {
 
 	// 3. Gemini（原生）
 	geminiConfig := h.App.Config.GeminiConfig
-	geminiEnabled := geminiConfig != nil && geminiConfig.Enabled && (geminiConfig.ApiKey != "" || len(geminiConfig.ApiKeys) > 0)
+	geminiEnabled := geminiConfig != nil && geminiConfig.Enabled && geminiConfig.HasApiKey()
 	geminiService := AIServiceStatusResponse{
 		Provider:  "gemini",
 		Name:      "Gemini（原生多模态）",
@@ -795,6 +847,8 @@ func (h *ConfigHandler) getAIServicesStatus(c *gin.Context) {
 	}
 	services = append(services, geminiService)
 
+	priority := h.applyBreakerStatus(services)
+
 	// 如果没有设置首选或首选服务未启用，自动选择第一个启用的服务
 	hasPrimary := false
 	for _, svc := range services {
@@ -821,10 +875,87 @@ func (h *ConfigHandler) getAIServicesStatus(c *gin.Context) {
 			"services":         services,
 			"primary_provider": primaryProvider,
 			"has_available":    openaiEnabled || deepseekEnabled || geminiEnabled,
+			"failover_order":   priority,
 		},
 	})
 }
 
+// applyBreakerStatus 填充熔断器和密钥状态，熔断中的服务标记为不可用；返回故障转移顺序
+func (h *ConfigHandler) applyBreakerStatus(list []AIServiceStatusResponse) []services.AIProvider {
+	manager := services.NewAIServiceManager(h.App.Config, h.App.Logger)
+	for i := range list {
+		breaker := manager.BreakerStatus(services.AIProvider(list[i].Provider))
+		list[i].Breaker = &breaker
+		list[i].Keys = manager.KeyStatus(services.AIProvider(list[i].Provider))
+		if breaker.State != services.BreakerClosed {
+			list[i].LastError = breaker.LastError
+		}
+		if breaker.State == services.BreakerOpen {
+			list[i].Available = false
+		}
+	}
+	return manager.Priority()
+}
+
+// probeAIServices 立即对冷却结束的服务发起探测
+func (h *ConfigHandler) probeAIServices(c *gin.Context) {
+	manager := services.NewAIServiceManager(h.App.Config, h.App.Logger)
+	results := make(map[string]string)
+	for provider, err := range manager.ProbeProviders(c.Request.Context()) {
+		results[string(provider)] = "ok"
+		if err != nil {
+			results[string(provider)] = err.Error()
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data":    results,
+	})
+}
+
+// resetAIServiceBreaker 手动恢复指定服务的熔断器
+func (h *ConfigHandler) resetAIServiceBreaker(c *gin.Context) {
+	provider := c.Param("provider")
+	switch provider {
+	case "openai_compatible", "deepseek", "gemini":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid provider. Must be one of: openai_compatible, deepseek, gemini",
+		})
+		return
+	}
+
+	manager := services.NewAIServiceManager(h.App.Config, h.App.Logger)
+	manager.ResetBreaker(services.AIProvider(provider))
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data":    manager.BreakerStatus(services.AIProvider(provider)),
+	})
+}
+
+// getAIServiceKeys 获取各服务密钥池的状态（轮换规则、每个密钥的用量、限额和冷却状态）
+func (h *ConfigHandler) getAIServiceKeys(c *gin.Context) {
+	manager := services.NewAIServiceManager(h.App.Config, h.App.Logger)
+	data := make(gin.H)
+	for _, provider := range []services.AIProvider{services.AIProviderOpenAICompatible, services.AIProviderDeepSeek, services.AIProviderGemini} {
+		data[string(provider)] = gin.H{
+			"policy": llm.KeyPoolPolicy(h.App.Config.AIKeyPoolConfig.Policy(string(provider))),
+			"keys":   manager.KeyStatus(provider),
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data":    data,
+	})
+}
+
 // SetPrimaryAIServiceRequest 设置首选AI服务请求
 type SetPrimaryAIServiceRequest struct {
 	Provider string `json:"provider"` // openai_compatible, deepseek, gemini
@@ -860,13 +991,13 @@ func (h *ConfigHandler) setPrimaryAIService(c *gin.Context) {
 	switch req.Provider {
 	case "openai_compatible":
 		cfg := h.App.Config.OpenAICompatibleConfig
-		isEnabled = cfg != nil && cfg.Enabled && cfg.ApiKey != ""
+		isEnabled = cfg != nil && cfg.Enabled && cfg.HasApiKey()
 	case "deepseek":
 		cfg := h.App.Config.DeepSeekTransConfig
-		isEnabled = cfg != nil && cfg.Enabled && cfg.ApiKey != ""
+		isEnabled = cfg != nil && cfg.Enabled && cfg.HasApiKey()
 	case "gemini":
 		cfg := h.App.Config.GeminiConfig
-		isEnabled = cfg != nil && cfg.Enabled && (cfg.ApiKey != "" || len(cfg.ApiKeys) > 0)
+		isEnabled = cfg != nil && cfg.Enabled && cfg.HasApiKey()
 	}
 
 	if !isEnabled {
@@ -1151,21 +1282,21 @@ func (h *ConfigHandler) validateGeminiApiKeys(c *gin.Context) {
 			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 			defer cancel()
 
-			client, err := genai.NewClient(ctx, option.WithAPIKey(key))
+			provider, err := llm.NewGemini(ctx, llm.Config{APIKey: key, Model: config.Model})
 			if err != nil {
 				result.Valid = false
 				result.Message = fmt.Sprintf("创建客户端失败: %v", err)
 				results[index] = result
 				return
 			}
-			defer client.Close()
+			defer provider.Close()
 
 			// 测试 API 调用
-			model := client.GenerativeModel(config.Model)
-			model.SetMaxOutputTokens(10)
-			model.SetTemperature(0.1)
-
-			_, err = model.GenerateContent(ctx, genai.Text("Hi"))
+			_, err = provider.Chat(ctx, &llm.Request{
+				Messages:    llm.Messages("", "Hi"),
+				Temperature: 0.1,
+				MaxTokens:   10,
+			})
 			if err != nil {
 				result.Valid = false
 				errMsg := err.Error()
@@ -0,0 +1,271 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxCookieFileSize cookies 文件大小上限
+const maxCookieFileSize = 1 << 20
+
+type CookieHandler struct {
+	BaseHandler
+	CookieProfileService *services.CookieProfileService
+}
+
+func NewCookieHandler(app *core.AppServer, cookieProfileService *services.CookieProfileService) *CookieHandler {
+	return &CookieHandler{
+		BaseHandler:          BaseHandler{App: app},
+		CookieProfileService: cookieProfileService,
+	}
+}
+
+// RegisterRoutes 注册 cookies 管理路由
+func (h *CookieHandler) RegisterRoutes(server *core.AppServer) {
+	api := server.Engine.Group("/api/v1")
+
+	cookies := api.Group("/cookies")
+	{
+		cookies.GET("", h.listProfiles)
+		cookies.POST("", h.createProfile)
+		cookies.PUT("/:id", h.updateProfile)
+		cookies.DELETE("/:id", h.deleteProfile)
+		cookies.POST("/:id/validate", h.validateProfile)
+	}
+}
+
+// CookieProfileRequest cookies 配置请求
+// cookies 内容可以通过 content 字段提交，也可以通过 multipart 的 file 字段上传
+type CookieProfileRequest struct {
+	Name    *string `json:"name,omitempty" form:"name"`       // 名称
+	Adapter *string `json:"adapter,omitempty" form:"adapter"` // 来源适配器: youtube, bilibili, generic
+	Channel *string `json:"channel,omitempty" form:"channel"` // 绑定的频道ID（为空表示所有频道）
+	Enabled *bool   `json:"enabled,omitempty" form:"enabled"` // 是否启用
+	Content *string `json:"content,omitempty" form:"content"` // Netscape 格式的 cookies 内容
+}
+
+// readContent 读取请求中的 cookies 内容
+func (h *CookieHandler) readContent(c *gin.Context, req *CookieProfileRequest) ([]byte, error) {
+	if fileHeader, err := c.FormFile("file"); err == nil {
+		file, err := fileHeader.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return io.ReadAll(io.LimitReader(file, maxCookieFileSize))
+	}
+
+	if req.Content != nil {
+		return []byte(*req.Content), nil
+	}
+	return nil, nil
+}
+
+// listProfiles 获取 cookies 配置列表（不返回 cookies 内容）
+func (h *CookieHandler) listProfiles(c *gin.Context) {
+	profiles, err := h.CookieProfileService.ListProfiles()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "获取 cookies 配置失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data":    profiles,
+	})
+}
+
+// createProfile 上传 cookies 文件
+func (h *CookieHandler) createProfile(c *gin.Context) {
+	var req CookieProfileRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	content, err := h.readContent(c, &req)
+	if err != nil || len(content) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "请上传 cookies 文件或提供 cookies 内容",
+		})
+		return
+	}
+
+	var name, adapter, channel string
+	if req.Name != nil {
+		name = *req.Name
+	}
+	if req.Adapter != nil {
+		adapter = *req.Adapter
+	}
+	if req.Channel != nil {
+		channel = *req.Channel
+	}
+	if name == "" {
+		name = "cookies"
+	}
+
+	profile, err := h.CookieProfileService.CreateProfile(name, adapter, channel, content)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	h.App.Logger.Infof("🍪 已添加 cookies 配置: %s (adapter: %s, channel: %s, %d 条 cookie)",
+		profile.Name, profile.Adapter, profile.Channel, profile.CookieCount)
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data":    profile,
+	})
+}
+
+// updateProfile 更新 cookies 配置（可同时替换 cookies 内容）
+func (h *CookieHandler) updateProfile(c *gin.Context) {
+	profileID, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	var req CookieProfileRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	profile, err := h.CookieProfileService.GetProfile(profileID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    404,
+			"message": "cookies 配置不存在",
+		})
+		return
+	}
+
+	if req.Name != nil {
+		profile.Name = *req.Name
+	}
+	if req.Adapter != nil && *req.Adapter != "" {
+		profile.Adapter = *req.Adapter
+	}
+	if req.Channel != nil {
+		profile.Channel = *req.Channel
+	}
+	if req.Enabled != nil {
+		profile.Enabled = *req.Enabled
+	}
+
+	content, err := h.readContent(c, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "读取 cookies 文件失败: " + err.Error(),
+		})
+		return
+	}
+
+	if len(content) > 0 {
+		err = h.CookieProfileService.ReplaceContent(profile, content)
+	} else {
+		err = h.CookieProfileService.UpdateProfile(profile)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data":    profile,
+	})
+}
+
+// deleteProfile 删除 cookies 配置
+func (h *CookieHandler) deleteProfile(c *gin.Context) {
+	profileID, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.CookieProfileService.DeleteProfile(profileID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "删除 cookies 配置失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+	})
+}
+
+// validateProfile 重新校验 cookies 有效期，未过期时解除冷却状态
+func (h *CookieHandler) validateProfile(c *gin.Context) {
+	profileID, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	profile, err := h.CookieProfileService.GetProfile(profileID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    404,
+			"message": "cookies 配置不存在",
+		})
+		return
+	}
+
+	if err := h.CookieProfileService.ValidateProfile(profile); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data":    profile,
+	})
+}
+
+// parseID 解析路径中的 cookies 配置ID
+func (h *CookieHandler) parseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "无效的ID",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
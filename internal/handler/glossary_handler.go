@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/services"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GlossaryHandler struct {
+	BaseHandler
+	GlossaryService *services.GlossaryService
+}
+
+func NewGlossaryHandler(app *core.AppServer, glossaryService *services.GlossaryService) *GlossaryHandler {
+	return &GlossaryHandler{
+		BaseHandler:     BaseHandler{App: app},
+		GlossaryService: glossaryService,
+	}
+}
+
+// RegisterRoutes 注册术语表路由
+func (h *GlossaryHandler) RegisterRoutes(server *core.AppServer) {
+	api := server.Engine.Group("/api/v1")
+
+	glossary := api.Group("/glossary")
+	{
+		glossary.GET("", h.listTerms)
+		glossary.POST("", h.createTerm)
+		glossary.PUT("/:id", h.updateTerm)
+		glossary.DELETE("/:id", h.deleteTerm)
+	}
+}
+
+// GlossaryTermRequest 术语请求
+type GlossaryTermRequest struct {
+	ChannelID      *string `json:"channel_id,omitempty"`       // 频道ID（为空表示所有频道）
+	Source         *string `json:"source,omitempty"`           // 原文术语
+	Target         *string `json:"target,omitempty"`           // 固定译法
+	DoNotTranslate *bool   `json:"do_not_translate,omitempty"` // 保持原文不翻译
+	CaseSensitive  *bool   `json:"case_sensitive,omitempty"`   // 是否区分大小写
+	Note           *string `json:"note,omitempty"`             // 备注
+	Enabled        *bool   `json:"enabled,omitempty"`          // 是否启用
+}
+
+// apply 将请求中提供的字段写入术语
+func (req *GlossaryTermRequest) apply(term *model.GlossaryTerm) {
+	if req.ChannelID != nil {
+		term.ChannelID = *req.ChannelID
+	}
+	if req.Source != nil {
+		term.Source = *req.Source
+	}
+	if req.Target != nil {
+		term.Target = *req.Target
+	}
+	if req.DoNotTranslate != nil {
+		term.DoNotTranslate = *req.DoNotTranslate
+	}
+	if req.CaseSensitive != nil {
+		term.CaseSensitive = *req.CaseSensitive
+	}
+	if req.Note != nil {
+		term.Note = *req.Note
+	}
+	if req.Enabled != nil {
+		term.Enabled = *req.Enabled
+	}
+}
+
+// listTerms 获取术语列表，可通过 channel_id 参数筛选（channel_id= 表示全局术语）
+func (h *GlossaryHandler) listTerms(c *gin.Context) {
+	var channelID *string
+	if value, ok := c.GetQuery("channel_id"); ok {
+		channelID = &value
+	}
+
+	terms, err := h.GlossaryService.ListTerms(channelID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "获取术语表失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data":    terms,
+	})
+}
+
+// createTerm 添加术语
+func (h *GlossaryHandler) createTerm(c *gin.Context) {
+	var req GlossaryTermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	term := &model.GlossaryTerm{Enabled: true}
+	req.apply(term)
+	if err := h.GlossaryService.CreateTerm(term); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	h.App.Logger.Infof("📖 已添加术语: %s → %s (频道: %s)", term.Source, term.Expected(), term.ChannelID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data":    term,
+	})
+}
+
+// updateTerm 更新术语
+func (h *GlossaryHandler) updateTerm(c *gin.Context) {
+	termID, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	var req GlossaryTermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	term, err := h.GlossaryService.GetTerm(termID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    404,
+			"message": "术语不存在",
+		})
+		return
+	}
+
+	req.apply(term)
+	if err := h.GlossaryService.UpdateTerm(term); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data":    term,
+	})
+}
+
+// deleteTerm 删除术语
+func (h *GlossaryHandler) deleteTerm(c *gin.Context) {
+	termID, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.GlossaryService.DeleteTerm(termID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "删除术语失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+	})
+}
+
+// parseID 解析路径中的术语ID
+func (h *GlossaryHandler) parseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "无效的ID",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
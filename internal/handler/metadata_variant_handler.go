@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/difyz9/bilibili-go-sdk/bilibili"
+	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MetadataVariantHandler struct {
+	BaseHandler
+	VariantService *services.MetadataVariantService
+}
+
+func NewMetadataVariantHandler(app *core.AppServer, variantService *services.MetadataVariantService) *MetadataVariantHandler {
+	return &MetadataVariantHandler{
+		BaseHandler:    BaseHandler{App: app},
+		VariantService: variantService,
+	}
+}
+
+// RegisterRoutes 注册标题候选路由
+func (h *MetadataVariantHandler) RegisterRoutes(server *core.AppServer) {
+	api := server.Engine.Group("/api/v1")
+
+	variants := api.Group("/metadata-variants")
+	{
+		variants.GET("/videos/:videoId", h.listVideoVariants)
+		variants.PUT("/:id/select", h.selectVariant)
+		variants.GET("/report", h.getReport)
+		variants.POST("/stats/refresh", h.refreshStats)
+	}
+}
+
+// listVideoVariants 获取视频的全部标题候选及投稿数据
+func (h *MetadataVariantHandler) listVideoVariants(c *gin.Context) {
+	variants, err := h.VariantService.ListByVideo(c.Param("videoId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "获取标题候选失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data":    variants,
+	})
+}
+
+// selectVariant 手动选择标题候选（投稿前）
+func (h *MetadataVariantHandler) selectVariant(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "无效的ID",
+		})
+		return
+	}
+
+	variant, err := h.VariantService.Select(uint(id))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	h.App.Logger.Infof("🎯 已手动选择标题候选: %s (视频: %s)", variant.Title, variant.VideoID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data":    variant,
+	})
+}
+
+// getReport 按分区汇总标题风格（group_by=style）或提示词模板版本（group_by=prompt）的投稿效果，可按 tid 筛选
+func (h *MetadataVariantHandler) getReport(c *gin.Context) {
+	tid, _ := strconv.Atoi(c.Query("tid"))
+	report, err := h.VariantService.Report(c.DefaultQuery("group_by", "style"), tid)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data":    report,
+	})
+}
+
+// refreshStats 立即获取已投稿视频的播放数据
+func (h *MetadataVariantHandler) refreshStats(c *gin.Context) {
+	windowDays := 0
+	if config := h.App.Config.MetadataConfig; config != nil {
+		windowDays = config.StatsWindowDays
+	}
+
+	updated, err := h.VariantService.RefreshStats(bilibili.NewClient().GetVideoStat, windowDays)
+	if err != nil {
+		h.App.Logger.Warnf("⚠️ 部分投稿播放数据获取失败: %v", err)
+	}
+
+	data := gin.H{"updated": updated}
+	if err != nil {
+		data["error"] = err.Error()
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data":    data,
+	})
+}
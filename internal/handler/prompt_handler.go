@@ -0,0 +1,293 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/services"
+	"github.com/difyz9/ytb2bili/pkg/prompt"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PromptHandler struct {
+	BaseHandler
+	PromptService *services.PromptService
+}
+
+func NewPromptHandler(app *core.AppServer, promptService *services.PromptService) *PromptHandler {
+	return &PromptHandler{
+		BaseHandler:   BaseHandler{App: app},
+		PromptService: promptService,
+	}
+}
+
+// RegisterRoutes 注册提示词模板路由
+func (h *PromptHandler) RegisterRoutes(server *core.AppServer) {
+	api := server.Engine.Group("/api/v1")
+
+	prompts := api.Group("/prompts")
+	{
+		prompts.GET("", h.listTemplates)
+		prompts.POST("", h.createTemplate)
+		prompts.GET("/definitions", h.listDefinitions)
+		prompts.GET("/active", h.getActive)
+		prompts.POST("/preview", h.preview)
+		prompts.POST("/deactivate", h.deactivate)
+		prompts.PUT("/:id/activate", h.activate)
+	}
+}
+
+// PromptTemplateRequest 保存模板新版本的请求
+type PromptTemplateRequest struct {
+	Name        string `json:"name" binding:"required"`    // 模板名称
+	ChannelID   string `json:"channel_id"`                 // 频道ID（为空表示所有频道）
+	Content     string `json:"content" binding:"required"` // 模板内容
+	Description string `json:"description"`                // 修改说明
+	Activate    *bool  `json:"activate,omitempty"`         // 是否立即启用（默认启用）
+}
+
+// PromptPreviewRequest 预览模板渲染结果的请求
+type PromptPreviewRequest struct {
+	Name    string      `json:"name" binding:"required"` // 模板名称
+	Content string      `json:"content"`                 // 模板内容（为空时使用内置默认模板）
+	Vars    prompt.Vars `json:"vars"`                    // 渲染使用的变量
+}
+
+// PromptDeactivateRequest 停用模板的请求
+type PromptDeactivateRequest struct {
+	Name      string `json:"name" binding:"required"` // 模板名称
+	ChannelID string `json:"channel_id"`              // 频道ID（为空表示全局模板）
+}
+
+// listTemplates 获取模板版本列表，可通过 name、channel_id、pipeline 参数筛选
+func (h *PromptHandler) listTemplates(c *gin.Context) {
+	var name, channelID *string
+	if value, ok := c.GetQuery("name"); ok {
+		name = &value
+	}
+	if value, ok := c.GetQuery("channel_id"); ok {
+		channelID = &value
+	}
+
+	templates, err := h.PromptService.List(name, channelID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "获取提示词模板失败: " + err.Error(),
+		})
+		return
+	}
+
+	if pipeline := c.Query("pipeline"); pipeline != "" {
+		names := make(map[string]bool)
+		for _, def := range prompt.Definitions(pipeline) {
+			names[def.Name] = true
+		}
+		filtered := templates[:0]
+		for _, t := range templates {
+			if names[t.Name] {
+				filtered = append(filtered, t)
+			}
+		}
+		templates = filtered
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data":    templates,
+	})
+}
+
+// listDefinitions 获取所有模板定义（名称、用途、可用变量和内置默认内容）
+func (h *PromptHandler) listDefinitions(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data":    prompt.Definitions(c.Query("pipeline")),
+	})
+}
+
+// getActive 获取频道实际生效的模板（频道模板 > 全局模板 > 内置默认）
+func (h *PromptHandler) getActive(c *gin.Context) {
+	name := c.Query("name")
+	def, ok := prompt.Lookup(name)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "未知的模板名称: " + name,
+		})
+		return
+	}
+
+	channelID := c.Query("channel_id")
+	scopes := []string{""}
+	if channelID != "" {
+		scopes = []string{channelID, ""}
+	}
+	for _, scope := range scopes {
+		active, err := h.PromptService.Active(name, scope)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"code":    500,
+				"message": "获取提示词模板失败: " + err.Error(),
+			})
+			return
+		}
+		if active != nil {
+			c.JSON(http.StatusOK, gin.H{
+				"code":    200,
+				"message": "success",
+				"data":    active,
+			})
+			return
+		}
+	}
+
+	// 未启用自定义模板，返回内置默认模板（版本 0）
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data": model.PromptTemplate{
+			Name:        def.Name,
+			Content:     def.Content,
+			Description: def.Description,
+			Active:      true,
+		},
+	})
+}
+
+// createTemplate 保存模板新版本
+func (h *PromptHandler) createTemplate(c *gin.Context) {
+	var req PromptTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	t := &model.PromptTemplate{
+		Name:        req.Name,
+		ChannelID:   req.ChannelID,
+		Content:     req.Content,
+		Description: req.Description,
+	}
+	activate := req.Activate == nil || *req.Activate
+	if err := h.PromptService.Create(t, activate); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	h.App.Logger.Infof("📝 已保存提示词模板: %s v%d (频道: %s, 启用: %v)", t.Name, t.Version, t.ChannelID, t.Active)
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data":    t,
+	})
+}
+
+// preview 用给定变量渲染模板，用于保存前检查效果
+func (h *PromptHandler) preview(c *gin.Context) {
+	var req PromptPreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	def, ok := prompt.Lookup(req.Name)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "未知的模板名称: " + req.Name,
+		})
+		return
+	}
+	content := req.Content
+	if content == "" {
+		content = def.Content
+	}
+
+	t, err := prompt.Parse(req.Name, content)
+	if err == nil {
+		content, err = t.Render(req.Vars)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data":    gin.H{"prompt": content},
+	})
+}
+
+// activate 启用指定版本（停用同一名称、频道的其他版本），可用于回滚
+func (h *PromptHandler) activate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "无效的ID",
+		})
+		return
+	}
+
+	t, err := h.PromptService.Activate(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    404,
+			"message": "提示词模板不存在",
+		})
+		return
+	}
+
+	h.App.Logger.Infof("📝 已启用提示词模板: %s v%d (频道: %s)", t.Name, t.Version, t.ChannelID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data":    t,
+	})
+}
+
+// deactivate 停用模板，恢复为全局模板或内置默认模板
+func (h *PromptHandler) deactivate(c *gin.Context) {
+	var req PromptDeactivateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	if err := h.PromptService.Deactivate(req.Name, req.ChannelID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "停用提示词模板失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+	})
+}
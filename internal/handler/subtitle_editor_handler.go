@@ -0,0 +1,322 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/difyz9/ytb2bili/internal/auth"
+	"github.com/difyz9/ytb2bili/internal/chain_task/handlers"
+	"github.com/difyz9/ytb2bili/internal/chain_task/manager"
+	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/services"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SubtitleEditorHandler 字幕编辑器：查看原文/译文对齐的字幕、逐条修改、编辑历史和审核
+type SubtitleEditorHandler struct {
+	BaseHandler
+	SavedVideoService   *services.SavedVideoService
+	SubtitleEditService *services.SubtitleEditService
+}
+
+func NewSubtitleEditorHandler(app *core.AppServer, savedVideoService *services.SavedVideoService, subtitleEditService *services.SubtitleEditService) *SubtitleEditorHandler {
+	return &SubtitleEditorHandler{
+		BaseHandler:         BaseHandler{App: app},
+		SavedVideoService:   savedVideoService,
+		SubtitleEditService: subtitleEditService,
+	}
+}
+
+// RegisterRoutes 注册字幕编辑器路由
+func (h *SubtitleEditorHandler) RegisterRoutes(server *core.AppServer) {
+	api := server.Engine.Group("/api/v1")
+
+	api.GET("/subtitle-reviews", h.listAwaitingReview)
+
+	video := api.Group("/videos")
+	{
+		video.GET("/:id/subtitles/:lang/cues", h.getCues)
+		video.PATCH("/:id/subtitles/:lang/cues/:index", h.patchCue)
+		video.GET("/:id/subtitle-edits", h.listEdits)
+		video.POST("/:id/subtitle-review/approve", h.approve)
+	}
+}
+
+// PatchCueRequest 修改字幕条目请求
+type PatchCueRequest struct {
+	services.CuePatch
+	Editor string `json:"editor,omitempty"` // 编辑者（未登录时使用）
+}
+
+// ApproveRequest 审核通过请求
+type ApproveRequest struct {
+	Editor string `json:"editor,omitempty"` // 审核人（未登录时使用）
+	Note   string `json:"note,omitempty"`   // 备注
+}
+
+// listAwaitingReview 获取等待字幕审核的视频
+func (h *SubtitleEditorHandler) listAwaitingReview(c *gin.Context) {
+	videos, err := h.SubtitleEditService.ListAwaitingReview()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "获取待审核视频失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data": gin.H{
+			"require_review": h.SubtitleEditService.RequireReview(),
+			"videos":         videos,
+		},
+	})
+}
+
+// getCues 获取原文与译文对齐的字幕条目
+func (h *SubtitleEditorHandler) getCues(c *gin.Context) {
+	video, ok := h.findVideo(c)
+	if !ok {
+		return
+	}
+
+	language := subtitle.NormalizeLanguage(c.Param("lang"))
+	stateManager := h.stateManager(video)
+	translated, err := subtitle.ReadFile(stateManager.TranslatedSRTPath(language))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    404,
+			"message": "译文字幕不存在: " + language,
+		})
+		return
+	}
+
+	// 原文字幕缺失时只返回译文
+	original, err := subtitle.ReadFile(h.originalSRTPath(stateManager))
+	if err != nil {
+		h.App.Logger.Warnf("⚠️  读取原文字幕失败: %v", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data": gin.H{
+			"video_id": video.VideoID,
+			"language": language,
+			"status":   video.Status,
+			"cues":     services.AlignCues(original, translated),
+		},
+	})
+}
+
+// patchCue 修改一条译文字幕（文本和时间轴），同步更新 VTT 和双语字幕
+func (h *SubtitleEditorHandler) patchCue(c *gin.Context) {
+	video, ok := h.findVideo(c)
+	if !ok {
+		return
+	}
+
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "无效的字幕条目下标",
+		})
+		return
+	}
+
+	var req PatchCueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	language := subtitle.NormalizeLanguage(c.Param("lang"))
+	stateManager := h.stateManager(video)
+	path := stateManager.TranslatedSRTPath(language)
+	if _, err := os.Stat(path); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    404,
+			"message": "译文字幕不存在: " + language,
+		})
+		return
+	}
+
+	cues, edit, err := h.SubtitleEditService.PatchCue(video.VideoID, language, path, index, &req.CuePatch, editorName(c, req.Editor))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCuePatch) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    400,
+				"message": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "修改字幕失败: " + err.Error(),
+		})
+		return
+	}
+
+	if edit != nil {
+		h.App.Logger.Infof("✏️  字幕已修改: %s [%s] 第 %d 条 (编辑者: %s)", video.VideoID, language, index+1, edit.Editor)
+		h.syncDerivedSubtitles(stateManager, language, cues)
+	}
+
+	original, _ := subtitle.ReadFile(h.originalSRTPath(stateManager))
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data": gin.H{
+			"cue":  services.AlignCues(original, cues)[index],
+			"edit": edit,
+		},
+	})
+}
+
+// listEdits 获取字幕编辑历史，可通过 language 参数筛选
+func (h *SubtitleEditorHandler) listEdits(c *gin.Context) {
+	video, ok := h.findVideo(c)
+	if !ok {
+		return
+	}
+
+	language := subtitle.NormalizeLanguage(c.Query("language"))
+	edits, err := h.SubtitleEditService.ListEdits(video.VideoID, language)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "获取编辑历史失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data":    edits,
+	})
+}
+
+// approve 字幕审核通过，视频进入准备就绪状态等待上传调度器上传
+func (h *SubtitleEditorHandler) approve(c *gin.Context) {
+	video, ok := h.findVideo(c)
+	if !ok {
+		return
+	}
+
+	var req ApproveRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    400,
+				"message": "Invalid request body: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	editor := editorName(c, req.Editor)
+	if err := h.SubtitleEditService.Approve(video, editor, req.Note); err != nil {
+		if errors.Is(err, services.ErrNotAwaitingReview) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    400,
+				"message": "当前状态 " + video.Status + " 不是等待字幕审核(100)，无需审核",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "审核失败: " + err.Error(),
+		})
+		return
+	}
+
+	h.App.Logger.Infof("✅ 字幕审核通过: %s (审核人: %s)，已进入上传队列", video.VideoID, editor)
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data": gin.H{
+			"video_id": video.VideoID,
+			"status":   video.Status,
+		},
+	})
+}
+
+// syncDerivedSubtitles 译文修改后重新生成该语言的 VTT，主语言同时重新生成双语字幕；
+// 过期的断句结果直接删除，上传前的断句步骤会按修改后的译文重新生成
+func (h *SubtitleEditorHandler) syncDerivedSubtitles(stateManager *manager.StateManager, language string, cues []subtitle.Cue) {
+	if err := os.Remove(stateManager.SegmentedSRTPath(language)); err != nil && !os.IsNotExist(err) {
+		h.App.Logger.Warnf("⚠️  删除过期的断句字幕失败: %v", err)
+	}
+	if err := subtitle.WriteFile(stateManager.TranslatedVTTPath(language), cues); err != nil {
+		h.App.Logger.Warnf("⚠️  更新VTT字幕失败: %v", err)
+	}
+
+	if language != handlers.TargetLanguages(h.App.Config)[0] {
+		return
+	}
+	original, err := subtitle.ReadFile(h.originalSRTPath(stateManager))
+	if err != nil {
+		return
+	}
+	if _, err := handlers.WriteBilingualSubtitles(h.App.Config, stateManager, original, cues); err != nil {
+		h.App.Logger.Warnf("⚠️  更新双语字幕失败: %v", err)
+	}
+}
+
+// findVideo 按数字ID或video_id查找视频，不存在时返回 404
+func (h *SubtitleEditorHandler) findVideo(c *gin.Context) (*model.SavedVideo, bool) {
+	idStr := c.Param("id")
+
+	var video *model.SavedVideo
+	var err error
+	if id, parseErr := strconv.ParseUint(idStr, 10, 32); parseErr == nil {
+		video, err = h.SavedVideoService.GetByID(uint(id))
+	} else {
+		video, err = h.SavedVideoService.GetVideoByVideoID(idStr)
+	}
+
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    404,
+			"message": "视频不存在",
+		})
+		return nil, false
+	}
+	return video, true
+}
+
+// stateManager 视频文件目录的状态管理器（与任务链使用相同的目录规则）
+func (h *SubtitleEditorHandler) stateManager(video *model.SavedVideo) *manager.StateManager {
+	currentDir, err := filepath.Abs(h.App.Config.FileUpDir)
+	if err != nil {
+		currentDir = h.App.Config.FileUpDir
+	}
+	return manager.NewStateManager(video.ID, video.VideoID, currentDir, video.CreatedAt)
+}
+
+// originalSRTPath 原文字幕路径（由生成字幕步骤生成）
+func (h *SubtitleEditorHandler) originalSRTPath(stateManager *manager.StateManager) string {
+	return filepath.Join(stateManager.CurrentDir, stateManager.VideoID+".srt")
+}
+
+// editorName 编辑者：已登录时为用户名，否则为请求中填写的名称
+func editorName(c *gin.Context, fallback string) string {
+	if username := c.GetString(auth.ContextKeyUsername); username != "" {
+		return username
+	}
+	return fallback
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/difyz9/ytb2bili/internal/auth"
 	"github.com/difyz9/ytb2bili/internal/core"
 	"github.com/difyz9/ytb2bili/pkg/store/model"
 	"github.com/difyz9/ytb2bili/pkg/utils"
@@ -80,6 +81,12 @@ func (h *SubtitleHandler) saveVideoSubtitles(c *gin.Context) {
 		fmt.Printf("字幕数据: %s\n", subtitlesJSONStr)
 	}
 
+	// 提交者（AI用量按用户统计，与会员系统的用户ID一致）
+	userID := auth.GetUserIDString(c)
+	if userID == "" {
+		userID = c.GetHeader("X-User-ID")
+	}
+
 	// 检查是否已存在相同的 videoId（包括已删除的记录）
 	var existingVideo model.SavedVideo
 	err = h.App.DB.Unscoped().Where("video_id = ?", videoID).First(&existingVideo).Error
@@ -95,11 +102,15 @@ func (h *SubtitleHandler) saveVideoSubtitles(c *gin.Context) {
 		existingVideo.Description = req.Description
 		existingVideo.OperationType = req.OperationType
 		existingVideo.Subtitles = subtitlesJSONStr
+		existingVideo.SubtitleSource = "" // 重新提交后以扩展字幕为准
 		existingVideo.PlaylistID = req.PlaylistID
 		existingVideo.Timestamp = req.Timestamp
 		existingVideo.SavedAt = req.SavedAt
 		existingVideo.Status = "001"               // 重置状态为待处理
 		existingVideo.DeletedAt = gorm.DeletedAt{} // 恢复记录（清除删除标记）
+		if userID != "" {
+			existingVideo.UserID = userID
+		}
 
 		// 更新到数据库（使用 Unscoped 以便更新已删除的记录）
 		if err := h.App.DB.Unscoped().Save(&existingVideo).Error; err != nil {
@@ -128,6 +139,7 @@ func (h *SubtitleHandler) saveVideoSubtitles(c *gin.Context) {
 			PlaylistID:    req.PlaylistID,
 			Timestamp:     req.Timestamp,
 			SavedAt:       req.SavedAt,
+			UserID:        userID,
 		}
 
 		// 保存到数据库
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TranslationMemoryHandler struct {
+	BaseHandler
+	TranslationMemoryService *services.TranslationMemoryService
+}
+
+func NewTranslationMemoryHandler(app *core.AppServer, translationMemoryService *services.TranslationMemoryService) *TranslationMemoryHandler {
+	return &TranslationMemoryHandler{
+		BaseHandler:              BaseHandler{App: app},
+		TranslationMemoryService: translationMemoryService,
+	}
+}
+
+// RegisterRoutes 注册翻译记忆路由
+func (h *TranslationMemoryHandler) RegisterRoutes(server *core.AppServer) {
+	api := server.Engine.Group("/api/v1")
+
+	memory := api.Group("/translation-memory")
+	{
+		memory.GET("/stats", h.getStats)
+		memory.DELETE("", h.clear)
+	}
+}
+
+// getStats 获取翻译记忆统计（条目数、累计命中、本次运行命中率）
+func (h *TranslationMemoryHandler) getStats(c *gin.Context) {
+	stats, err := h.TranslationMemoryService.Stats()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "获取翻译记忆统计失败: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data":    stats,
+	})
+}
+
+// clear 清空翻译记忆，expired=true 时只删除过期条目
+func (h *TranslationMemoryHandler) clear(c *gin.Context) {
+	var deleted int64
+	var err error
+	if c.Query("expired") == "true" {
+		deleted, err = h.TranslationMemoryService.PurgeExpired()
+	} else {
+		deleted, err = h.TranslationMemoryService.Clear()
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "清理翻译记忆失败: " + err.Error(),
+		})
+		return
+	}
+
+	h.App.Logger.Infof("🧹 已清理翻译记忆 %d 条", deleted)
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data": gin.H{
+			"deleted": deleted,
+		},
+	})
+}
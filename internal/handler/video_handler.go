@@ -12,6 +12,7 @@ import (
 	"github.com/difyz9/ytb2bili/internal/core"
 	"github.com/difyz9/ytb2bili/internal/core/services"
 	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
 
 	"github.com/gin-gonic/gin"
 )
@@ -421,6 +422,7 @@ func (h *VideoHandler) getVideoFiles(c *gin.Context) {
 	// 获取视频文件目录
 	videoDir := h.getVideoDirectory(savedVideo.VideoID)
 	files := h.listVideoFiles(videoDir)
+	subtitles := h.groupSubtitleFiles(files, savedVideo)
 
 	c.JSON(http.StatusOK, VideoListResponse{
 		Code:    200,
@@ -429,6 +431,7 @@ func (h *VideoHandler) getVideoFiles(c *gin.Context) {
 			"video_id":  savedVideo.VideoID,
 			"directory": videoDir,
 			"files":     files,
+			"subtitles": subtitles,
 		},
 	})
 }
@@ -522,6 +525,35 @@ func (h *VideoHandler) listVideoFiles(dirPattern string) []map[string]interface{
 	return files
 }
 
+// groupSubtitleFiles 按语言分组字幕文件（zh-Hans、zh-Hant、ja、bilingual 等），同时为字幕文件补充 language 字段
+// 原始字幕（<videoID>.srt）归入视频的字幕语言，无法识别语言的归入 other
+func (h *VideoHandler) groupSubtitleFiles(files []map[string]interface{}, video *model.SavedVideo) map[string][]map[string]interface{} {
+	sourceLang := subtitle.NormalizeLanguage(video.SubtitleLang)
+	if sourceLang == "" {
+		sourceLang = "en"
+	}
+
+	groups := make(map[string][]map[string]interface{})
+	for _, file := range files {
+		if file["type"] != "subtitle" {
+			continue
+		}
+
+		name := file["name"].(string)
+		language := subtitle.FileLanguage(name)
+		if strings.TrimSuffix(name, filepath.Ext(name)) == video.VideoID {
+			language = sourceLang
+		}
+		if language == "" {
+			language = "other"
+		}
+
+		file["language"] = language
+		groups[language] = append(groups[language], file)
+	}
+	return groups
+}
+
 // getFileType 根据文件扩展名判断文件类型
 func (h *VideoHandler) getFileType(filename string) string {
 	ext := strings.ToLower(filepath.Ext(filename))
@@ -529,7 +561,7 @@ func (h *VideoHandler) getFileType(filename string) string {
 	switch ext {
 	case ".mp4", ".flv", ".mkv", ".webm", ".avi", ".mov":
 		return "video"
-	case ".srt", ".vtt":
+	case ".srt", ".vtt", ".ass", ".bcc":
 		return "subtitle"
 	case ".jpg", ".jpeg", ".png", ".webp":
 		return "image"
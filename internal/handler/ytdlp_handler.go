@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type YtDlpHandler struct {
+	BaseHandler
+	Updater *utils.YtDlpUpdater
+}
+
+func NewYtDlpHandler(app *core.AppServer, updater *utils.YtDlpUpdater) *YtDlpHandler {
+	return &YtDlpHandler{
+		BaseHandler: BaseHandler{App: app},
+		Updater:     updater,
+	}
+}
+
+// RegisterRoutes 注册 yt-dlp 版本管理路由
+func (h *YtDlpHandler) RegisterRoutes(server *core.AppServer) {
+	api := server.Engine.Group("/api/v1")
+
+	ytdlp := api.Group("/ytdlp")
+	{
+		ytdlp.GET("/status", h.getStatus)
+		ytdlp.POST("/update", h.update)
+		ytdlp.PUT("/config", h.updateConfig)
+	}
+}
+
+// YtDlpUpdateRequest 手动更新请求
+type YtDlpUpdateRequest struct {
+	Version string `json:"version"` // 目标版本（可选，为空时使用固定版本或最新版本）
+}
+
+// YtDlpConfigRequest yt-dlp 版本管理配置请求
+type YtDlpConfigRequest struct {
+	AutoUpdate     *bool   `json:"auto_update,omitempty"`     // 是否定时检查更新（重启后生效）
+	UpdateCron     *string `json:"update_cron,omitempty"`     // cron 表达式（重启后生效）
+	PinnedVersion  *string `json:"pinned_version,omitempty"`  // 固定版本
+	VerifyChecksum *bool   `json:"verify_checksum,omitempty"` // 是否校验 SHA2-256SUMS
+	SmokeTestURL   *string `json:"smoke_test_url,omitempty"`  // 冒烟测试视频地址
+}
+
+// getStatus 获取 yt-dlp 已安装版本和最近一次更新结果
+func (h *YtDlpHandler) getStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data": gin.H{
+			"status": h.Updater.Status(),
+			"config": h.App.Config.YtDlpConfig,
+		},
+	})
+}
+
+// update 立即检查并更新 yt-dlp
+func (h *YtDlpHandler) update(c *gin.Context) {
+	var req YtDlpUpdateRequest
+	// 请求体可以为空
+	_ = c.ShouldBindJSON(&req)
+
+	status := h.Updater.UpdateTo(req.Version)
+	if status.LastResult == utils.YtDlpResultFailed {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "yt-dlp 更新失败: " + status.LastError,
+			"data":    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data":    status,
+	})
+}
+
+// updateConfig 更新 yt-dlp 版本管理配置
+func (h *YtDlpHandler) updateConfig(c *gin.Context) {
+	var req YtDlpConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	if h.App.Config.YtDlpConfig == nil {
+		h.App.Config.YtDlpConfig = types.NewDefaultConfig().YtDlpConfig
+	}
+	config := h.App.Config.YtDlpConfig
+
+	if req.AutoUpdate != nil {
+		config.AutoUpdate = *req.AutoUpdate
+	}
+	if req.UpdateCron != nil {
+		config.UpdateCron = *req.UpdateCron
+	}
+	if req.PinnedVersion != nil {
+		config.PinnedVersion = *req.PinnedVersion
+		h.App.Logger.Infof("📌 yt-dlp 固定版本: %q", config.PinnedVersion)
+	}
+	if req.VerifyChecksum != nil {
+		config.VerifyChecksum = *req.VerifyChecksum
+	}
+	if req.SmokeTestURL != nil {
+		config.SmokeTestURL = *req.SmokeTestURL
+	}
+
+	h.Updater.SetOptions(utils.YtDlpOptions{
+		ReleaseAPIURL:  config.ReleaseAPIURL,
+		PinnedVersion:  config.PinnedVersion,
+		VerifyChecksum: config.VerifyChecksum,
+		SmokeTestURL:   config.SmokeTestURL,
+	})
+
+	if err := types.SaveConfig(h.App.Config); err != nil {
+		h.App.Logger.Errorf("Failed to save config: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to save configuration: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "success",
+		"data":    config,
+	})
+}
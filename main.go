@@ -9,9 +9,9 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/difyz9/bilibili-go-sdk/bilibili"
 	"github.com/difyz9/ytb2bili/internal/auth"
 	"github.com/difyz9/ytb2bili/internal/chain_task"
-	"github.com/difyz9/ytb2bili/internal/chain_task/handlers"
 	"github.com/difyz9/ytb2bili/internal/core"
 	"github.com/difyz9/ytb2bili/internal/core/services"
 	"github.com/difyz9/ytb2bili/internal/core/types"
@@ -20,6 +20,7 @@ import (
 	"github.com/difyz9/ytb2bili/internal/web"
 	"github.com/difyz9/ytb2bili/pkg/analytics"
 	"github.com/difyz9/ytb2bili/pkg/cos"
+	"github.com/difyz9/ytb2bili/pkg/llm"
 	"github.com/difyz9/ytb2bili/pkg/logger"
 	"github.com/difyz9/ytb2bili/pkg/store"
 	"github.com/difyz9/ytb2bili/pkg/utils"
@@ -48,28 +49,22 @@ func (l *AppLifecycle) OnStop(context.Context) error {
 
 // testGeminiConnection 测试 Gemini API 连接
 func testGeminiConnection(config *types.AppConfig, logger *zap.SugaredLogger) error {
-	// 使用轮询 API Key
-	apiKey := config.GeminiConfig.GetCurrentApiKey()
 	keyCount := config.GeminiConfig.GetApiKeysCount()
 	if keyCount > 1 {
 		logger.Infof("│  🔑 使用 API Key 轮询 (%d 个密钥)", keyCount)
 	}
 
-	client, err := handlers.NewGeminiClient(
-		apiKey,
-		config.GeminiConfig.Model,
-		config.GeminiConfig.Timeout,
-		config.GeminiConfig.MaxTokens,
-	)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// 通过共享密钥池选择密钥，测试请求也计入密钥用量
+	provider, err := services.NewAIServiceManager(config, logger).NewProvider(ctx, services.AIProviderGemini)
 	if err != nil {
 		return err
 	}
-	defer client.Close()
+	defer provider.Close()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	return client.TestConnection(ctx)
+	return llm.Ping(ctx, provider)
 }
 
 func main() {
@@ -173,6 +168,67 @@ func main() {
 			return checkYtDlpInstallation(logger, config)
 		}),
 
+		// yt-dlp 定时更新
+		fx.Provide(func(logger *zap.SugaredLogger, config *types.AppConfig) *utils.YtDlpUpdater {
+			return utils.NewYtDlpUpdater(logger, config.YtDlpPath, ytdlpOptions(config))
+		}),
+		fx.Invoke(func(task *cron.Cron, updater *utils.YtDlpUpdater, config *types.AppConfig, logger *zap.SugaredLogger) error {
+			if config.YtDlpConfig == nil || !config.YtDlpConfig.AutoUpdate {
+				return nil
+			}
+			spec := config.YtDlpConfig.UpdateCron
+			if spec == "" {
+				spec = "0 30 4 * * *"
+			}
+			if _, err := task.AddFunc(spec, func() { updater.CheckAndUpdate() }); err != nil {
+				logger.Errorf("❌ 注册 yt-dlp 定时更新失败: %v", err)
+				return nil
+			}
+			logger.Infof("✓ yt-dlp auto update scheduled: %s", spec)
+			return nil
+		}),
+
+		// AI服务熔断探测
+		fx.Invoke(func(task *cron.Cron, config *types.AppConfig, logger *zap.SugaredLogger) error {
+			spec := "@every 30s"
+			if config.AIFailoverConfig != nil && config.AIFailoverConfig.ProbeCron != "" {
+				spec = config.AIFailoverConfig.ProbeCron
+			}
+			if spec == "-" {
+				return nil
+			}
+			if _, err := task.AddFunc(spec, func() {
+				services.NewAIServiceManager(config, logger).ProbeProviders(context.Background())
+			}); err != nil {
+				logger.Errorf("❌ 注册AI服务熔断探测失败: %v", err)
+				return nil
+			}
+			logger.Infof("✓ AI service breaker probe scheduled: %s", spec)
+			return nil
+		}),
+
+		// 标题候选投稿数据定时获取
+		fx.Invoke(func(task *cron.Cron, db *gorm.DB, config *types.AppConfig, logger *zap.SugaredLogger) error {
+			metaConfig := config.MetadataConfig
+			if metaConfig == nil || metaConfig.TitleVariants < 2 || metaConfig.StatsCron == "" || metaConfig.StatsCron == "-" {
+				return nil
+			}
+			if _, err := task.AddFunc(metaConfig.StatsCron, func() {
+				updated, err := services.NewMetadataVariantService(db).RefreshStats(bilibili.NewClient().GetVideoStat, metaConfig.StatsWindowDays)
+				if err != nil {
+					logger.Warnf("⚠️ 部分投稿播放数据获取失败: %v", err)
+				}
+				if updated > 0 {
+					logger.Infof("📊 已更新 %d 个投稿的播放数据", updated)
+				}
+			}); err != nil {
+				logger.Errorf("❌ 注册投稿播放数据定时任务失败: %v", err)
+				return nil
+			}
+			logger.Infof("✓ Title variant stats scheduled: %s", metaConfig.StatsCron)
+			return nil
+		}),
+
 		fx.Provide(chain_task.NewChainTaskHandler),
 		fx.Invoke(func(h *chain_task.ChainTaskHandler) {
 			// 设置并启动任务消费者（准备阶段：下载、字幕、翻译、元数据）
@@ -194,15 +250,23 @@ func main() {
 			savedVideoService *services.SavedVideoService,
 			taskStepService *services.TaskStepService,
 			uploadScheduler *chain_task.UploadScheduler,
+			ytdlpUpdater *utils.YtDlpUpdater,
 			analyticsMiddleware *analytics.Middleware,
 			analyticsClient *analytics.Client,
 			membershipHandler *membership.MembershipHandler,
+			membershipStore membership.MembershipStore,
 			authHandler *auth.AuthHandler,
 			authMiddleware *auth.AuthMiddleware,
 		) {
 			// 初始化服务器
 			server.Init(db)
 
+			// 启动代理池健康检查
+			if server.ProxyPool.Enabled() {
+				server.ProxyPool.Start()
+				logger.Info("✓ Proxy pool health check started")
+			}
+
 			// 添加分析中间件
 			if analyticsMiddleware != nil {
 				server.Engine.Use(analyticsMiddleware.Handler())
@@ -210,7 +274,7 @@ func main() {
 			}
 
 			// 注册所有 Handler 路由（包括连接 VideoHandler 和 UploadScheduler）
-			registerHandlers(server, logger, savedVideoService, taskStepService, uploadScheduler, analyticsClient, membershipHandler, authHandler, authMiddleware)
+			registerHandlers(server, logger, savedVideoService, taskStepService, uploadScheduler, ytdlpUpdater, analyticsClient, membershipHandler, membershipStore, authHandler, authMiddleware)
 
 			// 健康检查
 			server.Engine.GET("/health", func(c *gin.Context) {
@@ -261,7 +325,7 @@ func main() {
 					logger.Info("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 
 					// 1. 检查 OpenAI 兼容 API（用户首选）
-					if config.OpenAICompatibleConfig != nil && config.OpenAICompatibleConfig.Enabled && config.OpenAICompatibleConfig.ApiKey != "" {
+					if config.OpenAICompatibleConfig != nil && config.OpenAICompatibleConfig.Enabled && config.OpenAICompatibleConfig.HasApiKey() {
 						providerName := "自定义API"
 						switch config.OpenAICompatibleConfig.Provider {
 						case "openai":
@@ -281,7 +345,9 @@ func main() {
 						logger.Infof("│  📦 提供商: %s", providerName)
 						logger.Infof("│  🔧 模型: %s", config.OpenAICompatibleConfig.Model)
 						logger.Infof("│  🌐 API地址: %s", config.OpenAICompatibleConfig.BaseURL)
-						if len(config.OpenAICompatibleConfig.ApiKey) > 10 {
+						if keyCount := len(config.OpenAICompatibleConfig.Keys()); keyCount > 1 {
+							logger.Infof("│  🔑 使用 API Key 轮询 (%d 个密钥)", keyCount)
+						} else if len(config.OpenAICompatibleConfig.ApiKey) > 10 {
 							logger.Infof("│  🔑 API Key: %s...%s",
 								config.OpenAICompatibleConfig.ApiKey[:6],
 								config.OpenAICompatibleConfig.ApiKey[len(config.OpenAICompatibleConfig.ApiKey)-4:])
@@ -292,10 +358,12 @@ func main() {
 					}
 
 					// 2. 检查 DeepSeek
-					if config.DeepSeekTransConfig != nil && config.DeepSeekTransConfig.Enabled && config.DeepSeekTransConfig.ApiKey != "" {
+					if config.DeepSeekTransConfig != nil && config.DeepSeekTransConfig.Enabled && config.DeepSeekTransConfig.HasApiKey() {
 						logger.Info("┌─ 📘 DeepSeek 服务")
 						logger.Infof("│  🔧 模型: %s", config.DeepSeekTransConfig.Model)
-						if len(config.DeepSeekTransConfig.ApiKey) > 10 {
+						if keyCount := len(config.DeepSeekTransConfig.Keys()); keyCount > 1 {
+							logger.Infof("│  🔑 使用 API Key 轮询 (%d 个密钥)", keyCount)
+						} else if len(config.DeepSeekTransConfig.ApiKey) > 10 {
 							logger.Infof("│  🔑 API Key: %s...%s",
 								config.DeepSeekTransConfig.ApiKey[:6],
 								config.DeepSeekTransConfig.ApiKey[len(config.DeepSeekTransConfig.ApiKey)-4:])
@@ -311,7 +379,7 @@ func main() {
 
 					// 3. 检查 Gemini（原生多模态）
 					geminiHasKey := config.GeminiConfig != nil && config.GeminiConfig.Enabled &&
-						(config.GeminiConfig.ApiKey != "" || len(config.GeminiConfig.ApiKeys) > 0)
+						config.GeminiConfig.HasApiKey()
 					if geminiHasKey {
 						logger.Info("┌─ 🔮 Gemini 原生多模态服务")
 						logger.Infof("│  🔧 模型: %s", config.GeminiConfig.Model)
@@ -336,12 +404,12 @@ func main() {
 					primaryService := config.PrimaryAIService
 					if primaryService == "" {
 						// 如果用户未选择，自动选择第一个启用的服务
-						if config.OpenAICompatibleConfig != nil && config.OpenAICompatibleConfig.Enabled && config.OpenAICompatibleConfig.ApiKey != "" {
+						if config.OpenAICompatibleConfig != nil && config.OpenAICompatibleConfig.Enabled && config.OpenAICompatibleConfig.HasApiKey() {
 							primaryService = "openai_compatible"
-						} else if config.DeepSeekTransConfig != nil && config.DeepSeekTransConfig.Enabled && config.DeepSeekTransConfig.ApiKey != "" {
+						} else if config.DeepSeekTransConfig != nil && config.DeepSeekTransConfig.Enabled && config.DeepSeekTransConfig.HasApiKey() {
 							primaryService = "deepseek"
 						} else if config.GeminiConfig != nil && config.GeminiConfig.Enabled &&
-							(config.GeminiConfig.ApiKey != "" || len(config.GeminiConfig.ApiKeys) > 0) {
+							config.GeminiConfig.HasApiKey() {
 							primaryService = "gemini"
 						}
 					}
@@ -427,8 +495,10 @@ func registerHandlers(
 	savedVideoService *services.SavedVideoService,
 	taskStepService *services.TaskStepService,
 	uploadScheduler *chain_task.UploadScheduler,
+	ytdlpUpdater *utils.YtDlpUpdater,
 	analyticsClient *analytics.Client,
 	membershipHandler *membership.MembershipHandler,
+	membershipStore membership.MembershipStore,
 	authHandler *auth.AuthHandler,
 	authMiddleware *auth.AuthMiddleware,
 ) {
@@ -475,6 +545,47 @@ func registerHandlers(
 	configHandler.RegisterRoutes(server)
 	logger.Info("✓ Config routes registered")
 
+	// Cookies 管理 Handler
+	cookieHandler := handler.NewCookieHandler(server, services.NewCookieProfileService(server.DB, server.Config))
+	cookieHandler.RegisterRoutes(server)
+	logger.Info("✓ Cookie routes registered")
+
+	// 翻译术语表 Handler
+	glossaryHandler := handler.NewGlossaryHandler(server, services.NewGlossaryService(server.DB))
+	glossaryHandler.RegisterRoutes(server)
+	logger.Info("✓ Glossary routes registered")
+
+	// 提示词模板 Handler
+	promptHandler := handler.NewPromptHandler(server, services.NewPromptService(server.DB))
+	promptHandler.RegisterRoutes(server)
+	logger.Info("✓ Prompt template routes registered")
+
+	// 标题候选 Handler
+	metadataVariantHandler := handler.NewMetadataVariantHandler(server, services.NewMetadataVariantService(server.DB))
+	metadataVariantHandler.RegisterRoutes(server)
+	logger.Info("✓ Metadata variant routes registered")
+
+	// 翻译记忆 Handler
+	translationMemoryHandler := handler.NewTranslationMemoryHandler(server, services.NewTranslationMemoryService(server.DB, server.Config))
+	translationMemoryHandler.RegisterRoutes(server)
+	logger.Info("✓ Translation memory routes registered")
+
+	// 字幕编辑器 Handler
+	subtitleEditorHandler := handler.NewSubtitleEditorHandler(server, savedVideoService, services.NewSubtitleEditService(server.DB, server.Config))
+	subtitleEditorHandler.RegisterRoutes(server)
+	logger.Info("✓ Subtitle editor routes registered")
+
+	// AI用量统计 Handler
+	quotaService := membership.NewQuotaService(membershipStore, membership.NewFeatureChecker(membershipStore))
+	aiUsageHandler := handler.NewAIUsageHandler(server, services.NewAIUsageService(server.DB, server.Config), quotaService)
+	aiUsageHandler.RegisterRoutes(server)
+	logger.Info("✓ AI usage routes registered")
+
+	// yt-dlp 版本管理 Handler
+	ytdlpHandler := handler.NewYtDlpHandler(server, ytdlpUpdater)
+	ytdlpHandler.RegisterRoutes(server)
+	logger.Info("✓ yt-dlp routes registered")
+
 	// 会员 Handler
 	membershipHandler.RegisterRoutes(server.Engine.Group("/api/v1"))
 	logger.Info("✓ Membership routes registered")
@@ -482,6 +593,18 @@ func registerHandlers(
 	logger.Info("All handlers registered successfully")
 }
 
+// ytdlpOptions 根据配置构建 yt-dlp 安装/更新选项
+func ytdlpOptions(config *types.AppConfig) utils.YtDlpOptions {
+	options := utils.YtDlpOptions{VerifyChecksum: true}
+	if config != nil && config.YtDlpConfig != nil {
+		options.ReleaseAPIURL = config.YtDlpConfig.ReleaseAPIURL
+		options.PinnedVersion = config.YtDlpConfig.PinnedVersion
+		options.VerifyChecksum = config.YtDlpConfig.VerifyChecksum
+		options.SmokeTestURL = config.YtDlpConfig.SmokeTestURL
+	}
+	return options
+}
+
 // checkYtDlpInstallation 检查并自动安装 yt-dlp
 func checkYtDlpInstallation(logger *zap.SugaredLogger, config *types.AppConfig) error {
 	// 从配置中获取安装目录，如果未配置则使用默认值
@@ -490,8 +613,8 @@ func checkYtDlpInstallation(logger *zap.SugaredLogger, config *types.AppConfig)
 		installDir = config.YtDlpPath
 	}
 
-	// 创建 yt-dlp 管理器
-	manager := utils.NewYtDlpManager(logger, installDir)
+	// 创建 yt-dlp 管理器（按配置的固定版本、Release 地址安装）
+	manager := utils.NewYtDlpManagerWithOptions(logger, installDir, ytdlpOptions(config))
 
 	// 检查并自动安装
 	if err := manager.CheckAndInstall(); err != nil {
@@ -0,0 +1,357 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/googleapis/gax-go/v2/apierror"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc/codes"
+)
+
+// Gemini 默认配置
+const (
+	GeminiModel     = "gemini-1.5-pro"
+	GeminiTimeout   = 120 * time.Second
+	GeminiMaxTokens = 8000
+)
+
+// fileProcessingInterval 轮询上传文件处理状态的间隔
+const fileProcessingInterval = 2 * time.Second
+
+// Gemini Gemini 原生 API 后端（genai SDK），支持视频、音频、图片文件输入
+// 注意：原生 API 必须使用官方地址，不支持自定义代理（文件上传需要官方端点），BaseURL 被忽略
+type Gemini struct {
+	config Config
+	client *genai.Client
+}
+
+// NewGemini 创建 Gemini 后端
+func NewGemini(ctx context.Context, cfg Config) (*Gemini, error) {
+	client, err := genai.NewClient(ctx, option.WithAPIKey(cfg.APIKey))
+	if err != nil {
+		return nil, fmt.Errorf("创建 Gemini 客户端失败: %v", err)
+	}
+
+	if cfg.Model == "" {
+		cfg.Model = GeminiModel
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = GeminiTimeout
+	}
+	if cfg.MaxTokens <= 0 {
+		cfg.MaxTokens = GeminiMaxTokens
+	}
+	return &Gemini{config: cfg.withDefaults(), client: client}, nil
+}
+
+func (g *Gemini) Name() string  { return "gemini" }
+func (g *Gemini) Model() string { return g.config.Model }
+func (g *Gemini) Close() error  { return g.client.Close() }
+
+// Chat 对话补全；请求中的文件先上传并等待处理完成，结束后删除
+func (g *Gemini) Chat(ctx context.Context, req *Request) (*Response, error) {
+	model, history, parts, cleanup, err := g.prepare(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	var result *Response
+	err = retry(ctx, g.config, func() error {
+		attemptCtx, cancel := context.WithTimeout(ctx, g.config.Timeout)
+		defer cancel()
+
+		session := model.StartChat()
+		session.History = history
+		resp, err := session.SendMessage(attemptCtx, parts...)
+		if err != nil {
+			return g.apiError(err)
+		}
+		r, err := g.toResponse(resp)
+		if err != nil {
+			return err
+		}
+		result = r
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Stream 流式对话补全；已开始输出后失败不再重试
+func (g *Gemini) Stream(ctx context.Context, req *Request, onDelta func(delta string) error) (*Response, error) {
+	model, history, parts, cleanup, err := g.prepare(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	var result *Response
+	err = retry(ctx, g.config, func() error {
+		session := model.StartChat()
+		session.History = history
+		iter := session.SendMessageStream(ctx, parts...)
+
+		var content strings.Builder
+		r := &Response{Model: g.config.Model}
+		for {
+			resp, err := iter.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				if content.Len() > 0 {
+					return permanent(g.apiError(err))
+				}
+				return g.apiError(err)
+			}
+			if resp.UsageMetadata != nil {
+				r.Usage = usageFromGemini(resp.UsageMetadata)
+			}
+			if len(resp.Candidates) == 0 {
+				continue
+			}
+			if resp.Candidates[0].FinishReason != genai.FinishReasonUnspecified {
+				r.FinishReason = resp.Candidates[0].FinishReason.String()
+			}
+			if delta := candidateText(resp.Candidates[0]); delta != "" {
+				content.WriteString(delta)
+				if err := onDelta(delta); err != nil {
+					return permanent(err)
+				}
+			}
+		}
+
+		r.Content = content.String()
+		result = r
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// prepare 创建模型，上传文件，并把消息拆分为历史和本轮输入
+func (g *Gemini) prepare(ctx context.Context, req *Request) (*genai.GenerativeModel, []*genai.Content, []genai.Part, func(), error) {
+	name := g.config.Model
+	if req.Model != "" {
+		name = req.Model
+	}
+	model := g.client.GenerativeModel(name)
+
+	maxTokens := g.config.MaxTokens
+	if req.MaxTokens > 0 {
+		maxTokens = req.MaxTokens
+	}
+	model.SetMaxOutputTokens(int32(maxTokens))
+	if temperature := req.Temperature; temperature > 0 {
+		model.SetTemperature(float32(temperature))
+	} else if g.config.Temperature > 0 {
+		model.SetTemperature(float32(g.config.Temperature))
+	}
+	if req.JSON || req.Schema != nil {
+		model.ResponseMIMEType = "application/json"
+		model.ResponseSchema = geminiSchema(req.Schema)
+	}
+
+	// 降低过滤级别以处理更多类型的视频内容
+	model.SafetySettings = []*genai.SafetySetting{
+		{Category: genai.HarmCategoryHarassment, Threshold: genai.HarmBlockNone},
+		{Category: genai.HarmCategoryHateSpeech, Threshold: genai.HarmBlockNone},
+		{Category: genai.HarmCategorySexuallyExplicit, Threshold: genai.HarmBlockNone},
+		{Category: genai.HarmCategoryDangerousContent, Threshold: genai.HarmBlockNone},
+	}
+
+	var uploaded []*genai.File
+	cleanup := func() {
+		for _, file := range uploaded {
+			// 上传的文件 48 小时后也会自动删除，删除失败无需处理
+			_ = g.client.DeleteFile(context.Background(), file.Name)
+		}
+	}
+
+	var system []string
+	var contents []*genai.Content
+	for _, msg := range req.Messages {
+		if msg.Role == RoleSystem {
+			system = append(system, msg.Content)
+			continue
+		}
+
+		var parts []genai.Part
+		for _, file := range msg.Files {
+			f, err := g.upload(ctx, file)
+			if err != nil {
+				cleanup()
+				return nil, nil, nil, nil, err
+			}
+			uploaded = append(uploaded, f)
+			parts = append(parts, genai.FileData{URI: f.URI, MIMEType: f.MIMEType})
+		}
+		if msg.Content != "" {
+			parts = append(parts, genai.Text(msg.Content))
+		}
+
+		role := "user"
+		if msg.Role == RoleAssistant {
+			role = "model"
+		}
+		contents = append(contents, &genai.Content{Role: role, Parts: parts})
+	}
+	if len(system) > 0 {
+		model.SystemInstruction = genai.NewUserContent(genai.Text(strings.Join(system, "\n\n")))
+	}
+	if len(contents) == 0 {
+		cleanup()
+		return nil, nil, nil, nil, fmt.Errorf("请求中没有用户消息")
+	}
+
+	last := contents[len(contents)-1]
+	return model, contents[:len(contents)-1], last.Parts, cleanup, nil
+}
+
+// upload 上传文件并等待处理完成
+func (g *Gemini) upload(ctx context.Context, file File) (*genai.File, error) {
+	opts := &genai.UploadFileOptions{}
+	if mimeType := file.mimeType(); mimeType != "application/octet-stream" {
+		opts.MIMEType = mimeType
+	}
+	uploaded, err := g.client.UploadFileFromPath(ctx, file.Path, opts)
+	if err != nil {
+		return nil, fmt.Errorf("上传文件失败: %v", err)
+	}
+
+	for uploaded.State == genai.FileStateProcessing {
+		select {
+		case <-ctx.Done():
+			_ = g.client.DeleteFile(context.Background(), uploaded.Name)
+			return nil, fmt.Errorf("等待文件处理超时: %v", ctx.Err())
+		case <-time.After(fileProcessingInterval):
+		}
+		if uploaded, err = g.client.GetFile(ctx, uploaded.Name); err != nil {
+			return nil, fmt.Errorf("获取文件状态失败: %v", err)
+		}
+	}
+	if uploaded.State == genai.FileStateFailed {
+		_ = g.client.DeleteFile(context.Background(), uploaded.Name)
+		return nil, fmt.Errorf("文件处理失败")
+	}
+	return uploaded, nil
+}
+
+// toResponse 提取第一个候选结果
+func (g *Gemini) toResponse(resp *genai.GenerateContentResponse) (*Response, error) {
+	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil || len(resp.Candidates[0].Content.Parts) == 0 {
+		if resp.PromptFeedback != nil && resp.PromptFeedback.BlockReason != genai.BlockReasonUnspecified {
+			return nil, permanent(fmt.Errorf("内容被拦截: %s", resp.PromptFeedback.BlockReason))
+		}
+		return nil, fmt.Errorf("未生成任何内容")
+	}
+
+	r := &Response{
+		Content:      candidateText(resp.Candidates[0]),
+		Model:        g.config.Model,
+		FinishReason: resp.Candidates[0].FinishReason.String(),
+	}
+	if resp.UsageMetadata != nil {
+		r.Usage = usageFromGemini(resp.UsageMetadata)
+	}
+	return r, nil
+}
+
+// apiError 将 SDK 错误转换为 APIError，便于判断限流和是否可重试
+func (g *Gemini) apiError(err error) error {
+	var ae *apierror.APIError
+	if !errors.As(err, &ae) {
+		return fmt.Errorf("生成内容失败: %v", err)
+	}
+	status := ae.HTTPCode()
+	if status <= 0 && ae.GRPCStatus() != nil {
+		status = httpStatusFromCode(ae.GRPCStatus().Code())
+	}
+	return &APIError{Provider: g.Name(), StatusCode: status, Message: ae.Error()}
+}
+
+// httpStatusFromCode gRPC 状态码对应的 HTTP 状态码
+func httpStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return 400
+	case codes.Unauthenticated:
+		return 401
+	case codes.PermissionDenied:
+		return 403
+	case codes.NotFound:
+		return 404
+	case codes.ResourceExhausted:
+		return 429
+	case codes.Unavailable:
+		return 503
+	case codes.DeadlineExceeded:
+		return 504
+	default:
+		return 500
+	}
+}
+
+// candidateText 拼接候选结果中的文本片段
+func candidateText(candidate *genai.Candidate) string {
+	if candidate.Content == nil {
+		return ""
+	}
+	var text strings.Builder
+	for _, part := range candidate.Content.Parts {
+		if t, ok := part.(genai.Text); ok {
+			text.WriteString(string(t))
+		}
+	}
+	return text.String()
+}
+
+func usageFromGemini(usage *genai.UsageMetadata) Usage {
+	return Usage{
+		PromptTokens:     int(usage.PromptTokenCount),
+		CompletionTokens: int(usage.CandidatesTokenCount),
+		TotalTokens:      int(usage.TotalTokenCount),
+	}
+}
+
+// geminiTypes JSON Schema 类型对应的 Gemini 类型
+var geminiTypes = map[string]genai.Type{
+	"object":  genai.TypeObject,
+	"array":   genai.TypeArray,
+	"string":  genai.TypeString,
+	"integer": genai.TypeInteger,
+	"number":  genai.TypeNumber,
+	"boolean": genai.TypeBoolean,
+}
+
+// geminiSchema 转换为 Gemini 的响应 Schema
+func geminiSchema(schema *Schema) *genai.Schema {
+	if schema == nil {
+		return nil
+	}
+	result := &genai.Schema{
+		Type:        geminiTypes[schema.Type],
+		Description: schema.Description,
+		Items:       geminiSchema(schema.Items),
+		Required:    schema.Required,
+		Enum:        schema.Enum,
+	}
+	if len(schema.Properties) > 0 {
+		result.Properties = make(map[string]*genai.Schema, len(schema.Properties))
+		for name, property := range schema.Properties {
+			result.Properties[name] = geminiSchema(property)
+		}
+	}
+	return result
+}
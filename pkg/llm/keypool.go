@@ -0,0 +1,371 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// 密钥选择策略
+const (
+	KeyRoundRobin = "round_robin" // 轮询（默认）
+	KeyLeastUsed  = "least_used"  // 选择今日请求最少的密钥
+	KeyFailover   = "failover"    // 固定使用当前密钥，被限流或失效后才切换到下一个
+)
+
+// 密钥状态
+const (
+	KeyAvailable = "available" // 可用
+	KeyCooling   = "cooling"   // 被限流或认证失败后冷却中
+	KeyThrottled = "throttled" // 达到每分钟请求数限制
+	KeyExhausted = "exhausted" // 达到今日请求数或 token 配额
+)
+
+const (
+	defaultKeyCooldown = 60 * time.Second
+	invalidKeyCooldown = 10 * time.Minute // 认证失败（401/403）的密钥暂停使用的时间
+)
+
+// KeyPoolPolicy 密钥池规则，限额针对单个密钥，0 表示不限制
+type KeyPoolPolicy struct {
+	Strategy          string `json:"strategy"`            // round_robin / least_used / failover
+	RequestsPerMinute int    `json:"requests_per_minute"` // 每分钟请求数
+	DailyRequests     int    `json:"daily_requests"`      // 每日请求数
+	DailyTokens       int    `json:"daily_tokens"`        // 每日 token 数
+	CooldownSeconds   int    `json:"cooldown_seconds"`    // 被限流后的冷却时间（秒），默认 60
+}
+
+// KeyPool 一个服务的多个 API Key：按策略选择密钥，记录每个密钥的用量、限额和健康状态
+type KeyPool struct {
+	mu     sync.Mutex
+	name   string
+	policy KeyPoolPolicy
+	keys   []*poolKey
+	next   int
+}
+
+type poolKey struct {
+	key            string
+	inFlight       int
+	totalRequests  int64
+	totalFailures  int64
+	rateLimited    int64
+	minuteStart    time.Time
+	minuteRequests int
+	day            string
+	dayRequests    int
+	dayTokens      int
+	cooldownUntil  time.Time
+	lastError      string
+	lastUsedAt     time.Time
+}
+
+// KeyStatus 密钥状态快照（密钥脱敏）
+type KeyStatus struct {
+	Index          int        `json:"index"`
+	Key            string     `json:"key"`
+	State          string     `json:"state"`
+	InFlight       int        `json:"in_flight"`
+	TotalRequests  int64      `json:"total_requests"`
+	TotalFailures  int64      `json:"total_failures"`
+	RateLimited    int64      `json:"rate_limited"`
+	MinuteRequests int        `json:"minute_requests"`
+	DayRequests    int        `json:"day_requests"`
+	DayTokens      int        `json:"day_tokens"`
+	CooldownUntil  *time.Time `json:"cooldown_until,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	LastUsedAt     *time.Time `json:"last_used_at,omitempty"`
+}
+
+// NewKeyPool 创建密钥池
+func NewKeyPool(name string, keys []string, policy KeyPoolPolicy) *KeyPool {
+	p := &KeyPool{name: name}
+	p.update(keys, policy)
+	return p
+}
+
+// update 更新密钥和规则，保留未变化的密钥的状态
+func (p *KeyPool) update(keys []string, policy KeyPoolPolicy) {
+	existing := make(map[string]*poolKey, len(p.keys))
+	for _, k := range p.keys {
+		existing[k.key] = k
+	}
+	entries := make([]*poolKey, 0, len(keys))
+	for _, key := range keys {
+		if k, ok := existing[key]; ok {
+			entries = append(entries, k)
+		} else {
+			entries = append(entries, &poolKey{key: key})
+		}
+	}
+	p.keys = entries
+	p.policy = policy
+	if p.next >= len(entries) {
+		p.next = 0
+	}
+}
+
+// Size 密钥数量
+func (p *KeyPool) Size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.keys)
+}
+
+// state 密钥在 now 时的状态（会按需重置每分钟和每日计数）
+func (p *KeyPool) state(k *poolKey, now time.Time) string {
+	if now.Sub(k.minuteStart) >= time.Minute {
+		k.minuteStart, k.minuteRequests = now, 0
+	}
+	if today := now.Format("2006-01-02"); k.day != today {
+		k.day, k.dayRequests, k.dayTokens = today, 0, 0
+	}
+
+	switch {
+	case now.Before(k.cooldownUntil):
+		return KeyCooling
+	case p.policy.DailyRequests > 0 && k.dayRequests >= p.policy.DailyRequests,
+		p.policy.DailyTokens > 0 && k.dayTokens >= p.policy.DailyTokens:
+		return KeyExhausted
+	case p.policy.RequestsPerMinute > 0 && k.minuteRequests >= p.policy.RequestsPerMinute:
+		return KeyThrottled
+	default:
+		return KeyAvailable
+	}
+}
+
+// KeyLease 一次请求占用的密钥，请求结束后调用 Release
+type KeyLease struct {
+	Key   string
+	Index int
+	pool  *KeyPool
+	entry *poolKey
+}
+
+// Acquire 按策略选择一个可用的密钥；全部不可用时返回限流错误
+func (p *KeyPool) Acquire(now time.Time) (*KeyLease, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	n := len(p.keys)
+	if n == 0 {
+		return nil, fmt.Errorf("%s 未配置 API Key", p.name)
+	}
+
+	chosen := -1
+	switch p.policy.Strategy {
+	case KeyLeastUsed:
+		for i, k := range p.keys {
+			if p.state(k, now) != KeyAvailable {
+				continue
+			}
+			if chosen < 0 || k.dayRequests+k.inFlight < p.keys[chosen].dayRequests+p.keys[chosen].inFlight {
+				chosen = i
+			}
+		}
+	default:
+		for i := 0; i < n; i++ {
+			index := (p.next + i) % n
+			if p.state(p.keys[index], now) == KeyAvailable {
+				chosen = index
+				break
+			}
+		}
+		if chosen >= 0 {
+			p.next = chosen
+			if p.policy.Strategy != KeyFailover {
+				p.next = (chosen + 1) % n
+			}
+		}
+	}
+
+	if chosen < 0 {
+		return nil, &APIError{
+			Provider:   p.name,
+			StatusCode: http.StatusTooManyRequests,
+			Message:    fmt.Sprintf("%d 个 API Key 均在冷却中或已达到限额", n),
+		}
+	}
+
+	k := p.keys[chosen]
+	k.inFlight++
+	k.totalRequests++
+	k.minuteRequests++
+	k.dayRequests++
+	k.lastUsedAt = now
+	return &KeyLease{Key: k.key, Index: chosen, pool: p, entry: k}, nil
+}
+
+// Release 记录请求结果：被限流的密钥进入冷却，认证失败的密钥暂停使用
+func (l *KeyLease) Release(err error, tokens int) {
+	p := l.pool
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	k := l.entry
+	k.inFlight--
+	k.dayTokens += tokens
+	if err == nil {
+		k.lastError = ""
+		return
+	}
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	k.totalFailures++
+	k.lastError = err.Error()
+	switch {
+	case IsRateLimited(err):
+		k.rateLimited++
+		cooldown := defaultKeyCooldown
+		if p.policy.CooldownSeconds > 0 {
+			cooldown = time.Duration(p.policy.CooldownSeconds) * time.Second
+		}
+		k.cooldownUntil = time.Now().Add(cooldown)
+	case isUnauthorized(err):
+		k.cooldownUntil = time.Now().Add(invalidKeyCooldown)
+	}
+}
+
+// Status 各密钥的状态
+func (p *KeyPool) Status(now time.Time) []KeyStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	statuses := make([]KeyStatus, len(p.keys))
+	for i, k := range p.keys {
+		status := KeyStatus{
+			Index:          i,
+			Key:            MaskKey(k.key),
+			State:          p.state(k, now),
+			InFlight:       k.inFlight,
+			TotalRequests:  k.totalRequests,
+			TotalFailures:  k.totalFailures,
+			RateLimited:    k.rateLimited,
+			MinuteRequests: k.minuteRequests,
+			DayRequests:    k.dayRequests,
+			DayTokens:      k.dayTokens,
+			LastError:      k.lastError,
+		}
+		if now.Before(k.cooldownUntil) {
+			until := k.cooldownUntil
+			status.CooldownUntil = &until
+		}
+		if !k.lastUsedAt.IsZero() {
+			usedAt := k.lastUsedAt
+			status.LastUsedAt = &usedAt
+		}
+		statuses[i] = status
+	}
+	return statuses
+}
+
+// MaskKey 脱敏显示密钥
+func MaskKey(key string) string {
+	if len(key) > 10 {
+		return key[:6] + "..." + key[len(key)-4:]
+	}
+	return "***"
+}
+
+// isUnauthorized 密钥无效或无权限
+func isUnauthorized(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden)
+}
+
+// 进程内共享的密钥池：同一服务的所有任务步骤共享轮换位置、限额和冷却状态
+var sharedKeyPools = struct {
+	sync.Mutex
+	pools map[string]*KeyPool
+}{pools: make(map[string]*KeyPool)}
+
+// SharedKeyPool 获取服务的共享密钥池，密钥或规则变化（如修改了配置）时就地更新
+func SharedKeyPool(name string, keys []string, policy KeyPoolPolicy) *KeyPool {
+	sharedKeyPools.Lock()
+	defer sharedKeyPools.Unlock()
+
+	pool, ok := sharedKeyPools.pools[name]
+	if !ok {
+		pool = NewKeyPool(name, keys, policy)
+		sharedKeyPools.pools[name] = pool
+		return pool
+	}
+
+	pool.mu.Lock()
+	pool.update(keys, policy)
+	pool.mu.Unlock()
+	return pool
+}
+
+// Pooled 使用密钥池的 Provider：每次请求按策略选择密钥，被限流或密钥失效时换用下一个可用的密钥
+type Pooled struct {
+	name        string
+	model       string
+	pool        *KeyPool
+	newProvider func(ctx context.Context, apiKey string) (Provider, error)
+}
+
+// NewPooled 创建使用密钥池的 Provider，newProvider 用指定密钥创建实际的服务
+func NewPooled(name, model string, pool *KeyPool, newProvider func(ctx context.Context, apiKey string) (Provider, error)) *Pooled {
+	return &Pooled{name: name, model: model, pool: pool, newProvider: newProvider}
+}
+
+func (p *Pooled) Name() string  { return p.name }
+func (p *Pooled) Model() string { return p.model }
+func (p *Pooled) Close() error  { return nil }
+
+// Pool 使用的密钥池
+func (p *Pooled) Pool() *KeyPool { return p.pool }
+
+func (p *Pooled) Chat(ctx context.Context, req *Request) (*Response, error) {
+	return p.do(ctx, func(client Provider) (*Response, error) {
+		return client.Chat(ctx, req)
+	})
+}
+
+func (p *Pooled) Stream(ctx context.Context, req *Request, onDelta func(delta string) error) (*Response, error) {
+	return p.do(ctx, func(client Provider) (*Response, error) {
+		return client.Stream(ctx, req, onDelta)
+	})
+}
+
+func (p *Pooled) do(ctx context.Context, call func(client Provider) (*Response, error)) (*Response, error) {
+	var lastErr error
+	for attempt := 0; attempt < p.pool.Size(); attempt++ {
+		lease, err := p.pool.Acquire(time.Now())
+		if err != nil {
+			if lastErr != nil {
+				return nil, lastErr
+			}
+			return nil, err
+		}
+
+		client, err := p.newProvider(ctx, lease.Key)
+		if err != nil {
+			lease.Release(err, 0)
+			return nil, err
+		}
+		resp, err := call(client)
+		client.Close()
+
+		tokens := 0
+		if resp != nil {
+			tokens = resp.Usage.TotalTokens
+		}
+		lease.Release(err, tokens)
+		if err == nil {
+			return resp, nil
+		}
+
+		lastErr = err
+		if !IsRateLimited(err) && !isUnauthorized(err) {
+			return nil, err
+		}
+	}
+	return nil, lastErr
+}
@@ -0,0 +1,91 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestKeyPoolStrategies 测试轮询、最少使用、固定使用和每分钟限额
+func TestKeyPoolStrategies(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	acquire := func(p *KeyPool) string {
+		lease, err := p.Acquire(now)
+		if err != nil {
+			t.Fatalf("获取密钥失败: %v", err)
+		}
+		lease.Release(nil, 0)
+		return lease.Key
+	}
+
+	roundRobin := NewKeyPool("test", []string{"a", "b", "c"}, KeyPoolPolicy{})
+	var got []string
+	for i := 0; i < 4; i++ {
+		got = append(got, acquire(roundRobin))
+	}
+	if strings.Join(got, "") != "abca" {
+		t.Errorf("轮询顺序 = %v", got)
+	}
+
+	failover := NewKeyPool("test", []string{"a", "b"}, KeyPoolPolicy{Strategy: KeyFailover})
+	if acquire(failover) != "a" || acquire(failover) != "a" {
+		t.Error("failover 应固定使用第一个密钥")
+	}
+
+	leastUsed := NewKeyPool("test", []string{"a", "b"}, KeyPoolPolicy{Strategy: KeyLeastUsed})
+	held, _ := leastUsed.Acquire(now)
+	if key := acquire(leastUsed); key == held.Key {
+		t.Errorf("least_used 应选择未被占用的密钥, 得到 %s", key)
+	}
+	held.Release(nil, 0)
+
+	throttled := NewKeyPool("test", []string{"a"}, KeyPoolPolicy{RequestsPerMinute: 2})
+	acquire(throttled)
+	acquire(throttled)
+	if _, err := throttled.Acquire(now); !IsRateLimited(err) {
+		t.Errorf("超过每分钟限额应返回限流错误, 得到 %v", err)
+	}
+	if status := throttled.Status(now); status[0].State != KeyThrottled {
+		t.Errorf("状态 = %s", status[0].State)
+	}
+	if _, err := throttled.Acquire(now.Add(time.Minute)); err != nil {
+		t.Errorf("下一分钟应恢复: %v", err)
+	}
+}
+
+// TestPooledRotatesOnRateLimit 测试被限流的密钥进入冷却并换用下一个密钥
+func TestPooledRotatesOnRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer key-1" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"error":{"message":"quota exceeded"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"choices":[{"message":{"content":"ok"}}],"usage":{"prompt_tokens":3,"completion_tokens":2,"total_tokens":5}}`)
+	}))
+	defer server.Close()
+
+	pool := NewKeyPool("test", []string{"key-1", "key-2"}, KeyPoolPolicy{})
+	provider := NewPooled("test", "m", pool, func(ctx context.Context, apiKey string) (Provider, error) {
+		return NewOpenAICompatible(Config{APIKey: apiKey, BaseURL: server.URL, Model: "m", FailOnRateLimit: true}), nil
+	})
+
+	for i := 0; i < 2; i++ {
+		resp, err := provider.Chat(context.Background(), &Request{Messages: Messages("", "hi")})
+		if err != nil || resp.Content != "ok" {
+			t.Fatalf("第 %d 次请求失败: %v", i+1, err)
+		}
+	}
+
+	status := pool.Status(time.Now())
+	if status[0].State != KeyCooling || status[0].RateLimited != 1 {
+		t.Errorf("key-1 应在冷却中: %+v", status[0])
+	}
+	if status[1].TotalRequests != 2 || status[1].DayTokens != 10 {
+		t.Errorf("key-2 用量不正确: %+v", status[1])
+	}
+}
@@ -0,0 +1,207 @@
+// Package llm 统一的大模型调用接口
+// 翻译、元数据生成、字幕校验等都通过 Provider 调用 OpenAI 兼容 API、DeepSeek 或 Gemini，
+// HTTP 请求、重试和超时只在这里实现一份
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// 消息角色
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// ErrFilesNotSupported 后端不支持该类型的文件输入
+var ErrFilesNotSupported = errors.New("当前AI服务不支持该类型的文件输入")
+
+// File 多模态输入文件（视频、音频、图片），Path 为本地路径
+type File struct {
+	Path     string
+	MIMEType string // 为空时按扩展名推断
+}
+
+// Message 对话消息，Files 只对 user 消息生效
+type Message struct {
+	Role    string
+	Content string
+	Files   []File
+}
+
+// Request 对话请求，零值字段使用 Provider 的默认配置
+type Request struct {
+	Model       string
+	Messages    []Message
+	Temperature float64
+	MaxTokens   int
+	JSON        bool    // JSON 模式：要求模型只输出一个 JSON 对象
+	Schema      *Schema // 结构化输出：要求模型按 JSON Schema 输出（隐含 JSON 模式），不支持的服务退化为 JSON 模式
+}
+
+// Schema 结构化输出的 JSON Schema，只包含各服务都支持的部分
+type Schema struct {
+	Type        string             `json:"type"` // object、array、string、integer、number、boolean
+	Description string             `json:"description,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+}
+
+// Usage Token 使用量
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// Add 累加使用量
+func (u *Usage) Add(other Usage) {
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+	u.TotalTokens += other.TotalTokens
+}
+
+// Response 对话结果
+type Response struct {
+	Content      string
+	Model        string
+	FinishReason string
+	Usage        Usage
+}
+
+// Provider 大模型服务
+type Provider interface {
+	// Name 服务名称（openai_compatible、deepseek、gemini）
+	Name() string
+	// Model 默认模型
+	Model() string
+	// Chat 对话补全
+	Chat(ctx context.Context, req *Request) (*Response, error)
+	// Stream 流式对话补全，每收到一段内容调用一次 onDelta，返回完整结果
+	Stream(ctx context.Context, req *Request, onDelta func(delta string) error) (*Response, error)
+	// Close 释放连接
+	Close() error
+}
+
+// Config 后端配置
+type Config struct {
+	APIKey         string
+	BaseURL        string
+	Model          string
+	Timeout        time.Duration // 单次请求超时
+	MaxRetries     int           // 失败后的重试次数
+	RetryDelay     time.Duration // 重试间隔（按次数递增）
+	RateLimitDelay time.Duration // 限流时的重试间隔（按次数递增）
+	Temperature    float64
+	MaxTokens      int
+
+	FailOnRateLimit  bool // 限流时不重试直接返回（密钥池会换用下一个 API Key）
+	StructuredOutput bool // 支持 response_format=json_schema（否则结构化输出只使用 json_object）
+}
+
+// 默认值
+const (
+	DefaultTimeout        = 60 * time.Second
+	DefaultMaxRetries     = 3
+	DefaultRetryDelay     = 2 * time.Second
+	DefaultRateLimitDelay = 5 * time.Second
+)
+
+// withDefaults 补全未设置的通用配置
+func (c Config) withDefaults() Config {
+	if c.Timeout <= 0 {
+		c.Timeout = DefaultTimeout
+	}
+	if c.MaxRetries < 0 {
+		c.MaxRetries = 0
+	}
+	if c.RetryDelay <= 0 {
+		c.RetryDelay = DefaultRetryDelay
+	}
+	if c.RateLimitDelay <= 0 {
+		c.RateLimitDelay = DefaultRateLimitDelay
+	}
+	return c
+}
+
+// Seconds 将配置文件中的秒数转换为超时时间
+func Seconds(seconds int) time.Duration {
+	return time.Duration(seconds) * time.Second
+}
+
+// Messages 由系统提示词和用户提示词构造消息，系统提示词为空时省略
+func Messages(systemPrompt, userPrompt string) []Message {
+	var messages []Message
+	if systemPrompt != "" {
+		messages = append(messages, Message{Role: RoleSystem, Content: systemPrompt})
+	}
+	return append(messages, Message{Role: RoleUser, Content: userPrompt})
+}
+
+// Complete 单轮对话的便捷方法
+func Complete(ctx context.Context, p Provider, systemPrompt, userPrompt string) (*Response, error) {
+	return p.Chat(ctx, &Request{Messages: Messages(systemPrompt, userPrompt)})
+}
+
+// Ping 用一次简短的对话测试连接
+func Ping(ctx context.Context, p Provider) error {
+	resp, err := p.Chat(ctx, &Request{
+		Messages:    Messages("", "请回复：OK"),
+		Temperature: 0.1,
+		MaxTokens:   50,
+	})
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(resp.Content) == "" {
+		return fmt.Errorf("API 返回空响应")
+	}
+	return nil
+}
+
+// TrimCodeFence 去掉模型输出中包裹 JSON 的 markdown 代码块标记
+func TrimCodeFence(content string) string {
+	content = strings.TrimSpace(content)
+	if strings.HasPrefix(content, "```") {
+		content = strings.TrimPrefix(content, "```json")
+		content = strings.TrimPrefix(content, "```")
+		content = strings.TrimSuffix(content, "```")
+	}
+	return strings.TrimSpace(content)
+}
+
+// mediaTypes 系统 MIME 表中可能缺少的音视频类型
+var mediaTypes = map[string]string{
+	".mp4":  "video/mp4",
+	".webm": "video/webm",
+	".mkv":  "video/x-matroska",
+	".mov":  "video/quicktime",
+	".flv":  "video/x-flv",
+	".mp3":  "audio/mpeg",
+	".m4a":  "audio/mp4",
+	".wav":  "audio/wav",
+}
+
+// mimeType 文件的 MIME 类型，未指定时按扩展名推断
+func (f File) mimeType() string {
+	if f.MIMEType != "" {
+		return f.MIMEType
+	}
+	ext := strings.ToLower(filepath.Ext(f.Path))
+	if mimeType, ok := mediaTypes[ext]; ok {
+		return mimeType
+	}
+	if mimeType := mime.TypeByExtension(ext); mimeType != "" {
+		return strings.Split(mimeType, ";")[0]
+	}
+	return "application/octet-stream"
+}
@@ -0,0 +1,387 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// 默认服务地址和模型
+const (
+	OpenAIBaseURL   = "https://api.openai.com/v1"
+	OpenAIModel     = "gpt-3.5-turbo"
+	DeepSeekBaseURL = "https://api.deepseek.com/v1"
+	DeepSeekModel   = "deepseek-chat"
+)
+
+// OpenAICompatible OpenAI 兼容 API 后端
+// 支持任何兼容 OpenAI API 格式的服务，如 OpenAI、DeepSeek、通义千问、智谱AI、one-api/new-api 代理、Gemini 代理
+type OpenAICompatible struct {
+	name   string
+	config Config
+	apiURL string
+	client *http.Client
+}
+
+// NewOpenAICompatible 创建 OpenAI 兼容后端
+func NewOpenAICompatible(cfg Config) *OpenAICompatible {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = OpenAIBaseURL
+	}
+	if cfg.Model == "" {
+		cfg.Model = OpenAIModel
+	}
+	return newOpenAICompatible("openai_compatible", cfg)
+}
+
+// NewDeepSeek 创建 DeepSeek 后端（DeepSeek API 与 OpenAI 兼容）
+func NewDeepSeek(cfg Config) *OpenAICompatible {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = DeepSeekBaseURL
+	}
+	if cfg.Model == "" {
+		cfg.Model = DeepSeekModel
+	}
+	return newOpenAICompatible("deepseek", cfg)
+}
+
+func newOpenAICompatible(name string, cfg Config) *OpenAICompatible {
+	cfg = cfg.withDefaults()
+	return &OpenAICompatible{
+		name:   name,
+		config: cfg,
+		apiURL: ChatCompletionsURL(cfg.BaseURL),
+		client: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// versionPattern 路径中的 API 版本段（/v1、/v4、/v1beta）
+var versionPattern = regexp.MustCompile(`/v\d+[a-z]*(/|$)`)
+
+// ChatCompletionsURL 由服务地址得到 chat/completions 接口地址
+// 地址中没有版本段时补全 /v1，如 https://api.deepseek.com → https://api.deepseek.com/v1/chat/completions
+func ChatCompletionsURL(baseURL string) string {
+	apiURL := strings.TrimSuffix(baseURL, "/")
+	if strings.HasSuffix(apiURL, "/chat/completions") {
+		return apiURL
+	}
+	path := apiURL
+	if u, err := url.Parse(apiURL); err == nil {
+		path = u.Path
+	}
+	if !versionPattern.MatchString(path) {
+		apiURL += "/v1"
+	}
+	return apiURL + "/chat/completions"
+}
+
+func (c *OpenAICompatible) Name() string  { return c.name }
+func (c *OpenAICompatible) Model() string { return c.config.Model }
+func (c *OpenAICompatible) Close() error  { return nil }
+
+// openAIRequest OpenAI 格式请求
+type openAIRequest struct {
+	Model          string          `json:"model"`
+	Messages       []openAIMessage `json:"messages"`
+	Stream         bool            `json:"stream"`
+	StreamOptions  *streamOptions  `json:"stream_options,omitempty"`
+	Temperature    float64         `json:"temperature,omitempty"`
+	MaxTokens      int             `json:"max_tokens,omitempty"`
+	ResponseFormat *responseFormat `json:"response_format,omitempty"`
+}
+
+// openAIMessage Content 为字符串，或带图片时为内容片段数组
+type openAIMessage struct {
+	Role    string      `json:"role"`
+	Content interface{} `json:"content"`
+}
+
+type contentPart struct {
+	Type     string    `json:"type"`
+	Text     string    `json:"text,omitempty"`
+	ImageURL *imageURL `json:"image_url,omitempty"`
+}
+
+type imageURL struct {
+	URL string `json:"url"`
+}
+
+type streamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+type responseFormat struct {
+	Type       string            `json:"type"`
+	JSONSchema *jsonSchemaFormat `json:"json_schema,omitempty"`
+}
+
+type jsonSchemaFormat struct {
+	Name   string  `json:"name"`
+	Schema *Schema `json:"schema"`
+}
+
+// openAIResponse OpenAI 格式响应（流式响应的每个 chunk 结构相同，内容在 delta 中）
+type openAIResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *Usage `json:"usage"`
+	Error *struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+	} `json:"error,omitempty"`
+}
+
+// Chat 对话补全（带重试）
+func (c *OpenAICompatible) Chat(ctx context.Context, req *Request) (*Response, error) {
+	body, err := c.buildRequest(req, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *Response
+	err = retry(ctx, c.config, func() error {
+		data, err := c.post(ctx, body)
+		if err != nil {
+			return err
+		}
+
+		var response openAIResponse
+		if err := json.Unmarshal(data, &response); err != nil {
+			return fmt.Errorf("解析响应失败: %v, 原始响应: %s", err, truncate(string(data), 500))
+		}
+		if response.Error != nil {
+			return &APIError{Provider: c.name, Message: response.Error.Message}
+		}
+		if len(response.Choices) == 0 {
+			return fmt.Errorf("API响应中没有结果")
+		}
+
+		result = &Response{
+			Content:      response.Choices[0].Message.Content,
+			Model:        response.Model,
+			FinishReason: response.Choices[0].FinishReason,
+		}
+		if response.Usage != nil {
+			result.Usage = *response.Usage
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Stream 流式对话补全（SSE）；已开始输出后失败不再重试，避免重复输出
+func (c *OpenAICompatible) Stream(ctx context.Context, req *Request, onDelta func(delta string) error) (*Response, error) {
+	body, err := c.buildRequest(req, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *Response
+	err = retry(ctx, c.config, func() error {
+		httpReq, err := c.newHTTPRequest(ctx, body)
+		if err != nil {
+			return permanent(err)
+		}
+		// 流式响应的总时长不受单次请求超时限制，由 ctx 控制
+		resp, err := (&http.Client{Transport: c.client.Transport}).Do(httpReq)
+		if err != nil {
+			return fmt.Errorf("发送请求失败: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			data, _ := io.ReadAll(resp.Body)
+			return &APIError{Provider: c.name, StatusCode: resp.StatusCode, Message: truncate(string(data), 500)}
+		}
+
+		var content strings.Builder
+		r := &Response{Model: c.config.Model}
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if !strings.HasPrefix(line, "data:") {
+				continue
+			}
+			data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+			if data == "[DONE]" {
+				break
+			}
+
+			var chunk openAIResponse
+			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+				continue
+			}
+			if chunk.Error != nil {
+				return c.streamError(content.Len(), &APIError{Provider: c.name, Message: chunk.Error.Message})
+			}
+			if chunk.Model != "" {
+				r.Model = chunk.Model
+			}
+			if chunk.Usage != nil {
+				r.Usage = *chunk.Usage
+			}
+			if len(chunk.Choices) == 0 {
+				continue
+			}
+			if reason := chunk.Choices[0].FinishReason; reason != "" {
+				r.FinishReason = reason
+			}
+			if delta := chunk.Choices[0].Delta.Content; delta != "" {
+				content.WriteString(delta)
+				if err := onDelta(delta); err != nil {
+					return permanent(err)
+				}
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return c.streamError(content.Len(), fmt.Errorf("读取流式响应失败: %v", err))
+		}
+
+		r.Content = content.String()
+		result = r
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// streamError 已输出部分内容时错误不可重试
+func (c *OpenAICompatible) streamError(written int, err error) error {
+	if written > 0 {
+		return permanent(err)
+	}
+	return err
+}
+
+// buildRequest 构造请求体
+func (c *OpenAICompatible) buildRequest(req *Request, stream bool) ([]byte, error) {
+	request := openAIRequest{
+		Model:       c.config.Model,
+		Stream:      stream,
+		Temperature: c.config.Temperature,
+		MaxTokens:   c.config.MaxTokens,
+	}
+	if req.Model != "" {
+		request.Model = req.Model
+	}
+	if req.Temperature > 0 {
+		request.Temperature = req.Temperature
+	}
+	if req.MaxTokens > 0 {
+		request.MaxTokens = req.MaxTokens
+	}
+	if req.Schema != nil && c.config.StructuredOutput {
+		request.ResponseFormat = &responseFormat{Type: "json_schema", JSONSchema: &jsonSchemaFormat{Name: "response", Schema: req.Schema}}
+	} else if req.JSON || req.Schema != nil {
+		request.ResponseFormat = &responseFormat{Type: "json_object"}
+	}
+	if stream {
+		request.StreamOptions = &streamOptions{IncludeUsage: true}
+	}
+
+	for _, msg := range req.Messages {
+		message, err := toOpenAIMessage(msg)
+		if err != nil {
+			return nil, err
+		}
+		request.Messages = append(request.Messages, message)
+	}
+
+	data, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("序列化请求失败: %v", err)
+	}
+	return data, nil
+}
+
+// toOpenAIMessage 转换消息，图片以 data URL 内嵌（OpenAI 兼容 API 不支持视频、音频文件）
+func toOpenAIMessage(msg Message) (openAIMessage, error) {
+	if len(msg.Files) == 0 {
+		return openAIMessage{Role: msg.Role, Content: msg.Content}, nil
+	}
+
+	parts := []contentPart{{Type: "text", Text: msg.Content}}
+	for _, file := range msg.Files {
+		mimeType := file.mimeType()
+		if !strings.HasPrefix(mimeType, "image/") {
+			return openAIMessage{}, fmt.Errorf("%w: %s", ErrFilesNotSupported, mimeType)
+		}
+		data, err := os.ReadFile(file.Path)
+		if err != nil {
+			return openAIMessage{}, fmt.Errorf("读取文件失败: %v", err)
+		}
+		parts = append(parts, contentPart{
+			Type:     "image_url",
+			ImageURL: &imageURL{URL: "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data)},
+		})
+	}
+	return openAIMessage{Role: msg.Role, Content: parts}, nil
+}
+
+// post 发送单次请求，返回响应体
+func (c *OpenAICompatible) post(ctx context.Context, body []byte) ([]byte, error) {
+	httpReq, err := c.newHTTPRequest(ctx, body)
+	if err != nil {
+		return nil, permanent(err)
+	}
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("发送请求失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		message := truncate(string(data), 500)
+		var response openAIResponse
+		if json.Unmarshal(data, &response) == nil && response.Error != nil {
+			message = response.Error.Message
+		}
+		return nil, &APIError{Provider: c.name, StatusCode: resp.StatusCode, Message: message}
+	}
+	return data, nil
+}
+
+func (c *OpenAICompatible) newHTTPRequest(ctx context.Context, body []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.config.APIKey)
+	return req, nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,126 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChatCompletionsURL(t *testing.T) {
+	tests := map[string]string{
+		"https://api.deepseek.com":                                "https://api.deepseek.com/v1/chat/completions",
+		"https://api.openai.com/v1/":                              "https://api.openai.com/v1/chat/completions",
+		"https://open.bigmodel.cn/api/paas/v4/":                   "https://open.bigmodel.cn/api/paas/v4/chat/completions",
+		"https://generativelanguage.googleapis.com/v1beta/openai": "https://generativelanguage.googleapis.com/v1beta/openai/chat/completions",
+		"https://proxy.example.com/v1/chat/completions":           "https://proxy.example.com/v1/chat/completions",
+	}
+	for baseURL, want := range tests {
+		if got := ChatCompletionsURL(baseURL); got != want {
+			t.Errorf("ChatCompletionsURL(%q) = %q, 期望 %q", baseURL, got, want)
+		}
+	}
+}
+
+// TestOpenAICompatibleChat 测试 JSON 模式、使用量、限流重试和流式输出
+func TestOpenAICompatibleChat(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		var req openAIRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("解析请求失败: %v", err)
+		}
+		if r.Header.Get("Authorization") != "Bearer key" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+
+		if req.Stream {
+			w.Header().Set("Content-Type", "text/event-stream")
+			for _, delta := range []string{"你", "好"} {
+				fmt.Fprintf(w, "data: {\"choices\":[{\"delta\":{\"content\":%q}}]}\n\n", delta)
+			}
+			fmt.Fprint(w, "data: {\"choices\":[],\"usage\":{\"prompt_tokens\":3,\"completion_tokens\":2,\"total_tokens\":5}}\n\n")
+			fmt.Fprint(w, "data: [DONE]\n\n")
+			return
+		}
+
+		if calls == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"error":{"message":"rate limit exceeded"}}`)
+			return
+		}
+		if req.ResponseFormat == nil || req.ResponseFormat.Type != "json_object" || req.Model != "override" {
+			t.Errorf("请求参数不正确: %+v", req)
+		}
+		fmt.Fprint(w, `{"model":"override","choices":[{"message":{"content":"{\"ok\":true}"},"finish_reason":"stop"}],"usage":{"prompt_tokens":10,"completion_tokens":4,"total_tokens":14}}`)
+	}))
+	defer server.Close()
+
+	p := NewDeepSeek(Config{APIKey: "key", BaseURL: server.URL, MaxRetries: 2, RetryDelay: time.Millisecond, RateLimitDelay: time.Millisecond})
+	if p.Model() != DeepSeekModel {
+		t.Errorf("默认模型 = %s", p.Model())
+	}
+
+	ctx := context.Background()
+	resp, err := p.Chat(ctx, &Request{Model: "override", Messages: Messages("sys", "user"), JSON: true})
+	if err != nil {
+		t.Fatalf("Chat 失败: %v", err)
+	}
+	if resp.Content != `{"ok":true}` || resp.Usage.TotalTokens != 14 || calls != 2 {
+		t.Errorf("结果不正确: %+v (请求 %d 次)", resp, calls)
+	}
+
+	var deltas []string
+	resp, err = p.Stream(ctx, &Request{Messages: Messages("", "hi")}, func(delta string) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Stream 失败: %v", err)
+	}
+	if resp.Content != "你好" || strings.Join(deltas, "|") != "你|好" || resp.Usage.TotalTokens != 5 {
+		t.Errorf("流式结果不正确: %+v %v", resp, deltas)
+	}
+}
+
+func TestNonRetryableError(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error":{"message":"invalid api key"}}`)
+	}))
+	defer server.Close()
+
+	p := NewOpenAICompatible(Config{APIKey: "bad", BaseURL: server.URL, MaxRetries: 3, RetryDelay: time.Millisecond})
+	_, err := Complete(context.Background(), p, "", "hi")
+	if err == nil || calls != 1 || !strings.Contains(err.Error(), "invalid api key") {
+		t.Errorf("认证失败不应重试: %v (请求 %d 次)", err, calls)
+	}
+}
+
+// TestOpenAICompatibleSchema 测试结构化输出：支持时使用 json_schema，否则退化为 json_object
+func TestOpenAICompatibleSchema(t *testing.T) {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{"title": {Type: "string"}}, Required: []string{"title"}}
+	for _, structured := range []bool{true, false} {
+		c := NewOpenAICompatible(Config{StructuredOutput: structured})
+		body, err := c.buildRequest(&Request{Messages: Messages("", "hi"), Schema: schema}, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var req openAIRequest
+		json.Unmarshal(body, &req)
+		if structured {
+			if req.ResponseFormat.Type != "json_schema" || req.ResponseFormat.JSONSchema.Schema.Required[0] != "title" {
+				t.Errorf("应使用 json_schema: %s", body)
+			}
+		} else if req.ResponseFormat.Type != "json_object" || req.ResponseFormat.JSONSchema != nil {
+			t.Errorf("应退化为 json_object: %s", body)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// APIError 服务端返回的错误
+type APIError struct {
+	Provider   string
+	StatusCode int    // HTTP 状态码（SDK 错误时为 0）
+	Message    string // 服务端错误信息
+}
+
+func (e *APIError) Error() string {
+	if e.StatusCode == 0 {
+		return fmt.Sprintf("%s API错误: %s", e.Provider, e.Message)
+	}
+	return fmt.Sprintf("%s API返回错误 (状态码: %d): %s", e.Provider, e.StatusCode, e.Message)
+}
+
+// RateLimited 是否为限流错误
+func (e *APIError) RateLimited() bool {
+	if e.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	message := strings.ToLower(e.Message)
+	return strings.Contains(message, "rate limit") || strings.Contains(message, "resource_exhausted") || strings.Contains(message, "quota")
+}
+
+// retryable 客户端错误（除限流和超时外）重试无意义
+func (e *APIError) retryable() bool {
+	if e.RateLimited() || e.StatusCode == 0 || e.StatusCode == http.StatusRequestTimeout {
+		return true
+	}
+	return e.StatusCode >= 500
+}
+
+// IsRateLimited 错误是否由限流引起
+func IsRateLimited(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.RateLimited()
+}
+
+// permanentError 不需要重试的错误（如请求构造失败）
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// permanent 标记错误不再重试
+func permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// retry 执行 fn，失败后按递增间隔重试；限流时等待更久
+func retry(ctx context.Context, cfg Config, fn func() error) error {
+	var lastErr error
+	for attempt := 0; attempt <= cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			delay := cfg.RetryDelay * time.Duration(attempt)
+			if IsRateLimited(lastErr) {
+				delay = cfg.RateLimitDelay * time.Duration(attempt)
+			}
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("%v (上次错误: %v)", ctx.Err(), lastErr)
+			case <-time.After(delay):
+			}
+		}
+
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		var perm *permanentError
+		if errors.As(err, &perm) {
+			return perm.err
+		}
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && (!apiErr.retryable() || cfg.FailOnRateLimit && apiErr.RateLimited()) {
+			return err
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+	}
+
+	if cfg.MaxRetries == 0 {
+		return lastErr
+	}
+	return fmt.Errorf("重试 %d 次后仍然失败: %w", cfg.MaxRetries, lastErr)
+}
@@ -0,0 +1,340 @@
+package prompt
+
+// 提示词模板名称
+const (
+	MetadataGeminiVideo    = "metadata.gemini_video"    // Gemini 分析视频文件生成元数据
+	MetadataGeminiText     = "metadata.gemini_text"     // Gemini 根据字幕生成元数据
+	MetadataSystem         = "metadata.system"          // AI服务管理器生成元数据的系统提示词
+	MetadataUser           = "metadata.user"            // AI服务管理器生成元数据的用户提示词
+	MetadataDeepSeekSystem = "metadata.deepseek_system" // DeepSeek 生成元数据的系统提示词
+	MetadataDeepSeek       = "metadata.deepseek"        // DeepSeek 生成元数据的用户提示词
+	MetadataRepair         = "metadata.repair"          // 元数据未通过校验时要求模型修正
+	MetadataChapters       = "metadata.chapters"        // 根据带时间的译文字幕划分分段章节
+
+	TranslationSystem = "translation.system" // 大模型字幕翻译（分隔符格式，带前后文）
+	TranslationBatch  = "translation.batch"  // DeepSeek 翻译器的编号格式批量翻译
+	TranslationSingle = "translation.single" // DeepSeek 翻译器的单条翻译
+	ValidationFix     = "validation.fix"     // 字幕校验时重译缺失或有问题的条目
+	ValidationJudge   = "validation.judge"   // 译文质量评审
+)
+
+// 模板所属的流程
+const (
+	PipelineMetadata    = "metadata"    // 元数据生成
+	PipelineTranslation = "translation" // 字幕翻译
+	PipelineValidation  = "validation"  // 字幕校验和质量评审
+)
+
+const (
+	metadataVariables    = "{{.Subtitles}} {{.Partitions}} {{.Variants}} {{.Styles}} {{.SourceTitle}} {{.SourceDescription}} {{.Channel}} {{.ChannelID}} {{.VideoID}}"
+	translationVariables = "{{.Count}} {{.SourceLang}} {{.TargetLang}} {{.SourceLangCode}} {{.TargetLangCode}} {{.Separator}} {{.Glossary}} {{.SourceTitle}} {{.Channel}}"
+	chapterVariables     = "{{.Subtitles}} {{.Duration}} {{.MaxChapters}} {{.MinChapterSeconds}} {{.MaxTitleLength}} {{.SourceTitle}} {{.Channel}} {{.VideoID}}"
+	batchVariables       = "{{.SourceLang}} {{.TargetLang}} {{.TextType}} {{.Domain}} {{.SourceTitle}} {{.Channel}}"
+)
+
+var definitions = []Definition{
+	{
+		Name:        MetadataGeminiVideo,
+		Pipeline:    PipelineMetadata,
+		Description: "Gemini 分析视频文件，生成标题、简介和标签（JSON）",
+		Variables:   metadataVariables,
+		Content: `请作为一个专业的 Bilibili UP 主，分析这个视频并生成以下内容：
+
+1. 一个吸引眼球的标题（严格控制在30个字以内，能够准确概括视频主题）
+2. 一个精炼的视频介绍（严格控制在100个字以内，提炼视频的核心内容和亮点）
+3. 3-5个相关的标签
+4. 按视频内容划分的章节（3-8个，第一个章节从 00:00 开始）
+5. 投稿时发布的动态文字（60字以内）
+
+要求：
+- 必须使用中文
+- 标题要简洁有力，吸引观众点击
+- 介绍要精炼，突出重点，严格控制在100字以内
+- 标签要准确反映视频内容，每个标签不超过20字
+- 章节时间使用 mm:ss 格式（超过1小时使用 hh:mm:ss）
+{{- with .Partitions}}
+- 从以下分区中选择最合适的一个，填写分区ID（tid）：
+{{.}}
+{{- end}}
+{{- if .Variants}}
+- 另外给出 {{.Variants}} 个风格不同的标题候选（variants，第一个与 title 相同），每个附带一句封面文字（cover_text，15字以内）和标题风格（style，从以下选择：{{.Styles}}）
+{{- end}}
+- 输出格式必须是JSON，格式如下：
+{
+  "title": "视频标题",
+  "description": "视频介绍（100字以内）",
+  "tags": ["标签1", "标签2", "标签3"],
+  "chapters": [{"time": "00:00", "title": "章节标题"}],
+  {{- if .Partitions}}
+  "tid": 分区ID,
+  {{- end}}
+  {{- if .Variants}}
+  "variants": [{"title": "标题候选", "cover_text": "封面文字", "style": "标题风格"}],
+  {{- end}}
+  "dynamic": "动态文字"
+}
+
+请直接返回JSON格式的结果，不要包含任何其他说明文字。`,
+	},
+	{
+		Name:        MetadataGeminiText,
+		Pipeline:    PipelineMetadata,
+		Description: "Gemini 根据译文字幕生成标题、简介和标签（JSON）",
+		Variables:   metadataVariables,
+		Content: `请根据以下视频字幕内容，生成一个吸引人的视频标题、精炼介绍和3-5个相关标签。
+
+字幕内容：
+{{.Subtitles}}
+
+要求：
+1. 标题要简洁有力，严格控制在30个字以内，能够准确概括视频主题
+2. 介绍要精炼，严格控制在100个字以内，提炼视频的核心内容和亮点
+3. 标签要准确反映视频内容，3-5个即可，每个标签不超过20字
+4. 必须使用中文
+5. 动态是投稿时发布的一句话，60字以内
+{{- with .Partitions}}
+6. 从以下分区中选择最合适的一个，填写分区ID（tid）：
+{{.}}
+{{- end}}
+{{- if .Variants}}
+7. 另外给出 {{.Variants}} 个风格不同的标题候选（variants，第一个与 title 相同），每个附带一句封面文字（cover_text，15字以内）和标题风格（style，从以下选择：{{.Styles}}）
+{{- end}}
+输出格式必须是JSON，格式如下：
+{
+  "title": "视频标题",
+  "description": "视频介绍（100字以内）",
+  "tags": ["标签1", "标签2", "标签3"],
+  {{- if .Partitions}}
+  "tid": 分区ID,
+  {{- end}}
+  {{- if .Variants}}
+  "variants": [{"title": "标题候选", "cover_text": "封面文字", "style": "标题风格"}],
+  {{- end}}
+  "dynamic": "动态文字"
+}
+
+请直接返回JSON格式的结果，不要包含任何其他说明文字。`,
+	},
+	{
+		Name:        MetadataSystem,
+		Pipeline:    PipelineMetadata,
+		Description: "Gemini 不可用时，使用首选AI服务生成元数据的系统提示词",
+		Variables:   metadataVariables,
+		Content: `你是一个专业的视频内容分析师，擅长为Bilibili视频生成吸引人的标题和描述。
+
+请根据提供的字幕内容，生成：
+1. 标题：简洁有力，能吸引观众点击，不超过80个字符
+2. 描述：详细介绍视频内容，包含关键信息，适合SEO
+3. 标签：5-10个相关标签，用于视频分类和搜索，每个标签不超过20字
+4. 动态：投稿时发布的一句话，60字以内
+{{- with .Partitions}}
+5. 分区：从以下分区中选择最合适的一个，填写分区ID（tid）：
+{{.}}
+{{- end}}
+{{- if .Variants}}
+6. 另外给出 {{.Variants}} 个风格不同的标题候选（variants，第一个与 title 相同），每个附带一句封面文字（cover_text，15字以内）和标题风格（style，从以下选择：{{.Styles}}）
+{{- end}}
+
+请以JSON格式返回，格式如下：
+{
+  "title": "视频标题",
+  "description": "视频描述",
+  "tags": ["标签1", "标签2", "标签3"],
+  {{- if .Partitions}}
+  "tid": 分区ID,
+  {{- end}}
+  {{- if .Variants}}
+  "variants": [{"title": "标题候选", "cover_text": "封面文字", "style": "标题风格"}],
+  {{- end}}
+  "dynamic": "动态文字"
+}
+
+注意：
+- 标题要吸引人但不要标题党
+- 描述要详细但不要太长
+- 标签要相关且有搜索价值
+- 只返回JSON，不要添加其他内容`,
+	},
+	{
+		Name:        MetadataUser,
+		Pipeline:    PipelineMetadata,
+		Description: "Gemini 不可用时，使用首选AI服务生成元数据的用户提示词",
+		Variables:   metadataVariables,
+		Content:     "请根据以下字幕内容生成视频元数据：\n\n{{.Subtitles}}",
+	},
+	{
+		Name:        MetadataDeepSeekSystem,
+		Pipeline:    PipelineMetadata,
+		Description: "最后使用 DeepSeek 生成元数据时的系统提示词",
+		Variables:   metadataVariables,
+		Content:     "你是一个专业的视频内容分析助手，擅长根据视频字幕生成吸引人的标题和描述。",
+	},
+	{
+		Name:        MetadataDeepSeek,
+		Pipeline:    PipelineMetadata,
+		Description: "最后使用 DeepSeek 生成元数据时的用户提示词（JSON）",
+		Variables:   metadataVariables,
+		Content: `请根据以下视频字幕内容，生成一个吸引人的视频标题、详细描述和3-5个相关标签。
+
+字幕内容：
+{{.Subtitles}}
+
+要求：
+1. 标题要简洁有力，严格控制在30个字以内（B站限制80字，但建议30字以内更易读），能够准确概括视频主题，吸引观众点击
+2. 描述要详细但不要过长，严格控制在600-800字以内，包含视频的主要内容和亮点（注意：B站简介限制2000字，需要预留约200字给原视频链接和分隔线）
+3. 标签要准确反映视频内容，3-5个即可，每个标签不超过20字
+4. 必须使用中文
+5. 动态是投稿时发布的一句话，60字以内
+{{- with .Partitions}}
+6. 从以下分区中选择最合适的一个，填写分区ID（tid）：
+{{.}}
+{{- end}}
+{{- if .Variants}}
+7. 另外给出 {{.Variants}} 个风格不同的标题候选（variants，第一个与 title 相同），每个附带一句封面文字（cover_text，15字以内）和标题风格（style，从以下选择：{{.Styles}}）
+{{- end}}
+输出格式必须是JSON，格式如下：
+{
+  "title": "视频标题",
+  "description": "视频描述",
+  "tags": ["标签1", "标签2", "标签3"],
+  {{- if .Partitions}}
+  "tid": 分区ID,
+  {{- end}}
+  {{- if .Variants}}
+  "variants": [{"title": "标题候选", "cover_text": "封面文字", "style": "标题风格"}],
+  {{- end}}
+  "dynamic": "动态文字"
+}
+
+请直接返回JSON格式的结果，不要包含任何其他说明文字。`,
+	},
+	{
+		Name:        MetadataRepair,
+		Pipeline:    PipelineMetadata,
+		Description: "生成的元数据不符合B站限制（标题长度、标签数量、违禁词等）时，要求模型修正后重新输出",
+		Variables:   "{{.Issues}} " + metadataVariables,
+		Content: `你返回的元数据存在以下问题：
+{{.Issues}}
+
+请修正这些问题，其他内容尽量保持不变，按原来的 JSON 格式重新返回完整结果，不要包含任何其他说明文字。`,
+	},
+	{
+		Name:        MetadataChapters,
+		Pipeline:    PipelineMetadata,
+		Description: "根据带时间的译文字幕划分分段章节（JSON），结果写入投稿简介",
+		Variables:   chapterVariables,
+		Content: `你是一个专业的视频剪辑师，擅长为长视频划分章节。下面是视频的中文字幕，每行开头的 [时间] 是该行字幕的开始时间。
+{{- with .SourceTitle}}
+视频原标题：{{.}}
+{{- end}}
+视频时长：{{.Duration}}
+
+字幕：
+{{.Subtitles}}
+
+请按内容主题的变化划分章节：
+1. 第一个章节从 00:00 开始，章节按时间顺序排列
+2. 章节开始时间使用字幕中出现的时间，格式为 mm:ss（超过1小时使用 h:mm:ss）
+3. 最多 {{.MaxChapters}} 个章节，每个章节至少 {{.MinChapterSeconds}} 秒
+4. 章节标题使用中文，简洁概括该段内容，不超过 {{.MaxTitleLength}} 字
+5. 输出格式必须是JSON，格式如下：
+{
+  "chapters": [{"time": "00:00", "title": "章节标题"}]
+}
+
+请直接返回JSON格式的结果，不要包含任何其他说明文字。`,
+	},
+	{
+		Name:        TranslationSystem,
+		Pipeline:    PipelineTranslation,
+		Description: "AI服务翻译字幕的系统提示词：前后文只作参考，译文按分隔符输出",
+		Variables:   translationVariables,
+		Content: `你是一个专业的视频字幕翻译专家。将【待翻译】部分的 {{.Count}} 句{{.SourceLang}}字幕翻译成{{.TargetLang}}。
+【前文】和【后文】是相邻的字幕，仅用于理解语境（代词指代、术语、语气），不要翻译，也不要输出。
+
+翻译要求：
+1. 自然流畅：使用口语化表达，符合{{.TargetLang}}字幕习惯
+2. 上下文连贯：结合前后文确定指代和用词，与前后文保持一致
+3. 准确传神：忠实原文含义，保持语气和情感
+4. 简洁明了：字幕需要快速阅读，避免冗长
+5. 数量严格：必须输出 {{.Count}} 句翻译，不多不少，与待翻译句子一一对应
+6. 分隔符：每句翻译用"{{.Separator}}"分隔{{with .Glossary}}
+
+{{.}}{{end}}
+
+输出格式：只返回待翻译部分的{{.TargetLang}}翻译，用"{{.Separator}}"分隔
+
+注意：只返回翻译的{{.TargetLang}}文本，不要添加序号、解释或其他内容。`,
+	},
+	{
+		Name:        TranslationBatch,
+		Pipeline:    PipelineTranslation,
+		Description: "DeepSeek 翻译器批量翻译的系统提示词，译文需保持编号格式",
+		Variables:   batchVariables,
+		Content: `你是一位专业的翻译专家。请将以下编号的文本逐条翻译，保持相同的编号格式。
+
+翻译要求：
+1. 保持原文的意思和语调
+2. 使用自然流畅的目标语言表达
+3. 保留编号格式：1. 翻译内容
+4. 每个编号对应一行翻译结果
+{{if .SourceLang}}5. 源语言：{{.SourceLang}}
+{{end}}{{if .TargetLang}}6. 目标语言：{{.TargetLang}}
+{{end}}{{if .TextType}}7. 文本类型：{{.TextType}}
+{{end}}{{if .Domain}}8. 领域：{{.Domain}}
+{{end}}`,
+	},
+	{
+		Name:        TranslationSingle,
+		Pipeline:    PipelineTranslation,
+		Description: "DeepSeek 翻译器单条翻译的系统提示词（批量结果条数不符时逐条翻译）",
+		Variables:   batchVariables,
+		Content: `你是一位专业的翻译专家。请将给定的文本进行准确、自然的翻译。
+
+翻译要求：
+1. 保持原文的意思和语调
+2. 使用自然流畅的目标语言表达
+3. 保留原文的格式和结构
+4. 对于专业术语，使用准确的对应词汇
+{{if .SourceLang}}5. 源语言：{{.SourceLang}}
+{{end}}{{if .TargetLang}}6. 目标语言：{{.TargetLang}}
+{{end}}{{if .TextType}}7. 文本类型：{{.TextType}}
+{{end}}{{if .Domain}}8. 领域：{{.Domain}}
+{{end}}
+请直接返回翻译结果，不要包含任何解释或其他内容。`,
+	},
+	{
+		Name:        ValidationFix,
+		Pipeline:    PipelineValidation,
+		Description: "字幕校验发现缺失或未翻译的条目时，重新翻译这些条目的系统提示词",
+		Variables:   translationVariables,
+		Content: `你是专业的视频字幕翻译专家。现在需要重新翻译 {{.Count}} 句有问题的英文字幕。
+
+翻译要求：
+1. 自然流畅：使用口语化表达，符合{{.TargetLang}}字幕习惯
+2. 准确传神：忠实原文含义，保持语气和情感
+3. 简洁明了：字幕需要快速阅读，避免冗长
+4. 完整输出：必须为每句英文提供完整的{{.TargetLang}}翻译
+5. 数量严格：必须输出 {{.Count}} 句翻译，不多不少
+6. 分隔符：每句翻译用"{{.Separator}}"分隔
+
+注意：之前的翻译中可能有缺失或错误，请提供完整准确的重新翻译。
+
+输出格式：只返回{{.TargetLang}}翻译，用"{{.Separator}}"分隔，不要添加序号或其他内容。`,
+	},
+	{
+		Name:        ValidationJudge,
+		Pipeline:    PipelineValidation,
+		Description: "译文质量评审的系统提示词，必须返回约定格式的 JSON 评分",
+		Variables:   "{{.SourceLang}} {{.TargetLang}} {{.SourceLangCode}} {{.TargetLangCode}} {{.SourceTitle}} {{.Channel}}",
+		Content: `你是专业的视频字幕翻译质量评审。输入是 JSON 数组，每项包含 id、{{.SourceLang}}原文 source 和{{.TargetLang}}译文 translation。
+请逐条从以下维度打分（0-10 的整数，越高越好）：
+- fluency：译文是否自然流畅，符合{{.TargetLang}}字幕的表达习惯
+- omission：是否完整传达原文信息（10 表示没有漏译）
+- accuracy：是否准确理解原文（10 表示没有误译、错译）
+- length：译文长度是否适合字幕快速阅读（过长或过短都应扣分）
+
+只返回 JSON，格式为：{"scores":[{"id":1,"fluency":8,"omission":10,"accuracy":9,"length":8,"issue":"简要说明主要问题，没有问题时为空"}]}
+必须为每个 id 给出评分，不要输出其他内容。`,
+	},
+}
@@ -0,0 +1,238 @@
+package prompt
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// Vars 提示词模板可用的变量，模板中按字段名引用，如 {{.SourceTitle}}
+type Vars struct {
+	VideoID           string `json:"video_id,omitempty"`            // 视频ID
+	SourceTitle       string `json:"source_title,omitempty"`        // 原视频标题
+	SourceDescription string `json:"source_description,omitempty"`  // 原视频简介
+	Channel           string `json:"channel,omitempty"`             // 来源频道名称
+	ChannelID         string `json:"channel_id,omitempty"`          // 来源频道ID
+	SourceLang        string `json:"source_lang,omitempty"`         // 源语言名称（如 英文）
+	TargetLang        string `json:"target_lang,omitempty"`         // 目标语言名称（如 中文）
+	SourceLangCode    string `json:"source_lang_code,omitempty"`    // 源语言代码（如 en）
+	TargetLangCode    string `json:"target_lang_code,omitempty"`    // 目标语言代码（如 zh）
+	Glossary          string `json:"glossary,omitempty"`            // 术语表说明等附加要求
+	Count             int    `json:"count,omitempty"`               // 本次翻译的句数
+	Separator         string `json:"separator,omitempty"`           // 句子之间的分隔符
+	TextType          string `json:"text_type,omitempty"`           // 文本类型
+	Domain            string `json:"domain,omitempty"`              // 领域
+	Subtitles         string `json:"subtitles,omitempty"`           // 字幕文本（生成元数据时使用）
+	Partitions        string `json:"partitions,omitempty"`          // 可选的投稿分区，每行一个“分区ID 名称”（生成元数据时使用）
+	Issues            string `json:"issues,omitempty"`              // 上次生成的元数据未通过校验的问题（重新生成时使用）
+	Variants          int    `json:"variants,omitempty"`            // 需要生成的标题候选数，0 表示不生成（生成元数据时使用）
+	Styles            string `json:"styles,omitempty"`              // 标题候选可选的风格，用顿号分隔（生成元数据时使用）
+	Duration          string `json:"duration,omitempty"`            // 视频时长，如 12:34（生成章节时使用）
+	MaxChapters       int    `json:"max_chapters,omitempty"`        // 最多章节数（生成章节时使用）
+	MinChapterSeconds int    `json:"min_chapter_seconds,omitempty"` // 每个章节最短秒数（生成章节时使用）
+	MaxTitleLength    int    `json:"max_title_length,omitempty"`    // 章节标题最多字数（生成章节时使用）
+}
+
+// withDefaults 用公共变量（视频、频道）补全未填写的字段
+func (v Vars) withDefaults(base Vars) Vars {
+	fill := func(field *string, value string) {
+		if *field == "" {
+			*field = value
+		}
+	}
+	fill(&v.VideoID, base.VideoID)
+	fill(&v.SourceTitle, base.SourceTitle)
+	fill(&v.SourceDescription, base.SourceDescription)
+	fill(&v.Channel, base.Channel)
+	fill(&v.ChannelID, base.ChannelID)
+	return v
+}
+
+// sampleVars 校验模板时使用的示例变量
+var sampleVars = Vars{
+	VideoID:           "dQw4w9WgXcQ",
+	SourceTitle:       "Example video",
+	SourceDescription: "Example description",
+	Channel:           "Example channel",
+	ChannelID:         "UCexample",
+	SourceLang:        "英文",
+	TargetLang:        "中文",
+	SourceLangCode:    "en",
+	TargetLangCode:    "zh",
+	Glossary:          "术语表（必须严格遵守）：\n- Go → Go\n",
+	Count:             3,
+	Separator:         "###SENTENCE_BREAK###",
+	Subtitles:         "示例字幕",
+	Partitions:        "122 野生技术协会\n201 科学科普",
+	Issues:            "- 标题超过 80 字",
+	Variants:          3,
+	Styles:            "直述、悬念、提问",
+	Duration:          "12:34",
+	MaxChapters:       12,
+	MinChapterSeconds: 60,
+	MaxTitleLength:    20,
+}
+
+// Template 解析后的提示词模板
+type Template struct {
+	Name      string // 模板名称
+	Version   int    // 版本号，0 为内置默认模板
+	ChannelID string // 适用的频道（为空表示全局）
+	tmpl      *template.Template
+}
+
+// Parse 解析模板，并用示例变量试渲染一次，引用不存在的变量时返回错误
+func Parse(name, content string) (*Template, error) {
+	tmpl, err := template.New(name).Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("模板语法错误: %v", err)
+	}
+	t := &Template{Name: name, tmpl: tmpl}
+	if _, err := t.Render(sampleVars); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Render 渲染模板
+func (t *Template) Render(vars Vars) (string, error) {
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, vars); err != nil {
+		return "", fmt.Errorf("渲染模板 %s 失败: %v", t.Name, err)
+	}
+	return buf.String(), nil
+}
+
+// Ref 模板的版本信息
+func (t *Template) Ref() Ref {
+	return Ref{Version: t.Version, ChannelID: t.ChannelID}
+}
+
+// Ref 步骤结果中记录的模板版本，Version 为 0 表示内置默认模板
+type Ref struct {
+	Version   int    `json:"version"`
+	ChannelID string `json:"channel_id,omitempty"`
+}
+
+// Set 一次任务选用的提示词模板和公共变量，未选用的模板使用内置默认
+// 并发安全，记录实际渲染过的模板版本，写入步骤结果以便复现
+type Set struct {
+	Vars      Vars // 公共变量（视频、频道）
+	templates map[string]*Template
+
+	mu   sync.Mutex
+	used map[string]Ref
+}
+
+// NewSet 创建模板集合
+func NewSet(vars Vars, templates ...*Template) *Set {
+	s := &Set{Vars: vars, templates: make(map[string]*Template)}
+	for _, t := range templates {
+		s.templates[t.Name] = t
+	}
+	return s
+}
+
+// Template 集合中的模板，未选用时为内置默认（未知名称返回 nil）
+func (s *Set) Template(name string) *Template {
+	if s != nil {
+		if t, ok := s.templates[name]; ok {
+			return t
+		}
+	}
+	return Default(name)
+}
+
+// Render 渲染模板；自定义模板渲染失败时使用内置默认模板，s 为 nil 时直接使用内置默认模板
+func (s *Set) Render(name string, vars Vars) string {
+	t := s.Template(name)
+	if t == nil {
+		return ""
+	}
+	if s != nil {
+		vars = vars.withDefaults(s.Vars)
+	}
+
+	text, err := t.Render(vars)
+	if err != nil && t.Version != 0 {
+		t = Default(name)
+		text, err = t.Render(vars)
+	}
+	if err != nil {
+		return ""
+	}
+
+	if s != nil {
+		s.mu.Lock()
+		if s.used == nil {
+			s.used = make(map[string]Ref)
+		}
+		s.used[name] = t.Ref()
+		s.mu.Unlock()
+	}
+	return text
+}
+
+// Used 已渲染过的模板及其版本
+func (s *Set) Used() map[string]Ref {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	used := make(map[string]Ref, len(s.used))
+	for name, ref := range s.used {
+		used[name] = ref
+	}
+	return used
+}
+
+// Definition 提示词模板的定义
+type Definition struct {
+	Name        string `json:"name"`        // 模板名称
+	Pipeline    string `json:"pipeline"`    // 所属流程：metadata、translation、validation
+	Description string `json:"description"` // 用途说明
+	Variables   string `json:"variables"`   // 常用变量
+	Content     string `json:"content"`     // 内置默认模板
+}
+
+var (
+	defaultsOnce sync.Once
+	defaults     map[string]*Template
+)
+
+// Default 内置默认模板，未知名称返回 nil
+func Default(name string) *Template {
+	defaultsOnce.Do(func() {
+		defaults = make(map[string]*Template, len(definitions))
+		for _, def := range definitions {
+			defaults[def.Name] = &Template{Name: def.Name, tmpl: template.Must(template.New(def.Name).Parse(def.Content))}
+		}
+	})
+	return defaults[name]
+}
+
+// Lookup 模板定义
+func Lookup(name string) (Definition, bool) {
+	for _, def := range definitions {
+		if def.Name == name {
+			return def, true
+		}
+	}
+	return Definition{}, false
+}
+
+// Definitions 所有模板定义，pipeline 不为空时只返回该流程的模板
+func Definitions(pipeline string) []Definition {
+	var result []Definition
+	for _, def := range definitions {
+		if pipeline == "" || strings.EqualFold(def.Pipeline, pipeline) {
+			result = append(result, def)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Pipeline < result[j].Pipeline })
+	return result
+}
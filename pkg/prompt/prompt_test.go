@@ -0,0 +1,44 @@
+package prompt
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestDefaultsParse 测试内置默认模板都能用示例变量渲染
+func TestDefaultsParse(t *testing.T) {
+	for _, def := range Definitions("") {
+		if _, err := Parse(def.Name, def.Content); err != nil {
+			t.Errorf("%s: %v", def.Name, err)
+		}
+	}
+	if len(Definitions(PipelineValidation)) != 2 {
+		t.Errorf("validation 模板数量 = %d", len(Definitions(PipelineValidation)))
+	}
+}
+
+// TestSetRender 测试公共变量补全、可选段落和渲染失败时回退到默认模板
+func TestSetRender(t *testing.T) {
+	custom, err := Parse(TranslationBatch, "{{.Channel}}|{{.TargetLang}}{{if .Domain}}|{{.Domain}}{{end}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	custom.Version = 3
+	set := NewSet(Vars{Channel: "Tech"}, custom)
+
+	if got := set.Render(TranslationBatch, Vars{TargetLang: "中文"}); got != "Tech|中文" {
+		t.Errorf("Render = %q", got)
+	}
+	if got := set.Render(TranslationSystem, Vars{Count: 2, TargetLang: "中文", Separator: "##"}); !strings.Contains(got, "必须输出 2 句翻译") || strings.Contains(got, "<no value>") {
+		t.Errorf("默认模板渲染不正确: %q", got)
+	}
+	used := set.Used()
+	if used[TranslationBatch].Version != 3 || used[TranslationSystem].Version != 0 {
+		t.Errorf("Used = %+v", used)
+	}
+
+	var empty *Set
+	if got := empty.Render(MetadataDeepSeekSystem, Vars{}); got == "" {
+		t.Error("nil 集合应使用内置默认模板")
+	}
+}
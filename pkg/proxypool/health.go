@@ -0,0 +1,115 @@
+package proxypool
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Start 启动定时健康检查
+func (p *Pool) Start() {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	if p.stop != nil {
+		p.mu.Unlock()
+		return
+	}
+	p.stop = make(chan struct{})
+	stop := p.stop
+	p.mu.Unlock()
+
+	go func() {
+		p.CheckAll()
+		for {
+			p.mu.Lock()
+			interval := p.interval
+			p.mu.Unlock()
+
+			select {
+			case <-time.After(interval):
+				p.CheckAll()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop 停止健康检查
+func (p *Pool) Stop() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stop != nil {
+		close(p.stop)
+		p.stop = nil
+	}
+}
+
+// CheckAll 并发检查所有代理的可用性
+func (p *Pool) CheckAll() {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	entries := append([]*Entry{}, p.entries...)
+	checkURL := p.checkURL
+	p.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, entry := range entries {
+		wg.Add(1)
+		go func(entry *Entry) {
+			defer wg.Done()
+			latency, err := checkProxy(entry, checkURL)
+
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			wasHealthy := entry.Healthy
+			entry.LastCheck = time.Now()
+			entry.Latency = latency
+			entry.Healthy = err == nil
+			if err != nil {
+				entry.LastError = err.Error()
+			}
+
+			if p.logger == nil {
+				return
+			}
+			if wasHealthy && err != nil {
+				p.logger.Warnf("⚠️ 代理 %s 健康检查失败: %v", maskProxyURL(entry.parsed), err)
+			} else if !wasHealthy && err == nil {
+				p.logger.Infof("✅ 代理 %s 已恢复 (延迟 %dms)", maskProxyURL(entry.parsed), latency.Milliseconds())
+			}
+		}(entry)
+	}
+	wg.Wait()
+}
+
+// checkProxy 通过代理请求健康检查地址
+func checkProxy(entry *Entry, checkURL string) (time.Duration, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyURL(entry.parsed)
+	client := &http.Client{Transport: transport, Timeout: 15 * time.Second}
+	defer transport.CloseIdleConnections()
+
+	start := time.Now()
+	resp, err := client.Get(checkURL)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	latency := time.Since(start)
+
+	if resp.StatusCode >= 400 {
+		return latency, fmt.Errorf("健康检查返回状态码 %d", resp.StatusCode)
+	}
+	return latency, nil
+}
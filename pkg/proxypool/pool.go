@@ -0,0 +1,448 @@
+package proxypool
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultHealthCheckURL      = "https://www.youtube.com/generate_204"
+	DefaultHealthCheckInterval = 300 // 秒
+	DefaultCooldownSeconds     = 600 // 秒
+)
+
+// DefaultDirectDomains 默认直连的域名（Bilibili、腾讯云 COS）
+var DefaultDirectDomains = []string{
+	"bilibili.com",
+	"bilivideo.com",
+	"biliapi.net",
+	"hdslb.com",
+	"b23.tv",
+	"myqcloud.com",
+}
+
+// DefaultProxyDomains 默认走代理的域名（YouTube）
+var DefaultProxyDomains = []string{
+	"youtube.com",
+	"youtu.be",
+	"googlevideo.com",
+	"ytimg.com",
+	"ggpht.com",
+	"youtube-nocookie.com",
+}
+
+// Entry 代理池中的单个代理
+type Entry struct {
+	URL           string
+	Scheme        string
+	Healthy       bool
+	LastCheck     time.Time
+	Latency       time.Duration
+	Successes     int64
+	Failures      int64
+	RateLimited   int64
+	CooldownUntil time.Time
+	LastError     string
+
+	parsed *url.URL
+}
+
+// EntryStats 代理统计信息（用于 API 输出）
+type EntryStats struct {
+	URL           string     `json:"url"`
+	Scheme        string     `json:"scheme"`
+	Healthy       bool       `json:"healthy"`
+	Available     bool       `json:"available"`
+	LastCheck     *time.Time `json:"last_check,omitempty"`
+	LatencyMs     int64      `json:"latency_ms"`
+	Successes     int64      `json:"successes"`
+	Failures      int64      `json:"failures"`
+	RateLimited   int64      `json:"rate_limited"`
+	CooldownUntil *time.Time `json:"cooldown_until,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+}
+
+// Pool 代理池
+// 按目标域名决定是否走代理，多个代理之间轮换；yt-dlp 遇到 429/403 时将代理置为冷却状态并切换到下一个
+type Pool struct {
+	mu sync.Mutex
+
+	logger        *zap.SugaredLogger
+	entries       []*Entry
+	next          int
+	proxyDomains  []string
+	directDomains []string
+	checkURL      string
+	interval      time.Duration
+	cooldown      time.Duration
+	fallback      bool // 代理全部失败后直连重试
+
+	stop chan struct{}
+}
+
+// NewPool 根据配置创建代理池
+func NewPool(config *types.ProxyConfig, logger *zap.SugaredLogger) *Pool {
+	p := &Pool{logger: logger}
+	p.Reload(config)
+	return p
+}
+
+// Reload 重新加载配置（配置 API 更新后调用），保留已有代理的统计信息
+func (p *Pool) Reload(config *types.ProxyConfig) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	old := make(map[string]*Entry, len(p.entries))
+	for _, entry := range p.entries {
+		old[entry.URL] = entry
+	}
+
+	p.entries = nil
+	p.next = 0
+	p.proxyDomains = nil
+	p.directDomains = DefaultDirectDomains
+	p.checkURL = DefaultHealthCheckURL
+	p.interval = DefaultHealthCheckInterval * time.Second
+	p.cooldown = DefaultCooldownSeconds * time.Second
+	p.fallback = false
+
+	if config == nil || !config.UseProxy {
+		return
+	}
+
+	p.fallback = config.DirectFallback
+
+	if config.DirectDomains != nil {
+		p.directDomains = config.DirectDomains
+	}
+	p.proxyDomains = config.ProxyDomains
+	if config.HealthCheckURL != "" {
+		p.checkURL = config.HealthCheckURL
+	}
+	if config.HealthCheckInterval > 0 {
+		p.interval = time.Duration(config.HealthCheckInterval) * time.Second
+	}
+	if config.CooldownSeconds > 0 {
+		p.cooldown = time.Duration(config.CooldownSeconds) * time.Second
+	}
+
+	proxies := config.Proxies
+	if len(proxies) == 0 && config.ProxyHost != "" {
+		proxies = []string{config.ProxyHost}
+	}
+
+	seen := map[string]bool{}
+	for _, raw := range proxies {
+		raw = strings.TrimSpace(raw)
+		if raw == "" || seen[raw] {
+			continue
+		}
+		seen[raw] = true
+
+		parsed, err := parseProxyURL(raw)
+		if err != nil {
+			if p.logger != nil {
+				p.logger.Warnf("⚠️ 忽略无效的代理地址 %s: %v", raw, err)
+			}
+			continue
+		}
+
+		if entry, ok := old[raw]; ok {
+			p.entries = append(p.entries, entry)
+			continue
+		}
+		p.entries = append(p.entries, &Entry{
+			URL:     raw,
+			Scheme:  parsed.Scheme,
+			Healthy: true, // 在第一次健康检查之前默认可用
+			parsed:  parsed,
+		})
+	}
+}
+
+// parseProxyURL 解析代理地址，支持 http/https/socks5/socks5h，未写协议时按 http 处理
+func parseProxyURL(raw string) (*url.URL, error) {
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	switch parsed.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("不支持的代理协议: %s", parsed.Scheme)
+	}
+	if parsed.Host == "" {
+		return nil, fmt.Errorf("缺少代理主机")
+	}
+	return parsed, nil
+}
+
+// Enabled 是否配置了可用的代理
+func (p *Pool) Enabled() bool {
+	if p == nil {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.entries) > 0
+}
+
+// ShouldProxy 判断目标地址是否需要走代理
+// direct_domains 优先；配置了 proxy_domains 时只有匹配的域名走代理，否则其余域名都走代理
+func (p *Pool) ShouldProxy(target string) bool {
+	if p == nil {
+		return false
+	}
+	host := hostOf(target)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.shouldProxyLocked(host)
+}
+
+// shouldProxyLocked 判断主机名是否需要走代理，调用方需持有锁
+func (p *Pool) shouldProxyLocked(host string) bool {
+	if len(p.entries) == 0 {
+		return false
+	}
+	if matchDomain(host, p.directDomains) {
+		return false
+	}
+	if len(p.proxyDomains) > 0 {
+		return matchDomain(host, p.proxyDomains)
+	}
+	return true
+}
+
+// Attempts 返回访问目标地址时依次尝试的代理（轮换顺序），空字符串表示直连
+// 不需要走代理的目标只返回直连；需要走代理时只有开启 direct_fallback 才在最后追加直连，
+// 否则全部代理都不可用（冷却中或健康检查失败）时仍按轮换顺序使用全部代理，避免暴露服务器 IP。
+// 判断和轮换在同一次加锁内完成，避免与 Reload 并发时代理列表被清空
+func (p *Pool) Attempts(target string) []string {
+	if p == nil {
+		return []string{""}
+	}
+	host := hostOf(target)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.shouldProxyLocked(host) {
+		return []string{""}
+	}
+
+	now := time.Now()
+	var attempts, unavailable []string
+	for i := 0; i < len(p.entries); i++ {
+		entry := p.entries[(p.next+i)%len(p.entries)]
+		if entry.Healthy && now.After(entry.CooldownUntil) {
+			attempts = append(attempts, entry.URL)
+		} else {
+			unavailable = append(unavailable, entry.URL)
+		}
+	}
+	p.next = (p.next + 1) % len(p.entries)
+
+	if p.fallback {
+		return append(attempts, "")
+	}
+	if len(attempts) == 0 {
+		return unavailable
+	}
+	return attempts
+}
+
+// Pick 选择一个用于访问目标地址的代理，返回空字符串表示直连
+func (p *Pool) Pick(target string) string {
+	return p.Attempts(target)[0]
+}
+
+// ReportDirect 记录访问目标地址时使用了直连；需要走代理的目标回退直连（direct_fallback）时输出警告
+func (p *Pool) ReportDirect(target string) {
+	if p == nil || p.logger == nil {
+		return
+	}
+	host := hostOf(target)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.shouldProxyLocked(host) {
+		p.logger.Warnf("⚠️ 代理均不可用，直连访问 %s（已开启 direct_fallback，将使用服务器 IP）", host)
+	}
+}
+
+// ReportSuccess 记录代理请求成功
+func (p *Pool) ReportSuccess(proxy string) {
+	entry := p.find(proxy)
+	if entry == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry.Successes++
+}
+
+// ReportFailure 记录代理请求失败，返回是否应切换到下一个代理
+// 429/403 表示代理 IP 被限流或封禁，代理进入冷却状态
+func (p *Pool) ReportFailure(proxy, output string) bool {
+	entry := p.find(proxy)
+	if entry == nil {
+		return false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry.Failures++
+	entry.LastError = lastLine(output)
+	if IsBlockedError(output) {
+		entry.RateLimited++
+		entry.CooldownUntil = time.Now().Add(p.cooldown)
+		if p.logger != nil {
+			p.logger.Warnf("⚠️ 代理 %s 被限流/拒绝访问，冷却至 %s", maskProxyURL(entry.parsed), entry.CooldownUntil.Format("15:04:05"))
+		}
+	}
+	return true
+}
+
+// IsBlockedError 判断错误输出是否为 429/403（需要切换代理）
+func IsBlockedError(output string) bool {
+	lower := strings.ToLower(output)
+	return strings.Contains(lower, "http error 429") ||
+		strings.Contains(lower, "too many requests") ||
+		strings.Contains(lower, "http error 403") ||
+		strings.Contains(lower, "403 forbidden")
+}
+
+// find 查找代理条目
+func (p *Pool) find(proxy string) *Entry {
+	if p == nil || proxy == "" {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, entry := range p.entries {
+		if entry.URL == proxy {
+			return entry
+		}
+	}
+	return nil
+}
+
+// ProxyFunc 供 http.Transport 使用，按目标域名路由
+func (p *Pool) ProxyFunc(req *http.Request) (*url.URL, error) {
+	proxy := p.Pick(req.URL.String())
+	if proxy == "" {
+		p.ReportDirect(req.URL.String())
+		return nil, nil
+	}
+	return parseProxyURL(proxy)
+}
+
+// Transport 创建按目标域名路由的 http.Transport
+func (p *Pool) Transport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if p != nil {
+		transport.Proxy = p.ProxyFunc
+	}
+	return transport
+}
+
+// Stats 获取代理池统计信息
+func (p *Pool) Stats() []EntryStats {
+	if p == nil {
+		return []EntryStats{}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	stats := make([]EntryStats, 0, len(p.entries))
+	for _, entry := range p.entries {
+		item := EntryStats{
+			URL:         maskProxyURL(entry.parsed),
+			Scheme:      entry.Scheme,
+			Healthy:     entry.Healthy,
+			Available:   entry.Healthy && now.After(entry.CooldownUntil),
+			LatencyMs:   entry.Latency.Milliseconds(),
+			Successes:   entry.Successes,
+			Failures:    entry.Failures,
+			RateLimited: entry.RateLimited,
+			LastError:   entry.LastError,
+		}
+		if !entry.LastCheck.IsZero() {
+			lastCheck := entry.LastCheck
+			item.LastCheck = &lastCheck
+		}
+		if now.Before(entry.CooldownUntil) {
+			cooldownUntil := entry.CooldownUntil
+			item.CooldownUntil = &cooldownUntil
+		}
+		stats = append(stats, item)
+	}
+	return stats
+}
+
+// maskProxyURL 隐藏代理地址中的密码
+func maskProxyURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	if u.User == nil {
+		return u.String()
+	}
+	masked := *u
+	masked.User = url.UserPassword(u.User.Username(), "***")
+	return strings.Replace(masked.String(), "%2A%2A%2A", "***", 1)
+}
+
+// hostOf 解析目标地址的主机名
+func hostOf(target string) string {
+	if !strings.Contains(target, "://") {
+		target = "https://" + target
+	}
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Hostname())
+}
+
+// matchDomain 判断主机名是否属于域名列表（包括子域名）
+func matchDomain(host string, domains []string) bool {
+	if host == "" {
+		return false
+	}
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(domain), "."))
+		if domain == "" {
+			continue
+		}
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// lastLine 取输出的最后一行
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return lines[len(lines)-1]
+}
@@ -0,0 +1,169 @@
+package proxypool
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+)
+
+// TestShouldProxy 测试按目标域名决定是否走代理
+func TestShouldProxy(t *testing.T) {
+	pool := NewPool(&types.ProxyConfig{UseProxy: true, Proxies: []string{"127.0.0.1:7890"}}, nil)
+	cases := map[string]bool{
+		"https://www.youtube.com/watch?v=abc":         true,
+		"https://example.com/":                        true, // 未配置 proxy_domains 时除直连域名外都走代理
+		"https://member.bilibili.com/x/vu/web":        false,
+		"upos-sz.bilivideo.com":                       false,
+		"https://bucket.cos.ap-shanghai.myqcloud.com": false,
+	}
+	for target, want := range cases {
+		if got := pool.ShouldProxy(target); got != want {
+			t.Errorf("ShouldProxy(%q) = %v, 期望 %v", target, got, want)
+		}
+	}
+
+	pool.Reload(&types.ProxyConfig{
+		UseProxy:      true,
+		Proxies:       []string{"127.0.0.1:7890"},
+		ProxyDomains:  []string{"youtube.com", "googlevideo.com"},
+		DirectDomains: []string{"music.youtube.com"},
+	})
+	cases = map[string]bool{
+		"https://www.youtube.com/":               true,
+		"https://rr1---sn-abc.googlevideo.com/x": true,
+		"https://music.youtube.com/":             false, // direct_domains 优先
+		"https://example.com/":                   false,
+		"https://notyoutube.com/":                false,
+	}
+	for target, want := range cases {
+		if got := pool.ShouldProxy(target); got != want {
+			t.Errorf("ShouldProxy(%q) = %v, 期望 %v", target, got, want)
+		}
+	}
+
+	if NewPool(&types.ProxyConfig{UseProxy: false, ProxyHost: "127.0.0.1:7890"}, nil).ShouldProxy("https://www.youtube.com/") {
+		t.Error("未启用代理时不应走代理")
+	}
+}
+
+// TestAttemptsFailover 测试代理轮换、429 冷却后切换到下一个代理，未开启 direct_fallback 时不直连
+func TestAttemptsFailover(t *testing.T) {
+	pool := NewPool(&types.ProxyConfig{
+		UseProxy: true,
+		Proxies:  []string{"http://a:1", "socks5://b:2", "http://a:1", "ftp://bad:3"},
+	}, nil)
+	if stats := pool.Stats(); len(stats) != 2 {
+		t.Fatalf("应去掉重复和无效的代理，得到 %+v", stats)
+	}
+
+	const target = "https://www.youtube.com/watch?v=abc"
+	if got := pool.Attempts(target); len(got) != 2 || got[0] != "http://a:1" || got[1] != "socks5://b:2" {
+		t.Fatalf("Attempts = %q", got)
+	}
+	if got := pool.Pick(target); got != "socks5://b:2" {
+		t.Errorf("第二次应轮换到下一个代理，得到 %q", got)
+	}
+	if got := pool.Attempts("https://www.bilibili.com/"); len(got) != 1 || got[0] != "" {
+		t.Errorf("直连域名只应返回直连，得到 %q", got)
+	}
+
+	if !pool.ReportFailure("http://a:1", "ERROR: unable to download: HTTP Error 429: Too Many Requests") {
+		t.Fatal("失败后应切换代理")
+	}
+	pool.ReportFailure("socks5://b:2", "ERROR: Video unavailable")
+	pool.ReportSuccess("socks5://b:2")
+	for i := 0; i < 2; i++ {
+		if got := pool.Attempts(target); len(got) != 1 || got[0] != "socks5://b:2" {
+			t.Errorf("冷却中的代理不应被使用，得到 %q", got)
+		}
+	}
+
+	stats := pool.Stats()
+	if stats[0].Available || stats[0].RateLimited != 1 || stats[0].CooldownUntil == nil || stats[0].LastError == "" {
+		t.Errorf("被限流代理的统计不正确: %+v", stats[0])
+	}
+	if !stats[1].Available || stats[1].Failures != 1 || stats[1].Successes != 1 || stats[1].RateLimited != 0 {
+		t.Errorf("普通失败不应触发冷却: %+v", stats[1])
+	}
+
+	pool.ReportFailure("socks5://b:2", "HTTP Error 403: Forbidden")
+	if got := pool.Attempts(target); len(got) != 2 || got[0] == "" || got[1] == "" {
+		t.Errorf("全部代理冷却时仍应使用代理而不是直连，得到 %q", got)
+	}
+}
+
+// TestAttemptsDirectFallback 测试开启 direct_fallback 后全部代理失败时最后直连
+func TestAttemptsDirectFallback(t *testing.T) {
+	pool := NewPool(&types.ProxyConfig{
+		UseProxy:       true,
+		Proxies:        []string{"http://a:1", "socks5://b:2"},
+		DirectFallback: true,
+	}, nil)
+
+	const target = "https://www.youtube.com/watch?v=abc"
+	if got := pool.Attempts(target); len(got) != 3 || got[0] != "http://a:1" || got[1] != "socks5://b:2" || got[2] != "" {
+		t.Fatalf("Attempts = %q", got)
+	}
+
+	pool.ReportFailure("http://a:1", "HTTP Error 429: Too Many Requests")
+	pool.ReportFailure("socks5://b:2", "HTTP Error 403: Forbidden")
+	if got := pool.Attempts(target); len(got) != 1 || got[0] != "" {
+		t.Errorf("全部代理冷却时应直连，得到 %q", got)
+	}
+	if pool.ReportFailure("", "HTTP Error 429") {
+		t.Error("直连失败不应切换代理")
+	}
+	pool.ReportDirect(target)
+
+	pool.Reload(&types.ProxyConfig{UseProxy: true, Proxies: []string{"http://a:1"}})
+	if got := pool.Attempts(target); len(got) != 1 || got[0] != "http://a:1" {
+		t.Errorf("Reload 关闭 direct_fallback 后不应直连，得到 %q", got)
+	}
+}
+
+// TestReload 测试重新加载配置保留已有代理的统计信息，并且与并发请求互不影响
+func TestReload(t *testing.T) {
+	config := &types.ProxyConfig{UseProxy: true, Proxies: []string{"http://a:1", "http://b:2"}}
+	pool := NewPool(config, nil)
+	pool.ReportSuccess("http://a:1")
+
+	pool.Reload(&types.ProxyConfig{UseProxy: true, Proxies: []string{"http://b:2", "http://a:1", "http://c:3"}, CooldownSeconds: 60})
+	stats := pool.Stats()
+	if len(stats) != 3 || stats[1].URL != "http://a:1" || stats[1].Successes != 1 {
+		t.Fatalf("Reload 后统计信息不正确: %+v", stats)
+	}
+	if pool.cooldown.Seconds() != 60 {
+		t.Errorf("冷却时间 = %v", pool.cooldown)
+	}
+
+	pool.Reload(&types.ProxyConfig{UseProxy: true, ProxyHost: "socks5://user:pass@d:4"})
+	if stats := pool.Stats(); len(stats) != 1 || stats[0].URL != "socks5://user:***@d:4" {
+		t.Errorf("未配置 proxies 时应使用 proxy_host 并隐藏密码: %+v", stats)
+	}
+
+	pool.Reload(nil)
+	if pool.Enabled() || pool.Pick("https://www.youtube.com/") != "" {
+		t.Error("关闭代理后应直连")
+	}
+
+	// 并发清空代理池时不能出现除零 panic
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 500; j++ {
+				pool.Reload(config)
+				pool.Reload(nil)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 500; j++ {
+				pool.Attempts("https://www.youtube.com/")
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -13,5 +13,12 @@ func MigrateDatabase(db *gorm.DB) error {
 		&model.TaskStep{},
 		&model.App{},
 		&model.UserToken{},
+		&model.CookieProfile{},
+		&model.GlossaryTerm{},
+		&model.TranslationMemory{},
+		&model.SubtitleEdit{},
+		&model.AIUsage{},
+		&model.PromptTemplate{},
+		&model.MetadataVariant{},
 	)
 }
@@ -0,0 +1,24 @@
+package model
+
+// AIUsage 一次AI调用（或一个翻译服务在一个步骤中的合计）的用量和估算费用
+type AIUsage struct {
+	BaseModel
+	Day          string  `gorm:"type:varchar(10);index" json:"day"`        // 日期（2006-01-02），按天汇总用
+	VideoID      string  `gorm:"type:varchar(100);index" json:"video_id"`  // 所属视频（为空表示与视频无关，如配置测试）
+	Step         string  `gorm:"type:varchar(50)" json:"step"`             // 所属任务步骤
+	UserID       string  `gorm:"type:varchar(64);index" json:"user_id"`    // 所属用户（与会员系统的用户ID一致）
+	Provider     string  `gorm:"type:varchar(50);index" json:"provider"`   // 服务提供商
+	Model        string  `gorm:"type:varchar(100)" json:"model"`           // 模型
+	InputTokens  int     `gorm:"type:int;default:0" json:"input_tokens"`   // 输入token数
+	OutputTokens int     `gorm:"type:int;default:0" json:"output_tokens"`  // 输出token数
+	TotalTokens  int     `gorm:"type:int;default:0" json:"total_tokens"`   // 总token数
+	Characters   int     `gorm:"type:int;default:0" json:"characters"`     // 字符数（按字符计费的机器翻译）
+	Cost         float64 `gorm:"type:decimal(12,6);default:0" json:"cost"` // 估算费用
+	Currency     string  `gorm:"type:varchar(10)" json:"currency"`         // 费用货币单位
+	DurationMs   int64   `gorm:"type:bigint;default:0" json:"duration_ms"` // 耗时（毫秒）
+}
+
+// TableName 指定表名
+func (AIUsage) TableName() string {
+	return "cw_ai_usage"
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+)
+
+// CookieProfile yt-dlp 使用的 cookies 配置（内容加密存储）
+type CookieProfile struct {
+	BaseModel
+	Name             string     `gorm:"type:varchar(100);not null" json:"name"`         // 名称
+	Adapter          string     `gorm:"type:varchar(50);not null;index" json:"adapter"` // 来源适配器: youtube, bilibili, generic
+	Channel          string     `gorm:"type:varchar(100);index" json:"channel"`         // 绑定的频道ID（为空表示适用于所有频道）
+	EncryptedContent string     `gorm:"type:longtext;not null" json:"-"`                // 加密后的 Netscape cookies 内容
+	CookieCount      int        `gorm:"type:int" json:"cookie_count"`                   // cookie 数量
+	Domains          string     `gorm:"type:varchar(1000)" json:"domains"`              // 覆盖的域名（逗号分隔）
+	ExpiresAt        *time.Time `json:"expires_at"`                                     // 关键 cookie 的过期时间
+	Enabled          bool       `gorm:"type:boolean;default:true" json:"enabled"`       // 是否启用
+	Status           string     `gorm:"type:varchar(20);not null" json:"status"`        // 状态: active, rate_limited, age_gated, expired
+	CooldownUntil    *time.Time `json:"cooldown_until"`                                 // 冷却结束时间（限流后暂停使用）
+	LastUsedAt       *time.Time `json:"last_used_at"`                                   // 最后使用时间
+	UseCount         int64      `gorm:"type:bigint;default:0" json:"use_count"`         // 使用次数
+	FailureCount     int        `gorm:"type:int;default:0" json:"failure_count"`        // 连续失败次数
+	LastError        string     `gorm:"type:text" json:"last_error"`                    // 最后一次错误
+}
+
+// TableName 指定表名
+func (CookieProfile) TableName() string {
+	return "cw_cookie_profiles"
+}
+
+// CookieProfileStatus cookies 配置状态常量
+const (
+	CookieStatusActive      = "active"       // 可用
+	CookieStatusRateLimited = "rate_limited" // 被限流（429 / 机器人验证）
+	CookieStatusAgeGated    = "age_gated"    // 年龄限制（账号无法观看受限内容）
+	CookieStatusExpired     = "expired"      // 已过期
+)
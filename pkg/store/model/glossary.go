@@ -0,0 +1,27 @@
+package model
+
+// GlossaryTerm 翻译术语表条目
+// 按频道维护固定译法和不翻译的名称，翻译字幕时注入提示词并在翻译后校验
+type GlossaryTerm struct {
+	BaseModel
+	ChannelID      string `gorm:"type:varchar(100);index" json:"channel_id"`          // 频道ID（为空表示适用于所有频道）
+	Source         string `gorm:"type:varchar(200);not null" json:"source"`           // 原文术语
+	Target         string `gorm:"type:varchar(200)" json:"target"`                    // 固定译法（不翻译时为空）
+	DoNotTranslate bool   `gorm:"type:boolean;default:false" json:"do_not_translate"` // 保持原文不翻译（人名、品牌名等）
+	CaseSensitive  bool   `gorm:"type:boolean;default:false" json:"case_sensitive"`   // 匹配原文时是否区分大小写
+	Note           string `gorm:"type:varchar(500)" json:"note"`                      // 备注（会作为说明提供给翻译模型）
+	Enabled        bool   `gorm:"type:boolean;default:true" json:"enabled"`           // 是否启用
+}
+
+// TableName 指定表名
+func (GlossaryTerm) TableName() string {
+	return "cw_glossary_terms"
+}
+
+// Expected 译文中应出现的文本
+func (t GlossaryTerm) Expected() string {
+	if t.DoNotTranslate || t.Target == "" {
+		return t.Source
+	}
+	return t.Target
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// MetadataVariant 元数据生成步骤生成的标题/封面文字候选
+// 每个视频保存全部候选，按 MetadataConfig.VariantStrategy 选中一个；投稿后记录 BVID 和分区，
+// 定时获取B站播放、点赞等数据，用于比较不同标题风格和提示词模板在各分区的效果
+type MetadataVariant struct {
+	BaseModel
+	VideoID       string     `gorm:"type:varchar(100);index;not null" json:"video_id"` // 所属视频
+	Position      int        `gorm:"type:int" json:"position"`                         // 模型返回的顺序（从 0 开始）
+	Title         string     `gorm:"type:varchar(500)" json:"title"`                   // 标题
+	CoverText     string     `gorm:"type:varchar(200)" json:"cover_text"`              // 封面文字
+	Style         string     `gorm:"type:varchar(50);index" json:"style"`              // 标题风格（悬念、提问、数字等）
+	PromptName    string     `gorm:"type:varchar(100)" json:"prompt_name"`             // 生成时使用的提示词模板
+	PromptVersion int        `gorm:"type:int" json:"prompt_version"`                   // 模板版本（0 为内置默认模板）
+	PromptChannel string     `gorm:"type:varchar(100)" json:"prompt_channel"`          // 模板所属频道（为空表示全局模板）
+	Strategy      string     `gorm:"type:varchar(50)" json:"strategy"`                 // 选中时使用的策略
+	Selected      bool       `gorm:"type:boolean;default:false;index" json:"selected"` // 是否选中
+	Tid           int        `gorm:"type:int;index" json:"tid"`                        // 投稿分区（投稿后记录）
+	BVID          string     `gorm:"column:bvid;type:varchar(50);index" json:"bvid"`   // 投稿后的 BVID
+	UploadedAt    *time.Time `json:"uploaded_at"`                                      // 投稿时间
+	Views         int        `gorm:"type:int;default:0" json:"views"`                  // 播放数
+	Likes         int        `gorm:"type:int;default:0" json:"likes"`                  // 点赞数
+	Coins         int        `gorm:"type:int;default:0" json:"coins"`                  // 投币数
+	Favorites     int        `gorm:"type:int;default:0" json:"favorites"`              // 收藏数
+	Shares        int        `gorm:"type:int;default:0" json:"shares"`                 // 分享数
+	Replies       int        `gorm:"type:int;default:0" json:"replies"`                // 评论数
+	Danmaku       int        `gorm:"type:int;default:0" json:"danmaku"`                // 弹幕数
+	StatsAt       *time.Time `json:"stats_at"`                                         // 最近一次获取数据的时间
+}
+
+// TableName 指定表名
+func (MetadataVariant) TableName() string {
+	return "cw_metadata_variants"
+}
@@ -81,6 +81,15 @@ type SavedVideoSubtitle struct {
 	Lang     string  `json:"lang"`     // 语言
 }
 
+// 字幕来源
+const (
+	SubtitleSourceSubmitted = "submitted" // 扩展提交的字幕
+	SubtitleSourceManual    = "manual"    // YouTube 人工上传字幕
+	SubtitleSourceAuto      = "auto"      // YouTube 自动生成字幕
+	SubtitleSourceASR       = "asr"       // 本地/接口语音识别生成
+	SubtitleSourceNone      = "none"      // 未获取到字幕
+)
+
 // SavedVideo 保存的视频信息
 type SavedVideo struct {
 	BaseModel
@@ -96,9 +105,20 @@ type SavedVideo struct {
 	BiliAID        int64  `gorm:"type:bigint" json:"bili_aid"`                            // Bilibili AID
 	OperationType  string `gorm:"type:varchar(50)" json:"operation_type"`                 // 操作类型 (download/upload等)
 	Subtitles      string `gorm:"type:longtext" json:"subtitles"`                         // 字幕JSON字符串
+	SubtitleSource string `gorm:"type:varchar(20)" json:"subtitle_source"`                // 字幕来源 (submitted/manual/auto/asr)
+	SubtitleLang   string `gorm:"type:varchar(20)" json:"subtitle_lang"`                  // 字幕语言
 	PlaylistID     string `gorm:"type:varchar(100);index" json:"playlist_id"`             // 播放列表ID
+	ChannelID      string `gorm:"type:varchar(100);index" json:"channel_id"`              // 来源频道ID
+	ChannelName    string `gorm:"type:varchar(200)" json:"channel_name"`                  // 来源频道名称
+	UserID         string `gorm:"type:varchar(64);index" json:"user_id"`                  // 提交视频的用户ID（AI用量按用户统计）
 	Timestamp      string `gorm:"type:varchar(50)" json:"timestamp"`                      // 时间戳
 	SavedAt        string `gorm:"type:varchar(50)" json:"saved_at"`                       // 保存时间
+
+	// 元数据生成步骤的其他结果
+	GeneratedTid       int    `gorm:"type:int" json:"generated_tid"`                 // AI建议的投稿分区
+	GeneratedDynamic   string `gorm:"type:varchar(1000)" json:"generated_dynamic"`   // AI生成的投稿动态
+	GeneratedChapters  string `gorm:"type:text" json:"generated_chapters"`           // AI生成的章节（JSON数组）
+	GeneratedCoverText string `gorm:"type:varchar(200)" json:"generated_cover_text"` // 选中的标题候选的封面文字
 }
 
 // TableName 指定表名
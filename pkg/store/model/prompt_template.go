@@ -0,0 +1,19 @@
+package model
+
+// PromptTemplate 提示词模板版本
+// 同一名称、同一频道的模板每次修改保存为新版本，最多一个版本处于启用状态
+// 频道模板优先于全局模板（ChannelID 为空），都未启用时使用内置默认模板
+type PromptTemplate struct {
+	BaseModel
+	Name        string `gorm:"type:varchar(100);index;not null" json:"name"`   // 模板名称（如 translation.system）
+	ChannelID   string `gorm:"type:varchar(100);index" json:"channel_id"`      // 频道ID（为空表示适用于所有频道）
+	Version     int    `gorm:"type:int;not null" json:"version"`               // 版本号（同一名称、频道内从 1 递增）
+	Content     string `gorm:"type:text;not null" json:"content"`              // 模板内容（Go text/template）
+	Description string `gorm:"type:varchar(500)" json:"description"`           // 修改说明
+	Active      bool   `gorm:"type:boolean;default:false;index" json:"active"` // 是否启用
+}
+
+// TableName 指定表名
+func (PromptTemplate) TableName() string {
+	return "cw_prompt_templates"
+}
@@ -0,0 +1,30 @@
+package model
+
+// 字幕编辑历史的操作类型
+const (
+	SubtitleEditActionEdit    = "edit"    // 修改字幕条目
+	SubtitleEditActionApprove = "approve" // 审核通过
+)
+
+// SubtitleEdit 字幕编辑历史
+// 记录人工修改译文字幕（文本、时间轴）和审核通过的操作
+type SubtitleEdit struct {
+	BaseModel
+	VideoID    string `gorm:"type:varchar(100);index;not null" json:"video_id"` // 视频ID
+	Language   string `gorm:"type:varchar(20)" json:"language"`                 // 字幕语言（审核操作为空）
+	Action     string `gorm:"type:varchar(20);not null" json:"action"`          // 操作类型: edit, approve
+	CueIndex   int    `gorm:"type:int" json:"cue_index"`                        // 字幕条目下标（从 0 开始）
+	OldText    string `gorm:"type:text" json:"old_text"`                        // 修改前的文本
+	NewText    string `gorm:"type:text" json:"new_text"`                        // 修改后的文本
+	OldStartMs int64  `gorm:"type:bigint" json:"old_start_ms"`                  // 修改前的开始时间（毫秒）
+	OldEndMs   int64  `gorm:"type:bigint" json:"old_end_ms"`                    // 修改前的结束时间（毫秒）
+	NewStartMs int64  `gorm:"type:bigint" json:"new_start_ms"`                  // 修改后的开始时间（毫秒）
+	NewEndMs   int64  `gorm:"type:bigint" json:"new_end_ms"`                    // 修改后的结束时间（毫秒）
+	Editor     string `gorm:"type:varchar(100)" json:"editor"`                  // 编辑者
+	Note       string `gorm:"type:varchar(500)" json:"note"`                    // 备注
+}
+
+// TableName 指定表名
+func (SubtitleEdit) TableName() string {
+	return "cw_subtitle_edits"
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// TranslationMemory 翻译记忆条目
+// 以 (原文哈希, 源语言, 目标语言, 提供商, 模型, 术语表版本) 为键，片头片尾等重复内容和重试的视频直接复用译文
+type TranslationMemory struct {
+	BaseModel
+	SourceHash      string     `gorm:"type:varchar(64);not null;uniqueIndex:idx_translation_memory_key" json:"source_hash"`      // 原文 SHA-256
+	SourceLang      string     `gorm:"type:varchar(20);not null;uniqueIndex:idx_translation_memory_key" json:"source_lang"`      // 源语言
+	TargetLang      string     `gorm:"type:varchar(20);not null;uniqueIndex:idx_translation_memory_key" json:"target_lang"`      // 目标语言
+	Provider        string     `gorm:"type:varchar(50);not null;uniqueIndex:idx_translation_memory_key" json:"provider"`         // 翻译服务提供商
+	Model           string     `gorm:"type:varchar(100);not null;uniqueIndex:idx_translation_memory_key" json:"model"`           // 使用的模型
+	GlossaryVersion string     `gorm:"type:varchar(32);not null;uniqueIndex:idx_translation_memory_key" json:"glossary_version"` // 术语表版本
+	SourceText      string     `gorm:"type:text;not null" json:"source_text"`                                                    // 原文
+	TranslatedText  string     `gorm:"type:text;not null" json:"translated_text"`                                                // 译文
+	HitCount        int64      `gorm:"type:bigint;default:0" json:"hit_count"`                                                   // 命中次数
+	LastHitAt       *time.Time `json:"last_hit_at"`                                                                              // 最后命中时间
+}
+
+// TableName 指定表名
+func (TranslationMemory) TableName() string {
+	return "cw_translation_memory"
+}
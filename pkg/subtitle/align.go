@@ -0,0 +1,327 @@
+package subtitle
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// 时间轴校正方式
+const (
+	AlignMethodNone    = "none"    // 不校正
+	AlignMethodShift   = "shift"   // 整体偏移
+	AlignMethodStretch = "stretch" // 线性拉伸（偏移 + 缩放）
+)
+
+// Interval 时间区间
+type Interval struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// AlignOptions 时间轴校正规则
+type AlignOptions struct {
+	MaxShift      time.Duration // 允许的最大偏移
+	MaxStretch    float64       // 允许的最大拉伸比例（0.05 表示 ±5%），0 表示只做整体偏移
+	MinShift      time.Duration // 偏移小于该值时不校正
+	Tolerance     time.Duration // 字幕开始时间与语音起点的匹配容差
+	MinMatchRatio float64       // 校正后匹配字幕的最低比例
+}
+
+// Alignment 时间轴校正结果：校正后时间 = 原时间 × Scale + Offset
+type Alignment struct {
+	Method       string        // none, shift, stretch
+	Offset       time.Duration // 偏移
+	Scale        float64       // 缩放比例（1 表示不拉伸）
+	Total        int           // 参与对齐的字幕条数
+	Baseline     int           // 校正前开始时间与语音起点匹配的条数
+	Matched      int           // 校正后匹配的条数
+	MeanErrorMs  int64         // 校正后匹配条目的平均误差（毫秒）
+	SpeechOnsets int           // 检测到的语音起点数
+	Reason       string        // 不校正的原因
+}
+
+// Apply 对时间点应用校正
+func (a Alignment) Apply(t time.Duration) time.Duration {
+	if a.Method == AlignMethodNone || a.Method == "" {
+		return t
+	}
+	return time.Duration(math.Round(float64(t)*a.Scale)) + a.Offset
+}
+
+// minVotes 可靠估计偏移所需的最少匹配数
+const minVotes = 3
+
+// SpeechIntervals 由静音区间求有声区间（静音区间需按时间排序）
+func SpeechIntervals(silences []Interval, total time.Duration) []Interval {
+	var speech []Interval
+	var cursor time.Duration
+	for _, silence := range silences {
+		if silence.Start > cursor {
+			speech = append(speech, Interval{Start: cursor, End: silence.Start})
+		}
+		if silence.End > cursor {
+			cursor = silence.End
+		}
+	}
+	if total > cursor {
+		speech = append(speech, Interval{Start: cursor, End: total})
+	}
+	return speech
+}
+
+// EstimateAlignment 将字幕开始时间与语音起点对齐，估计整体偏移或线性拉伸
+// 先用所有字幕投票得到整体偏移；再搜索拉伸比例并用最小二乘拟合。
+// 只有匹配数明显增加时才采用校正结果，拉伸需要比整体偏移匹配得更好
+func EstimateAlignment(cues []Cue, speech []Interval, opts AlignOptions) Alignment {
+	starts := make([]time.Duration, len(cues))
+	for i, cue := range cues {
+		starts[i] = cue.Start
+	}
+	onsets := make([]time.Duration, len(speech))
+	for i, interval := range speech {
+		onsets[i] = interval.Start
+	}
+	sort.Slice(onsets, func(i, j int) bool { return onsets[i] < onsets[j] })
+
+	result := Alignment{Method: AlignMethodNone, Scale: 1, Total: len(starts), SpeechOnsets: len(onsets)}
+	if len(starts) < minVotes || len(onsets) < minVotes {
+		result.Reason = "字幕或语音起点太少"
+		return result
+	}
+
+	result.Baseline, _ = countMatches(starts, onsets, 1, 0, opts.Tolerance)
+	margin := len(starts) / 20
+	if margin < 2 {
+		margin = 2
+	}
+
+	// 整体偏移
+	best := candidate{scale: 1}
+	if offset, votes := voteOffset(starts, onsets, opts.MaxShift, opts.Tolerance); votes >= minVotes {
+		offset = refineShift(starts, onsets, offset, opts.Tolerance)
+		matched, _ := countMatches(starts, onsets, 1, offset, opts.Tolerance)
+		best = candidate{scale: 1, offset: offset, matched: matched}
+	}
+
+	// 线性拉伸
+	if opts.MaxStretch > 0 {
+		if stretch, ok := fitStretch(starts, onsets, opts); ok && stretch.matched >= best.matched+margin {
+			best = stretch
+		}
+	}
+
+	result.Matched, result.MeanErrorMs = countMatches(starts, onsets, best.scale, best.offset, opts.Tolerance)
+	result.Scale = best.scale
+	result.Offset = best.offset
+
+	switch {
+	case best.matched == 0:
+		result.Reason = "未找到可靠的偏移"
+	case float64(best.matched) < opts.MinMatchRatio*float64(len(starts)):
+		result.Reason = "校正后匹配比例过低"
+	case best.matched < result.Baseline+margin:
+		result.Reason = "校正后匹配没有明显提升"
+	case best.scale == 1 && absDuration(best.offset) < opts.MinShift:
+		result.Reason = "偏移小于阈值"
+	default:
+		result.Method = AlignMethodShift
+		if best.scale != 1 {
+			result.Method = AlignMethodStretch
+		}
+		return result
+	}
+
+	// 不校正时报告的是原时间轴的匹配情况
+	result.Matched, result.MeanErrorMs = countMatches(starts, onsets, 1, 0, opts.Tolerance)
+	return result
+}
+
+// ApplyAlignment 对所有字幕应用时间轴校正，校正后早于 0 的部分截断，完全早于 0 的字幕丢弃
+func ApplyAlignment(cues []Cue, alignment Alignment) []Cue {
+	aligned := make([]Cue, 0, len(cues))
+	for _, cue := range cues {
+		cue.Start = alignment.Apply(cue.Start)
+		cue.End = alignment.Apply(cue.End)
+		if cue.End <= 0 {
+			continue
+		}
+		if cue.Start < 0 {
+			cue.Start = 0
+		}
+		aligned = append(aligned, cue)
+	}
+	return aligned
+}
+
+// candidate 候选的校正参数
+type candidate struct {
+	scale   float64
+	offset  time.Duration
+	matched int
+}
+
+// voteOffset 在 ±maxShift 范围内统计 (语音起点 - 字幕开始时间) 的差值，
+// 取 2×tolerance 窗口内差值最多的位置，返回窗口内差值的中位数和票数
+func voteOffset(starts, onsets []time.Duration, maxShift, tolerance time.Duration) (time.Duration, int) {
+	var diffs []time.Duration
+	for _, start := range starts {
+		lo := sort.Search(len(onsets), func(i int) bool { return onsets[i] >= start-maxShift })
+		for k := lo; k < len(onsets) && onsets[k] <= start+maxShift; k++ {
+			diffs = append(diffs, onsets[k]-start)
+		}
+	}
+	if len(diffs) == 0 {
+		return 0, 0
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i] < diffs[j] })
+
+	bestLo, bestHi := 0, 0
+	hi := 0
+	for lo := range diffs {
+		for hi < len(diffs) && diffs[hi]-diffs[lo] <= 2*tolerance {
+			hi++
+		}
+		if hi-lo > bestHi-bestLo {
+			bestLo, bestHi = lo, hi
+		}
+	}
+	return diffs[(bestLo+bestHi)/2], bestHi - bestLo
+}
+
+// refineShift 用匹配条目的平均差值修正偏移
+func refineShift(starts, onsets []time.Duration, offset, tolerance time.Duration) time.Duration {
+	var sum time.Duration
+	count := 0
+	for _, start := range starts {
+		if onset, ok := nearestOnset(onsets, start+offset, tolerance); ok {
+			sum += onset - start
+			count++
+		}
+	}
+	if count == 0 {
+		return offset
+	}
+	return sum / time.Duration(count)
+}
+
+// maxScaleSteps 搜索拉伸比例时的最大步数
+const maxScaleSteps = 2000
+
+// fitStretch 在 1±MaxStretch 范围内搜索拉伸比例（步长保证最后一条字幕的漂移不超过容差），
+// 每个比例按偏移直方图投票，取票数最多的比例和偏移，再对匹配点做最小二乘拟合（语音起点 = 开始时间 × scale + offset）
+func fitStretch(starts, onsets []time.Duration, opts AlignOptions) (candidate, bool) {
+	last := starts[len(starts)-1]
+	if len(starts) < 4*minVotes || last <= 0 || opts.Tolerance <= 0 {
+		return candidate{}, false
+	}
+
+	step := float64(opts.Tolerance) / float64(last)
+	if steps := opts.MaxStretch / step; steps > maxScaleSteps {
+		step = opts.MaxStretch / maxScaleSteps
+	}
+
+	bins := make([]int, int(2*opts.MaxShift/opts.Tolerance)+2)
+	bestVotes, bestScale, bestOffset := 0, 1.0, time.Duration(0)
+	for scale := 1 - opts.MaxStretch; scale <= 1+opts.MaxStretch; scale += step {
+		for i := range bins {
+			bins[i] = 0
+		}
+		for _, start := range starts {
+			scaled := time.Duration(float64(start) * scale)
+			lo := sort.Search(len(onsets), func(i int) bool { return onsets[i] >= scaled-opts.MaxShift })
+			for k := lo; k < len(onsets) && onsets[k] <= scaled+opts.MaxShift; k++ {
+				bins[int((onsets[k]-scaled+opts.MaxShift)/opts.Tolerance)]++
+			}
+		}
+		// 相邻两个桶合计，避免峰值正好落在桶边界
+		for b := 0; b+1 < len(bins); b++ {
+			if votes := bins[b] + bins[b+1]; votes > bestVotes {
+				bestVotes, bestScale = votes, scale
+				bestOffset = time.Duration(b+1)*opts.Tolerance - opts.MaxShift
+			}
+		}
+	}
+	if bestVotes < minVotes {
+		return candidate{}, false
+	}
+
+	var xs, ys []float64
+	for _, start := range starts {
+		t := time.Duration(float64(start)*bestScale) + bestOffset
+		if onset, ok := nearestOnset(onsets, t, opts.Tolerance); ok {
+			xs = append(xs, float64(start))
+			ys = append(ys, float64(onset))
+		}
+	}
+	scale, intercept, ok := linearFit(xs, ys)
+	if !ok || math.Abs(scale-1) > opts.MaxStretch {
+		return candidate{}, false
+	}
+	offset := time.Duration(math.Round(intercept))
+	if absDuration(offset) > opts.MaxShift {
+		return candidate{}, false
+	}
+	matched, _ := countMatches(starts, onsets, scale, offset, opts.Tolerance)
+	return candidate{scale: scale, offset: offset, matched: matched}, true
+}
+
+// linearFit 最小二乘拟合 y = a·x + b
+func linearFit(xs, ys []float64) (float64, float64, bool) {
+	n := float64(len(xs))
+	if len(xs) < 2*minVotes {
+		return 0, 0, false
+	}
+	var sumX, sumY, sumXX, sumXY float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+		sumXX += xs[i] * xs[i]
+		sumXY += xs[i] * ys[i]
+	}
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0, 0, false
+	}
+	a := (n*sumXY - sumX*sumY) / denominator
+	return a, (sumY - a*sumX) / n, true
+}
+
+// countMatches 校正后开始时间在容差内有语音起点的字幕条数，以及这些条目的平均误差（毫秒）
+func countMatches(starts, onsets []time.Duration, scale float64, offset, tolerance time.Duration) (int, int64) {
+	matched := 0
+	var totalError time.Duration
+	for _, start := range starts {
+		t := time.Duration(float64(start)*scale) + offset
+		if onset, ok := nearestOnset(onsets, t, tolerance); ok {
+			matched++
+			totalError += absDuration(onset - t)
+		}
+	}
+	if matched == 0 {
+		return 0, 0
+	}
+	return matched, (totalError / time.Duration(matched)).Milliseconds()
+}
+
+// nearestOnset 与 t 最近且在容差内的语音起点（onsets 已排序）
+func nearestOnset(onsets []time.Duration, t, tolerance time.Duration) (time.Duration, bool) {
+	i := sort.Search(len(onsets), func(i int) bool { return onsets[i] >= t })
+	best, found := time.Duration(0), false
+	for _, k := range []int{i - 1, i} {
+		if k < 0 || k >= len(onsets) {
+			continue
+		}
+		if d := absDuration(onsets[k] - t); d <= tolerance && (!found || d < absDuration(best-t)) {
+			best, found = onsets[k], true
+		}
+	}
+	return best, found
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
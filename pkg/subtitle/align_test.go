@@ -0,0 +1,75 @@
+package subtitle
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// alignFixture 间隔不规则的字幕，以及按 scale、offset 变换后的语音区间（每条字幕开始处有语音起点）
+func alignFixture(scale float64, offset time.Duration) ([]Cue, []Interval) {
+	var cues []Cue
+	var silences []Interval
+	t := 2 * time.Second
+	for i := 0; i < 80; i++ {
+		duration := 1200*time.Millisecond + time.Duration(i*7%11)*130*time.Millisecond
+		cues = append(cues, Cue{Start: t, End: t + duration, Text: "line"})
+
+		// 字幕之间为静音
+		start := time.Duration(float64(t+duration)*scale) + offset
+		t += duration + 400*time.Millisecond + time.Duration(i*5%13)*90*time.Millisecond
+		silences = append(silences, Interval{Start: start, End: time.Duration(float64(t)*scale) + offset})
+	}
+	first := Interval{Start: 0, End: time.Duration(2*float64(time.Second)*scale) + offset}
+	return cues, SpeechIntervals(append([]Interval{first}, silences...), t+time.Minute)
+}
+
+func TestEstimateAlignment(t *testing.T) {
+	opts := AlignOptions{
+		MaxShift:      30 * time.Second,
+		MaxStretch:    0.05,
+		MinShift:      200 * time.Millisecond,
+		Tolerance:     300 * time.Millisecond,
+		MinMatchRatio: 0.15,
+	}
+
+	tests := []struct {
+		name   string
+		scale  float64
+		offset time.Duration
+		method string
+	}{
+		{"已对齐", 1, 0, AlignMethodNone},
+		{"整体偏移", 1, 3500 * time.Millisecond, AlignMethodShift},
+		{"负偏移", 1, -1800 * time.Millisecond, AlignMethodShift},
+		{"线性拉伸", 1.02, time.Second, AlignMethodStretch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cues, speech := alignFixture(tt.scale, tt.offset)
+			alignment := EstimateAlignment(cues, speech, opts)
+			if alignment.Method != tt.method {
+				t.Fatalf("校正方式 = %s (%s), 期望 %s", alignment.Method, alignment.Reason, tt.method)
+			}
+			if alignment.Method == AlignMethodNone {
+				return
+			}
+			if math.Abs(alignment.Scale-tt.scale) > 0.002 {
+				t.Errorf("缩放比例 = %.4f, 期望 %.4f", alignment.Scale, tt.scale)
+			}
+			if diff := alignment.Offset - tt.offset; diff > 100*time.Millisecond || diff < -100*time.Millisecond {
+				t.Errorf("偏移 = %v, 期望 %v", alignment.Offset, tt.offset)
+			}
+			if alignment.Matched < alignment.Total*9/10 {
+				t.Errorf("校正后匹配 %d/%d 条", alignment.Matched, alignment.Total)
+			}
+
+			aligned := ApplyAlignment(cues, alignment)
+			want := time.Duration(float64(cues[40].Start)*tt.scale) + tt.offset
+			if diff := aligned[40].Start - want; diff > 100*time.Millisecond || diff < -100*time.Millisecond {
+				t.Errorf("校正后开始时间 = %v, 期望 %v", aligned[40].Start, want)
+			}
+		})
+	}
+}
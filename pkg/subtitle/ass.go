@@ -0,0 +1,183 @@
+package subtitle
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ASSStyle ASS 字幕样式
+type ASSStyle struct {
+	Name          string  // 样式名
+	FontName      string  // 字体
+	FontSize      int     // 字号
+	PrimaryColour string  // 文字颜色 (&HAABBGGRR)
+	OutlineColour string  // 描边颜色 (&HAABBGGRR)
+	BackColour    string  // 阴影颜色 (&HAABBGGRR)
+	Bold          bool    // 是否加粗
+	Outline       float64 // 描边宽度
+	Shadow        float64 // 阴影深度
+	Alignment     int     // 对齐方式（小键盘布局，2=底部居中，8=顶部居中）
+	MarginV       int     // 垂直边距
+}
+
+// DefaultASSStyle 默认样式（底部居中白字黑边）
+func DefaultASSStyle() ASSStyle {
+	return ASSStyle{
+		Name:          "Default",
+		FontName:      "Microsoft YaHei",
+		FontSize:      48,
+		PrimaryColour: "&H00FFFFFF",
+		OutlineColour: "&H00000000",
+		BackColour:    "&H80000000",
+		Outline:       2,
+		Shadow:        0,
+		Alignment:     2,
+		MarginV:       40,
+	}
+}
+
+// assDefaultEventFormat 标准 [Events] 字段顺序
+var assDefaultEventFormat = []string{"Layer", "Start", "End", "Style", "Name", "MarginL", "MarginR", "MarginV", "Effect", "Text"}
+
+// assOverridePattern ASS 行内样式标签，如 {\an8}、{\i1}
+var assOverridePattern = regexp.MustCompile(`\{[^}]*\}`)
+
+// ParseASS 解析 ASS/SSA 字幕（只读取 Dialogue 行，去掉行内样式标签）
+func ParseASS(data []byte) ([]Cue, error) {
+	content := strings.TrimPrefix(strings.ReplaceAll(string(data), "\r\n", "\n"), "\ufeff")
+
+	var cues []Cue
+	format := assDefaultEventFormat
+	inEvents := false
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			inEvents = strings.EqualFold(line, "[Events]")
+			continue
+		}
+		if !inEvents {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "Format":
+			format = nil
+			for _, field := range strings.Split(value, ",") {
+				format = append(format, strings.TrimSpace(field))
+			}
+		case "Dialogue":
+			fields := strings.SplitN(strings.TrimSpace(value), ",", len(format))
+			if len(fields) != len(format) {
+				return nil, fmt.Errorf("解析ASS字幕失败: 无效的 Dialogue 行: %s", line)
+			}
+
+			var start, end time.Duration
+			var text string
+			var err error
+			for i, name := range format {
+				switch name {
+				case "Start":
+					start, err = ParseTimestamp(fields[i])
+				case "End":
+					end, err = ParseTimestamp(fields[i])
+				case "Text":
+					text = fields[i]
+				}
+				if err != nil {
+					return nil, fmt.Errorf("解析ASS字幕失败: %v", err)
+				}
+			}
+
+			text = assOverridePattern.ReplaceAllString(text, "")
+			text = strings.NewReplacer(`\N`, "\n", `\n`, "\n", `\h`, " ").Replace(text)
+			text = normalizeLines(text)
+			if text == "" {
+				continue
+			}
+
+			cues = append(cues, Cue{Index: len(cues) + 1, Start: start, End: end, Text: text})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("解析ASS字幕失败: %v", err)
+	}
+
+	return cues, nil
+}
+
+// MarshalASS 生成 ASS 字幕内容
+func MarshalASS(cues []Cue, style ASSStyle) []byte {
+	var sb strings.Builder
+	writeASSHeader(&sb, style)
+	for _, cue := range cues {
+		writeASSDialogue(&sb, cue, style.Name)
+	}
+	return []byte(sb.String())
+}
+
+// writeASSHeader 写入 [Script Info]、[V4+ Styles] 和 [Events] 头部
+func writeASSHeader(sb *strings.Builder, styles ...ASSStyle) {
+	sb.WriteString("[Script Info]\n")
+	sb.WriteString("ScriptType: v4.00+\n")
+	sb.WriteString("PlayResX: 1920\n")
+	sb.WriteString("PlayResY: 1080\n")
+	sb.WriteString("WrapStyle: 0\n")
+	sb.WriteString("ScaledBorderAndShadow: yes\n\n")
+
+	sb.WriteString("[V4+ Styles]\n")
+	sb.WriteString("Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding\n")
+	for _, style := range styles {
+		bold := 0
+		if style.Bold {
+			bold = -1
+		}
+		fmt.Fprintf(sb, "Style: %s,%s,%d,%s,&H000000FF,%s,%s,%d,0,0,0,100,100,0,0,1,%g,%g,%d,20,20,%d,1\n",
+			style.Name, style.FontName, style.FontSize, style.PrimaryColour, style.OutlineColour, style.BackColour,
+			bold, style.Outline, style.Shadow, style.Alignment, style.MarginV)
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString("[Events]\n")
+	sb.WriteString("Format: " + strings.Join(assDefaultEventFormat, ", ") + "\n")
+}
+
+// ASSEvent 指定样式的字幕条目
+type ASSEvent struct {
+	Cue   Cue
+	Style string
+}
+
+// MarshalASSStyles 生成包含多个样式的 ASS 字幕内容，events 中的样式名需在 styles 中定义
+func MarshalASSStyles(styles []ASSStyle, events []ASSEvent) []byte {
+	var sb strings.Builder
+	writeASSHeader(&sb, styles...)
+	for _, event := range events {
+		writeASSDialogue(&sb, event.Cue, event.Style)
+	}
+	return []byte(sb.String())
+}
+
+// writeASSDialogue 写入一行 Dialogue
+func writeASSDialogue(sb *strings.Builder, cue Cue, style string) {
+	text := strings.ReplaceAll(cue.Text, "\n", `\N`)
+	fmt.Fprintf(sb, "Dialogue: 0,%s,%s,%s,,0,0,0,,%s\n", FormatASSTime(cue.Start), FormatASSTime(cue.End), style, text)
+}
+
+// FormatASSTime 格式化 ASS 时间戳 (H:MM:SS.cc)，四舍五入到百分之一秒
+func FormatASSTime(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	cs := d.Round(10*time.Millisecond).Milliseconds() / 10
+	return fmt.Sprintf("%d:%02d:%02d.%02d", cs/360000, cs/6000%60, cs/100%60, cs%100)
+}
@@ -0,0 +1,86 @@
+package subtitle
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// BCCDocument Bilibili 字幕 (BCC) 文档
+type BCCDocument struct {
+	FontSize        float64   `json:"font_size"`
+	FontColor       string    `json:"font_color"`
+	BackgroundAlpha float64   `json:"background_alpha"`
+	BackgroundColor string    `json:"background_color"`
+	Stroke          string    `json:"Stroke"`
+	Body            []BCCItem `json:"body"`
+}
+
+// BCCItem BCC 字幕条目，from/to 单位为秒
+type BCCItem struct {
+	From     float64 `json:"from"`
+	To       float64 `json:"to"`
+	Location int     `json:"location"`
+	Content  string  `json:"content"`
+}
+
+// NewBCCDocument 创建使用 Bilibili 默认样式的 BCC 文档
+func NewBCCDocument(cues []Cue) BCCDocument {
+	doc := BCCDocument{
+		FontSize:        0.4,
+		FontColor:       "#FFFFFF",
+		BackgroundAlpha: 0.5,
+		BackgroundColor: "#9C27B0",
+		Stroke:          "none",
+		Body:            []BCCItem{},
+	}
+	for _, cue := range cues {
+		doc.Body = append(doc.Body, BCCItem{
+			From:     bccSeconds(cue.Start),
+			To:       bccSeconds(cue.End),
+			Location: 2,
+			Content:  cue.Text,
+		})
+	}
+	return doc
+}
+
+// ParseBCC 解析 Bilibili BCC 字幕
+func ParseBCC(data []byte) ([]Cue, error) {
+	var doc BCCDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("解析BCC字幕失败: %v", err)
+	}
+
+	var cues []Cue
+	for _, item := range doc.Body {
+		text := strings.TrimSpace(item.Content)
+		if text == "" {
+			continue
+		}
+		cues = append(cues, Cue{
+			Index: len(cues) + 1,
+			Start: Seconds(item.From),
+			End:   Seconds(item.To),
+			Text:  text,
+		})
+	}
+
+	return cues, nil
+}
+
+// MarshalBCC 生成 Bilibili BCC 字幕内容
+func MarshalBCC(cues []Cue) ([]byte, error) {
+	data, err := json.MarshalIndent(NewBCCDocument(cues), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("生成BCC字幕失败: %v", err)
+	}
+	return data, nil
+}
+
+// bccSeconds 转换为秒，保留三位小数
+func bccSeconds(d time.Duration) float64 {
+	return math.Round(d.Seconds()*1000) / 1000
+}
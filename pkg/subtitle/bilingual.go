@@ -0,0 +1,71 @@
+package subtitle
+
+import (
+	"strings"
+	"time"
+)
+
+// Bilingual 合并两条字幕轨道为双语字幕，每条文本为 "上行\n下行"
+// 以 top 的时间轴为准，bottom 中与之时间重叠的条目合并为下行（译文沿用原文时间轴时即一一对应）
+func Bilingual(top, bottom []Cue) []Cue {
+	result := make([]Cue, 0, len(top))
+	j := 0
+	for _, cue := range top {
+		// bottom 已按时间排序，跳过完全在当前条目之前的部分
+		for j < len(bottom) && bottom[j].End <= cue.Start {
+			j++
+		}
+
+		var lines []string
+		for k := j; k < len(bottom) && bottom[k].Start < cue.End; k++ {
+			if overlap(cue, bottom[k]) > bottom[k].Duration()/2 || overlap(cue, bottom[k]) > cue.Duration()/2 {
+				lines = append(lines, flattenLines(bottom[k].Text))
+			}
+		}
+
+		text := flattenLines(cue.Text)
+		if second := strings.Join(lines, " "); second != "" {
+			text += "\n" + second
+		}
+		result = append(result, Cue{Start: cue.Start, End: cue.End, Text: text})
+	}
+	return reindex(result)
+}
+
+// overlap 两条字幕重叠的时长
+func overlap(a, b Cue) time.Duration {
+	start, end := a.Start, a.End
+	if b.Start > start {
+		start = b.Start
+	}
+	if b.End < end {
+		end = b.End
+	}
+	if end <= start {
+		return 0
+	}
+	return end - start
+}
+
+// DefaultSecondaryASSStyle 双语字幕下行的默认样式（字号较小的浅灰色）
+func DefaultSecondaryASSStyle() ASSStyle {
+	style := DefaultASSStyle()
+	style.Name = "Secondary"
+	style.FontSize = 36
+	style.PrimaryColour = "&H00D0D0D0"
+	return style
+}
+
+// MarshalBilingualASS 生成双语 ASS 字幕，上行使用 primary 样式，下行使用 secondary 样式
+func MarshalBilingualASS(cues []Cue, primary, secondary ASSStyle) []byte {
+	var sb strings.Builder
+	writeASSHeader(&sb, primary, secondary)
+	for _, cue := range cues {
+		// 下行通过 {\r样式名} 切换样式，保证上下两行作为一个整体显示
+		if first, second, ok := strings.Cut(cue.Text, "\n"); ok {
+			cue.Text = first + `\N{\r` + secondary.Name + `}` + strings.ReplaceAll(second, "\n", " ")
+		}
+		writeASSDialogue(&sb, cue, primary.Name)
+	}
+	return []byte(sb.String())
+}
@@ -0,0 +1,62 @@
+package subtitle
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// DefaultLanguage 默认译文语言（简体中文）
+const DefaultLanguage = "zh-Hans"
+
+// languageCodePattern 可作为字幕文件名的语言代码（如 ja、zh-Hant、en-US）
+var languageCodePattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z]{2,4})?$`)
+
+// NormalizeLanguage 统一语言代码写法（与 Bilibili 字幕语言一致）：
+// zh、zh-CN → zh-Hans，zh-TW、zh-HK → zh-Hant，其余为小写语言 + 大写地区（en-us → en-US）
+func NormalizeLanguage(lang string) string {
+	lang = strings.TrimSpace(strings.ReplaceAll(lang, "_", "-"))
+	switch strings.ToLower(lang) {
+	case "":
+		return ""
+	case "zh", "zh-cn", "zh-sg", "zh-hans":
+		return "zh-Hans"
+	case "zh-tw", "zh-hk", "zh-mo", "zh-hant":
+		return "zh-Hant"
+	}
+
+	parts := strings.SplitN(lang, "-", 2)
+	if len(parts) == 1 {
+		return strings.ToLower(parts[0])
+	}
+	if len(parts[1]) == 2 {
+		return strings.ToLower(parts[0]) + "-" + strings.ToUpper(parts[1])
+	}
+	return strings.ToLower(parts[0]) + "-" + strings.ToUpper(parts[1][:1]) + strings.ToLower(parts[1][1:])
+}
+
+// LanguageFilePrefix 译文字幕的文件名前缀：简体中文沿用 zh（zh.srt、zh_optimized.srt），其余为语言代码（zh-Hant.srt、ja.srt）
+func LanguageFilePrefix(lang string) string {
+	lang = NormalizeLanguage(lang)
+	if lang == DefaultLanguage {
+		return "zh"
+	}
+	return lang
+}
+
+// FileLanguage 从字幕文件名解析语言代码（zh_optimized.srt → zh-Hans，ja.srt → ja），
+// 双语字幕返回 bilingual，无法识别时返回空字符串
+func FileLanguage(name string) string {
+	base := strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+	for _, suffix := range []string{"_segmented", "_optimized"} {
+		base = strings.TrimSuffix(base, suffix)
+	}
+
+	if base == "bilingual" {
+		return "bilingual"
+	}
+	if !languageCodePattern.MatchString(base) {
+		return ""
+	}
+	return NormalizeLanguage(base)
+}
@@ -0,0 +1,377 @@
+package subtitle
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SegmentOptions 字幕重新断句规则
+type SegmentOptions struct {
+	MergeSentences bool          // 是否将碎片合并为整句（滚动式自动字幕）
+	MaxMergeGap    time.Duration // 合并时允许的最大间隔，超过则断开
+	MaxCPS         float64       // 每秒最多字符数（阅读速度），显示时间不足时延长
+	MaxLineLength  int           // 每行最大字符数
+	MaxLines       int           // 每条字幕最多行数
+	MinDuration    time.Duration // 最短显示时长
+	MaxDuration    time.Duration // 最长显示时长，超过则拆分
+	MinGap         time.Duration // 相邻字幕最小间隔
+}
+
+// maxChars 每条字幕最多字符数
+func (o SegmentOptions) maxChars() int {
+	if o.MaxLineLength <= 0 {
+		return 0
+	}
+	lines := o.MaxLines
+	if lines <= 0 {
+		lines = 1
+	}
+	return o.MaxLineLength * lines
+}
+
+// sentenceEnders 句末标点
+const sentenceEnders = ".!?。！？…"
+
+// breakPunctuation 可以断开的标点（句末标点和停顿标点）
+const breakPunctuation = sentenceEnders + ",;:，；：、"
+
+// Resegment 重新断句：合并碎片、按长度和时长拆分、调整时间轴并断行
+func Resegment(cues []Cue, opts SegmentOptions) []Cue {
+	cues = cleanCues(cues)
+	if opts.MergeSentences {
+		cues = mergeFragments(cues, opts)
+	}
+	cues = splitLongCues(cues, opts)
+	cues = EnforceTiming(cues, opts)
+	if opts.MaxLineLength > 0 {
+		for i := range cues {
+			cues[i].Text = BreakLines(cues[i].Text, opts.MaxLineLength, opts.MaxLines)
+		}
+	}
+	return reindex(cues)
+}
+
+// cleanCues 按开始时间排序，合并多行文本并去掉空字幕
+func cleanCues(cues []Cue) []Cue {
+	result := make([]Cue, 0, len(cues))
+	for _, cue := range cues {
+		cue.Text = flattenLines(cue.Text)
+		if cue.Text == "" {
+			continue
+		}
+		if cue.End < cue.Start {
+			cue.End = cue.Start
+		}
+		result = append(result, cue)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Start < result[j].Start })
+	return result
+}
+
+// mergeFragments 将碎片合并为整句：遇到句末标点、间隔过大或超出长度/时长限制时断开
+func mergeFragments(cues []Cue, opts SegmentOptions) []Cue {
+	maxChars := opts.maxChars()
+
+	var result []Cue
+	for _, cue := range cues {
+		if n := len(result); n > 0 {
+			last := &result[n-1]
+			merged := joinText(last.Text, cue.Text)
+			if !endsSentence(last.Text) &&
+				(opts.MaxMergeGap <= 0 || cue.Start-last.End <= opts.MaxMergeGap) &&
+				(maxChars <= 0 || runeLen(merged) <= maxChars) &&
+				(opts.MaxDuration <= 0 || cue.End-last.Start <= opts.MaxDuration) {
+				last.Text = merged
+				if cue.End > last.End {
+					last.End = cue.End
+				}
+				continue
+			}
+		}
+		result = append(result, cue)
+	}
+	return result
+}
+
+// splitLongCues 拆分超出长度或时长限制的字幕，时间按字符数比例分配
+func splitLongCues(cues []Cue, opts SegmentOptions) []Cue {
+	maxChars := opts.maxChars()
+
+	var result []Cue
+	for _, cue := range cues {
+		length := runeLen(cue.Text)
+		pieces := 1
+		if maxChars > 0 && length > maxChars {
+			pieces = ceilDiv(length, maxChars)
+		}
+		if opts.MaxDuration > 0 && cue.Duration() > opts.MaxDuration {
+			if n := int(math.Ceil(float64(cue.Duration()) / float64(opts.MaxDuration))); n > pieces {
+				pieces = n
+			}
+		}
+		if pieces <= 1 {
+			result = append(result, cue)
+			continue
+		}
+
+		parts := splitText(cue.Text, ceilDiv(length, pieces), maxChars, 0)
+		result = append(result, distribute(cue, parts)...)
+	}
+	return result
+}
+
+// distribute 按字符数比例把时间分配给拆分后的文本
+func distribute(cue Cue, parts []string) []Cue {
+	total := 0
+	for _, part := range parts {
+		total += runeLen(part)
+	}
+	if total == 0 {
+		return nil
+	}
+
+	cues := make([]Cue, 0, len(parts))
+	start := cue.Start
+	done := 0
+	for i, part := range parts {
+		done += runeLen(part)
+		end := cue.Start + time.Duration(float64(cue.Duration())*float64(done)/float64(total)).Round(time.Millisecond)
+		if i == len(parts)-1 {
+			end = cue.End
+		}
+		cues = append(cues, Cue{Start: start, End: end, Text: part})
+		start = end
+	}
+	return cues
+}
+
+// EnforceTiming 调整时间轴：保证最短时长和阅读速度（在不压到下一条的前提下延长），保证相邻字幕的最小间隔
+func EnforceTiming(cues []Cue, opts SegmentOptions) []Cue {
+	for i := range cues {
+		cue := &cues[i]
+
+		// 阅读速度和最短时长需要的显示时间
+		want := opts.MinDuration
+		if opts.MaxCPS > 0 {
+			if need := time.Duration(float64(runeLen(cue.Text)) / opts.MaxCPS * float64(time.Second)); need > want {
+				want = need
+			}
+		}
+		if opts.MaxDuration > 0 && want > opts.MaxDuration {
+			want = opts.MaxDuration
+		}
+
+		// 下一条开始前必须留出的间隔
+		limit := time.Duration(math.MaxInt64)
+		if i+1 < len(cues) {
+			limit = cues[i+1].Start - opts.MinGap
+		}
+
+		if cue.Duration() < want {
+			end := cue.Start + want
+			if end > limit {
+				end = limit
+			}
+			if end > cue.End {
+				cue.End = end
+			}
+		}
+		if cue.End > limit {
+			cue.End = limit
+			// 间隔不足时至少保留到下一条开始，不让字幕消失
+			if cue.End <= cue.Start && i+1 < len(cues) {
+				cue.End = cues[i+1].Start
+			}
+		}
+	}
+	return cues
+}
+
+// BreakLines 按每行最大字符数断行，最多 maxLines 行（超出时最后一行不再断开）
+func BreakLines(text string, maxLineLength, maxLines int) string {
+	text = flattenLines(text)
+	length := runeLen(text)
+	if maxLineLength <= 0 || length <= maxLineLength {
+		return text
+	}
+
+	lines := ceilDiv(length, maxLineLength)
+	if maxLines > 0 && lines > maxLines {
+		lines = maxLines
+	}
+	if lines <= 1 {
+		return text
+	}
+	return strings.Join(splitText(text, ceilDiv(length, lines), 0, lines), "\n")
+}
+
+// splitText 拆分文本，每段约 target 个字符、不超过 limit 个字符（limit<=0 表示不限），最多 maxParts 段（<=0 表示不限）
+// 优先在标点处断开，其次在空格处，中文可以在任意字符处断开
+func splitText(text string, target, limit, maxParts int) []string {
+	units := textUnits(text)
+
+	// 到达目标长度后，在该范围内有标点时延后到标点处断开
+	lookahead := limit
+	if lookahead <= 0 {
+		lookahead = target + target/3
+	}
+
+	var parts []string
+	var current string
+	for i, unit := range units {
+		if current == "" {
+			current = strings.TrimLeft(unit, " ")
+			continue
+		}
+		if maxParts > 0 && len(parts) == maxParts-1 {
+			current += unit
+			continue
+		}
+
+		length := runeLen(current)
+		next := runeLen(strings.TrimRight(current+unit, " "))
+		split := false
+		switch {
+		case limit > 0 && next > limit:
+			split = true
+		case length*10 >= target*6 && endsWithBreak(current) && !startsWithPunct(unit):
+			split = true
+		case next > target && length*2 >= target:
+			split = !breakAhead(units[i:], length, lookahead)
+		}
+
+		if split {
+			parts = append(parts, strings.TrimSpace(current))
+			current = strings.TrimLeft(unit, " ")
+		} else {
+			current += unit
+		}
+	}
+	if current = strings.TrimSpace(current); current != "" {
+		parts = append(parts, current)
+	}
+	return parts
+}
+
+// breakAhead 在不超过 limit 个字符的范围内，后面是否有可以断开的标点
+func breakAhead(units []string, length, limit int) bool {
+	for _, unit := range units {
+		length += runeLen(unit)
+		if length > limit {
+			return false
+		}
+		if endsWithBreak(unit) {
+			return true
+		}
+	}
+	return false
+}
+
+// textUnits 将文本拆成不可再分的单元：英文单词、单个中文字符，标点跟随前一个单元
+// 单元之间的空格保留在后一个单元的开头
+func textUnits(text string) []string {
+	var units []string
+	var current strings.Builder
+	space := false
+	flush := func() {
+		if current.Len() > 0 {
+			units = append(units, current.String())
+			current.Reset()
+		}
+	}
+	start := func() {
+		if space {
+			current.WriteRune(' ')
+			space = false
+		}
+	}
+
+	for _, r := range text {
+		switch {
+		case unicode.IsSpace(r):
+			flush()
+			space = len(units) > 0
+		case isCJK(r):
+			flush()
+			start()
+			current.WriteRune(r)
+			flush()
+		case unicode.IsPunct(r) && current.Len() == 0 && !space && len(units) > 0:
+			// 标点附着在前一个单元上，避免出现在行首
+			units[len(units)-1] += string(r)
+		default:
+			start()
+			current.WriteRune(r)
+		}
+	}
+	flush()
+	return units
+}
+
+// flattenLines 将多行文本合并为一行
+func flattenLines(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	result := ""
+	for _, line := range lines {
+		result = joinText(result, strings.TrimSpace(line))
+	}
+	return result
+}
+
+// joinText 拼接两段文本，中文之间不加空格
+func joinText(a, b string) string {
+	if a == "" {
+		return b
+	}
+	if b == "" {
+		return a
+	}
+	last, _ := utf8.DecodeLastRuneInString(a)
+	first, _ := utf8.DecodeRuneInString(b)
+	if isCJK(last) || isCJK(first) || isCJKPunct(last) {
+		return a + b
+	}
+	return a + " " + b
+}
+
+// endsSentence 是否以句末标点结尾（忽略结尾的引号和括号）
+func endsSentence(text string) bool {
+	text = strings.TrimRight(text, "\"'”’)）」』 ")
+	last, _ := utf8.DecodeLastRuneInString(text)
+	return strings.ContainsRune(sentenceEnders, last)
+}
+
+// endsWithBreak 是否以可断开的标点结尾
+func endsWithBreak(text string) bool {
+	last, _ := utf8.DecodeLastRuneInString(strings.TrimSpace(text))
+	return strings.ContainsRune(breakPunctuation, last)
+}
+
+// startsWithPunct 是否以标点开头
+func startsWithPunct(text string) bool {
+	first, _ := utf8.DecodeRuneInString(strings.TrimSpace(text))
+	return unicode.IsPunct(first)
+}
+
+// isCJK 是否为中日韩文字
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r)
+}
+
+// isCJKPunct 是否为全角标点
+func isCJKPunct(r rune) bool {
+	return strings.ContainsRune("。，！？；：、…“”‘’（）《》「」『』", r)
+}
+
+// runeLen 字符数
+func runeLen(text string) int {
+	return utf8.RuneCountInString(text)
+}
+
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
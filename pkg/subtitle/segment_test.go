@@ -0,0 +1,96 @@
+package subtitle
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestResegmentMergesFragments(t *testing.T) {
+	cues := []Cue{
+		{Start: ms(0), End: ms(800), Text: "so today we are"},
+		{Start: ms(800), End: ms(1600), Text: "going to talk"},
+		{Start: ms(1600), End: ms(2400), Text: "about subtitles."},
+		{Start: ms(2400), End: ms(3000), Text: "Next"},
+		{Start: ms(6000), End: ms(7000), Text: "after a pause"},
+	}
+	got := Resegment(cues, SegmentOptions{
+		MergeSentences: true,
+		MaxMergeGap:    time.Second,
+		MaxLineLength:  42,
+		MaxLines:       2,
+		MaxDuration:    7 * time.Second,
+	})
+	want := []Cue{
+		{Index: 1, Start: ms(0), End: ms(2400), Text: "so today we are going\nto talk about subtitles."},
+		{Index: 2, Start: ms(2400), End: ms(3000), Text: "Next"},
+		{Index: 3, Start: ms(6000), End: ms(7000), Text: "after a pause"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestResegmentSplitsLongChineseCue(t *testing.T) {
+	cues := []Cue{{Start: 0, End: ms(6000), Text: "今天我们来聊一聊字幕的断句问题，这是一个很常见但又经常被忽略的问题。"}}
+	got := Resegment(cues, SegmentOptions{MaxLineLength: 16, MaxLines: 1, MinGap: ms(80)})
+	if len(got) < 2 {
+		t.Fatalf("expected split, got %+v", got)
+	}
+	if got[0].Text != "今天我们来聊一聊字幕的断句问题，" {
+		t.Errorf("expected break after punctuation, got %q", got[0].Text)
+	}
+	var joined string
+	for i, cue := range got {
+		if runeLen(cue.Text) > 16 {
+			t.Errorf("cue %d too long: %q", i, cue.Text)
+		}
+		if i > 0 && cue.Start-got[i-1].End < ms(80) {
+			t.Errorf("cue %d gap too small: %v", i, cue.Start-got[i-1].End)
+		}
+		joined += cue.Text
+	}
+	if joined != cues[0].Text {
+		t.Errorf("text changed: %q", joined)
+	}
+	if got[0].Start != 0 || got[len(got)-1].End != ms(6000) {
+		t.Errorf("time span changed: %v - %v", got[0].Start, got[len(got)-1].End)
+	}
+}
+
+func TestEnforceTiming(t *testing.T) {
+	cues := []Cue{
+		{Start: ms(0), End: ms(300), Text: "短"},
+		{Start: ms(2000), End: ms(2500), Text: "这句话需要更长的阅读时间"},
+		{Start: ms(3000), End: ms(3500), Text: "下一句"},
+	}
+	got := EnforceTiming(cues, SegmentOptions{MinDuration: time.Second, MaxCPS: 8, MinGap: ms(100)})
+	if got[0].End != ms(1000) {
+		t.Errorf("min duration: got end %v", got[0].End)
+	}
+	if got[1].End != ms(2900) {
+		t.Errorf("reading speed should extend up to the gap: got end %v", got[1].End)
+	}
+	if got[2].End != ms(3500)+ms(500) {
+		t.Errorf("last cue: got end %v", got[2].End)
+	}
+}
+
+func TestBreakLines(t *testing.T) {
+	tests := []struct {
+		text     string
+		length   int
+		lines    int
+		expected string
+	}{
+		{"short", 12, 2, "short"},
+		{"one two three four five six", 16, 2, "one two three\nfour five six"},
+		{"one two three four five six seven eight", 10, 2, "one two three four\nfive six seven eight"},
+		{"我们今天讨论字幕，这非常重要", 10, 2, "我们今天讨论字幕，\n这非常重要"},
+	}
+	for _, tt := range tests {
+		if got := BreakLines(tt.text, tt.length, tt.lines); got != tt.expected {
+			t.Errorf("BreakLines(%q, %d, %d) = %q, want %q", tt.text, tt.length, tt.lines, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package subtitle
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseSRT 解析 SRT 字幕
+// 序号缺失时按出现顺序编号，时间戳兼容 "," 和 "." 两种毫秒分隔符
+func ParseSRT(data []byte) ([]Cue, error) {
+	content := strings.TrimPrefix(strings.ReplaceAll(string(data), "\r\n", "\n"), "\ufeff")
+
+	var cues []Cue
+	for _, block := range splitBlocks(content) {
+		lines := strings.Split(block, "\n")
+
+		timingIdx := -1
+		for i, line := range lines {
+			if strings.Contains(line, "-->") {
+				timingIdx = i
+				break
+			}
+		}
+		if timingIdx < 0 {
+			continue
+		}
+
+		start, end, err := parseTimingLine(lines[timingIdx])
+		if err != nil {
+			return nil, fmt.Errorf("解析SRT字幕失败: %v", err)
+		}
+
+		index := len(cues) + 1
+		if timingIdx > 0 {
+			if n, err := strconv.Atoi(strings.TrimSpace(lines[timingIdx-1])); err == nil {
+				index = n
+			}
+		}
+
+		text := strings.TrimSpace(strings.Join(trimLines(lines[timingIdx+1:]), "\n"))
+		if text == "" {
+			continue
+		}
+
+		cues = append(cues, Cue{Index: index, Start: start, End: end, Text: text})
+	}
+
+	return cues, nil
+}
+
+// MarshalSRT 生成 SRT 字幕内容（按顺序从1开始编号）
+func MarshalSRT(cues []Cue) []byte {
+	var sb strings.Builder
+	for i, cue := range cues {
+		fmt.Fprintf(&sb, "%d\n%s\n%s\n\n", i+1, cue.TimeCode(), cue.Text)
+	}
+	return []byte(sb.String())
+}
+
+// splitBlocks 按空行切分字幕块
+func splitBlocks(content string) []string {
+	var blocks []string
+	var current []string
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) == "" {
+			if len(current) > 0 {
+				blocks = append(blocks, strings.Join(current, "\n"))
+				current = nil
+			}
+			continue
+		}
+		current = append(current, line)
+	}
+	if len(current) > 0 {
+		blocks = append(blocks, strings.Join(current, "\n"))
+	}
+	return blocks
+}
+
+// parseTimingLine 解析 "start --> end [settings]" 时间轴
+func parseTimingLine(line string) (start, end time.Duration, err error) {
+	left, right, ok := strings.Cut(line, "-->")
+	if !ok {
+		return 0, 0, fmt.Errorf("无效的时间轴: %s", line)
+	}
+	// VTT 时间轴后面可能带有 position/align 等设置
+	if fields := strings.Fields(right); len(fields) > 0 {
+		right = fields[0]
+	}
+	if start, err = ParseTimestamp(left); err != nil {
+		return 0, 0, err
+	}
+	if end, err = ParseTimestamp(right); err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+// trimLines 去掉每行首尾空白
+func trimLines(lines []string) []string {
+	result := make([]string, len(lines))
+	for i, line := range lines {
+		result[i] = strings.TrimSpace(line)
+	}
+	return result
+}
@@ -0,0 +1,250 @@
+package subtitle
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cue 字幕条目（统一模型，所有格式的读写都基于它）
+type Cue struct {
+	Index int           // 序号（从1开始，写出时按顺序重新编号）
+	Start time.Duration // 开始时间
+	End   time.Duration // 结束时间
+	Text  string        // 文本，多行用 \n 分隔
+}
+
+// Duration 显示时长
+func (c Cue) Duration() time.Duration {
+	return c.End - c.Start
+}
+
+// TimeCode SRT 格式的时间轴，如 "00:00:01,000 --> 00:00:02,500"
+func (c Cue) TimeCode() string {
+	return FormatSRTTime(c.Start) + " --> " + FormatSRTTime(c.End)
+}
+
+// Format 字幕格式
+type Format string
+
+const (
+	FormatSRT   Format = "srt"
+	FormatVTT   Format = "vtt"
+	FormatASS   Format = "ass"
+	FormatSRV3  Format = "srv3"  // YouTube timedtext format 3 (XML)
+	FormatJSON3 Format = "json3" // YouTube timedtext JSON
+	FormatBCC   Format = "bcc"   // Bilibili 字幕 JSON
+)
+
+// DetectFormat 根据文件扩展名判断字幕格式
+func DetectFormat(path string) (Format, error) {
+	switch strings.ToLower(strings.TrimPrefix(filepath.Ext(path), ".")) {
+	case "srt":
+		return FormatSRT, nil
+	case "vtt":
+		return FormatVTT, nil
+	case "ass", "ssa":
+		return FormatASS, nil
+	case "srv3", "xml":
+		return FormatSRV3, nil
+	case "json3":
+		return FormatJSON3, nil
+	case "bcc", "json":
+		return FormatBCC, nil
+	default:
+		return "", fmt.Errorf("不支持的字幕格式: %s", filepath.Ext(path))
+	}
+}
+
+// Parse 按指定格式解析字幕
+func Parse(format Format, data []byte) ([]Cue, error) {
+	switch format {
+	case FormatSRT:
+		return ParseSRT(data)
+	case FormatVTT:
+		return ParseVTT(data)
+	case FormatASS:
+		return ParseASS(data)
+	case FormatSRV3:
+		return ParseSRV3(data)
+	case FormatJSON3:
+		return ParseJSON3(data)
+	case FormatBCC:
+		return ParseBCC(data)
+	default:
+		return nil, fmt.Errorf("不支持的字幕格式: %s", format)
+	}
+}
+
+// Marshal 按指定格式生成字幕内容
+func Marshal(format Format, cues []Cue) ([]byte, error) {
+	switch format {
+	case FormatSRT:
+		return MarshalSRT(cues), nil
+	case FormatVTT:
+		return MarshalVTT(cues), nil
+	case FormatASS:
+		return MarshalASS(cues, DefaultASSStyle()), nil
+	case FormatSRV3:
+		return MarshalSRV3(cues)
+	case FormatJSON3:
+		return MarshalJSON3(cues)
+	case FormatBCC:
+		return MarshalBCC(cues)
+	default:
+		return nil, fmt.Errorf("不支持的字幕格式: %s", format)
+	}
+}
+
+// ReadFile 读取字幕文件，格式由扩展名决定
+func ReadFile(path string) ([]Cue, error) {
+	format, err := DetectFormat(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(format, data)
+}
+
+// WriteFile 写入字幕文件，格式由扩展名决定
+func WriteFile(path string, cues []Cue) error {
+	format, err := DetectFormat(path)
+	if err != nil {
+		return err
+	}
+	data, err := Marshal(format, cues)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("创建字幕目录失败: %v", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Seconds 将秒转换为时间，统一四舍五入到毫秒
+func Seconds(seconds float64) time.Duration {
+	return time.Duration(math.Round(seconds*1000)) * time.Millisecond
+}
+
+// Texts 提取所有字幕文本
+func Texts(cues []Cue) []string {
+	texts := make([]string, len(cues))
+	for i, cue := range cues {
+		texts[i] = cue.Text
+	}
+	return texts
+}
+
+// WithTexts 复制时间轴并替换文本（用于生成翻译字幕），texts 不足时保留原文
+func WithTexts(cues []Cue, texts []string) []Cue {
+	result := make([]Cue, len(cues))
+	for i, cue := range cues {
+		result[i] = cue
+		if i < len(texts) {
+			result[i].Text = texts[i]
+		}
+	}
+	return result
+}
+
+// ClipOverlaps 截断与下一条重叠的字幕时长（自动字幕的显示窗口通常互相重叠）
+func ClipOverlaps(cues []Cue) []Cue {
+	for i := 0; i < len(cues)-1; i++ {
+		next := cues[i+1].Start
+		if cues[i].End > next && next > cues[i].Start {
+			cues[i].End = next
+		}
+	}
+	return cues
+}
+
+// NormalizeText 清理字幕文本中的实体和多余空白，并合并为单行
+func NormalizeText(text string) string {
+	text = html.UnescapeString(text)
+	text = strings.ReplaceAll(text, "\u00A0", " ")
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// normalizeLines 逐行清理文本并去掉空行，保留换行
+func normalizeLines(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = NormalizeText(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatTimestamp 格式化为 HH:MM:SS<sep>mmm
+func formatTimestamp(d time.Duration, sep string) string {
+	if d < 0 {
+		d = 0
+	}
+	ms := d.Round(time.Millisecond).Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
+
+// FormatSRTTime 格式化 SRT 时间戳 (HH:MM:SS,mmm)
+func FormatSRTTime(d time.Duration) string {
+	return formatTimestamp(d, ",")
+}
+
+// FormatVTTTime 格式化 WebVTT 时间戳 (HH:MM:SS.mmm)
+func FormatVTTTime(d time.Duration) string {
+	return formatTimestamp(d, ".")
+}
+
+// ParseTimestamp 解析 SRT/VTT/ASS 时间戳（HH:MM:SS,mmm、HH:MM:SS.mmm、MM:SS.mmm、H:MM:SS.cc）
+func ParseTimestamp(ts string) (time.Duration, error) {
+	ts = strings.ReplaceAll(strings.TrimSpace(ts), ",", ".")
+	parts := strings.Split(ts, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("无效的时间戳: %s", ts)
+	}
+
+	secs, frac, _ := strings.Cut(parts[len(parts)-1], ".")
+	parts[len(parts)-1] = secs
+
+	var total time.Duration
+	for _, part := range parts {
+		value, err := strconv.Atoi(part)
+		if err != nil || value < 0 {
+			return 0, fmt.Errorf("无效的时间戳: %s", ts)
+		}
+		total = total*60 + time.Duration(value)*time.Second
+	}
+
+	// 小数部分按位数换算为毫秒（ASS 为百分之一秒）
+	if frac != "" {
+		if len(frac) > 3 {
+			frac = frac[:3]
+		}
+		value, err := strconv.Atoi(frac)
+		if err != nil || value < 0 {
+			return 0, fmt.Errorf("无效的时间戳: %s", ts)
+		}
+		for n := len(frac); n < 3; n++ {
+			value *= 10
+		}
+		total += time.Duration(value) * time.Millisecond
+	}
+	return total, nil
+}
+
+// reindex 按顺序重新编号
+func reindex(cues []Cue) []Cue {
+	for i := range cues {
+		cues[i].Index = i + 1
+	}
+	return cues
+}
@@ -0,0 +1,259 @@
+package subtitle
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func ms(n int64) time.Duration {
+	return time.Duration(n) * time.Millisecond
+}
+
+// sampleCues 测试用字幕（时间取 10ms 的整数倍，保证 ASS 往返无损）
+func sampleCues() []Cue {
+	return []Cue{
+		{Index: 1, Start: ms(0), End: ms(1500), Text: "Hello, world!"},
+		{Index: 2, Start: ms(1500), End: ms(4020), Text: "Two lines\nof text & more"},
+		{Index: 3, Start: ms(3723450), End: ms(3725000), Text: "你好，世界"},
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, format := range []Format{FormatSRT, FormatVTT, FormatASS, FormatSRV3, FormatJSON3, FormatBCC} {
+		t.Run(string(format), func(t *testing.T) {
+			data, err := Marshal(format, sampleCues())
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			cues, err := Parse(format, data)
+			if err != nil {
+				t.Fatalf("parse: %v\n%s", err, data)
+			}
+			if !reflect.DeepEqual(cues, sampleCues()) {
+				t.Fatalf("round trip mismatch:\n got %+v\nwant %+v\n%s", cues, sampleCues(), data)
+			}
+		})
+	}
+}
+
+func TestFileRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.srt", "a.vtt", "a.ass", "a.srv3", "a.json3", "a.bcc"} {
+		path := filepath.Join(dir, name)
+		if err := WriteFile(path, sampleCues()); err != nil {
+			t.Fatalf("%s: write: %v", name, err)
+		}
+		cues, err := ReadFile(path)
+		if err != nil {
+			t.Fatalf("%s: read: %v", name, err)
+		}
+		if !reflect.DeepEqual(cues, sampleCues()) {
+			t.Fatalf("%s: round trip mismatch: %+v", name, cues)
+		}
+	}
+}
+
+func TestFormatSRTTimeRounding(t *testing.T) {
+	tests := []struct {
+		seconds float64
+		want    string
+	}{
+		{0, "00:00:00,000"},
+		{1.9999, "00:00:02,000"},
+		{2.345, "00:00:02,345"},
+		{59.9996, "00:01:00,000"},
+		{3723.0504, "01:02:03,050"},
+		{-1, "00:00:00,000"},
+	}
+	for _, tt := range tests {
+		if got := FormatSRTTime(Seconds(tt.seconds)); got != tt.want {
+			t.Errorf("FormatSRTTime(%v) = %s, want %s", tt.seconds, got, tt.want)
+		}
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	tests := map[string]time.Duration{
+		"00:00:01,500": ms(1500),
+		"00:00:01.500": ms(1500),
+		"01:00.250":    ms(60250),
+		"1:02:03.45":   ms(3723450),
+	}
+	for input, want := range tests {
+		got, err := ParseTimestamp(input)
+		if err != nil || got != want {
+			t.Errorf("ParseTimestamp(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+	if _, err := ParseTimestamp("abc"); err == nil {
+		t.Error("expected error for invalid timestamp")
+	}
+}
+
+func TestParseSRTLenient(t *testing.T) {
+	input := "\ufeff1\r\n00:00:01,000 --> 00:00:02,000\r\n  first  \r\n\r\n\r\n" +
+		"00:00:03.000 --> 00:00:04.000\nno index\n\n" +
+		"7\n00:00:05,000 --> 00:00:06,000\n\n"
+	cues, err := ParseSRT([]byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Cue{
+		{Index: 1, Start: ms(1000), End: ms(2000), Text: "first"},
+		{Index: 2, Start: ms(3000), End: ms(4000), Text: "no index"},
+	}
+	if !reflect.DeepEqual(cues, want) {
+		t.Fatalf("got %+v", cues)
+	}
+}
+
+func TestParseVTTRollingCaptions(t *testing.T) {
+	input := `WEBVTT
+Kind: captions
+Language: en
+
+00:00:00.000 --> 00:00:02.000 align:start position:0%
+hello<00:00:00.500><c> world</c>
+
+00:00:02.000 --> 00:00:04.000 align:start position:0%
+hello world
+how are you
+
+00:00:04.000 --> 00:00:06.000 align:start position:0%
+how are you
+I&#39;m fine &amp; you
+`
+	cues, err := ParseVTT([]byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cues = RemoveRollingDuplicates(cues)
+	got := Texts(cues)
+	want := []string{"hello world", "how are you", "I'm fine & you"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if cues[2].Index != 3 || cues[2].Start != ms(4000) {
+		t.Fatalf("unexpected cue %+v", cues[2])
+	}
+}
+
+func TestParseYouTubeCaptions(t *testing.T) {
+	srv3 := `<?xml version="1.0" encoding="utf-8" ?><timedtext format="3"><body>
+<p t="0" d="3000"><s>Hello</s><s t="500"> there</s></p>
+<p t="2500" d="2000">it&amp;#39;s  me</p>
+<p t="5000" d="100">
+</p>
+</body></timedtext>`
+	cues, err := ParseSRV3([]byte(srv3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cues = ClipOverlaps(cues)
+	want := []Cue{
+		{Index: 1, Start: 0, End: ms(2500), Text: "Hello there"},
+		{Index: 2, Start: ms(2500), End: ms(4500), Text: "it's me"},
+	}
+	if !reflect.DeepEqual(cues, want) {
+		t.Fatalf("srv3: got %+v", cues)
+	}
+
+	json3 := `{"events":[{"tStartMs":0,"dDurationMs":2000,"segs":[{"utf8":"Hello"},{"utf8":" there"}]},{"tStartMs":1000,"segs":[{"utf8":"\n"}]},{"tStartMs":2000,"dDurationMs":1000}]}`
+	cues, err = ParseJSON3([]byte(json3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cues) != 1 || cues[0].Text != "Hello there" || cues[0].End != ms(2000) {
+		t.Fatalf("json3: got %+v", cues)
+	}
+}
+
+func TestMarshalBCC(t *testing.T) {
+	data, err := MarshalBCC([]Cue{{Start: ms(1234), End: ms(2000), Text: "测试"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"from": 1.234`, `"to": 2`, `"content": "测试"`, `"location": 2`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("BCC output missing %s:\n%s", want, data)
+		}
+	}
+}
+
+func TestParseASSOverrides(t *testing.T) {
+	input := `[Script Info]
+Title: test
+
+[Events]
+Format: Layer, Start, End, Style, Text
+Comment: 0,0:00:00.00,0:00:01.00,Default,ignored
+Dialogue: 0,0:00:01.00,0:00:02.50,Default,{\an8}Top, with comma\Nsecond\hline
+`
+	cues, err := ParseASS([]byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Cue{{Index: 1, Start: ms(1000), End: ms(2500), Text: "Top, with comma\nsecond line"}}
+	if !reflect.DeepEqual(cues, want) {
+		t.Fatalf("got %+v", cues)
+	}
+}
+
+func TestBilingual(t *testing.T) {
+	en := []Cue{
+		{Start: ms(0), End: ms(2000), Text: "Hello there,\nfriend."},
+		{Start: ms(2000), End: ms(4000), Text: "How are you?"},
+		{Start: ms(5000), End: ms(6000), Text: "Untranslated"},
+	}
+	zh := []Cue{
+		{Start: ms(0), End: ms(2000), Text: "你好，朋友。"},
+		{Start: ms(2000), End: ms(3000), Text: "你好"},
+		{Start: ms(3000), End: ms(4000), Text: "吗？"},
+	}
+	got := Bilingual(zh, en)
+	want := []Cue{
+		{Index: 1, Start: ms(0), End: ms(2000), Text: "你好，朋友。\nHello there, friend."},
+		{Index: 2, Start: ms(2000), End: ms(3000), Text: "你好\nHow are you?"},
+		{Index: 3, Start: ms(3000), End: ms(4000), Text: "吗？\nHow are you?"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v", got)
+	}
+
+	data := MarshalBilingualASS(got[:1], DefaultASSStyle(), DefaultSecondaryASSStyle())
+	if !strings.Contains(string(data), `你好，朋友。\N{\rSecondary}Hello there, friend.`) {
+		t.Fatalf("unexpected ASS output:\n%s", data)
+	}
+	cues, err := ParseASS(data)
+	if err != nil || len(cues) != 1 || cues[0].Text != got[0].Text {
+		t.Fatalf("parse bilingual ASS: %+v, %v", cues, err)
+	}
+}
+
+func TestFileLanguage(t *testing.T) {
+	cases := map[string]string{
+		"zh.srt":           "zh-Hans",
+		"zh_optimized.srt": "zh-Hans",
+		"zh_segmented.srt": "zh-Hans",
+		"zh-Hant.srt":      "zh-Hant",
+		"ja_optimized.srt": "ja",
+		"en.srt":           "en",
+		"bilingual.ass":    "bilingual",
+		"hardsub.ass":      "",
+		"dQw4w9WgXcQ.srt":  "",
+	}
+	for name, want := range cases {
+		if got := FileLanguage(name); got != want {
+			t.Errorf("FileLanguage(%q) = %q, want %q", name, got, want)
+		}
+	}
+
+	for lang, want := range map[string]string{"zh-CN": "zh", "zh-Hans": "zh", "zh-tw": "zh-Hant", "JA": "ja", "en_us": "en-US"} {
+		if got := LanguageFilePrefix(lang); got != want {
+			t.Errorf("LanguageFilePrefix(%q) = %q, want %q", lang, got, want)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package subtitle
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+var vttTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// ParseVTT 解析 WebVTT 字幕
+// 会去掉样式标签（<c>、<i>、逐词时间戳等）并解码 HTML 实体，NOTE/STYLE/REGION 块会被忽略
+func ParseVTT(data []byte) ([]Cue, error) {
+	content := strings.TrimPrefix(strings.ReplaceAll(string(data), "\r\n", "\n"), "\ufeff")
+	if !strings.HasPrefix(content, "WEBVTT") {
+		return nil, fmt.Errorf("不是有效的WebVTT文件")
+	}
+
+	var cues []Cue
+	for _, block := range splitBlocks(content) {
+		lines := strings.Split(block, "\n")
+
+		timingIdx := -1
+		for i, line := range lines {
+			if strings.Contains(line, "-->") {
+				timingIdx = i
+				break
+			}
+		}
+		if timingIdx < 0 {
+			continue
+		}
+
+		start, end, err := parseTimingLine(lines[timingIdx])
+		if err != nil {
+			return nil, fmt.Errorf("解析WebVTT字幕失败: %v", err)
+		}
+
+		var textLines []string
+		for _, line := range lines[timingIdx+1:] {
+			line = NormalizeText(vttTagPattern.ReplaceAllString(line, ""))
+			if line != "" {
+				textLines = append(textLines, line)
+			}
+		}
+		if len(textLines) == 0 {
+			continue
+		}
+
+		cues = append(cues, Cue{
+			Index: len(cues) + 1,
+			Start: start,
+			End:   end,
+			Text:  strings.Join(textLines, "\n"),
+		})
+	}
+
+	return cues, nil
+}
+
+// MarshalVTT 生成 WebVTT 字幕内容
+func MarshalVTT(cues []Cue) []byte {
+	var sb strings.Builder
+	sb.WriteString("WEBVTT\n\n")
+	for i, cue := range cues {
+		var lines []string
+		for _, line := range strings.Split(cue.Text, "\n") {
+			lines = append(lines, html.EscapeString(line))
+		}
+		fmt.Fprintf(&sb, "%d\n%s --> %s\n%s\n\n", i+1, FormatVTTTime(cue.Start), FormatVTTTime(cue.End), strings.Join(lines, "\n"))
+	}
+	return []byte(sb.String())
+}
+
+// RemoveRollingDuplicates 去掉滚动式字幕中的重复行
+// YouTube 自动字幕是滚动式的（每条重复上一条的内容），去重后每条只保留新出现的行，并合并为单行
+func RemoveRollingDuplicates(cues []Cue) []Cue {
+	var result []Cue
+	var previousLines map[string]bool
+
+	for _, cue := range cues {
+		currentLines := map[string]bool{}
+		var textLines []string
+		for _, line := range strings.Split(cue.Text, "\n") {
+			if line == "" {
+				continue
+			}
+			currentLines[line] = true
+			if previousLines[line] {
+				continue
+			}
+			textLines = append(textLines, line)
+		}
+		previousLines = currentLines
+
+		if len(textLines) == 0 {
+			continue
+		}
+		cue.Text = strings.Join(textLines, " ")
+		result = append(result, cue)
+	}
+
+	return reindex(result)
+}
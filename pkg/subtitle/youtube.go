@@ -0,0 +1,148 @@
+package subtitle
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// srv3Document YouTube SRV3 (timedtext format 3) 字幕文档
+type srv3Document struct {
+	XMLName    xml.Name        `xml:"timedtext"`
+	Format     string          `xml:"format,attr,omitempty"`
+	Paragraphs []srv3Paragraph `xml:"body>p"`
+}
+
+// srv3Paragraph SRV3 段落，t/d 单位为毫秒
+type srv3Paragraph struct {
+	Start    int64         `xml:"t,attr"`
+	Duration int64         `xml:"d,attr"`
+	Text     string        `xml:",chardata"`
+	Segments []srv3Segment `xml:"s"`
+}
+
+// srv3Segment SRV3 分词片段
+type srv3Segment struct {
+	Text string `xml:",chardata"`
+}
+
+// ParseSRV3 解析 YouTube SRV3 字幕
+func ParseSRV3(data []byte) ([]Cue, error) {
+	var doc srv3Document
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("解析SRV3字幕失败: %v", err)
+	}
+
+	var cues []Cue
+	for _, p := range doc.Paragraphs {
+		text := p.Text
+		if len(p.Segments) > 0 {
+			var sb strings.Builder
+			for _, seg := range p.Segments {
+				sb.WriteString(seg.Text)
+			}
+			text = sb.String()
+		}
+
+		text = normalizeLines(text)
+		if text == "" {
+			continue
+		}
+
+		start := time.Duration(p.Start) * time.Millisecond
+		cues = append(cues, Cue{
+			Index: len(cues) + 1,
+			Start: start,
+			End:   start + time.Duration(p.Duration)*time.Millisecond,
+			Text:  text,
+		})
+	}
+
+	return cues, nil
+}
+
+// MarshalSRV3 生成 YouTube SRV3 字幕内容
+func MarshalSRV3(cues []Cue) ([]byte, error) {
+	doc := srv3Document{Format: "3"}
+	for _, cue := range cues {
+		doc.Paragraphs = append(doc.Paragraphs, srv3Paragraph{
+			Start:    cue.Start.Milliseconds(),
+			Duration: cue.Duration().Milliseconds(),
+			Text:     cue.Text,
+		})
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("生成SRV3字幕失败: %v", err)
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// json3Document YouTube timedtext JSON 字幕文档
+type json3Document struct {
+	Events []json3Event `json:"events"`
+}
+
+// json3Event JSON3 事件，时间单位为毫秒
+type json3Event struct {
+	StartMs    int64          `json:"tStartMs"`
+	DurationMs int64          `json:"dDurationMs,omitempty"`
+	Segments   []json3Segment `json:"segs,omitempty"`
+}
+
+// json3Segment JSON3 分词片段
+type json3Segment struct {
+	Text string `json:"utf8"`
+}
+
+// ParseJSON3 解析 YouTube JSON3 字幕（忽略只包含换行的追加事件）
+func ParseJSON3(data []byte) ([]Cue, error) {
+	var doc json3Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("解析JSON3字幕失败: %v", err)
+	}
+
+	var cues []Cue
+	for _, event := range doc.Events {
+		var sb strings.Builder
+		for _, seg := range event.Segments {
+			sb.WriteString(seg.Text)
+		}
+
+		text := normalizeLines(sb.String())
+		if text == "" {
+			continue
+		}
+
+		start := time.Duration(event.StartMs) * time.Millisecond
+		cues = append(cues, Cue{
+			Index: len(cues) + 1,
+			Start: start,
+			End:   start + time.Duration(event.DurationMs)*time.Millisecond,
+			Text:  text,
+		})
+	}
+
+	return cues, nil
+}
+
+// MarshalJSON3 生成 YouTube JSON3 字幕内容
+func MarshalJSON3(cues []Cue) ([]byte, error) {
+	doc := json3Document{Events: []json3Event{}}
+	for _, cue := range cues {
+		doc.Events = append(doc.Events, json3Event{
+			StartMs:    cue.Start.Milliseconds(),
+			DurationMs: cue.Duration().Milliseconds(),
+			Segments:   []json3Segment{{Text: cue.Text}},
+		})
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("生成JSON3字幕失败: %v", err)
+	}
+	return data, nil
+}
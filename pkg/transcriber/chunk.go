@@ -0,0 +1,100 @@
+package transcriber
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+// DefaultChunkSeconds 默认分片时长（16kHz 单声道 WAV 10 分钟约 19MB，低于常见接口 25MB 的上限）
+const DefaultChunkSeconds = 600
+
+// TranscribeLong 将长音频切分为多个分片依次识别，并把时间戳还原到原始音频时间轴
+// 第一个分片识别出的语言会用于后续分片，避免不同分片识别成不同语言
+func TranscribeLong(ctx context.Context, t Transcriber, req *TranscriptionRequest, chunkSeconds int) (*TranscriptionResult, error) {
+	if chunkSeconds <= 0 {
+		chunkSeconds = DefaultChunkSeconds
+	}
+
+	chunkDir, err := os.MkdirTemp(filepath.Dir(req.AudioPath), "asr_chunks_")
+	if err != nil {
+		return nil, fmt.Errorf("创建分片目录失败: %v", err)
+	}
+	defer os.RemoveAll(chunkDir)
+
+	chunks, err := splitAudio(req.AudioPath, chunkDir, chunkSeconds)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := &TranscriptionResult{Language: req.Language}
+	for i, chunkPath := range chunks {
+		offset := float64(i * chunkSeconds)
+
+		chunkReq := &TranscriptionRequest{
+			AudioPath: chunkPath,
+			Language:  merged.Language,
+			Prompt:    req.Prompt,
+		}
+		result, err := t.Transcribe(ctx, chunkReq)
+		if err != nil {
+			return nil, fmt.Errorf("识别第 %d/%d 个分片失败: %v", i+1, len(chunks), err)
+		}
+
+		if isAutoLanguage(merged.Language) && result.Language != "" {
+			merged.Language = result.Language
+		}
+		merged.Provider = result.Provider
+		merged.Model = result.Model
+
+		for _, seg := range result.Segments {
+			seg.Start += offset
+			seg.End += offset
+			for j := range seg.Words {
+				seg.Words[j].Start += offset
+				seg.Words[j].End += offset
+			}
+			merged.Segments = append(merged.Segments, seg)
+		}
+
+		chunkEnd := offset + result.Duration
+		if chunkEnd > merged.Duration {
+			merged.Duration = chunkEnd
+		}
+	}
+
+	return merged, nil
+}
+
+// splitAudio 使用 ffmpeg 按固定时长切分音频
+func splitAudio(audioPath, outputDir string, chunkSeconds int) ([]string, error) {
+	pattern := filepath.Join(outputDir, "chunk_%04d.wav")
+	cmd := exec.Command(
+		"ffmpeg",
+		"-y",
+		"-i", audioPath,
+		"-f", "segment",
+		"-segment_time", strconv.Itoa(chunkSeconds),
+		"-ar", "16000",
+		"-ac", "1",
+		"-c:a", "pcm_s16le",
+		pattern,
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("ffmpeg 切分音频失败: %v, 输出: %s", err, lastLines(string(output), 5))
+	}
+
+	chunks, err := filepath.Glob(filepath.Join(outputDir, "chunk_*.wav"))
+	if err != nil {
+		return nil, err
+	}
+	if len(chunks) == 0 {
+		return nil, fmt.Errorf("切分音频后没有生成分片")
+	}
+	sort.Strings(chunks)
+	return chunks, nil
+}
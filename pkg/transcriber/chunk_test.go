@@ -0,0 +1,122 @@
+package transcriber
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// fakeChunkTranscriber 按分片文件名返回固定的识别结果，并记录收到的请求
+type fakeChunkTranscriber struct {
+	requests []TranscriptionRequest
+	failAt   int // 在第几个分片（从 1 开始）返回错误，0 表示不出错
+}
+
+func (f *fakeChunkTranscriber) Transcribe(ctx context.Context, req *TranscriptionRequest) (*TranscriptionResult, error) {
+	f.requests = append(f.requests, *req)
+	if len(f.requests) == f.failAt {
+		return nil, fmt.Errorf("接口超时")
+	}
+
+	result := &TranscriptionResult{Duration: 600, Provider: "fake", Model: "whisper-1"}
+	if isAutoLanguage(req.Language) {
+		result.Language = "en"
+	}
+	if strings.HasSuffix(req.AudioPath, "chunk_0002.wav") {
+		result.Duration = 125
+	}
+	result.Segments = []Segment{{
+		Text:  filepath.Base(req.AudioPath),
+		Start: 1,
+		End:   3.5,
+		Words: []Word{{Text: "hello", Start: 1, End: 2}, {Text: "world.", Start: 2.2, End: 3.5}},
+	}}
+	return result, nil
+}
+
+func (f *fakeChunkTranscriber) GetName() string                    { return "fake" }
+func (f *fakeChunkTranscriber) IsHealthy(ctx context.Context) bool { return true }
+
+// installFakeFFmpeg 在 PATH 最前面放一个假的 ffmpeg，按输出文件名模板生成 3 个分片，并记录调用参数
+func installFakeFFmpeg(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("假的 ffmpeg 使用 shell 脚本")
+	}
+
+	binDir := t.TempDir()
+	argsFile := filepath.Join(binDir, "args")
+	script := "#!/bin/sh\necho \"$@\" > " + argsFile + "\nfor a; do last=$a; done\nfor i in 2 0 1; do : > \"$(printf \"$last\" $i)\"; done\n"
+	if err := os.WriteFile(filepath.Join(binDir, "ffmpeg"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return argsFile
+}
+
+// TestTranscribeLong 测试长音频分片识别：按顺序识别分片，时间戳加上分片偏移，第一个分片识别出的语言用于后续分片
+func TestTranscribeLong(t *testing.T) {
+	argsFile := installFakeFFmpeg(t)
+	audioPath := filepath.Join(t.TempDir(), "video.wav")
+	if err := os.WriteFile(audioPath, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	asr := &fakeChunkTranscriber{}
+	result, err := TranscribeLong(context.Background(), asr, &TranscriptionRequest{AudioPath: audioPath, Prompt: "Kubernetes"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args, _ := os.ReadFile(argsFile)
+	if !strings.Contains(string(args), "-segment_time 600") {
+		t.Errorf("未配置分片时长时应使用默认 %d 秒: %s", DefaultChunkSeconds, args)
+	}
+	if len(asr.requests) != 3 {
+		t.Fatalf("应识别 3 个分片，实际 %d 个", len(asr.requests))
+	}
+	for i, req := range asr.requests {
+		if chunk := fmt.Sprintf("chunk_%04d.wav", i); filepath.Base(req.AudioPath) != chunk || req.Prompt != "Kubernetes" {
+			t.Errorf("第 %d 个请求 = %+v, 期望分片 %s", i+1, req, chunk)
+		}
+		want := "en"
+		if i == 0 {
+			want = "" // 第一个分片自动检测
+		}
+		if req.Language != want {
+			t.Errorf("第 %d 个请求的语言 = %q, 期望 %q", i+1, req.Language, want)
+		}
+	}
+
+	if result.Language != "en" || result.Provider != "fake" || result.Duration != 1325 || len(result.Segments) != 3 {
+		t.Fatalf("合并结果不正确: %+v", result)
+	}
+	last := result.Segments[2]
+	if last.Text != "chunk_0002.wav" || last.Start != 1201 || last.End != 1203.5 || last.Words[1].Start != 1202.2 {
+		t.Errorf("第 3 个分片的时间戳未加上偏移: %+v", last)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(filepath.Dir(audioPath), "asr_chunks_*")); len(matches) != 0 {
+		t.Errorf("分片目录未清理: %v", matches)
+	}
+}
+
+// TestTranscribeLongFailure 测试某个分片识别失败时返回错误，且指定的语言不会被覆盖
+func TestTranscribeLongFailure(t *testing.T) {
+	installFakeFFmpeg(t)
+	audioPath := filepath.Join(t.TempDir(), "video.wav")
+
+	asr := &fakeChunkTranscriber{failAt: 2}
+	_, err := TranscribeLong(context.Background(), asr, &TranscriptionRequest{AudioPath: audioPath, Language: "ja"}, 300)
+	if err == nil || !strings.Contains(err.Error(), "第 2/3 个分片") {
+		t.Fatalf("期望第 2 个分片的错误，得到 %v", err)
+	}
+	for _, req := range asr.requests {
+		if req.Language != "ja" {
+			t.Errorf("指定语言后不应自动检测: %+v", req)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package transcriber
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Cue 合并后的字幕条目（秒）
+type Cue struct {
+	Start float64
+	End   float64
+	Text  string
+}
+
+// CueOptions 单词合并为字幕条目的规则
+type CueOptions struct {
+	MaxChars    int     // 每条字幕最大字符数
+	MaxDuration float64 // 每条字幕最长时长（秒）
+	MaxGap      float64 // 单词间隔超过该值时断开（秒）
+}
+
+// DefaultCueOptions 默认合并规则
+func DefaultCueOptions() CueOptions {
+	return CueOptions{
+		MaxChars:    80,
+		MaxDuration: 7,
+		MaxGap:      1.0,
+	}
+}
+
+// BuildCues 将识别结果合并为字幕条目
+// 有单词时间戳时按句末标点、长度、时长和停顿切分；否则直接使用片段
+func BuildCues(segments []Segment, opts CueOptions) []Cue {
+	defaults := DefaultCueOptions()
+	if opts.MaxChars <= 0 {
+		opts.MaxChars = defaults.MaxChars
+	}
+	if opts.MaxDuration <= 0 {
+		opts.MaxDuration = defaults.MaxDuration
+	}
+	if opts.MaxGap <= 0 {
+		opts.MaxGap = defaults.MaxGap
+	}
+
+	var cues []Cue
+	var pending []Word
+
+	flush := func() {
+		if len(pending) == 0 {
+			return
+		}
+		text := joinWords(pending)
+		if text != "" {
+			cues = append(cues, Cue{Start: pending[0].Start, End: pending[len(pending)-1].End, Text: text})
+		}
+		pending = nil
+	}
+
+	for _, seg := range segments {
+		if len(seg.Words) == 0 {
+			flush()
+			text := strings.TrimSpace(seg.Text)
+			if text != "" {
+				cues = append(cues, Cue{Start: seg.Start, End: seg.End, Text: text})
+			}
+			continue
+		}
+
+		for _, word := range seg.Words {
+			if strings.TrimSpace(word.Text) == "" {
+				continue
+			}
+
+			if len(pending) > 0 {
+				last := pending[len(pending)-1]
+				candidate := joinWords(append(append([]Word{}, pending...), word))
+				if word.Start-last.End > opts.MaxGap ||
+					word.End-pending[0].Start > opts.MaxDuration ||
+					utf8.RuneCountInString(candidate) > opts.MaxChars {
+					flush()
+				}
+			}
+
+			pending = append(pending, word)
+			if endsSentence(word.Text) {
+				flush()
+			}
+		}
+	}
+	flush()
+
+	return cues
+}
+
+// joinWords 拼接单词；中日韩文字之间不加空格
+func joinWords(words []Word) string {
+	var sb strings.Builder
+	var prev rune
+	for _, word := range words {
+		text := strings.TrimSpace(word.Text)
+		if text == "" {
+			continue
+		}
+		first, _ := utf8.DecodeRuneInString(text)
+		if sb.Len() > 0 && !(isCJK(prev) && isCJK(first)) && !unicode.IsPunct(first) {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(text)
+		prev, _ = utf8.DecodeLastRuneInString(text)
+	}
+	return sb.String()
+}
+
+// endsSentence 判断单词是否以句末标点结尾
+func endsSentence(text string) bool {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return false
+	}
+	last, _ := utf8.DecodeLastRuneInString(text)
+	return strings.ContainsRune(".?!。？！…", last)
+}
+
+// isCJK 判断是否为中日韩文字
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r)
+}
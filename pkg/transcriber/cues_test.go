@@ -0,0 +1,67 @@
+package transcriber
+
+import "testing"
+
+// TestBuildCues 测试单词合并为字幕条目：句末标点、停顿、时长和字数都会断开
+func TestBuildCues(t *testing.T) {
+	segments := []Segment{
+		{Words: []Word{
+			{Text: " Hello", Start: 0, End: 0.4},
+			{Text: "world", Start: 0.5, End: 0.9},
+			{Text: ",", Start: 0.9, End: 1.0},
+			{Text: "again.", Start: 1.1, End: 1.5}, // 句末标点
+			{Text: "After", Start: 1.6, End: 2.0},
+			{Text: "pause", Start: 4.0, End: 4.5}, // 停顿超过 1 秒
+			{Text: " ", Start: 4.5, End: 4.6},
+			{Text: "slow", Start: 4.6, End: 9.0},
+			{Text: "speech", Start: 9.1, End: 12.0}, // 超过 7 秒
+		}},
+		{Text: "  没有单词时间戳的片段  ", Start: 12.5, End: 14},
+		{Text: "   ", Start: 14, End: 15},
+		{Words: []Word{
+			{Text: "你好", Start: 15, End: 15.5},
+			{Text: "世界", Start: 15.5, End: 16},
+			{Text: "！", Start: 16, End: 16.1},
+		}},
+	}
+
+	want := []Cue{
+		{Start: 0, End: 1.5, Text: "Hello world, again."},
+		{Start: 1.6, End: 2.0, Text: "After"},
+		{Start: 4.0, End: 9.0, Text: "pause slow"},
+		{Start: 9.1, End: 12.0, Text: "speech"},
+		{Start: 12.5, End: 14, Text: "没有单词时间戳的片段"},
+		{Start: 15, End: 16.1, Text: "你好世界！"},
+	}
+	got := BuildCues(segments, CueOptions{})
+	if len(got) != len(want) {
+		t.Fatalf("BuildCues = %+v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("第 %d 条 = %+v, 期望 %+v", i+1, got[i], want[i])
+		}
+	}
+}
+
+// TestBuildCuesMaxChars 测试超过字数限制时在加入下一个单词前断开
+func TestBuildCuesMaxChars(t *testing.T) {
+	var words []Word
+	for i, text := range []string{"one", "two", "three", "four", "five"} {
+		words = append(words, Word{Text: text, Start: float64(i), End: float64(i) + 0.8})
+	}
+
+	got := BuildCues([]Segment{{Words: words}}, CueOptions{MaxChars: 9, MaxDuration: 60})
+	want := []string{"one two", "three", "four five"}
+	if len(got) != len(want) {
+		t.Fatalf("BuildCues = %+v", got)
+	}
+	for i := range want {
+		if got[i].Text != want[i] {
+			t.Errorf("第 %d 条 = %q, 期望 %q", i+1, got[i].Text, want[i])
+		}
+	}
+	if got[2].Start != 3 || got[2].End != 4.8 {
+		t.Errorf("第 3 条的时间 = %v-%v", got[2].Start, got[2].End)
+	}
+}
@@ -0,0 +1,33 @@
+package transcriber
+
+import (
+	"fmt"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+)
+
+// NewTranscriber 根据配置创建语音识别器
+func NewTranscriber(config *types.AppConfig) (Transcriber, error) {
+	cfg := config.TranscriberConfig
+	if cfg == nil || !cfg.Enabled {
+		return nil, fmt.Errorf("transcriber not enabled or config not found")
+	}
+
+	switch cfg.Provider {
+	case "whisper_cpp", "":
+		return NewWhisperCppTranscriber(cfg.WhisperCppPath, cfg.ModelPath, cfg.Threads)
+	case "openai":
+		apiKey := cfg.ApiKey
+		baseURL := cfg.BaseURL
+		// 未单独配置时复用 OpenAI 兼容 API 的密钥和地址
+		if apiKey == "" && config.OpenAICompatibleConfig != nil {
+			apiKey = config.OpenAICompatibleConfig.ApiKey
+			if baseURL == "" {
+				baseURL = config.OpenAICompatibleConfig.BaseURL
+			}
+		}
+		return NewOpenAITranscriber(apiKey, baseURL, cfg.Model, cfg.Timeout)
+	default:
+		return nil, fmt.Errorf("unsupported transcriber provider: %s", cfg.Provider)
+	}
+}
@@ -0,0 +1,78 @@
+package transcriber
+
+import "context"
+
+// Word 带时间戳的单词（秒）
+type Word struct {
+	Text  string  `json:"text"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+// Segment 识别出的语音片段（秒）
+type Segment struct {
+	Text  string  `json:"text"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	Words []Word  `json:"words,omitempty"` // 单词级时间戳（后端支持时提供）
+}
+
+// TranscriptionRequest 语音识别请求
+type TranscriptionRequest struct {
+	AudioPath string // 音频文件路径（16kHz 单声道 WAV）
+	Language  string // 语言代码，空或 auto 表示自动检测
+	Prompt    string // 提示词（可选，用于专有名词等）
+}
+
+// TranscriptionResult 语音识别结果
+type TranscriptionResult struct {
+	Language string    `json:"language"` // 识别出的语言代码
+	Duration float64   `json:"duration"` // 音频时长（秒）
+	Segments []Segment `json:"segments"` // 片段列表
+	Provider string    `json:"provider"` // 识别服务提供商
+	Model    string    `json:"model"`    // 使用的模型
+}
+
+// Transcriber 语音识别接口
+type Transcriber interface {
+	// Transcribe 识别单个音频文件
+	Transcribe(ctx context.Context, req *TranscriptionRequest) (*TranscriptionResult, error)
+
+	// GetName 获取提供商名称
+	GetName() string
+
+	// IsHealthy 检查服务是否可用
+	IsHealthy(ctx context.Context) bool
+}
+
+// isAutoLanguage 判断是否为自动检测语言
+func isAutoLanguage(lang string) bool {
+	return lang == "" || lang == "auto"
+}
+
+// languageNames 部分接口返回语言全称，这里映射为语言代码
+var languageNames = map[string]string{
+	"english":    "en",
+	"chinese":    "zh",
+	"japanese":   "ja",
+	"korean":     "ko",
+	"french":     "fr",
+	"german":     "de",
+	"spanish":    "es",
+	"russian":    "ru",
+	"portuguese": "pt",
+	"italian":    "it",
+	"arabic":     "ar",
+	"hindi":      "hi",
+	"vietnamese": "vi",
+	"thai":       "th",
+	"indonesian": "id",
+}
+
+// normalizeLanguage 规范化语言标识为语言代码
+func normalizeLanguage(lang string) string {
+	if code, ok := languageNames[lang]; ok {
+		return code
+	}
+	return lang
+}
@@ -0,0 +1,194 @@
+package transcriber
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// OpenAITranscriber 基于 OpenAI 兼容 /audio/transcriptions 接口的语音识别
+type OpenAITranscriber struct {
+	APIKey  string
+	BaseURL string
+	Model   string
+	Client  *http.Client
+}
+
+// openAITranscriptionResponse verbose_json 格式的响应
+type openAITranscriptionResponse struct {
+	Language string  `json:"language"`
+	Duration float64 `json:"duration"`
+	Text     string  `json:"text"`
+	Segments []struct {
+		Start float64 `json:"start"`
+		End   float64 `json:"end"`
+		Text  string  `json:"text"`
+	} `json:"segments"`
+	Words []struct {
+		Word  string  `json:"word"`
+		Start float64 `json:"start"`
+		End   float64 `json:"end"`
+	} `json:"words"`
+}
+
+// NewOpenAITranscriber 创建 OpenAI 兼容语音识别器
+func NewOpenAITranscriber(apiKey, baseURL, model string, timeout int) (*OpenAITranscriber, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("openai transcription api key is required")
+	}
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+	if model == "" {
+		model = "whisper-1"
+	}
+	if timeout <= 0 {
+		timeout = 300
+	}
+
+	return &OpenAITranscriber{
+		APIKey:  apiKey,
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		Model:   model,
+		Client:  &http.Client{Timeout: time.Duration(timeout) * time.Second},
+	}, nil
+}
+
+// GetName 获取提供商名称
+func (o *OpenAITranscriber) GetName() string {
+	return "openai"
+}
+
+// IsHealthy 检查服务是否可用
+func (o *OpenAITranscriber) IsHealthy(ctx context.Context) bool {
+	req, err := http.NewRequestWithContext(ctx, "GET", o.BaseURL+"/models", nil)
+	if err != nil {
+		return false
+	}
+	req.Header.Set("Authorization", "Bearer "+o.APIKey)
+
+	resp, err := o.Client.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
+
+// Transcribe 识别音频文件
+func (o *OpenAITranscriber) Transcribe(ctx context.Context, req *TranscriptionRequest) (*TranscriptionResult, error) {
+	file, err := os.Open(req.AudioPath)
+	if err != nil {
+		return nil, fmt.Errorf("打开音频文件失败: %v", err)
+	}
+	defer file.Close()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	part, err := writer.CreateFormFile("file", filepath.Base(req.AudioPath))
+	if err != nil {
+		return nil, fmt.Errorf("创建表单失败: %v", err)
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return nil, fmt.Errorf("写入音频数据失败: %v", err)
+	}
+
+	fields := map[string]string{
+		"model":           o.Model,
+		"response_format": "verbose_json",
+	}
+	if !isAutoLanguage(req.Language) {
+		fields["language"] = req.Language
+	}
+	if req.Prompt != "" {
+		fields["prompt"] = req.Prompt
+	}
+	for key, value := range fields {
+		if err := writer.WriteField(key, value); err != nil {
+			return nil, fmt.Errorf("写入表单字段失败: %v", err)
+		}
+	}
+	for _, granularity := range []string{"word", "segment"} {
+		if err := writer.WriteField("timestamp_granularities[]", granularity); err != nil {
+			return nil, fmt.Errorf("写入表单字段失败: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("关闭表单失败: %v", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", o.BaseURL+"/audio/transcriptions", body)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %v", err)
+	}
+	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
+	httpReq.Header.Set("Authorization", "Bearer "+o.APIKey)
+
+	resp, err := o.Client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("发送请求失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API返回错误 (状态码: %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var response openAITranscriptionResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("解析响应失败: %v", err)
+	}
+
+	result := &TranscriptionResult{
+		Language: normalizeLanguage(strings.ToLower(response.Language)),
+		Duration: response.Duration,
+		Provider: o.GetName(),
+		Model:    o.Model,
+	}
+
+	// 有单词时间戳时把单词分配到所属片段，否则直接使用片段
+	for _, seg := range response.Segments {
+		result.Segments = append(result.Segments, Segment{
+			Text:  strings.TrimSpace(seg.Text),
+			Start: seg.Start,
+			End:   seg.End,
+		})
+	}
+	if len(response.Words) > 0 {
+		words := make([]Word, 0, len(response.Words))
+		for _, w := range response.Words {
+			words = append(words, Word{Text: w.Word, Start: w.Start, End: w.End})
+		}
+		if len(result.Segments) == 0 {
+			result.Segments = []Segment{{Text: response.Text, Start: words[0].Start, End: words[len(words)-1].End}}
+		}
+		assignWords(result.Segments, words)
+	}
+
+	return result, nil
+}
+
+// assignWords 按开始时间把单词分配到片段中
+func assignWords(segments []Segment, words []Word) {
+	idx := 0
+	for _, word := range words {
+		for idx < len(segments)-1 && word.Start >= segments[idx].End {
+			idx++
+		}
+		segments[idx].Words = append(segments[idx].Words, word)
+	}
+}
@@ -0,0 +1,153 @@
+package transcriber
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// WhisperCppTranscriber 基于 whisper.cpp 命令行的本地语音识别（仅使用 CPU）
+type WhisperCppTranscriber struct {
+	BinaryPath string // whisper.cpp 可执行文件（whisper-cli）
+	ModelPath  string // ggml 模型文件路径
+	Threads    int    // 线程数
+}
+
+// whisperCppOutput whisper.cpp -oj 输出的 JSON 结构
+type whisperCppOutput struct {
+	Result struct {
+		Language string `json:"language"`
+	} `json:"result"`
+	Transcription []struct {
+		Offsets struct {
+			From int64 `json:"from"`
+			To   int64 `json:"to"`
+		} `json:"offsets"`
+		Text string `json:"text"`
+	} `json:"transcription"`
+}
+
+// NewWhisperCppTranscriber 创建 whisper.cpp 识别器
+func NewWhisperCppTranscriber(binaryPath, modelPath string, threads int) (*WhisperCppTranscriber, error) {
+	if binaryPath == "" {
+		binaryPath = "whisper-cli"
+	}
+	if modelPath == "" {
+		return nil, fmt.Errorf("whisper.cpp model path is required")
+	}
+	if threads <= 0 {
+		threads = 4
+	}
+
+	return &WhisperCppTranscriber{
+		BinaryPath: binaryPath,
+		ModelPath:  modelPath,
+		Threads:    threads,
+	}, nil
+}
+
+// GetName 获取提供商名称
+func (w *WhisperCppTranscriber) GetName() string {
+	return "whisper_cpp"
+}
+
+// IsHealthy 检查可执行文件和模型是否存在
+func (w *WhisperCppTranscriber) IsHealthy(ctx context.Context) bool {
+	if _, err := exec.LookPath(w.BinaryPath); err != nil {
+		return false
+	}
+	if _, err := os.Stat(w.ModelPath); err != nil {
+		return false
+	}
+	return true
+}
+
+// Transcribe 识别音频文件
+// 使用 -ml 1 -sow 让 whisper.cpp 按单词输出片段，得到单词级时间戳
+func (w *WhisperCppTranscriber) Transcribe(ctx context.Context, req *TranscriptionRequest) (*TranscriptionResult, error) {
+	language := req.Language
+	if isAutoLanguage(language) {
+		language = "auto"
+	}
+
+	outputPrefix := strings.TrimSuffix(req.AudioPath, filepath.Ext(req.AudioPath)) + ".whisper"
+	args := []string{
+		"-m", w.ModelPath,
+		"-f", req.AudioPath,
+		"-l", language,
+		"-t", strconv.Itoa(w.Threads),
+		"-ng",      // 不使用 GPU
+		"-ml", "1", // 每个片段最多一个单词
+		"-sow", // 按单词切分
+		"-oj",  // 输出 JSON
+		"-of", outputPrefix,
+		"-np", // 不打印进度
+	}
+	if req.Prompt != "" {
+		args = append(args, "--prompt", req.Prompt)
+	}
+
+	cmd := exec.CommandContext(ctx, w.BinaryPath, args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("whisper.cpp 执行失败: %v, 输出: %s", err, lastLines(string(output), 5))
+	}
+
+	jsonPath := outputPrefix + ".json"
+	defer os.Remove(jsonPath)
+
+	data, err := os.ReadFile(jsonPath)
+	if err != nil {
+		return nil, fmt.Errorf("读取 whisper.cpp 输出失败: %v", err)
+	}
+
+	var output whisperCppOutput
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, fmt.Errorf("解析 whisper.cpp 输出失败: %v", err)
+	}
+
+	// 每个片段是一个单词，统一放入一个片段中，由 BuildCues 按句子重新切分
+	segment := Segment{}
+	for _, item := range output.Transcription {
+		text := item.Text
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		word := Word{
+			Text:  text,
+			Start: float64(item.Offsets.From) / 1000,
+			End:   float64(item.Offsets.To) / 1000,
+		}
+		if len(segment.Words) == 0 {
+			segment.Start = word.Start
+		}
+		segment.End = word.End
+		segment.Words = append(segment.Words, word)
+	}
+
+	result := &TranscriptionResult{
+		Language: normalizeLanguage(output.Result.Language),
+		Duration: segment.End,
+		Provider: w.GetName(),
+		Model:    filepath.Base(w.ModelPath),
+	}
+	if len(segment.Words) > 0 {
+		segment.Text = joinWords(segment.Words)
+		result.Segments = []Segment{segment}
+	}
+
+	return result, nil
+}
+
+// lastLines 取输出的最后几行（用于错误信息）
+func lastLines(output string, n int) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
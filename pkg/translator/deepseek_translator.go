@@ -2,31 +2,18 @@ package translator
 
 import (
 	"github.com/difyz9/ytb2bili/internal/core/types"
-	"bytes"
+	"github.com/difyz9/ytb2bili/pkg/llm"
+	"github.com/difyz9/ytb2bili/pkg/prompt"
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
 	"strings"
 	"time"
 )
 
 // DeepSeekTranslator DeepSeek翻译器实现
 type DeepSeekTranslator struct {
-	apiKey    string
-	model     string
-	endpoint  string
-	timeout   int
-	maxTokens int
-	client    *http.Client
-}
-
-// DeepSeekRequest DeepSeek API请求结构
-type DeepSeekRequest struct {
-	Model     string            `json:"model"`
-	Messages  []DeepSeekMessage `json:"messages"`
-	Stream    bool              `json:"stream"`
-	MaxTokens int               `json:"max_tokens,omitempty"`
+	model    string
+	provider llm.Provider
 }
 
 // DeepSeekMessage DeepSeek消息结构
@@ -35,32 +22,9 @@ type DeepSeekMessage struct {
 	Content string `json:"content"`
 }
 
-// DeepSeekResponse DeepSeek API响应结构
-type DeepSeekResponse struct {
-	ID      string           `json:"id"`
-	Object  string           `json:"object"`
-	Created int64            `json:"created"`
-	Model   string           `json:"model"`
-	Choices []DeepSeekChoice `json:"choices"`
-	Usage   DeepSeekUsage    `json:"usage"`
-}
-
-// DeepSeekChoice 选择结构
-type DeepSeekChoice struct {
-	Index        int             `json:"index"`
-	Message      DeepSeekMessage `json:"message"`
-	FinishReason string          `json:"finish_reason"`
-}
-
-// DeepSeekUsage 使用统计
-type DeepSeekUsage struct {
-	PromptTokens     int `json:"prompt_tokens"`
-	CompletionTokens int `json:"completion_tokens"`
-	TotalTokens      int `json:"total_tokens"`
-}
-
 // NewDeepSeekTranslator 创建DeepSeek翻译器实例
-func NewDeepSeekTranslator(config *types.DeepSeekTransConfig) (*DeepSeekTranslator, error) {
+// pool 为空时使用 config 中的密钥单独创建密钥池
+func NewDeepSeekTranslator(config *types.DeepSeekTransConfig, pool *llm.KeyPool) (*DeepSeekTranslator, error) {
 	if config == nil {
 		return nil, fmt.Errorf("deepseek translator config is nil")
 	}
@@ -69,40 +33,39 @@ func NewDeepSeekTranslator(config *types.DeepSeekTransConfig) (*DeepSeekTranslat
 		return nil, fmt.Errorf("deepseek translator is not enabled")
 	}
 
-	if config.ApiKey == "" {
+	keys := config.Keys()
+	if len(keys) == 0 {
 		return nil, fmt.Errorf("deepseek API key is required")
 	}
 
 	// 设置默认值
-	endpoint := config.Endpoint
-	if endpoint == "" {
-		endpoint = "https://api.deepseek.com"
-	}
-
-	model := config.Model
-	if model == "" {
-		model = "deepseek-chat"
+	maxTokens := config.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = 4000 // 默认4000 tokens
 	}
 
-	timeout := config.Timeout
-	if timeout == 0 {
-		timeout = 60 // 默认60秒超时
+	llmConfig := llm.Config{
+		BaseURL:         config.Endpoint,
+		Model:           config.Model,
+		Timeout:         llm.Seconds(config.Timeout), // 未设置时默认60秒超时
+		MaxRetries:      llm.DefaultMaxRetries,
+		MaxTokens:       maxTokens,
+		FailOnRateLimit: len(keys) > 1,
 	}
+	model := llm.NewDeepSeek(llmConfig).Model()
 
-	maxTokens := config.MaxTokens
-	if maxTokens == 0 {
-		maxTokens = 4000 // 默认4000 tokens
+	if pool == nil {
+		pool = llm.NewKeyPool("deepseek", keys, llm.KeyPoolPolicy{})
 	}
+	provider := llm.NewPooled("deepseek", model, pool, func(ctx context.Context, apiKey string) (llm.Provider, error) {
+		keyConfig := llmConfig
+		keyConfig.APIKey = apiKey
+		return llm.NewDeepSeek(keyConfig), nil
+	})
 
 	return &DeepSeekTranslator{
-		apiKey:    config.ApiKey,
-		model:     model,
-		endpoint:  endpoint,
-		timeout:   timeout,
-		maxTokens: maxTokens,
-		client: &http.Client{
-			Timeout: time.Duration(timeout) * time.Second,
-		},
+		model:    model,
+		provider: provider,
 	}, nil
 }
 
@@ -115,7 +78,7 @@ func (d *DeepSeekTranslator) Translate(ctx context.Context, req *TranslationRequ
 	startTime := time.Now()
 
 	// 构建翻译提示词
-	systemPrompt := d.buildSystemPrompt(req.SourceLang, req.TargetLang, req.TextType, req.Domain)
+	systemPrompt := req.Prompts.Render(prompt.TranslationSingle, d.promptVars(req.SourceLang, req.TargetLang, req.TextType, req.Domain))
 	userPrompt := req.Text
 
 	// 调用DeepSeek API
@@ -143,7 +106,7 @@ func (d *DeepSeekTranslator) Translate(ctx context.Context, req *TranslationRequ
 
 	return &TranslationResult{
 		OriginalText:   req.Text,
-		TranslatedText: response.Choices[0].Message.Content,
+		TranslatedText: response.Content,
 		SourceLang:     sourceLang,
 		TargetLang:     req.TargetLang,
 		Provider:       "deepseek",
@@ -174,7 +137,7 @@ func (d *DeepSeekTranslator) BatchTranslate(ctx context.Context, req *BatchTrans
 		}
 
 		batchTexts := req.Texts[i:end]
-		batchResults, err := d.translateBatch(ctx, batchTexts, req.SourceLang, req.TargetLang, req.TextType, req.Domain)
+		batchResults, err := d.translateBatch(ctx, req.Prompts, batchTexts, req.SourceLang, req.TargetLang, req.TextType, req.Domain)
 		if err != nil {
 			return nil, fmt.Errorf("batch translation failed: %w", err)
 		}
@@ -202,9 +165,9 @@ func (d *DeepSeekTranslator) BatchTranslate(ctx context.Context, req *BatchTrans
 }
 
 // translateBatch 翻译一批文本
-func (d *DeepSeekTranslator) translateBatch(ctx context.Context, texts []string, sourceLang, targetLang, textType, domain string) ([]*TranslationResult, error) {
+func (d *DeepSeekTranslator) translateBatch(ctx context.Context, prompts *prompt.Set, texts []string, sourceLang, targetLang, textType, domain string) ([]*TranslationResult, error) {
 	// 构建批量翻译提示词
-	systemPrompt := d.buildBatchSystemPrompt(sourceLang, targetLang, textType, domain)
+	systemPrompt := prompts.Render(prompt.TranslationBatch, d.promptVars(sourceLang, targetLang, textType, domain))
 
 	// 将文本组合成编号格式
 	var userPrompt strings.Builder
@@ -220,12 +183,12 @@ func (d *DeepSeekTranslator) translateBatch(ctx context.Context, texts []string,
 	}
 
 	// 解析批量翻译结果
-	translatedTexts := d.parseBatchResponse(response.Choices[0].Message.Content, len(texts))
+	translatedTexts := d.parseBatchResponse(response.Content, len(texts))
 
 	// 确保翻译结果数量匹配
 	if len(translatedTexts) != len(texts) {
 		// 如果批量翻译失败，降级为逐个翻译
-		return d.fallbackToIndividualTranslation(ctx, texts, sourceLang, targetLang, textType, domain)
+		return d.fallbackToIndividualTranslation(ctx, prompts, texts, sourceLang, targetLang, textType, domain)
 	}
 
 	// 构建结果
@@ -249,7 +212,7 @@ func (d *DeepSeekTranslator) translateBatch(ctx context.Context, texts []string,
 }
 
 // fallbackToIndividualTranslation 降级为逐个翻译
-func (d *DeepSeekTranslator) fallbackToIndividualTranslation(ctx context.Context, texts []string, sourceLang, targetLang, textType, domain string) ([]*TranslationResult, error) {
+func (d *DeepSeekTranslator) fallbackToIndividualTranslation(ctx context.Context, prompts *prompt.Set, texts []string, sourceLang, targetLang, textType, domain string) ([]*TranslationResult, error) {
 	results := make([]*TranslationResult, len(texts))
 
 	for i, text := range texts {
@@ -259,6 +222,7 @@ func (d *DeepSeekTranslator) fallbackToIndividualTranslation(ctx context.Context
 			TargetLang: targetLang,
 			TextType:   textType,
 			Domain:     domain,
+			Prompts:    prompts,
 		}
 
 		result, err := d.Translate(ctx, req)
@@ -310,7 +274,7 @@ func (d *DeepSeekTranslator) DetectLanguage(ctx context.Context, text string) (s
 	}
 
 	// 解析语言代码
-	langCode := strings.TrimSpace(strings.ToLower(response.Choices[0].Message.Content))
+	langCode := strings.TrimSpace(strings.ToLower(response.Content))
 
 	// 简单验证语言代码格式
 	if len(langCode) < 2 || len(langCode) > 5 {
@@ -353,123 +317,32 @@ func (d *DeepSeekTranslator) IsHealthy(ctx context.Context) error {
 }
 
 // callDeepSeekAPI 调用DeepSeek API
-func (d *DeepSeekTranslator) callDeepSeekAPI(ctx context.Context, systemPrompt, userPrompt string) (*DeepSeekResponse, error) {
-	// 构建请求
-	reqBody := DeepSeekRequest{
-		Model: d.model,
-		Messages: []DeepSeekMessage{
-			{
-				Role:    "system",
-				Content: systemPrompt,
-			},
-			{
-				Role:    "user",
-				Content: userPrompt,
-			},
-		},
-		Stream:    false,
-		MaxTokens: d.maxTokens,
-	}
-
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	// 创建HTTP请求
-	url := fmt.Sprintf("%s/chat/completions", strings.TrimSuffix(d.endpoint, "/"))
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+func (d *DeepSeekTranslator) callDeepSeekAPI(ctx context.Context, systemPrompt, userPrompt string) (*llm.Response, error) {
+	response, err := llm.Complete(ctx, d.provider, systemPrompt, userPrompt)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+d.apiKey)
-
-	// 发送请求
-	resp, err := d.client.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("deepseek API returned status %d", resp.StatusCode)
-	}
-
-	// 解析响应
-	var response DeepSeekResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		return nil, err
 	}
-
-	if len(response.Choices) == 0 {
+	if response.Content == "" {
 		return nil, fmt.Errorf("no translation result in response")
 	}
-
-	return &response, nil
+	return response, nil
 }
 
-// buildSystemPrompt 构建系统提示词
-func (d *DeepSeekTranslator) buildSystemPrompt(sourceLang, targetLang, textType, domain string) string {
-	var prompt strings.Builder
-
-	prompt.WriteString("你是一位专业的翻译专家。请将给定的文本进行准确、自然的翻译。\n\n")
-	prompt.WriteString("翻译要求：\n")
-	prompt.WriteString("1. 保持原文的意思和语调\n")
-	prompt.WriteString("2. 使用自然流畅的目标语言表达\n")
-	prompt.WriteString("3. 保留原文的格式和结构\n")
-	prompt.WriteString("4. 对于专业术语，使用准确的对应词汇\n")
-
-	if sourceLang != "" && sourceLang != "auto" {
-		prompt.WriteString(fmt.Sprintf("5. 源语言：%s\n", d.getLanguageName(sourceLang)))
-	}
-
-	if targetLang != "" {
-		prompt.WriteString(fmt.Sprintf("6. 目标语言：%s\n", d.getLanguageName(targetLang)))
-	}
-
-	if textType != "" {
-		prompt.WriteString(fmt.Sprintf("7. 文本类型：%s\n", textType))
+// promptVars 翻译提示词模板的变量（自动检测源语言时不填写源语言）
+func (d *DeepSeekTranslator) promptVars(sourceLang, targetLang, textType, domain string) prompt.Vars {
+	vars := prompt.Vars{
+		SourceLangCode: sourceLang,
+		TargetLangCode: targetLang,
+		TextType:       textType,
+		Domain:         domain,
 	}
-
-	if domain != "" {
-		prompt.WriteString(fmt.Sprintf("8. 领域：%s\n", domain))
-	}
-
-	prompt.WriteString("\n请直接返回翻译结果，不要包含任何解释或其他内容。")
-
-	return prompt.String()
-}
-
-// buildBatchSystemPrompt 构建批量翻译系统提示词
-func (d *DeepSeekTranslator) buildBatchSystemPrompt(sourceLang, targetLang, textType, domain string) string {
-	var prompt strings.Builder
-
-	prompt.WriteString("你是一位专业的翻译专家。请将以下编号的文本逐条翻译，保持相同的编号格式。\n\n")
-	prompt.WriteString("翻译要求：\n")
-	prompt.WriteString("1. 保持原文的意思和语调\n")
-	prompt.WriteString("2. 使用自然流畅的目标语言表达\n")
-	prompt.WriteString("3. 保留编号格式：1. 翻译内容\n")
-	prompt.WriteString("4. 每个编号对应一行翻译结果\n")
-
 	if sourceLang != "" && sourceLang != "auto" {
-		prompt.WriteString(fmt.Sprintf("5. 源语言：%s\n", d.getLanguageName(sourceLang)))
+		vars.SourceLang = d.getLanguageName(sourceLang)
 	}
-
 	if targetLang != "" {
-		prompt.WriteString(fmt.Sprintf("6. 目标语言：%s\n", d.getLanguageName(targetLang)))
-	}
-
-	if textType != "" {
-		prompt.WriteString(fmt.Sprintf("7. 文本类型：%s\n", textType))
+		vars.TargetLang = d.getLanguageName(targetLang)
 	}
-
-	if domain != "" {
-		prompt.WriteString(fmt.Sprintf("8. 领域：%s\n", domain))
-	}
-
-	return prompt.String()
+	return vars
 }
 
 // getLanguageName 获取语言名称
@@ -524,7 +397,7 @@ func (d *DeepSeekTranslator) parseBatchResponse(response string, expectedCount i
 }
 
 // distributeUsage 分配使用统计
-func (d *DeepSeekTranslator) distributeUsage(usage DeepSeekUsage, count int) *Usage {
+func (d *DeepSeekTranslator) distributeUsage(usage llm.Usage, count int) *Usage {
 	if count <= 0 {
 		count = 1
 	}
@@ -2,6 +2,7 @@ package translator
 
 import (
 	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/llm"
 	"fmt"
 )
 
@@ -25,22 +26,59 @@ func (f *Factory) CreateTranslator(provider string, config map[string]interface{
 		return f.createBaiduTranslator(config)
 	case "deepseek":
 		return f.createDeepSeekTranslator(config)
+	case "tencent":
+		return f.createTencentTranslator(config)
+	case "microsoft":
+		return f.createMicrosoftTranslator(config)
+	case "google":
+		return f.createGoogleTranslator(config)
+	case "ollama":
+		return f.createOllamaTranslator(config)
 	default:
 		return nil, fmt.Errorf("unsupported translator provider: %s", provider)
 	}
 }
 
-// GetSupportedProviders 获取支持的提供商列表
+// GetSupportedProviders 获取支持的提供商列表（均可由 CreateTranslator 创建）
 func (f *Factory) GetSupportedProviders() []string {
 	return []string{
 		"tencent",
 		"microsoft",
-		"alibaba",
-		"ollama",
 		"google",
 		"baidu",
 		"deepseek",
+		"ollama",
+	}
+}
+
+// GetEnabledProviders 获取配置中已启用的提供商，顺序同 GetSupportedProviders
+func (f *Factory) GetEnabledProviders() []string {
+	var providers []string
+	for _, provider := range f.GetSupportedProviders() {
+		if f.isEnabled(provider) {
+			providers = append(providers, provider)
+		}
 	}
+	return providers
+}
+
+// isEnabled 提供商是否已在配置中启用
+func (f *Factory) isEnabled(provider string) bool {
+	switch provider {
+	case "baidu":
+		return f.config.BaiduTransConfig != nil && f.config.BaiduTransConfig.Enabled
+	case "deepseek":
+		return f.config.DeepSeekTransConfig != nil && f.config.DeepSeekTransConfig.Enabled
+	case "tencent":
+		return f.config.TencentTransConfig != nil && f.config.TencentTransConfig.Enabled
+	case "microsoft":
+		return f.config.MicrosoftTransConfig != nil && f.config.MicrosoftTransConfig.Enabled
+	case "google":
+		return f.config.GoogleTransConfig != nil && f.config.GoogleTransConfig.Enabled
+	case "ollama":
+		return f.config.OllamaTransConfig != nil && f.config.OllamaTransConfig.Enabled
+	}
+	return false
 }
 
 // createBaiduTranslator 创建百度翻译器
@@ -83,8 +121,11 @@ func (f *Factory) createDeepSeekTranslator(config map[string]interface{}) (Trans
 	configCopy := *deepseekConfig
 
 	// 覆盖配置
+	keyOverridden := false
 	if apiKey, ok := config["api_key"].(string); ok && apiKey != "" {
 		configCopy.ApiKey = apiKey
+		configCopy.ApiKeys = nil
+		keyOverridden = true
 	}
 	if model, ok := config["model"].(string); ok && model != "" {
 		configCopy.Model = model
@@ -99,5 +140,106 @@ func (f *Factory) createDeepSeekTranslator(config map[string]interface{}) (Trans
 		configCopy.MaxTokens = maxTokens
 	}
 
-	return NewDeepSeekTranslator(&configCopy)
+	// 使用应用配置的密钥时与AI服务管理器共用 deepseek 的密钥池，轮换位置和限额在所有任务间共享
+	var pool *llm.KeyPool
+	if !keyOverridden {
+		pool = llm.SharedKeyPool("deepseek", configCopy.Keys(), llm.KeyPoolPolicy(f.config.AIKeyPoolConfig.Policy("deepseek")))
+	}
+	return NewDeepSeekTranslator(&configCopy, pool)
+}
+
+// createTencentTranslator 创建腾讯云机器翻译器
+func (f *Factory) createTencentTranslator(config map[string]interface{}) (Translator, error) {
+	tencentConfig := f.config.TencentTransConfig
+	if tencentConfig == nil || !tencentConfig.Enabled {
+		return nil, fmt.Errorf("tencent translator not enabled or config not found")
+	}
+
+	// 创建配置副本
+	configCopy := *tencentConfig
+
+	// 覆盖配置
+	if secretId, ok := config["secret_id"].(string); ok && secretId != "" {
+		configCopy.SecretId = secretId
+	}
+	if secretKey, ok := config["secret_key"].(string); ok && secretKey != "" {
+		configCopy.SecretKey = secretKey
+	}
+	if region, ok := config["region"].(string); ok && region != "" {
+		configCopy.Region = region
+	}
+	if endpoint, ok := config["endpoint"].(string); ok && endpoint != "" {
+		configCopy.Endpoint = endpoint
+	}
+
+	return NewTencentTranslator(&configCopy)
+}
+
+// createMicrosoftTranslator 创建微软翻译器
+func (f *Factory) createMicrosoftTranslator(config map[string]interface{}) (Translator, error) {
+	microsoftConfig := f.config.MicrosoftTransConfig
+	if microsoftConfig == nil || !microsoftConfig.Enabled {
+		return nil, fmt.Errorf("microsoft translator not enabled or config not found")
+	}
+
+	// 创建配置副本
+	configCopy := *microsoftConfig
+
+	// 覆盖配置
+	if apiKey, ok := config["api_key"].(string); ok && apiKey != "" {
+		configCopy.ApiKey = apiKey
+	}
+	if region, ok := config["region"].(string); ok && region != "" {
+		configCopy.Region = region
+	}
+	if endpoint, ok := config["endpoint"].(string); ok && endpoint != "" {
+		configCopy.Endpoint = endpoint
+	}
+
+	return NewMicrosoftTranslator(&configCopy)
+}
+
+// createGoogleTranslator 创建Google翻译器
+func (f *Factory) createGoogleTranslator(config map[string]interface{}) (Translator, error) {
+	googleConfig := f.config.GoogleTransConfig
+	if googleConfig == nil || !googleConfig.Enabled {
+		return nil, fmt.Errorf("google translator not enabled or config not found")
+	}
+
+	// 创建配置副本
+	configCopy := *googleConfig
+
+	// 覆盖配置
+	if apiKey, ok := config["api_key"].(string); ok && apiKey != "" {
+		configCopy.ApiKey = apiKey
+	}
+	if endpoint, ok := config["endpoint"].(string); ok && endpoint != "" {
+		configCopy.Endpoint = endpoint
+	}
+
+	return NewGoogleTranslator(&configCopy)
+}
+
+// createOllamaTranslator 创建Ollama本地翻译器
+func (f *Factory) createOllamaTranslator(config map[string]interface{}) (Translator, error) {
+	ollamaConfig := f.config.OllamaTransConfig
+	if ollamaConfig == nil || !ollamaConfig.Enabled {
+		return nil, fmt.Errorf("ollama translator not enabled or config not found")
+	}
+
+	// 创建配置副本
+	configCopy := *ollamaConfig
+
+	// 覆盖配置
+	if model, ok := config["model"].(string); ok && model != "" {
+		configCopy.Model = model
+	}
+	if endpoint, ok := config["endpoint"].(string); ok && endpoint != "" {
+		configCopy.Endpoint = endpoint
+	}
+	if timeout, ok := config["timeout"].(int); ok && timeout > 0 {
+		configCopy.Timeout = timeout
+	}
+
+	return NewOllamaTranslator(&configCopy)
 }
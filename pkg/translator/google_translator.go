@@ -0,0 +1,295 @@
+package translator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+)
+
+// googleMaxSegments Google v2 单次请求最多 128 段文本
+const googleMaxSegments = 128
+
+// GoogleTranslator Google翻译器（Cloud Translation v2）
+type GoogleTranslator struct {
+	apiKey   string
+	endpoint string
+	client   *http.Client
+}
+
+// GoogleTranslateRequest Google翻译请求
+type GoogleTranslateRequest struct {
+	Q      []string `json:"q"`
+	Source string   `json:"source,omitempty"`
+	Target string   `json:"target,omitempty"`
+	Format string   `json:"format,omitempty"`
+}
+
+// GoogleTranslateResponse Google翻译响应
+type GoogleTranslateResponse struct {
+	Data struct {
+		Translations []struct {
+			TranslatedText         string `json:"translatedText"`
+			DetectedSourceLanguage string `json:"detectedSourceLanguage,omitempty"`
+		} `json:"translations"`
+		Detections [][]struct {
+			Language   string  `json:"language"`
+			Confidence float64 `json:"confidence"`
+		} `json:"detections"`
+		Languages []struct {
+			Language string `json:"language"`
+			Name     string `json:"name"`
+		} `json:"languages"`
+	} `json:"data"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// NewGoogleTranslator 创建Google翻译器实例
+func NewGoogleTranslator(config *types.GoogleTransConfig) (*GoogleTranslator, error) {
+	if config == nil {
+		return nil, fmt.Errorf("google translator config is nil")
+	}
+	if config.ApiKey == "" {
+		return nil, fmt.Errorf("google translator api_key is required")
+	}
+
+	endpoint := config.Endpoint
+	if endpoint == "" {
+		endpoint = "https://translation.googleapis.com"
+	}
+
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = 30
+	}
+
+	return &GoogleTranslator{
+		apiKey:   config.ApiKey,
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		client: &http.Client{
+			Timeout: time.Duration(timeout) * time.Second,
+		},
+	}, nil
+}
+
+// Translate 翻译文本
+func (g *GoogleTranslator) Translate(ctx context.Context, req *TranslationRequest) (*TranslationResult, error) {
+	if req.Text == "" {
+		return nil, fmt.Errorf("text cannot be empty")
+	}
+
+	batch, err := g.BatchTranslate(ctx, &BatchTranslationRequest{
+		Texts:      []string{req.Text},
+		SourceLang: req.SourceLang,
+		TargetLang: req.TargetLang,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := batch.Results[0]
+	result.Usage = batch.Usage
+	return result, nil
+}
+
+// BatchTranslate 批量翻译，超过单次上限时分多次请求
+func (g *GoogleTranslator) BatchTranslate(ctx context.Context, req *BatchTranslationRequest) (*BatchTranslationResult, error) {
+	if len(req.Texts) == 0 {
+		return nil, fmt.Errorf("texts cannot be empty")
+	}
+
+	startTime := time.Now()
+	sourceLang := g.convertLanguageCode(req.SourceLang)
+	targetLang := g.convertLanguageCode(req.TargetLang)
+
+	results := make([]*TranslationResult, 0, len(req.Texts))
+	totalChars := 0
+	for start := 0; start < len(req.Texts); start += googleMaxSegments {
+		end := start + googleMaxSegments
+		if end > len(req.Texts) {
+			end = len(req.Texts)
+		}
+		chunk := req.Texts[start:end]
+
+		var resp GoogleTranslateResponse
+		if err := g.call(ctx, "/language/translate/v2", GoogleTranslateRequest{
+			Q:      chunk,
+			Source: sourceLang,
+			Target: targetLang,
+			Format: "text",
+		}, &resp); err != nil {
+			return nil, err
+		}
+		if len(resp.Data.Translations) != len(chunk) {
+			return nil, fmt.Errorf("google translate returned %d results for %d texts", len(resp.Data.Translations), len(chunk))
+		}
+
+		for i, item := range resp.Data.Translations {
+			detected := req.SourceLang
+			if item.DetectedSourceLanguage != "" {
+				detected = g.revertLanguageCode(item.DetectedSourceLanguage)
+			}
+			results = append(results, &TranslationResult{
+				OriginalText:   chunk[i],
+				TranslatedText: item.TranslatedText,
+				SourceLang:     detected,
+				TargetLang:     req.TargetLang,
+				Provider:       "google",
+				Usage: &Usage{
+					Characters: len(chunk[i]),
+				},
+			})
+			totalChars += len(chunk[i])
+		}
+	}
+
+	duration := time.Since(startTime)
+	logger.Infof("Google batch translation completed: %d texts, %d chars (%dms)",
+		len(req.Texts), totalChars, duration.Milliseconds())
+
+	return &BatchTranslationResult{
+		Results:  results,
+		Provider: "google",
+		Usage: &Usage{
+			Characters: totalChars,
+			Duration:   duration.Milliseconds(),
+		},
+	}, nil
+}
+
+// GetSupportedLanguages 获取支持的语言列表
+func (g *GoogleTranslator) GetSupportedLanguages(ctx context.Context) ([]LanguageInfo, error) {
+	var resp GoogleTranslateResponse
+	if err := g.call(ctx, "/language/translate/v2/languages", map[string]string{"target": "zh-CN"}, &resp); err != nil {
+		return nil, err
+	}
+
+	languages := make([]LanguageInfo, 0, len(resp.Data.Languages))
+	for _, lang := range resp.Data.Languages {
+		languages = append(languages, LanguageInfo{
+			Code:        g.revertLanguageCode(lang.Language),
+			Name:        lang.Name,
+			NativeName:  lang.Name,
+			Direction:   "ltr",
+			IsSupported: true,
+		})
+	}
+	return languages, nil
+}
+
+// DetectLanguage 检测语言
+func (g *GoogleTranslator) DetectLanguage(ctx context.Context, text string) (string, float64, error) {
+	if text == "" {
+		return "", 0, fmt.Errorf("text cannot be empty")
+	}
+
+	var resp GoogleTranslateResponse
+	if err := g.call(ctx, "/language/translate/v2/detect", GoogleTranslateRequest{Q: []string{text}}, &resp); err != nil {
+		return "", 0, fmt.Errorf("failed to detect language: %w", err)
+	}
+	if len(resp.Data.Detections) == 0 || len(resp.Data.Detections[0]) == 0 {
+		return "", 0, fmt.Errorf("no detection result returned")
+	}
+
+	detection := resp.Data.Detections[0][0]
+	return g.revertLanguageCode(detection.Language), detection.Confidence, nil
+}
+
+// IsHealthy 健康检查
+func (g *GoogleTranslator) IsHealthy(ctx context.Context) error {
+	if _, _, err := g.DetectLanguage(ctx, "hello"); err != nil {
+		return fmt.Errorf("google translator health check failed: %w", err)
+	}
+	return nil
+}
+
+// GetInfo 获取翻译器信息
+func (g *GoogleTranslator) GetInfo() *TranslatorInfo {
+	return &TranslatorInfo{
+		Name:               "Google Cloud Translation",
+		Provider:           "google",
+		Version:            "v2",
+		MaxTextLength:      30000, // 官方建议单次请求不超过 30K 码点
+		SupportedLanguages: []string{"zh", "zh-cn", "zh-tw", "en", "ja", "ko", "es", "fr", "de", "ru", "it", "pt", "ar", "hi", "th", "vi"},
+		Features:           []string{"translate", "batch_translate", "language_detect"},
+		IsOnline:           true,
+	}
+}
+
+// call 调用 Google API，API 密钥通过 key 参数传递
+func (g *GoogleTranslator) call(ctx context.Context, path string, payload interface{}, out *GoogleTranslateResponse) error {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	apiURL := g.endpoint + path + "?key=" + url.QueryEscape(g.apiKey)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewReader(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := g.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("google API returned status %d: %s", resp.StatusCode, truncate(string(body), 200))
+	}
+	if out.Error != nil {
+		return fmt.Errorf("google API error: %d - %s", out.Error.Code, out.Error.Message)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("google API returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// convertLanguageCode 转换语言代码到 Google 格式
+func (g *GoogleTranslator) convertLanguageCode(code string) string {
+	switch strings.ToLower(code) {
+	case "", "auto":
+		return ""
+	case "zh", "zh-cn", "zh-hans":
+		return "zh-CN"
+	case "zh-tw", "zh-hk", "zh-hant":
+		return "zh-TW"
+	}
+	return code
+}
+
+// revertLanguageCode 将 Google 语言代码转换回标准格式
+func (g *GoogleTranslator) revertLanguageCode(code string) string {
+	switch code {
+	case "zh-CN":
+		return "zh"
+	case "zh-TW":
+		return "zh-tw"
+	}
+	return code
+}
+
+// truncate 截断过长的文本（用于错误信息）
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -1,6 +1,10 @@
 package translator
 
-import "context"
+import (
+	"context"
+
+	"github.com/difyz9/ytb2bili/pkg/prompt"
+)
 
 // TranslationRequest 翻译请求
 type TranslationRequest struct {
@@ -11,17 +15,22 @@ type TranslationRequest struct {
 	Domain     string `json:"domain,omitempty"`              // 领域：general, medical, legal等
 	ProjectId  string `json:"projectId,omitempty"`           // 项目ID（某些服务需要）
 	Model      string `json:"model,omitempty"`               // 使用的模型（如Ollama）
+	Prompts    *prompt.Set `json:"-"`                      // 提示词模板（为空时使用内置默认模板）
 }
 
 // BatchTranslationRequest 批量翻译请求
 type BatchTranslationRequest struct {
-	Texts      []string `json:"texts" binding:"required"`      // 要翻译的文本列表
-	SourceLang string   `json:"sourceLang,omitempty"`          // 源语言
-	TargetLang string   `json:"targetLang" binding:"required"` // 目标语言
-	TextType   string   `json:"textType,omitempty"`            // 文本类型
-	Domain     string   `json:"domain,omitempty"`              // 领域
-	ProjectId  string   `json:"projectId,omitempty"`           // 项目ID
-	Model      string   `json:"model,omitempty"`               // 使用的模型
+	Texts           []string `json:"texts" binding:"required"`      // 要翻译的文本列表
+	SourceLang      string   `json:"sourceLang,omitempty"`          // 源语言
+	TargetLang      string   `json:"targetLang" binding:"required"` // 目标语言
+	TextType        string   `json:"textType,omitempty"`            // 文本类型
+	Domain          string   `json:"domain,omitempty"`              // 领域
+	ProjectId       string   `json:"projectId,omitempty"`           // 项目ID
+	Model           string   `json:"model,omitempty"`               // 使用的模型
+	PrevContext     []string `json:"prevContext,omitempty"`         // 前文（只用于理解语境，不翻译；大模型翻译器使用）
+	NextContext     []string `json:"nextContext,omitempty"`         // 后文（同上）
+	Instructions    string   `json:"instructions,omitempty"`        // 附加翻译要求（如术语表）；大模型翻译器使用
+	Prompts         *prompt.Set `json:"-"`                         // 提示词模板（为空时使用内置默认模板）；大模型翻译器使用
 }
 
 // TranslationResult 翻译结果
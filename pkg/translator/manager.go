@@ -4,6 +4,7 @@ import (
 	"github.com/difyz9/ytb2bili/internal/core/types"
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 )
@@ -22,8 +23,13 @@ type TranslatorManager struct {
 func NewTranslatorManager(config *types.AppConfig) *TranslatorManager {
 	factory := NewTranslatorFactory(config)
 
-	defaultProvider := "tencent"
-	fallbackProviders := []string{"microsoft", "google"}
+	// 未配置时使用已启用的提供商：第一个为默认，其余依次作为备选
+	var defaultProvider string
+	var fallbackProviders []string
+	if enabled := factory.GetEnabledProviders(); len(enabled) > 0 {
+		defaultProvider = enabled[0]
+		fallbackProviders = enabled[1:]
+	}
 
 	// 从配置中读取默认提供商和备选提供商
 	if config.TranslatorConfig != nil {
@@ -44,8 +50,19 @@ func NewTranslatorManager(config *types.AppConfig) *TranslatorManager {
 	}
 }
 
+// RegisterTranslator 注册不由工厂创建的翻译器（如基于AI服务管理器的 ai 翻译器），同名时覆盖
+func (tm *TranslatorManager) RegisterTranslator(provider string, translator Translator) {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+	tm.translators[provider] = translator
+}
+
 // GetTranslator 获取翻译器实例
 func (tm *TranslatorManager) GetTranslator(provider string) (Translator, error) {
+	if provider == "" {
+		return nil, fmt.Errorf("no translator provider enabled")
+	}
+
 	tm.mutex.Lock()
 	defer tm.mutex.Unlock()
 
@@ -128,6 +145,31 @@ func (tm *TranslatorManager) BatchTranslateWithProvider(ctx context.Context, pro
 	return translator.BatchTranslate(ctx, req)
 }
 
+// BatchTranslateWithFallback 按顺序尝试提供商链进行批量翻译，无法创建或翻译失败时换下一个
+func (tm *TranslatorManager) BatchTranslateWithFallback(ctx context.Context, providers []string, req *BatchTranslationRequest) (*BatchTranslationResult, error) {
+	var errs []string
+	for _, provider := range providers {
+		result, err := tm.BatchTranslateWithProvider(ctx, provider, req)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", provider, err))
+			continue
+		}
+		if len(result.Results) != len(req.Texts) {
+			errs = append(errs, fmt.Sprintf("%s: returned %d results, expected %d", provider, len(result.Results), len(req.Texts)))
+			continue
+		}
+		if result.Provider == "" {
+			result.Provider = provider
+		}
+		return result, nil
+	}
+
+	if len(errs) == 0 {
+		return nil, fmt.Errorf("no translator provider enabled")
+	}
+	return nil, fmt.Errorf("all translators failed: %s", strings.Join(errs, "; "))
+}
+
 // GetSupportedLanguages 获取支持的语言列表
 func (tm *TranslatorManager) GetSupportedLanguages(ctx context.Context, provider string) ([]LanguageInfo, error) {
 	translator, err := tm.GetTranslator(provider)
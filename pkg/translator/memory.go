@@ -0,0 +1,11 @@
+package translator
+
+// MemoryKey 翻译记忆的查找条件（原文之外的部分）
+// 同一句原文在不同语言方向、不同翻译服务/模型、不同术语表版本下的译文分别记录
+type MemoryKey struct {
+	SourceLang      string // 源语言
+	TargetLang      string // 目标语言
+	Provider        string // 翻译服务提供商
+	Model           string // 使用的模型（机器翻译为空）
+	GlossaryVersion string // 术语表版本（未使用术语表时为空）
+}
@@ -0,0 +1,334 @@
+package translator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+)
+
+const (
+	// microsoftMaxElements 单次请求最多 1000 个文本元素
+	microsoftMaxElements = 1000
+	// microsoftMaxChars 单次请求的总字符数上限
+	microsoftMaxChars = 50000
+)
+
+// MicrosoftTranslator 微软翻译器（Azure Translator v3）
+type MicrosoftTranslator struct {
+	apiKey   string
+	region   string
+	endpoint string
+	client   *http.Client
+}
+
+// MicrosoftTextItem 微软翻译请求体元素
+type MicrosoftTextItem struct {
+	Text string `json:"Text"`
+}
+
+// MicrosoftTranslateItem 微软翻译响应元素
+type MicrosoftTranslateItem struct {
+	DetectedLanguage *struct {
+		Language string  `json:"language"`
+		Score    float64 `json:"score"`
+	} `json:"detectedLanguage,omitempty"`
+	Translations []struct {
+		Text string `json:"text"`
+		To   string `json:"to"`
+	} `json:"translations"`
+}
+
+// MicrosoftDetectItem 微软语言检测响应元素
+type MicrosoftDetectItem struct {
+	Language string  `json:"language"`
+	Score    float64 `json:"score"`
+}
+
+// MicrosoftErrorResponse 微软翻译错误响应
+type MicrosoftErrorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// NewMicrosoftTranslator 创建微软翻译器实例
+func NewMicrosoftTranslator(config *types.MicrosoftTransConfig) (*MicrosoftTranslator, error) {
+	if config == nil {
+		return nil, fmt.Errorf("microsoft translator config is nil")
+	}
+	if config.ApiKey == "" {
+		return nil, fmt.Errorf("microsoft translator api_key is required")
+	}
+
+	endpoint := config.Endpoint
+	if endpoint == "" {
+		endpoint = "https://api.cognitive.microsofttranslator.com"
+	}
+
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = 30
+	}
+
+	return &MicrosoftTranslator{
+		apiKey:   config.ApiKey,
+		region:   config.Region,
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		client: &http.Client{
+			Timeout: time.Duration(timeout) * time.Second,
+		},
+	}, nil
+}
+
+// Translate 翻译文本
+func (m *MicrosoftTranslator) Translate(ctx context.Context, req *TranslationRequest) (*TranslationResult, error) {
+	if req.Text == "" {
+		return nil, fmt.Errorf("text cannot be empty")
+	}
+
+	batch, err := m.BatchTranslate(ctx, &BatchTranslationRequest{
+		Texts:      []string{req.Text},
+		SourceLang: req.SourceLang,
+		TargetLang: req.TargetLang,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := batch.Results[0]
+	result.Usage = batch.Usage
+	return result, nil
+}
+
+// BatchTranslate 批量翻译，按元素数和字符数上限分多次请求
+func (m *MicrosoftTranslator) BatchTranslate(ctx context.Context, req *BatchTranslationRequest) (*BatchTranslationResult, error) {
+	if len(req.Texts) == 0 {
+		return nil, fmt.Errorf("texts cannot be empty")
+	}
+
+	startTime := time.Now()
+	params := url.Values{}
+	params.Set("api-version", "3.0")
+	params.Set("to", m.convertLanguageCode(req.TargetLang))
+	if from := m.convertLanguageCode(req.SourceLang); from != "" {
+		params.Set("from", from)
+	}
+
+	results := make([]*TranslationResult, 0, len(req.Texts))
+	totalChars := 0
+	for _, chunk := range m.splitChunks(req.Texts) {
+		body := make([]MicrosoftTextItem, len(chunk))
+		for i, text := range chunk {
+			body[i] = MicrosoftTextItem{Text: text}
+		}
+
+		var items []MicrosoftTranslateItem
+		if err := m.call(ctx, "/translate", params, body, &items); err != nil {
+			return nil, err
+		}
+		if len(items) != len(chunk) {
+			return nil, fmt.Errorf("microsoft translator returned %d results for %d texts", len(items), len(chunk))
+		}
+
+		for i, item := range items {
+			if len(item.Translations) == 0 {
+				return nil, fmt.Errorf("no translation result for text %d", len(results)+1)
+			}
+			sourceLang := req.SourceLang
+			confidence := 0.0
+			if item.DetectedLanguage != nil {
+				sourceLang = m.revertLanguageCode(item.DetectedLanguage.Language)
+				confidence = item.DetectedLanguage.Score
+			}
+			results = append(results, &TranslationResult{
+				OriginalText:   chunk[i],
+				TranslatedText: item.Translations[0].Text,
+				SourceLang:     sourceLang,
+				TargetLang:     req.TargetLang,
+				Confidence:     confidence,
+				Provider:       "microsoft",
+				Usage: &Usage{
+					Characters: len(chunk[i]),
+				},
+			})
+			totalChars += len(chunk[i])
+		}
+	}
+
+	duration := time.Since(startTime)
+	logger.Infof("Microsoft batch translation completed: %d texts, %d chars (%dms)",
+		len(req.Texts), totalChars, duration.Milliseconds())
+
+	return &BatchTranslationResult{
+		Results:  results,
+		Provider: "microsoft",
+		Usage: &Usage{
+			Characters: totalChars,
+			Duration:   duration.Milliseconds(),
+		},
+	}, nil
+}
+
+// splitChunks 按单次请求上限切分文本
+func (m *MicrosoftTranslator) splitChunks(texts []string) [][]string {
+	var chunks [][]string
+	start, chars := 0, 0
+	for i, text := range texts {
+		n := len([]rune(text))
+		if i > start && (i-start >= microsoftMaxElements || chars+n > microsoftMaxChars) {
+			chunks = append(chunks, texts[start:i])
+			start, chars = i, 0
+		}
+		chars += n
+	}
+	return append(chunks, texts[start:])
+}
+
+// GetSupportedLanguages 获取支持的语言列表
+func (m *MicrosoftTranslator) GetSupportedLanguages(ctx context.Context) ([]LanguageInfo, error) {
+	params := url.Values{}
+	params.Set("api-version", "3.0")
+	params.Set("scope", "translation")
+
+	var resp struct {
+		Translation map[string]struct {
+			Name       string `json:"name"`
+			NativeName string `json:"nativeName"`
+			Dir        string `json:"dir"`
+		} `json:"translation"`
+	}
+	if err := m.call(ctx, "/languages", params, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	languages := make([]LanguageInfo, 0, len(resp.Translation))
+	for code, lang := range resp.Translation {
+		languages = append(languages, LanguageInfo{
+			Code:        m.revertLanguageCode(code),
+			Name:        lang.Name,
+			NativeName:  lang.NativeName,
+			Direction:   lang.Dir,
+			IsSupported: true,
+		})
+	}
+	return languages, nil
+}
+
+// DetectLanguage 检测语言
+func (m *MicrosoftTranslator) DetectLanguage(ctx context.Context, text string) (string, float64, error) {
+	if text == "" {
+		return "", 0, fmt.Errorf("text cannot be empty")
+	}
+
+	params := url.Values{}
+	params.Set("api-version", "3.0")
+
+	var items []MicrosoftDetectItem
+	if err := m.call(ctx, "/detect", params, []MicrosoftTextItem{{Text: text}}, &items); err != nil {
+		return "", 0, fmt.Errorf("failed to detect language: %w", err)
+	}
+	if len(items) == 0 {
+		return "", 0, fmt.Errorf("no detection result returned")
+	}
+	return m.revertLanguageCode(items[0].Language), items[0].Score, nil
+}
+
+// IsHealthy 健康检查
+func (m *MicrosoftTranslator) IsHealthy(ctx context.Context) error {
+	if _, _, err := m.DetectLanguage(ctx, "hello"); err != nil {
+		return fmt.Errorf("microsoft translator health check failed: %w", err)
+	}
+	return nil
+}
+
+// GetInfo 获取翻译器信息
+func (m *MicrosoftTranslator) GetInfo() *TranslatorInfo {
+	return &TranslatorInfo{
+		Name:               "Microsoft Translator",
+		Provider:           "microsoft",
+		Version:            "3.0",
+		MaxTextLength:      microsoftMaxChars,
+		SupportedLanguages: []string{"zh", "zh-cn", "zh-tw", "en", "ja", "ko", "es", "fr", "de", "ru", "it", "pt", "ar", "hi", "th", "vi"},
+		Features:           []string{"translate", "batch_translate", "language_detect"},
+		IsOnline:           true,
+	}
+}
+
+// call 调用微软翻译 API，body 为 nil 时发送 GET 请求
+func (m *MicrosoftTranslator) call(ctx context.Context, path string, params url.Values, body interface{}, out interface{}) error {
+	method := "GET"
+	var reader io.Reader
+	if body != nil {
+		jsonData, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		method = "POST"
+		reader = bytes.NewReader(jsonData)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, m.endpoint+path+"?"+params.Encode(), reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Ocp-Apim-Subscription-Key", m.apiKey)
+	if m.region != "" {
+		httpReq.Header.Set("Ocp-Apim-Subscription-Region", m.region)
+	}
+
+	resp, err := m.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr MicrosoftErrorResponse
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error.Message != "" {
+			return fmt.Errorf("microsoft API error: %d - %s", apiErr.Error.Code, apiErr.Error.Message)
+		}
+		return fmt.Errorf("microsoft API returned status %d: %s", resp.StatusCode, truncate(string(data), 200))
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// convertLanguageCode 转换语言代码到微软格式
+func (m *MicrosoftTranslator) convertLanguageCode(code string) string {
+	switch strings.ToLower(code) {
+	case "", "auto":
+		return ""
+	case "zh", "zh-cn", "zh-hans":
+		return "zh-Hans"
+	case "zh-tw", "zh-hk", "zh-hant":
+		return "zh-Hant"
+	}
+	return code
+}
+
+// revertLanguageCode 将微软语言代码转换回标准格式
+func (m *MicrosoftTranslator) revertLanguageCode(code string) string {
+	switch code {
+	case "zh-Hans":
+		return "zh"
+	case "zh-Hant":
+		return "zh-tw"
+	}
+	return code
+}
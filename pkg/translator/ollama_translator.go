@@ -0,0 +1,390 @@
+package translator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+)
+
+// OllamaTranslator Ollama本地模型翻译器
+type OllamaTranslator struct {
+	endpoint string
+	model    string
+	client   *http.Client
+}
+
+// OllamaChatRequest Ollama /api/chat 请求
+type OllamaChatRequest struct {
+	Model    string                 `json:"model"`
+	Messages []DeepSeekMessage      `json:"messages"`
+	Stream   bool                   `json:"stream"`
+	Format   string                 `json:"format,omitempty"`
+	Options  map[string]interface{} `json:"options,omitempty"`
+}
+
+// OllamaChatResponse Ollama /api/chat 响应
+type OllamaChatResponse struct {
+	Model           string          `json:"model"`
+	Message         DeepSeekMessage `json:"message"`
+	Done            bool            `json:"done"`
+	PromptEvalCount int             `json:"prompt_eval_count"`
+	EvalCount       int             `json:"eval_count"`
+	Error           string          `json:"error,omitempty"`
+}
+
+// ollamaBatchInput 批量翻译的输入，前后文只用于理解语境
+type ollamaBatchInput struct {
+	ContextBefore []string `json:"context_before,omitempty"`
+	Texts         []string `json:"texts"`
+	ContextAfter  []string `json:"context_after,omitempty"`
+}
+
+// NewOllamaTranslator 创建Ollama翻译器实例
+func NewOllamaTranslator(config *types.OllamaTransConfig) (*OllamaTranslator, error) {
+	if config == nil {
+		return nil, fmt.Errorf("ollama translator config is nil")
+	}
+	if config.Model == "" {
+		return nil, fmt.Errorf("ollama translator model is required")
+	}
+
+	endpoint := config.Endpoint
+	if endpoint == "" {
+		endpoint = "http://localhost:11434"
+	}
+
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = 120 // 本地模型首次加载较慢
+	}
+
+	return &OllamaTranslator{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		model:    config.Model,
+		client: &http.Client{
+			Timeout: time.Duration(timeout) * time.Second,
+		},
+	}, nil
+}
+
+// Translate 翻译文本
+func (o *OllamaTranslator) Translate(ctx context.Context, req *TranslationRequest) (*TranslationResult, error) {
+	if req.Text == "" {
+		return nil, fmt.Errorf("text cannot be empty")
+	}
+
+	startTime := time.Now()
+	model := o.modelFor(req.Model)
+	resp, err := o.chat(ctx, model, o.buildSystemPrompt(req.SourceLang, req.TargetLang), req.Text, "")
+	if err != nil {
+		return nil, fmt.Errorf("ollama API call failed: %w", err)
+	}
+
+	return &TranslationResult{
+		OriginalText:   req.Text,
+		TranslatedText: strings.TrimSpace(resp.Message.Content),
+		SourceLang:     req.SourceLang,
+		TargetLang:     req.TargetLang,
+		Provider:       "ollama",
+		Model:          model,
+		Usage: &Usage{
+			InputTokens:  resp.PromptEvalCount,
+			OutputTokens: resp.EvalCount,
+			TotalTokens:  resp.PromptEvalCount + resp.EvalCount,
+			Characters:   len(req.Text),
+			Duration:     time.Since(startTime).Milliseconds(),
+		},
+	}, nil
+}
+
+// BatchTranslate 批量翻译，要求模型以 JSON 数组返回译文，条数不一致时逐条翻译
+func (o *OllamaTranslator) BatchTranslate(ctx context.Context, req *BatchTranslationRequest) (*BatchTranslationResult, error) {
+	if len(req.Texts) == 0 {
+		return nil, fmt.Errorf("texts cannot be empty")
+	}
+
+	startTime := time.Now()
+	model := o.modelFor(req.Model)
+	input, err := json.Marshal(ollamaBatchInput{
+		ContextBefore: req.PrevContext,
+		Texts:         req.Texts,
+		ContextAfter:  req.NextContext,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal texts: %w", err)
+	}
+
+	resp, err := o.chat(ctx, model, o.buildBatchSystemPrompt(req.SourceLang, req.TargetLang, req.Instructions), string(input), "json")
+	if err != nil {
+		return nil, fmt.Errorf("ollama API call failed: %w", err)
+	}
+
+	var output struct {
+		Translations []string `json:"translations"`
+	}
+	if err := json.Unmarshal([]byte(resp.Message.Content), &output); err != nil || len(output.Translations) != len(req.Texts) {
+		logger.Warnf("Ollama batch result mismatch (%d texts), falling back to individual translation", len(req.Texts))
+		return o.batchTranslateIndividually(ctx, req)
+	}
+
+	results := make([]*TranslationResult, len(req.Texts))
+	totalChars := 0
+	for i, text := range req.Texts {
+		results[i] = &TranslationResult{
+			OriginalText:   text,
+			TranslatedText: strings.TrimSpace(output.Translations[i]),
+			SourceLang:     req.SourceLang,
+			TargetLang:     req.TargetLang,
+			Provider:       "ollama",
+			Model:          model,
+			Usage: &Usage{
+				Characters: len(text),
+			},
+		}
+		totalChars += len(text)
+	}
+
+	duration := time.Since(startTime)
+	logger.Infof("Ollama batch translation completed: %d texts, %d chars (%dms)",
+		len(req.Texts), totalChars, duration.Milliseconds())
+
+	return &BatchTranslationResult{
+		Results:  results,
+		Provider: "ollama",
+		Usage: &Usage{
+			InputTokens:  resp.PromptEvalCount,
+			OutputTokens: resp.EvalCount,
+			TotalTokens:  resp.PromptEvalCount + resp.EvalCount,
+			Characters:   totalChars,
+			Duration:     duration.Milliseconds(),
+		},
+	}, nil
+}
+
+// batchTranslateIndividually 逐个翻译（备用方法）
+func (o *OllamaTranslator) batchTranslateIndividually(ctx context.Context, req *BatchTranslationRequest) (*BatchTranslationResult, error) {
+	startTime := time.Now()
+	results := make([]*TranslationResult, len(req.Texts))
+	usage := &Usage{}
+
+	for i, text := range req.Texts {
+		result, err := o.Translate(ctx, &TranslationRequest{
+			Text:       text,
+			SourceLang: req.SourceLang,
+			TargetLang: req.TargetLang,
+			Model:      req.Model,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to translate text %d: %w", i+1, err)
+		}
+		results[i] = result
+		usage.InputTokens += result.Usage.InputTokens
+		usage.OutputTokens += result.Usage.OutputTokens
+		usage.TotalTokens += result.Usage.TotalTokens
+		usage.Characters += result.Usage.Characters
+	}
+
+	usage.Duration = time.Since(startTime).Milliseconds()
+	return &BatchTranslationResult{
+		Results:  results,
+		Provider: "ollama",
+		Usage:    usage,
+	}, nil
+}
+
+// GetSupportedLanguages 获取支持的语言列表（取决于所用模型，这里列出常用语言）
+func (o *OllamaTranslator) GetSupportedLanguages(ctx context.Context) ([]LanguageInfo, error) {
+	codes := o.GetInfo().SupportedLanguages
+	languages := make([]LanguageInfo, 0, len(codes))
+	for _, code := range codes {
+		languages = append(languages, LanguageInfo{
+			Code:        code,
+			Name:        languageName(code),
+			NativeName:  languageName(code),
+			Direction:   "ltr",
+			IsSupported: true,
+		})
+	}
+	return languages, nil
+}
+
+// DetectLanguage 检测语言
+func (o *OllamaTranslator) DetectLanguage(ctx context.Context, text string) (string, float64, error) {
+	if text == "" {
+		return "", 0, fmt.Errorf("text cannot be empty")
+	}
+
+	systemPrompt := "你是一个语言检测专家。请检测给定文本的语言，并返回ISO 639-1语言代码（如'en'、'zh'、'ja'等）。只返回语言代码，不要其他说明。"
+	resp, err := o.chat(ctx, o.model, systemPrompt, text, "")
+	if err != nil {
+		return "", 0, fmt.Errorf("language detection failed: %w", err)
+	}
+
+	langCode := strings.Trim(strings.TrimSpace(strings.ToLower(resp.Message.Content)), "'\".")
+	if len(langCode) < 2 || len(langCode) > 5 {
+		langCode = "auto"
+	}
+	return langCode, 0.8, nil
+}
+
+// IsHealthy 健康检查：Ollama 服务可访问且已拉取配置的模型
+func (o *OllamaTranslator) IsHealthy(ctx context.Context) error {
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", o.endpoint+"/api/tags", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := o.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("ollama health check failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("ollama health check failed: status %d", resp.StatusCode)
+	}
+
+	var tags struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	for _, m := range tags.Models {
+		if m.Name == o.model || strings.TrimSuffix(m.Name, ":latest") == o.model {
+			return nil
+		}
+	}
+	return fmt.Errorf("ollama model %s not found, run: ollama pull %s", o.model, o.model)
+}
+
+// GetInfo 获取翻译器信息
+func (o *OllamaTranslator) GetInfo() *TranslatorInfo {
+	return &TranslatorInfo{
+		Name:          fmt.Sprintf("Ollama Local Translator (%s)", o.model),
+		Provider:      "ollama",
+		Version:       "1.0.0",
+		MaxTextLength: 8000,
+		SupportedLanguages: []string{
+			"zh", "zh-cn", "zh-tw", "en", "ja", "ko", "es", "fr", "de", "ru", "it", "pt", "ar", "hi", "th", "vi",
+		},
+		Features: []string{"translate", "batch_translate", "detect_language"},
+		IsOnline: false,
+	}
+}
+
+// modelFor 请求指定模型时优先使用
+func (o *OllamaTranslator) modelFor(model string) string {
+	if model != "" {
+		return model
+	}
+	return o.model
+}
+
+// chat 调用 Ollama /api/chat（非流式），format 为 "json" 时约束模型输出 JSON
+func (o *OllamaTranslator) chat(ctx context.Context, model, systemPrompt, userPrompt, format string) (*OllamaChatResponse, error) {
+	reqBody := OllamaChatRequest{
+		Model: model,
+		Messages: []DeepSeekMessage{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: userPrompt},
+		},
+		Stream:  false,
+		Format:  format,
+		Options: map[string]interface{}{"temperature": 0.2},
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", o.endpoint+"/api/chat", bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := o.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var response OllamaChatResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("ollama API returned status %d: %s", resp.StatusCode, truncate(string(body), 200))
+	}
+	if response.Error != "" {
+		return nil, fmt.Errorf("ollama API error: %s", response.Error)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ollama API returned status %d", resp.StatusCode)
+	}
+	return &response, nil
+}
+
+// buildSystemPrompt 构建单条翻译的系统提示词
+func (o *OllamaTranslator) buildSystemPrompt(sourceLang, targetLang string) string {
+	var prompt strings.Builder
+	prompt.WriteString("你是一位专业的翻译专家。请将用户给出的文本准确、自然地翻译")
+	if sourceLang != "" && sourceLang != "auto" {
+		prompt.WriteString("，源语言：" + languageName(sourceLang))
+	}
+	prompt.WriteString("，目标语言：" + languageName(targetLang) + "。\n")
+	prompt.WriteString("请直接返回翻译结果，不要包含任何解释或其他内容。")
+	return prompt.String()
+}
+
+// buildBatchSystemPrompt 构建批量翻译的系统提示词，instructions 为附加要求（如术语表）
+func (o *OllamaTranslator) buildBatchSystemPrompt(sourceLang, targetLang, instructions string) string {
+	var prompt strings.Builder
+	prompt.WriteString(o.buildSystemPrompt(sourceLang, targetLang))
+	prompt.WriteString("\n输入是 JSON 对象 {\"texts\": [...]}，请逐条翻译 texts，")
+	prompt.WriteString("context_before 和 context_after 是相邻的上下文，只用于理解语境，不要翻译。")
+	prompt.WriteString("只返回 JSON 对象 {\"translations\": [...]}，译文条数和顺序必须与 texts 一致。")
+	if instructions = strings.TrimSpace(instructions); instructions != "" {
+		prompt.WriteString("\n\n" + instructions)
+	}
+	return prompt.String()
+}
+
+// languageName 获取语言的中文名称
+func languageName(code string) string {
+	names := map[string]string{
+		"zh":    "中文",
+		"zh-cn": "简体中文",
+		"zh-tw": "繁体中文",
+		"en":    "英语",
+		"ja":    "日语",
+		"ko":    "韩语",
+		"es":    "西班牙语",
+		"fr":    "法语",
+		"de":    "德语",
+		"ru":    "俄语",
+		"it":    "意大利语",
+		"pt":    "葡萄牙语",
+		"ar":    "阿拉伯语",
+		"hi":    "印地语",
+		"th":    "泰语",
+		"vi":    "越南语",
+	}
+	if name, ok := names[strings.ToLower(code)]; ok {
+		return name
+	}
+	return code
+}
@@ -0,0 +1,365 @@
+package translator
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+)
+
+const (
+	// tencentService 腾讯云机器翻译服务名
+	tencentService = "tmt"
+	// tencentVersion 机器翻译 API 版本
+	tencentVersion = "2018-03-21"
+	// tencentMaxChars 单次请求的文本总长度上限
+	tencentMaxChars = 6000
+)
+
+// TencentTranslator 腾讯云机器翻译器（TMT，TC3-HMAC-SHA256 签名）
+type TencentTranslator struct {
+	secretId  string
+	secretKey string
+	region    string
+	projectId int64
+	endpoint  string
+	host      string
+	client    *http.Client
+	now       func() time.Time
+}
+
+// TencentResponse 腾讯云 API 响应
+type TencentResponse struct {
+	Response struct {
+		TargetText     string   `json:"TargetText,omitempty"`
+		TargetTextList []string `json:"TargetTextList,omitempty"`
+		Source         string   `json:"Source,omitempty"`
+		Target         string   `json:"Target,omitempty"`
+		Lang           string   `json:"Lang,omitempty"`
+		RequestId      string   `json:"RequestId"`
+		Error          *struct {
+			Code    string `json:"Code"`
+			Message string `json:"Message"`
+		} `json:"Error,omitempty"`
+	} `json:"Response"`
+}
+
+// NewTencentTranslator 创建腾讯云机器翻译器实例
+func NewTencentTranslator(config *types.TencentTransConfig) (*TencentTranslator, error) {
+	if config == nil {
+		return nil, fmt.Errorf("tencent translator config is nil")
+	}
+	if config.SecretId == "" || config.SecretKey == "" {
+		return nil, fmt.Errorf("tencent translator secret_id and secret_key are required")
+	}
+
+	endpoint := config.Endpoint
+	if endpoint == "" {
+		endpoint = "https://tmt.tencentcloudapi.com"
+	}
+	parsed, err := url.Parse(endpoint)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid tencent translator endpoint: %s", endpoint)
+	}
+
+	region := config.Region
+	if region == "" {
+		region = "ap-guangzhou"
+	}
+
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = 30
+	}
+
+	return &TencentTranslator{
+		secretId:  config.SecretId,
+		secretKey: config.SecretKey,
+		region:    region,
+		projectId: config.ProjectId,
+		endpoint:  endpoint,
+		host:      parsed.Host,
+		client: &http.Client{
+			Timeout: time.Duration(timeout) * time.Second,
+		},
+		now: time.Now,
+	}, nil
+}
+
+// Translate 翻译文本
+func (t *TencentTranslator) Translate(ctx context.Context, req *TranslationRequest) (*TranslationResult, error) {
+	if req.Text == "" {
+		return nil, fmt.Errorf("text cannot be empty")
+	}
+
+	startTime := time.Now()
+	var resp TencentResponse
+	if err := t.call(ctx, "TextTranslate", map[string]interface{}{
+		"SourceText": req.Text,
+		"Source":     t.convertLanguageCode(req.SourceLang),
+		"Target":     t.convertLanguageCode(req.TargetLang),
+		"ProjectId":  t.projectId,
+	}, &resp); err != nil {
+		return nil, err
+	}
+
+	return &TranslationResult{
+		OriginalText:   req.Text,
+		TranslatedText: resp.Response.TargetText,
+		SourceLang:     t.revertLanguageCode(resp.Response.Source),
+		TargetLang:     req.TargetLang,
+		Provider:       "tencent",
+		Usage: &Usage{
+			Characters: len(req.Text),
+			Duration:   time.Since(startTime).Milliseconds(),
+		},
+	}, nil
+}
+
+// BatchTranslate 批量翻译（TextTranslateBatch），按总长度上限分多次请求
+func (t *TencentTranslator) BatchTranslate(ctx context.Context, req *BatchTranslationRequest) (*BatchTranslationResult, error) {
+	if len(req.Texts) == 0 {
+		return nil, fmt.Errorf("texts cannot be empty")
+	}
+
+	startTime := time.Now()
+	results := make([]*TranslationResult, 0, len(req.Texts))
+	totalChars := 0
+	for _, chunk := range t.splitChunks(req.Texts) {
+		var resp TencentResponse
+		if err := t.call(ctx, "TextTranslateBatch", map[string]interface{}{
+			"SourceTextList": chunk,
+			"Source":         t.convertLanguageCode(req.SourceLang),
+			"Target":         t.convertLanguageCode(req.TargetLang),
+			"ProjectId":      t.projectId,
+		}, &resp); err != nil {
+			return nil, err
+		}
+		if len(resp.Response.TargetTextList) != len(chunk) {
+			return nil, fmt.Errorf("tencent TMT returned %d results for %d texts", len(resp.Response.TargetTextList), len(chunk))
+		}
+
+		for i, text := range resp.Response.TargetTextList {
+			results = append(results, &TranslationResult{
+				OriginalText:   chunk[i],
+				TranslatedText: text,
+				SourceLang:     t.revertLanguageCode(resp.Response.Source),
+				TargetLang:     req.TargetLang,
+				Provider:       "tencent",
+				Usage: &Usage{
+					Characters: len(chunk[i]),
+				},
+			})
+			totalChars += len(chunk[i])
+		}
+	}
+
+	duration := time.Since(startTime)
+	logger.Infof("Tencent batch translation completed: %d texts, %d chars (%dms)",
+		len(req.Texts), totalChars, duration.Milliseconds())
+
+	return &BatchTranslationResult{
+		Results:  results,
+		Provider: "tencent",
+		Usage: &Usage{
+			Characters: totalChars,
+			Duration:   duration.Milliseconds(),
+		},
+	}, nil
+}
+
+// splitChunks 按单次请求的总长度上限切分文本
+func (t *TencentTranslator) splitChunks(texts []string) [][]string {
+	var chunks [][]string
+	start, chars := 0, 0
+	for i, text := range texts {
+		n := len([]rune(text))
+		if i > start && chars+n > tencentMaxChars {
+			chunks = append(chunks, texts[start:i])
+			start, chars = i, 0
+		}
+		chars += n
+	}
+	return append(chunks, texts[start:])
+}
+
+// GetSupportedLanguages 获取支持的语言列表
+func (t *TencentTranslator) GetSupportedLanguages(ctx context.Context) ([]LanguageInfo, error) {
+	// 腾讯云机器翻译支持的语种
+	languages := []LanguageInfo{
+		{Code: "zh", Name: "简体中文", NativeName: "简体中文", Direction: "ltr", IsSupported: true},
+		{Code: "zh-tw", Name: "繁体中文", NativeName: "繁體中文", Direction: "ltr", IsSupported: true},
+		{Code: "en", Name: "英语", NativeName: "English", Direction: "ltr", IsSupported: true},
+		{Code: "ja", Name: "日语", NativeName: "日本語", Direction: "ltr", IsSupported: true},
+		{Code: "ko", Name: "韩语", NativeName: "한국어", Direction: "ltr", IsSupported: true},
+		{Code: "fr", Name: "法语", NativeName: "Français", Direction: "ltr", IsSupported: true},
+		{Code: "es", Name: "西班牙语", NativeName: "Español", Direction: "ltr", IsSupported: true},
+		{Code: "it", Name: "意大利语", NativeName: "Italiano", Direction: "ltr", IsSupported: true},
+		{Code: "de", Name: "德语", NativeName: "Deutsch", Direction: "ltr", IsSupported: true},
+		{Code: "tr", Name: "土耳其语", NativeName: "Türkçe", Direction: "ltr", IsSupported: true},
+		{Code: "ru", Name: "俄语", NativeName: "Русский", Direction: "ltr", IsSupported: true},
+		{Code: "pt", Name: "葡萄牙语", NativeName: "Português", Direction: "ltr", IsSupported: true},
+		{Code: "vi", Name: "越南语", NativeName: "Tiếng Việt", Direction: "ltr", IsSupported: true},
+		{Code: "id", Name: "印尼语", NativeName: "Bahasa Indonesia", Direction: "ltr", IsSupported: true},
+		{Code: "th", Name: "泰语", NativeName: "ไทย", Direction: "ltr", IsSupported: true},
+		{Code: "ms", Name: "马来语", NativeName: "Bahasa Melayu", Direction: "ltr", IsSupported: true},
+		{Code: "ar", Name: "阿拉伯语", NativeName: "العربية", Direction: "rtl", IsSupported: true},
+		{Code: "hi", Name: "印地语", NativeName: "हिन्दी", Direction: "ltr", IsSupported: true},
+	}
+	return languages, nil
+}
+
+// DetectLanguage 检测语言
+func (t *TencentTranslator) DetectLanguage(ctx context.Context, text string) (string, float64, error) {
+	if text == "" {
+		return "", 0, fmt.Errorf("text cannot be empty")
+	}
+
+	var resp TencentResponse
+	if err := t.call(ctx, "LanguageDetect", map[string]interface{}{
+		"Text":      text,
+		"ProjectId": t.projectId,
+	}, &resp); err != nil {
+		return "", 0, fmt.Errorf("failed to detect language: %w", err)
+	}
+
+	// 接口不返回置信度
+	return t.revertLanguageCode(resp.Response.Lang), 0.9, nil
+}
+
+// IsHealthy 健康检查
+func (t *TencentTranslator) IsHealthy(ctx context.Context) error {
+	if _, _, err := t.DetectLanguage(ctx, "hello"); err != nil {
+		return fmt.Errorf("tencent translator health check failed: %w", err)
+	}
+	return nil
+}
+
+// GetInfo 获取翻译器信息
+func (t *TencentTranslator) GetInfo() *TranslatorInfo {
+	return &TranslatorInfo{
+		Name:               "Tencent Machine Translation",
+		Provider:           "tencent",
+		Version:            tencentVersion,
+		MaxTextLength:      tencentMaxChars,
+		SupportedLanguages: []string{"zh", "zh-tw", "en", "ja", "ko", "fr", "es", "it", "de", "tr", "ru", "pt", "vi", "id", "th", "ms", "ar", "hi"},
+		Features:           []string{"translate", "batch_translate", "language_detect"},
+		IsOnline:           true,
+	}
+}
+
+// call 调用腾讯云 API
+func (t *TencentTranslator) call(ctx context.Context, action string, params map[string]interface{}, out *TencentResponse) error {
+	payload, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	timestamp := t.now().Unix()
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", t.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json; charset=utf-8")
+	httpReq.Header.Set("X-TC-Action", action)
+	httpReq.Header.Set("X-TC-Version", tencentVersion)
+	httpReq.Header.Set("X-TC-Region", t.region)
+	httpReq.Header.Set("X-TC-Timestamp", strconv.FormatInt(timestamp, 10))
+	httpReq.Header.Set("Authorization", t.authorization(payload, timestamp))
+
+	resp, err := t.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("tencent API returned status %d: %s", resp.StatusCode, truncate(string(body), 200))
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	if out.Response.Error != nil {
+		return fmt.Errorf("tencent API error: %s - %s", out.Response.Error.Code, out.Response.Error.Message)
+	}
+	return nil
+}
+
+// authorization 生成 TC3-HMAC-SHA256 签名的 Authorization 头
+func (t *TencentTranslator) authorization(payload []byte, timestamp int64) string {
+	date := time.Unix(timestamp, 0).UTC().Format("2006-01-02")
+	signedHeaders := "content-type;host"
+	canonicalRequest := strings.Join([]string{
+		"POST",
+		"/",
+		"",
+		"content-type:application/json; charset=utf-8\nhost:" + t.host + "\n",
+		signedHeaders,
+		sha256Hex(payload),
+	}, "\n")
+
+	credentialScope := date + "/" + tencentService + "/tc3_request"
+	stringToSign := strings.Join([]string{
+		"TC3-HMAC-SHA256",
+		strconv.FormatInt(timestamp, 10),
+		credentialScope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	secretDate := hmacSHA256([]byte("TC3"+t.secretKey), date)
+	secretService := hmacSHA256(secretDate, tencentService)
+	secretSigning := hmacSHA256(secretService, "tc3_request")
+	signature := hex.EncodeToString(hmacSHA256(secretSigning, stringToSign))
+
+	return fmt.Sprintf("TC3-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		t.secretId, credentialScope, signedHeaders, signature)
+}
+
+// sha256Hex 计算 SHA-256 十六进制摘要
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hmacSHA256 计算 HMAC-SHA256
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// convertLanguageCode 转换语言代码到腾讯云格式
+func (t *TencentTranslator) convertLanguageCode(code string) string {
+	switch strings.ToLower(code) {
+	case "", "auto":
+		return "auto"
+	case "zh", "zh-cn", "zh-hans":
+		return "zh"
+	case "zh-tw", "zh-hk", "zh-hant":
+		return "zh-TW"
+	}
+	return code
+}
+
+// revertLanguageCode 将腾讯云语言代码转换回标准格式
+func (t *TencentTranslator) revertLanguageCode(code string) string {
+	if code == "zh-TW" {
+		return "zh-tw"
+	}
+	return code
+}
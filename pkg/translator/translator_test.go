@@ -0,0 +1,362 @@
+package translator
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+
+	"go.uber.org/zap"
+)
+
+// TestMain 测试期间不写日志文件（包级 logger 默认同时输出到 logs/app.log）
+func TestMain(m *testing.M) {
+	logger = zap.NewNop().Sugar()
+	os.Exit(m.Run())
+}
+
+// upper 测试用的“翻译”：转大写
+func upper(texts []string) []string {
+	out := make([]string, len(texts))
+	for i, text := range texts {
+		out[i] = strings.ToUpper(text)
+	}
+	return out
+}
+
+// checkBatch 校验批量翻译结果
+func checkBatch(t *testing.T, tr Translator, provider string) {
+	t.Helper()
+	result, err := tr.BatchTranslate(context.Background(), &BatchTranslationRequest{
+		Texts:      []string{"hello", "world"},
+		SourceLang: "en",
+		TargetLang: "zh",
+	})
+	if err != nil {
+		t.Fatalf("批量翻译失败: %v", err)
+	}
+	if len(result.Results) != 2 || result.Results[0].TranslatedText != "HELLO" || result.Results[1].TranslatedText != "WORLD" {
+		t.Fatalf("批量翻译结果不正确: %+v", result.Results)
+	}
+	if result.Provider != provider || result.Results[0].Provider != provider {
+		t.Fatalf("提供商应为 %s: %s", provider, result.Provider)
+	}
+	if result.Usage == nil || result.Usage.Characters != 10 {
+		t.Fatalf("使用统计不正确: %+v", result.Usage)
+	}
+}
+
+// TestGoogleTranslator 测试 Google v2 请求格式、语言代码转换和错误处理
+func TestGoogleTranslator(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("key") != "test-key" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error":{"code":403,"message":"API key not valid"}}`))
+			return
+		}
+
+		var req GoogleTranslateRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		switch r.URL.Path {
+		case "/language/translate/v2":
+			if req.Target != "zh-CN" || req.Source != "en" || req.Format != "text" {
+				t.Errorf("请求参数不正确: %+v", req)
+			}
+			var items []map[string]string
+			for _, text := range upper(req.Q) {
+				items = append(items, map[string]string{"translatedText": text})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"translations": items}})
+		case "/language/translate/v2/detect":
+			w.Write([]byte(`{"data":{"detections":[[{"language":"zh-CN","confidence":0.98}]]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tr, err := NewGoogleTranslator(&types.GoogleTransConfig{ApiKey: "test-key", Endpoint: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBatch(t, tr, "google")
+
+	lang, confidence, err := tr.DetectLanguage(context.Background(), "你好")
+	if err != nil || lang != "zh" || confidence != 0.98 {
+		t.Fatalf("语言检测结果不正确: %s %v %v", lang, confidence, err)
+	}
+
+	bad, _ := NewGoogleTranslator(&types.GoogleTransConfig{ApiKey: "wrong", Endpoint: server.URL})
+	if _, err := bad.Translate(context.Background(), &TranslationRequest{Text: "hi", TargetLang: "zh"}); err == nil || !strings.Contains(err.Error(), "API key not valid") {
+		t.Fatalf("应返回 API 错误信息: %v", err)
+	}
+}
+
+// TestMicrosoftTranslator 测试微软翻译的请求头、查询参数和分块
+func TestMicrosoftTranslator(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Ocp-Apim-Subscription-Key") != "test-key" || r.Header.Get("Ocp-Apim-Subscription-Region") != "eastasia" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":{"code":401000,"message":"invalid credentials"}}`))
+			return
+		}
+		query := r.URL.Query()
+		if query.Get("api-version") != "3.0" {
+			t.Errorf("缺少 api-version: %s", r.URL.RawQuery)
+		}
+
+		var items []MicrosoftTextItem
+		json.NewDecoder(r.Body).Decode(&items)
+		switch r.URL.Path {
+		case "/translate":
+			if query.Get("to") != "zh-Hans" || query.Get("from") != "en" {
+				t.Errorf("语言参数不正确: %s", r.URL.RawQuery)
+			}
+			var resp []map[string]interface{}
+			for _, item := range items {
+				resp = append(resp, map[string]interface{}{
+					"translations": []map[string]string{{"text": strings.ToUpper(item.Text), "to": "zh-Hans"}},
+				})
+			}
+			json.NewEncoder(w).Encode(resp)
+		case "/detect":
+			w.Write([]byte(`[{"language":"en","score":0.92}]`))
+		}
+	}))
+	defer server.Close()
+
+	tr, err := NewMicrosoftTranslator(&types.MicrosoftTransConfig{ApiKey: "test-key", Region: "eastasia", Endpoint: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBatch(t, tr, "microsoft")
+
+	lang, confidence, err := tr.DetectLanguage(context.Background(), "hello")
+	if err != nil || lang != "en" || confidence != 0.92 {
+		t.Fatalf("语言检测结果不正确: %s %v %v", lang, confidence, err)
+	}
+
+	bad, _ := NewMicrosoftTranslator(&types.MicrosoftTransConfig{ApiKey: "wrong", Endpoint: server.URL})
+	if _, err := bad.Translate(context.Background(), &TranslationRequest{Text: "hi", TargetLang: "zh"}); err == nil || !strings.Contains(err.Error(), "401000") {
+		t.Fatalf("应返回 API 错误信息: %v", err)
+	}
+
+	texts := make([]string, microsoftMaxElements+1)
+	if chunks := tr.splitChunks(texts); len(chunks) != 2 || len(chunks[0]) != microsoftMaxElements {
+		t.Fatalf("分块不正确: %d", len(chunks))
+	}
+}
+
+// TestTencentTranslator 测试腾讯云 TC3-HMAC-SHA256 签名和批量翻译
+func TestTencentTranslator(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := io.ReadAll(r.Body)
+		if !verifyTC3(r, payload, "secret-key") {
+			w.Write([]byte(`{"Response":{"Error":{"Code":"AuthFailure.SignatureFailure","Message":"signature mismatch"},"RequestId":"1"}}`))
+			return
+		}
+
+		var req map[string]interface{}
+		json.Unmarshal(payload, &req)
+		switch r.Header.Get("X-TC-Action") {
+		case "TextTranslateBatch":
+			if req["Source"] != "en" || req["Target"] != "zh" {
+				t.Errorf("语言参数不正确: %v", req)
+			}
+			var texts []string
+			for _, text := range req["SourceTextList"].([]interface{}) {
+				texts = append(texts, text.(string))
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"Response": map[string]interface{}{"TargetTextList": upper(texts), "Source": "en", "Target": "zh", "RequestId": "2"},
+			})
+		case "LanguageDetect":
+			w.Write([]byte(`{"Response":{"Lang":"zh-TW","RequestId":"3"}}`))
+		}
+	}))
+	defer server.Close()
+
+	tr, err := NewTencentTranslator(&types.TencentTransConfig{SecretId: "secret-id", SecretKey: "secret-key", Endpoint: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBatch(t, tr, "tencent")
+
+	if lang, _, err := tr.DetectLanguage(context.Background(), "你好"); err != nil || lang != "zh-tw" {
+		t.Fatalf("语言检测结果不正确: %s %v", lang, err)
+	}
+
+	bad, _ := NewTencentTranslator(&types.TencentTransConfig{SecretId: "secret-id", SecretKey: "wrong", Endpoint: server.URL})
+	if _, err := bad.Translate(context.Background(), &TranslationRequest{Text: "hi", TargetLang: "zh"}); err == nil || !strings.Contains(err.Error(), "AuthFailure") {
+		t.Fatalf("签名错误应返回 AuthFailure: %v", err)
+	}
+}
+
+// verifyTC3 按腾讯云文档独立计算签名并与请求中的签名比对
+func verifyTC3(r *http.Request, payload []byte, secretKey string) bool {
+	timestamp, err := strconv.ParseInt(r.Header.Get("X-TC-Timestamp"), 10, 64)
+	if err != nil || r.Header.Get("X-TC-Version") != "2018-03-21" {
+		return false
+	}
+	date := time.Unix(timestamp, 0).UTC().Format("2006-01-02")
+
+	hash := func(b []byte) string {
+		sum := sha256.Sum256(b)
+		return hex.EncodeToString(sum[:])
+	}
+	sign := func(key []byte, s string) []byte {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(s))
+		return mac.Sum(nil)
+	}
+
+	canonical := "POST\n/\n\ncontent-type:" + r.Header.Get("Content-Type") + "\nhost:" + r.Host + "\n\ncontent-type;host\n" + hash(payload)
+	stringToSign := "TC3-HMAC-SHA256\n" + strconv.FormatInt(timestamp, 10) + "\n" + date + "/tmt/tc3_request\n" + hash([]byte(canonical))
+	key := sign(sign(sign([]byte("TC3"+secretKey), date), "tmt"), "tc3_request")
+	signature := hex.EncodeToString(sign(key, stringToSign))
+
+	expected := "TC3-HMAC-SHA256 Credential=secret-id/" + date + "/tmt/tc3_request, SignedHeaders=content-type;host, Signature=" + signature
+	return r.Header.Get("Authorization") == expected
+}
+
+// TestOllamaTranslator 测试 Ollama JSON 批量翻译、条数不一致时逐条回退和健康检查
+func TestOllamaTranslator(t *testing.T) {
+	mismatch := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/tags":
+			w.Write([]byte(`{"models":[{"name":"qwen2.5:7b"}]}`))
+			return
+		case "/api/chat":
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		var req OllamaChatRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Model != "qwen2.5:7b" || req.Stream || len(req.Messages) != 2 {
+			t.Errorf("请求不正确: %+v", req)
+		}
+		content := strings.ToUpper(req.Messages[1].Content)
+		if req.Format == "json" {
+			var input struct {
+				Texts []string `json:"texts"`
+			}
+			json.Unmarshal([]byte(req.Messages[1].Content), &input)
+			translations := upper(input.Texts)
+			if mismatch {
+				translations = translations[:1]
+			}
+			data, _ := json.Marshal(map[string][]string{"translations": translations})
+			content = string(data)
+		}
+		json.NewEncoder(w).Encode(OllamaChatResponse{
+			Model:           req.Model,
+			Message:         DeepSeekMessage{Role: "assistant", Content: content},
+			Done:            true,
+			PromptEvalCount: 10,
+			EvalCount:       5,
+		})
+	}))
+	defer server.Close()
+
+	tr, err := NewOllamaTranslator(&types.OllamaTransConfig{Endpoint: server.URL, Model: "qwen2.5:7b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBatch(t, tr, "ollama")
+
+	mismatch = true
+	checkBatch(t, tr, "ollama")
+
+	if err := tr.IsHealthy(context.Background()); err != nil {
+		t.Fatalf("健康检查失败: %v", err)
+	}
+	missing, _ := NewOllamaTranslator(&types.OllamaTransConfig{Endpoint: server.URL, Model: "llama3"})
+	if err := missing.IsHealthy(context.Background()); err == nil {
+		t.Fatal("模型不存在时健康检查应失败")
+	}
+}
+
+// TestFactoryProviders 测试工厂声明的提供商都能创建，管理器默认只使用已启用的提供商
+func TestFactoryProviders(t *testing.T) {
+	config := &types.AppConfig{
+		BaiduTransConfig:     &types.BaiduTransConfig{Enabled: true, AppId: "id", SecretKey: "key"},
+		DeepSeekTransConfig:  &types.DeepSeekTransConfig{Enabled: true, ApiKey: "key"},
+		TencentTransConfig:   &types.TencentTransConfig{Enabled: true, SecretId: "id", SecretKey: "key"},
+		MicrosoftTransConfig: &types.MicrosoftTransConfig{Enabled: true, ApiKey: "key"},
+		GoogleTransConfig:    &types.GoogleTransConfig{Enabled: true, ApiKey: "key"},
+		OllamaTransConfig:    &types.OllamaTransConfig{Enabled: true, Model: "qwen2.5:7b"},
+	}
+	factory := NewTranslatorFactory(config)
+	for _, provider := range factory.GetSupportedProviders() {
+		tr, err := factory.CreateTranslator(provider, map[string]interface{}{})
+		if err != nil {
+			t.Fatalf("无法创建提供商 %s: %v", provider, err)
+		}
+		if tr.GetInfo().Provider != provider {
+			t.Fatalf("提供商名称不一致: %s != %s", tr.GetInfo().Provider, provider)
+		}
+	}
+
+	config = types.NewDefaultConfig()
+	config.MicrosoftTransConfig.Enabled = true
+	config.MicrosoftTransConfig.ApiKey = "key"
+	config.OllamaTransConfig.Enabled = true
+	manager := NewTranslatorManager(config)
+	if manager.defaultProvider != "microsoft" || len(manager.fallbackProviders) != 1 || manager.fallbackProviders[0] != "ollama" {
+		t.Fatalf("默认提供商不正确: %s %v", manager.defaultProvider, manager.fallbackProviders)
+	}
+	if _, err := manager.GetDefaultTranslator(); err != nil {
+		t.Fatalf("默认翻译器创建失败: %v", err)
+	}
+}
+
+// stubTranslator 测试用翻译器
+type stubTranslator struct {
+	Translator
+	err error
+}
+
+func (s *stubTranslator) BatchTranslate(ctx context.Context, req *BatchTranslationRequest) (*BatchTranslationResult, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	var results []*TranslationResult
+	for _, text := range upper(req.Texts) {
+		results = append(results, &TranslationResult{TranslatedText: text})
+	}
+	return &BatchTranslationResult{Results: results}, nil
+}
+
+// TestBatchTranslateWithFallback 测试提供商链：跳过无法创建和失败的提供商
+func TestBatchTranslateWithFallback(t *testing.T) {
+	manager := NewTranslatorManager(types.NewDefaultConfig())
+	manager.RegisterTranslator("broken", &stubTranslator{err: context.DeadlineExceeded})
+	manager.RegisterTranslator("stub", &stubTranslator{})
+
+	req := &BatchTranslationRequest{Texts: []string{"hi"}, TargetLang: "zh"}
+	result, err := manager.BatchTranslateWithFallback(context.Background(), []string{"google", "broken", "stub"}, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Provider != "stub" || result.Results[0].TranslatedText != "HI" {
+		t.Fatalf("应由 stub 完成翻译: %+v", result)
+	}
+
+	if _, err := manager.BatchTranslateWithFallback(context.Background(), []string{"google", "broken"}, req); err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Fatalf("全部失败时应返回各提供商的错误: %v", err)
+	}
+}
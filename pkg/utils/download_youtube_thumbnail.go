@@ -46,6 +46,7 @@ type DownloadOptions struct {
 	QualityFallback  bool
 	CreateDirs       bool
 	Overwrite        bool
+	Transport        http.RoundTripper // 自定义传输（例如按域名路由的代理），为空时使用默认传输
 }
 
 type YouTubeThumbnailDownloader struct {
@@ -100,7 +101,7 @@ func (d *YouTubeThumbnailDownloader) downloadSingleQuality(videoID string, quali
 	var lastErr error
 	for attempt := 0; attempt < d.Options.MaxRetries; attempt++ {
 		log.Printf("尝试下载 %s 质量图片，第 %d 次: %s", quality, attempt+1, url)
-		client := &http.Client{Timeout: d.Options.Timeout}
+		client := &http.Client{Timeout: d.Options.Timeout, Transport: d.Options.Transport}
 		resp, err := client.Get(url)
 		if err != nil {
 			lastErr = err
@@ -40,6 +40,69 @@ func TranscodeVideo(inputVideoPath, outputVideoPath, preset string, crf int, aud
 	return nil
 }
 
+// BurnSubtitles 将字幕烧录到视频画面中（硬字幕），ASS 字幕使用文件内的样式，音频流直接复制
+// fontsDir 为额外的字体目录，可以为空
+func BurnSubtitles(inputVideoPath, subtitlePath, outputVideoPath, fontsDir, preset string, crf int) error {
+	inputVideoPath, err := filepath.Abs(inputVideoPath)
+	if err != nil {
+		return err
+	}
+	outputVideoPath, err = filepath.Abs(outputVideoPath)
+	if err != nil {
+		return err
+	}
+
+	// 在字幕所在目录执行 ffmpeg，滤镜参数中只需要文件名，避免路径中的特殊字符
+	filter := "subtitles=filename=" + escapeFilterValue(filepath.Base(subtitlePath))
+	if strings.EqualFold(filepath.Ext(subtitlePath), ".ass") {
+		filter = "ass=filename=" + escapeFilterValue(filepath.Base(subtitlePath))
+	}
+	if fontsDir != "" {
+		if fontsDir, err = filepath.Abs(fontsDir); err != nil {
+			return err
+		}
+		filter += ":fontsdir=" + escapeFilterValue(filepath.ToSlash(fontsDir))
+	}
+
+	cmd := []string{
+		"-y",
+		"-i", inputVideoPath,
+		"-vf", filter,
+		"-c:v", "libx264",
+		"-preset", preset,
+		"-crf", strconv.Itoa(crf),
+		"-pix_fmt", "yuv420p",
+		"-c:a", "copy",
+		"-movflags", "+faststart",
+		"-f", "mp4",
+		outputVideoPath,
+	}
+
+	ffmpegCmd := exec.Command("ffmpeg", cmd...)
+	ffmpegCmd.Dir = filepath.Dir(subtitlePath)
+
+	output, err := ffmpegCmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("烧录字幕失败: %v\n%s", err, lastLines(string(output), 10))
+	}
+	return nil
+}
+
+// escapeFilterValue 转义 ffmpeg 滤镜参数值（先转义参数级的特殊字符，再转义滤镜图级的特殊字符）
+func escapeFilterValue(value string) string {
+	value = strings.NewReplacer(`\`, `\\`, `'`, `\'`, `:`, `\:`).Replace(value)
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`, `[`, `\[`, `]`, `\]`, `,`, `\,`, `;`, `\;`).Replace(value)
+}
+
+// lastLines 返回输出的最后 n 行（ffmpeg 的错误信息通常在末尾）
+func lastLines(output string, n int) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
 // ExtractWaveAudio 从视频文件中分离出WAV格式的音频
 func ExtractWaveAudio(inputFile, outputFile string) error {
 	// 构造 ffmpeg 命令，提取音频并转换为WAV格式
@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NetscapeCookie Netscape cookies.txt 格式的单条 cookie
+type NetscapeCookie struct {
+	Domain     string
+	HostOnly   bool
+	Path       string
+	Secure     bool
+	Expiration int64 // Unix 时间戳，0 表示会话 cookie
+	Name       string
+	Value      string
+	HttpOnly   bool
+}
+
+// authCookieNames 决定登录状态的关键 cookie（YouTube/Google）
+var authCookieNames = map[string]bool{
+	"SID":               true,
+	"HSID":              true,
+	"SSID":              true,
+	"APISID":            true,
+	"SAPISID":           true,
+	"LOGIN_INFO":        true,
+	"__Secure-1PSID":    true,
+	"__Secure-3PSID":    true,
+	"__Secure-1PAPISID": true,
+	"__Secure-3PAPISID": true,
+}
+
+// ParseNetscapeCookies 解析 Netscape 格式的 cookies 文件（yt-dlp --cookies 使用的格式）
+func ParseNetscapeCookies(content []byte) ([]NetscapeCookie, error) {
+	var cookies []NetscapeCookie
+
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		httpOnly := false
+		if strings.HasPrefix(line, "#HttpOnly_") {
+			line = strings.TrimPrefix(line, "#HttpOnly_")
+			httpOnly = true
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) != 7 {
+			return nil, fmt.Errorf("第 %d 行格式错误: 需要 7 个以制表符分隔的字段，实际 %d 个", i+1, len(fields))
+		}
+
+		expiration, err := strconv.ParseInt(fields[4], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 行过期时间无效: %s", i+1, fields[4])
+		}
+
+		cookies = append(cookies, NetscapeCookie{
+			Domain:     fields[0],
+			HostOnly:   !strings.EqualFold(fields[1], "TRUE"),
+			Path:       fields[2],
+			Secure:     strings.EqualFold(fields[3], "TRUE"),
+			Expiration: expiration,
+			Name:       fields[5],
+			Value:      fields[6],
+			HttpOnly:   httpOnly,
+		})
+	}
+
+	if len(cookies) == 0 {
+		return nil, fmt.Errorf("cookies 文件中没有任何 cookie")
+	}
+
+	return cookies, nil
+}
+
+// CookiesExpiry 计算 cookies 的有效期
+// 优先取关键登录 cookie 中最早的过期时间；没有关键 cookie 时取所有持久 cookie 中最早的过期时间
+// 全部为会话 cookie 时返回 nil
+func CookiesExpiry(cookies []NetscapeCookie) *time.Time {
+	var authExpiry, anyExpiry int64
+	for _, cookie := range cookies {
+		if cookie.Expiration <= 0 {
+			continue
+		}
+		if anyExpiry == 0 || cookie.Expiration < anyExpiry {
+			anyExpiry = cookie.Expiration
+		}
+		if authCookieNames[cookie.Name] && (authExpiry == 0 || cookie.Expiration < authExpiry) {
+			authExpiry = cookie.Expiration
+		}
+	}
+
+	expiry := authExpiry
+	if expiry == 0 {
+		expiry = anyExpiry
+	}
+	if expiry == 0 {
+		return nil
+	}
+
+	t := time.Unix(expiry, 0)
+	return &t
+}
+
+// CookiesDomains 返回 cookies 覆盖的域名（去重）
+func CookiesDomains(cookies []NetscapeCookie) []string {
+	seen := map[string]bool{}
+	var domains []string
+	for _, cookie := range cookies {
+		domain := strings.TrimPrefix(cookie.Domain, ".")
+		if !seen[domain] {
+			seen[domain] = true
+			domains = append(domains, domain)
+		}
+	}
+	return domains
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const testCookiesFile = "# Netscape HTTP Cookie File\r\n" +
+	"# This is a generated file! Do not edit.\r\n" +
+	"\r\n" +
+	".youtube.com\tTRUE\t/\tTRUE\t1900000000\tPREF\tf6=40000000\r\n" +
+	"#HttpOnly_.youtube.com\tTRUE\t/\tTRUE\t1800000000\tSID\tabc\r\n" +
+	"accounts.google.com\tFALSE\t/\tFALSE\t0\tSESSION\tx\r\n"
+
+// TestParseNetscapeCookies 测试解析 cookies.txt（注释、#HttpOnly_ 前缀、CRLF、会话 cookie）
+func TestParseNetscapeCookies(t *testing.T) {
+	cookies, err := ParseNetscapeCookies([]byte(testCookiesFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cookies) != 3 {
+		t.Fatalf("解析到 %d 条 cookie，期望 3 条", len(cookies))
+	}
+
+	sid := cookies[1]
+	if sid.Name != "SID" || !sid.HttpOnly || sid.HostOnly || !sid.Secure || sid.Expiration != 1800000000 {
+		t.Errorf("SID 解析不正确: %+v", sid)
+	}
+	if session := cookies[2]; !session.HostOnly || session.Secure || session.Expiration != 0 {
+		t.Errorf("会话 cookie 解析不正确: %+v", session)
+	}
+
+	// 有效期取关键登录 cookie 的过期时间，而不是最早的任意 cookie
+	if expiry := CookiesExpiry(cookies); expiry == nil || !expiry.Equal(time.Unix(1800000000, 0)) {
+		t.Errorf("CookiesExpiry = %v", expiry)
+	}
+	if domains := CookiesDomains(cookies); strings.Join(domains, ",") != "youtube.com,accounts.google.com" {
+		t.Errorf("CookiesDomains = %v", domains)
+	}
+}
+
+// TestParseNetscapeCookiesInvalid 测试格式错误的 cookies 文件
+func TestParseNetscapeCookiesInvalid(t *testing.T) {
+	cases := map[string]string{
+		"7 个以制表符分隔的字段": ".youtube.com TRUE / TRUE 0 SID abc\n",
+		"过期时间":         ".youtube.com\tTRUE\t/\tTRUE\tnever\tSID\tabc\n",
+		"没有任何":         "# Netscape HTTP Cookie File\n\n",
+	}
+	for want, content := range cases {
+		if _, err := ParseNetscapeCookies([]byte(content)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("期望包含“%s”的错误，得到 %v", want, err)
+		}
+	}
+
+	if expiry := CookiesExpiry([]NetscapeCookie{{Name: "SID"}}); expiry != nil {
+		t.Errorf("全部为会话 cookie 时有效期应为 nil，得到 %v", expiry)
+	}
+}
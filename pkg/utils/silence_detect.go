@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SilenceInterval 静音区间（秒）
+type SilenceInterval struct {
+	Start float64
+	End   float64
+}
+
+var (
+	silenceStartPattern = regexp.MustCompile(`silence_start:\s*(-?[\d.]+)`)
+	silenceEndPattern   = regexp.MustCompile(`silence_end:\s*(-?[\d.]+)`)
+	durationPattern     = regexp.MustCompile(`Duration:\s*(\d+):(\d{2}):(\d{2}(?:\.\d+)?)`)
+)
+
+// DetectSilence 使用 ffmpeg silencedetect 检测音频中的静音区间，返回静音区间和媒体总时长（秒）
+// noiseDB 为静音阈值（如 -30），minDuration 为最短静音时长（秒）
+func DetectSilence(inputFile string, noiseDB, minDuration float64) ([]SilenceInterval, float64, error) {
+	filter := fmt.Sprintf("silencedetect=noise=%gdB:d=%g", noiseDB, minDuration)
+	cmd := exec.Command("ffmpeg", "-hide_banner", "-nostats", "-i", inputFile, "-vn", "-af", filter, "-f", "null", "-")
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, 0, fmt.Errorf("静音检测失败: %v\n%s", err, lastLines(string(output), 10))
+	}
+
+	silences, duration := ParseSilenceDetect(string(output))
+	return silences, duration, nil
+}
+
+// ParseSilenceDetect 解析 silencedetect 的输出；音频以静音结尾时最后一个区间延续到媒体结尾
+func ParseSilenceDetect(output string) ([]SilenceInterval, float64) {
+	var duration float64
+	if m := durationPattern.FindStringSubmatch(output); m != nil {
+		hours, _ := strconv.Atoi(m[1])
+		minutes, _ := strconv.Atoi(m[2])
+		seconds, _ := strconv.ParseFloat(m[3], 64)
+		duration = float64(hours*3600+minutes*60) + seconds
+	}
+
+	var silences []SilenceInterval
+	open := false
+	lines := strings.FieldsFunc(output, func(r rune) bool { return r == '\n' || r == '\r' })
+	for _, line := range lines {
+		if m := silenceStartPattern.FindStringSubmatch(line); m != nil {
+			start, _ := strconv.ParseFloat(m[1], 64)
+			if start < 0 {
+				start = 0
+			}
+			silences = append(silences, SilenceInterval{Start: start, End: duration})
+			open = true
+		} else if m := silenceEndPattern.FindStringSubmatch(line); m != nil && open {
+			end, _ := strconv.ParseFloat(m[1], 64)
+			silences[len(silences)-1].End = end
+			open = false
+		}
+	}
+	return silences, duration
+}
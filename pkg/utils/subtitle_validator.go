@@ -1,36 +1,38 @@
 package utils
 
 import (
-	"bufio"
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
-	"net/http"
-	"os"
-	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
 
+	"github.com/difyz9/ytb2bili/pkg/llm"
+	"github.com/difyz9/ytb2bili/pkg/prompt"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
+
 	"go.uber.org/zap"
 )
 
 // SubtitleValidator 字幕校验和优化器
 type SubtitleValidator struct {
 	logger        *zap.SugaredLogger
-	apiKey        string
-	maxRetries    int
+	provider      llm.Provider // 修复问题条目使用的AI服务，为空时只校验不修复
 	retryInterval time.Duration
+	targetLang    string      // 译文语言代码
+	targetName    string      // 译文语言名称（用于修复提示词）
+	prompts       *prompt.Set // 修复提示词模板，为空时使用内置默认
 }
 
 // SubtitleEntry 字幕条目
 type SubtitleEntry struct {
 	Index      int
 	TimeCode   string
-	Original   string // 原始英文
-	Translated string // 翻译中文
-	Status     string // 状态: "ok", "missing", "incomplete", "error"
+	Start      time.Duration // 开始时间
+	End        time.Duration // 结束时间
+	Original   string        // 原始英文
+	Translated string        // 翻译中文
+	Status     string        // 状态: "ok", "missing", "incomplete", "error"
 }
 
 // ValidationResult 校验结果
@@ -46,15 +48,27 @@ type ValidationResult struct {
 }
 
 // NewSubtitleValidator 创建字幕校验器
-func NewSubtitleValidator(logger *zap.SugaredLogger, apiKey string) *SubtitleValidator {
+func NewSubtitleValidator(logger *zap.SugaredLogger, provider llm.Provider) *SubtitleValidator {
 	return &SubtitleValidator{
 		logger:        logger,
-		apiKey:        apiKey,
-		maxRetries:    3,
+		provider:      provider,
 		retryInterval: 2 * time.Second,
+		targetLang:    "zh-Hans",
+		targetName:    "中文",
 	}
 }
 
+// SetTargetLanguage 设置译文语言（默认简体中文），影响未翻译检测和修复提示词
+func (v *SubtitleValidator) SetTargetLanguage(code, name string) {
+	v.targetLang = code
+	v.targetName = name
+}
+
+// SetPrompts 设置修复问题条目时使用的提示词模板
+func (v *SubtitleValidator) SetPrompts(prompts *prompt.Set) {
+	v.prompts = prompts
+}
+
 // ValidateAndFixSubtitles 校验并修复字幕文件
 func (v *SubtitleValidator) ValidateAndFixSubtitles(originalSRTPath, translatedSRTPath, outputPath string) (*ValidationResult, error) {
 	startTime := time.Now()
@@ -160,57 +174,22 @@ func (v *SubtitleValidator) ValidateAndFixSubtitles(originalSRTPath, translatedS
 
 // parseSRTFile 解析SRT文件
 func (v *SubtitleValidator) parseSRTFile(filePath string) ([]SubtitleEntry, error) {
-	file, err := os.Open(filePath)
+	cues, err := subtitle.ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
 
-	var entries []SubtitleEntry
-	scanner := bufio.NewScanner(file)
-
-	var currentEntry SubtitleEntry
-	var textLines []string
-	stage := 0 // 0=等待序号, 1=等待时间码, 2=读取文本
-
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-
-		if line == "" {
-			// 空行表示一个条目结束
-			if stage == 2 && len(textLines) > 0 {
-				currentEntry.Translated = strings.Join(textLines, "\n")
-				entries = append(entries, currentEntry)
-				textLines = nil
-				stage = 0
-			}
-			continue
+	entries := make([]SubtitleEntry, len(cues))
+	for i, cue := range cues {
+		entries[i] = SubtitleEntry{
+			Index:      cue.Index,
+			TimeCode:   cue.TimeCode(),
+			Start:      cue.Start,
+			End:        cue.End,
+			Translated: cue.Text,
 		}
-
-		switch stage {
-		case 0: // 读取序号
-			var index int
-			if _, err := fmt.Sscanf(line, "%d", &index); err == nil {
-				currentEntry = SubtitleEntry{Index: index}
-				stage = 1
-			}
-		case 1: // 读取时间码
-			if strings.Contains(line, "-->") {
-				currentEntry.TimeCode = line
-				stage = 2
-			}
-		case 2: // 读取文本
-			textLines = append(textLines, line)
-		}
-	}
-
-	// 处理最后一个条目
-	if stage == 2 && len(textLines) > 0 {
-		currentEntry.Translated = strings.Join(textLines, "\n")
-		entries = append(entries, currentEntry)
 	}
-
-	return entries, scanner.Err()
+	return entries, nil
 }
 
 // mergeAndAnalyzeEntries 合并并分析原始和翻译字幕
@@ -228,6 +207,8 @@ func (v *SubtitleValidator) mergeAndAnalyzeEntries(original, translated []Subtit
 		entry := SubtitleEntry{
 			Index:      translatedEntry.Index,
 			TimeCode:   translatedEntry.TimeCode,
+			Start:      translatedEntry.Start,
+			End:        translatedEntry.End,
 			Translated: translatedEntry.Translated,
 		}
 
@@ -237,7 +218,7 @@ func (v *SubtitleValidator) mergeAndAnalyzeEntries(original, translated []Subtit
 		}
 
 		// 分析翻译状态
-		entry.Status = v.analyzeTranslationStatus(entry.Translated)
+		entry.Status = v.analyzeTranslationStatus(entry.Original, entry.Translated)
 
 		entries = append(entries, entry)
 	}
@@ -246,7 +227,7 @@ func (v *SubtitleValidator) mergeAndAnalyzeEntries(original, translated []Subtit
 }
 
 // analyzeTranslationStatus 分析翻译状态
-func (v *SubtitleValidator) analyzeTranslationStatus(text string) string {
+func (v *SubtitleValidator) analyzeTranslationStatus(original, text string) string {
 	if text == "" {
 		return "missing"
 	}
@@ -281,8 +262,8 @@ func (v *SubtitleValidator) analyzeTranslationStatus(text string) string {
 		}
 	}
 
-	// 检测纯英文（可能翻译失败）
-	if v.isPureEnglish(text) && len(text) > 10 {
+	// 检测未翻译的原文（可能翻译失败）
+	if v.isUntranslated(original, text) && len(text) > 10 {
 		return "incomplete"
 	}
 
@@ -294,17 +275,25 @@ func (v *SubtitleValidator) analyzeTranslationStatus(text string) string {
 	return "ok"
 }
 
-// isPureEnglish 检测是否为纯英文
-func (v *SubtitleValidator) isPureEnglish(text string) bool {
-	// 简单的中文字符检测
-	chinesePattern := regexp.MustCompile(`[\p{Han}]`)
-	return !chinesePattern.MatchString(text)
+// isUntranslated 检测译文是否仍为原文：中日韩文按文字检测，其他语言与原文比较
+func (v *SubtitleValidator) isUntranslated(original, text string) bool {
+	lang := strings.ToLower(v.targetLang)
+	switch {
+	case strings.HasPrefix(lang, "zh"):
+		return !regexp.MustCompile(`[\p{Han}]`).MatchString(text)
+	case strings.HasPrefix(lang, "ja"):
+		return !regexp.MustCompile(`[\p{Han}\p{Hiragana}\p{Katakana}]`).MatchString(text)
+	case strings.HasPrefix(lang, "ko"):
+		return !regexp.MustCompile(`[\p{Hangul}]`).MatchString(text)
+	default:
+		return original != "" && strings.EqualFold(strings.TrimSpace(original), strings.TrimSpace(text))
+	}
 }
 
 // fixProblemEntries 修复问题条目
 func (v *SubtitleValidator) fixProblemEntries(problemEntries []SubtitleEntry) ([]SubtitleEntry, error) {
-	if v.apiKey == "" {
-		return nil, fmt.Errorf("API Key 未配置，无法进行自动修复")
+	if v.provider == nil {
+		return nil, fmt.Errorf("AI服务未配置，无法进行自动修复")
 	}
 
 	var fixedEntries []SubtitleEntry
@@ -355,31 +344,27 @@ func (v *SubtitleValidator) fixBatchEntries(entries []SubtitleEntry) ([]Subtitle
 	}
 
 	// 构建修复提示
-	systemPrompt := fmt.Sprintf(`你是专业的视频字幕翻译专家。现在需要重新翻译 %d 句有问题的英文字幕。
-
-翻译要求：
-1. 自然流畅：使用口语化表达，符合中文字幕习惯  
-2. 准确传神：忠实原文含义，保持语气和情感
-3. 简洁明了：字幕需要快速阅读，避免冗长
-4. 完整输出：必须为每句英文提供完整的中文翻译
-5. 数量严格：必须输出 %d 句翻译，不多不少
-6. 分隔符：每句翻译用"###SENTENCE_BREAK###"分隔
-
-注意：之前的翻译中可能有缺失或错误，请提供完整准确的重新翻译。
-
-输出格式：只返回中文翻译，用"###SENTENCE_BREAK###"分隔，不要添加序号或其他内容。`,
-		len(englishTexts), len(englishTexts))
+	systemPrompt := v.prompts.Render(prompt.ValidationFix, prompt.Vars{
+		TargetLang:     v.targetName,
+		TargetLangCode: v.targetLang,
+		Count:          len(englishTexts),
+		Separator:      "###SENTENCE_BREAK###",
+	})
 
 	combinedText := strings.Join(englishTexts, "\n###SENTENCE_BREAK###\n")
 
 	// 调用翻译API
-	translatedText, err := v.callDeepSeekAPI(systemPrompt, combinedText)
+	response, err := v.provider.Chat(context.Background(), &llm.Request{
+		Messages:    llm.Messages(systemPrompt, combinedText),
+		Temperature: 0.3,
+		MaxTokens:   4000,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("调用翻译API失败: %v", err)
 	}
 
 	// 解析翻译结果
-	translatedSentences := strings.Split(translatedText, "###SENTENCE_BREAK###")
+	translatedSentences := strings.Split(response.Content, "###SENTENCE_BREAK###")
 	for i := range translatedSentences {
 		translatedSentences[i] = strings.TrimSpace(translatedSentences[i])
 	}
@@ -412,192 +397,18 @@ func (v *SubtitleValidator) fixBatchEntries(entries []SubtitleEntry) ([]Subtitle
 
 // generateOptimizedSRT 生成优化后的SRT文件
 func (v *SubtitleValidator) generateOptimizedSRT(entries []SubtitleEntry, outputPath string) error {
-	// 确保输出目录存在
-	dir := filepath.Dir(outputPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("创建输出目录失败: %v", err)
-	}
-
-	file, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("创建输出文件失败: %v", err)
-	}
-	defer file.Close()
-
-	writer := bufio.NewWriter(file)
-	defer writer.Flush()
-
-	for _, entry := range entries {
-		// 写入序号
-		fmt.Fprintf(writer, "%d\n", entry.Index)
-
-		// 写入时间码
-		fmt.Fprintf(writer, "%s\n", entry.TimeCode)
-
-		// 写入翻译内容
-		fmt.Fprintf(writer, "%s\n\n", entry.Translated)
-	}
-
-	return nil
-}
-
-// GenerateValidationReport 生成校验报告
-func (v *SubtitleValidator) GenerateValidationReport(result *ValidationResult, reportPath string) error {
-	file, err := os.Create(reportPath)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	writer := bufio.NewWriter(file)
-	defer writer.Flush()
-
-	fmt.Fprintf(writer, "字幕校验报告\n")
-	fmt.Fprintf(writer, "========================================\n")
-	fmt.Fprintf(writer, "生成时间: %s\n", time.Now().Format("2006-01-02 15:04:05"))
-	fmt.Fprintf(writer, "处理时间: %v\n", result.ProcessingTime)
-	fmt.Fprintf(writer, "\n")
-
-	fmt.Fprintf(writer, "统计摘要:\n")
-	fmt.Fprintf(writer, "- 总条目数: %d\n", result.TotalEntries)
-	fmt.Fprintf(writer, "- 有效条目: %d (%.1f%%)\n", result.ValidEntries,
-		float64(result.ValidEntries)/float64(result.TotalEntries)*100)
-	fmt.Fprintf(writer, "- 问题条目: %d (%.1f%%)\n", result.MissingEntries,
-		float64(result.MissingEntries)/float64(result.TotalEntries)*100)
-	fmt.Fprintf(writer, "- 修复条目: %d\n", len(result.FixedEntries))
-	fmt.Fprintf(writer, "\n")
-
-	if len(result.IssueDetails) > 0 {
-		fmt.Fprintf(writer, "问题详情:\n")
-		for index, detail := range result.IssueDetails {
-			fmt.Fprintf(writer, "- 条目 %d: %s\n", index, detail)
+	cues := make([]subtitle.Cue, len(entries))
+	for i, entry := range entries {
+		cues[i] = subtitle.Cue{
+			Index: entry.Index,
+			Start: entry.Start,
+			End:   entry.End,
+			Text:  entry.Translated,
 		}
-		fmt.Fprintf(writer, "\n")
 	}
 
-	if len(result.FixedEntries) > 0 {
-		fmt.Fprintf(writer, "修复的条目: %v\n", result.FixedEntries)
+	if err := subtitle.WriteFile(outputPath, cues); err != nil {
+		return fmt.Errorf("写入优化字幕文件失败: %v", err)
 	}
-
 	return nil
 }
-
-// DeepSeek API 相关结构体
-type deepSeekRequest struct {
-	Model    string            `json:"model"`
-	Messages []deepSeekMessage `json:"messages"`
-	Stream   bool              `json:"stream"`
-	Settings *deepSeekSettings `json:"settings,omitempty"`
-}
-
-type deepSeekMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-}
-
-type deepSeekSettings struct {
-	Temperature float64 `json:"temperature,omitempty"`
-	MaxTokens   int     `json:"max_tokens,omitempty"`
-}
-
-type deepSeekResponse struct {
-	ID      string           `json:"id"`
-	Object  string           `json:"object"`
-	Created int64            `json:"created"`
-	Model   string           `json:"model"`
-	Choices []deepSeekChoice `json:"choices"`
-}
-
-type deepSeekChoice struct {
-	Index        int             `json:"index"`
-	Message      deepSeekMessage `json:"message"`
-	FinishReason string          `json:"finish_reason"`
-}
-
-// callDeepSeekAPI 调用DeepSeek API
-func (v *SubtitleValidator) callDeepSeekAPI(systemPrompt, userPrompt string) (string, error) {
-	var lastErr error
-
-	for attempt := 0; attempt <= v.maxRetries; attempt++ {
-		if attempt > 0 {
-			time.Sleep(v.retryInterval * time.Duration(attempt))
-		}
-
-		result, err := v.doRequest(systemPrompt, userPrompt)
-		if err == nil {
-			return result, nil
-		}
-
-		lastErr = err
-		v.logger.Warnf("API调用失败 (尝试 %d/%d): %v", attempt+1, v.maxRetries+1, err)
-
-		// 如果是API限制错误，延长等待时间
-		if strings.Contains(err.Error(), "rate limit") || strings.Contains(err.Error(), "429") {
-			time.Sleep(time.Duration(attempt+1) * 5 * time.Second)
-		}
-	}
-
-	return "", fmt.Errorf("重试 %d 次后仍然失败: %v", v.maxRetries, lastErr)
-}
-
-// doRequest 执行单次API请求
-func (v *SubtitleValidator) doRequest(systemPrompt, userPrompt string) (string, error) {
-	request := deepSeekRequest{
-		Model: "deepseek-chat",
-		Messages: []deepSeekMessage{
-			{
-				Role:    "system",
-				Content: systemPrompt,
-			},
-			{
-				Role:    "user",
-				Content: userPrompt,
-			},
-		},
-		Stream: false,
-		Settings: &deepSeekSettings{
-			Temperature: 0.3,
-			MaxTokens:   4000,
-		},
-	}
-
-	jsonData, err := json.Marshal(request)
-	if err != nil {
-		return "", fmt.Errorf("序列化请求失败: %v", err)
-	}
-
-	req, err := http.NewRequest("POST", "https://api.deepseek.com/v1/chat/completions", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return "", fmt.Errorf("创建请求失败: %v", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", v.apiKey))
-
-	client := &http.Client{Timeout: 60 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("发送请求失败: %v", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("读取响应失败: %v", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API返回错误 (状态码: %d): %s", resp.StatusCode, string(body))
-	}
-
-	var response deepSeekResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return "", fmt.Errorf("解析响应失败: %v", err)
-	}
-
-	if len(response.Choices) == 0 {
-		return "", fmt.Errorf("API响应中没有结果")
-	}
-
-	return response.Choices[0].Message.Content, nil
-}
@@ -1,12 +1,17 @@
 package utils
 
 import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -15,11 +20,27 @@ import (
 	"go.uber.org/zap"
 )
 
+// DefaultYtDlpReleaseAPI yt-dlp 的 GitHub Release API 地址
+const DefaultYtDlpReleaseAPI = "https://api.github.com/repos/yt-dlp/yt-dlp"
+
+// ytdlpChecksumAsset Release 中的校验文件名
+const ytdlpChecksumAsset = "SHA2-256SUMS"
+
 // YtDlpManager yt-dlp 管理器
 type YtDlpManager struct {
-	logger     *zap.SugaredLogger
-	installDir string
-	binaryPath string
+	logger      *zap.SugaredLogger
+	installDir  string
+	binaryPath  string // 使用的 yt-dlp（可能是系统中已安装的）
+	managedPath string // 安装目录中由本程序管理的 yt-dlp，安装和更新只写入这里
+	options     YtDlpOptions
+}
+
+// YtDlpOptions yt-dlp 安装/更新选项
+type YtDlpOptions struct {
+	ReleaseAPIURL  string // GitHub Release API 地址（为空时使用官方地址，测试时可替换为本地服务器）
+	PinnedVersion  string // 固定版本，为空表示最新版本
+	VerifyChecksum bool   // 是否校验 SHA2-256SUMS
+	SmokeTestURL   string // 冒烟测试使用的视频地址（为空时只检查 --version）
 }
 
 // GitHubRelease GitHub发布信息
@@ -33,6 +54,16 @@ type GitHubRelease struct {
 
 // NewYtDlpManager 创建 yt-dlp 管理器
 func NewYtDlpManager(logger *zap.SugaredLogger, installDir string) *YtDlpManager {
+	return NewYtDlpManagerWithOptions(logger, installDir, YtDlpOptions{VerifyChecksum: true})
+}
+
+// NewYtDlpManagerWithOptions 创建带版本管理选项的 yt-dlp 管理器
+func NewYtDlpManagerWithOptions(logger *zap.SugaredLogger, installDir string, options YtDlpOptions) *YtDlpManager {
+	if options.ReleaseAPIURL == "" {
+		options.ReleaseAPIURL = DefaultYtDlpReleaseAPI
+	}
+	options.ReleaseAPIURL = strings.TrimRight(options.ReleaseAPIURL, "/")
+
 	if installDir == "" {
 		// 默认安装目录
 		homeDir, _ := os.UserHomeDir()
@@ -45,9 +76,11 @@ func NewYtDlpManager(logger *zap.SugaredLogger, installDir string) *YtDlpManager
 	}
 
 	return &YtDlpManager{
-		logger:     logger,
-		installDir: installDir,
-		binaryPath: binaryPath,
+		logger:      logger,
+		installDir:  installDir,
+		binaryPath:  binaryPath,
+		managedPath: binaryPath,
+		options:     options,
 	}
 }
 
@@ -101,22 +134,46 @@ func (m *YtDlpManager) IsInstalled() bool {
 	return false
 }
 
+// IsManagedInstalled 安装目录中是否已有本程序管理的 yt-dlp
+func (m *YtDlpManager) IsManagedInstalled() bool {
+	_, err := os.Stat(m.managedPath)
+	return err == nil
+}
+
+// ManagedVersion 获取安装目录中 yt-dlp 的版本，未安装时返回空
+func (m *YtDlpManager) ManagedVersion() string {
+	if !m.IsManagedInstalled() {
+		return ""
+	}
+	output, err := exec.Command(m.managedPath, "--version").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
 // GetBinaryPath 获取 yt-dlp 二进制文件路径
 func (m *YtDlpManager) GetBinaryPath() string {
 	return m.binaryPath
 }
 
-// Install 下载并安装 yt-dlp
+// Install 下载并安装 yt-dlp（配置了固定版本时安装固定版本，否则安装最新版本）
 func (m *YtDlpManager) Install() error {
+	return m.InstallVersion(m.options.PinnedVersion)
+}
+
+// InstallVersion 下载并安装指定版本的 yt-dlp 到安装目录，version 为空表示最新版本
+// 系统中已安装的 yt-dlp（/usr/bin、PATH 等）不会被覆盖，安装后改用安装目录中的版本
+func (m *YtDlpManager) InstallVersion(version string) error {
 	m.logger.Info("📥 开始下载 yt-dlp...")
 
-	// 1. 获取最新版本信息
-	release, err := m.getLatestRelease()
+	// 1. 获取版本信息
+	release, err := m.GetRelease(version)
 	if err != nil {
-		return fmt.Errorf("获取最新版本信息失败: %v", err)
+		return fmt.Errorf("获取版本信息失败: %v", err)
 	}
 
-	m.logger.Infof("🔄 最新版本: %s", release.TagName)
+	m.logger.Infof("🔄 目标版本: %s", release.TagName)
 
 	// 2. 选择合适的下载链接
 	downloadURL, err := m.getDownloadURL(release)
@@ -124,34 +181,46 @@ func (m *YtDlpManager) Install() error {
 		return fmt.Errorf("获取下载链接失败: %v", err)
 	}
 
+	// 获取校验和
+	var checksum string
+	if m.options.VerifyChecksum {
+		if checksum, err = m.getChecksum(release, path.Base(downloadURL)); err != nil {
+			return fmt.Errorf("获取校验和失败: %v", err)
+		}
+	}
+
 	// 3. 创建安装目录
 	if err := os.MkdirAll(m.installDir, 0755); err != nil {
 		return fmt.Errorf("创建安装目录失败: %v", err)
 	}
 
 	// 4. 下载文件
-	if err := m.downloadFile(downloadURL); err != nil {
+	if err := m.downloadFile(downloadURL, checksum); err != nil {
 		return fmt.Errorf("下载文件失败: %v", err)
 	}
 
 	// 5. 设置执行权限 (非 Windows)
 	if runtime.GOOS != "windows" {
-		if err := os.Chmod(m.binaryPath, 0755); err != nil {
+		if err := os.Chmod(m.managedPath, 0755); err != nil {
 			return fmt.Errorf("设置执行权限失败: %v", err)
 		}
 	}
 
 	// 6. 验证安装
-	if !m.IsInstalled() {
+	if !m.IsManagedInstalled() {
 		return fmt.Errorf("安装验证失败")
 	}
+	m.binaryPath = m.managedPath
 
 	return nil
 }
 
-// getLatestRelease 获取最新版本信息
-func (m *YtDlpManager) getLatestRelease() (*GitHubRelease, error) {
-	url := "https://api.github.com/repos/yt-dlp/yt-dlp/releases/latest"
+// GetRelease 获取版本信息，version 为空表示最新版本
+func (m *YtDlpManager) GetRelease(version string) (*GitHubRelease, error) {
+	url := m.options.ReleaseAPIURL + "/releases/latest"
+	if version != "" {
+		url = m.options.ReleaseAPIURL + "/releases/tags/" + version
+	}
 
 	client := &http.Client{Timeout: 30 * time.Second}
 	resp, err := client.Get(url)
@@ -222,8 +291,47 @@ func (m *YtDlpManager) getDownloadURL(release *GitHubRelease) (string, error) {
 	return "", fmt.Errorf("未找到适合 %s/%s 的下载文件", runtime.GOOS, runtime.GOARCH)
 }
 
-// downloadFile 下载文件
-func (m *YtDlpManager) downloadFile(url string) error {
+// getChecksum 从 Release 的 SHA2-256SUMS 中获取指定文件的校验和
+func (m *YtDlpManager) getChecksum(release *GitHubRelease, fileName string) (string, error) {
+	var sumsURL string
+	for _, asset := range release.Assets {
+		if asset.Name == ytdlpChecksumAsset {
+			sumsURL = asset.BrowserDownloadURL
+			break
+		}
+	}
+	if sumsURL == "" {
+		return "", fmt.Errorf("版本 %s 中没有 %s", release.TagName, ytdlpChecksumAsset)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(sumsURL)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("下载 %s 失败，状态码: %d", ytdlpChecksumAsset, resp.StatusCode)
+	}
+
+	// 每行格式: <sha256>  <文件名>
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == fileName {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	return "", fmt.Errorf("%s 中没有 %s 的校验和", ytdlpChecksumAsset, fileName)
+}
+
+// downloadFile 下载文件，checksum 不为空时校验 SHA-256
+func (m *YtDlpManager) downloadFile(url, checksum string) error {
 	m.logger.Infof("📥 下载中: %s", url)
 
 	client := &http.Client{Timeout: 5 * time.Minute}
@@ -238,22 +346,33 @@ func (m *YtDlpManager) downloadFile(url string) error {
 	}
 
 	// 创建临时文件
-	tempFile := m.binaryPath + ".tmp"
+	tempFile := m.managedPath + ".tmp"
 	out, err := os.Create(tempFile)
 	if err != nil {
 		return err
 	}
 	defer out.Close()
 
-	// 下载文件并显示进度
-	_, err = io.Copy(out, resp.Body)
+	// 下载文件并计算校验和
+	hasher := sha256.New()
+	_, err = io.Copy(io.MultiWriter(out, hasher), resp.Body)
+	out.Close()
 	if err != nil {
 		os.Remove(tempFile)
 		return err
 	}
 
+	if checksum != "" {
+		actual := hex.EncodeToString(hasher.Sum(nil))
+		if actual != checksum {
+			os.Remove(tempFile)
+			return fmt.Errorf("校验和不匹配: 期望 %s，实际 %s", checksum, actual)
+		}
+		m.logger.Info("✅ 校验和验证通过")
+	}
+
 	// 移动到最终位置
-	if err := os.Rename(tempFile, m.binaryPath); err != nil {
+	if err := os.Rename(tempFile, m.managedPath); err != nil {
 		os.Remove(tempFile)
 		return err
 	}
@@ -263,39 +382,77 @@ func (m *YtDlpManager) downloadFile(url string) error {
 
 // checkVersion 检查版本信息
 func (m *YtDlpManager) checkVersion() error {
-	cmd := exec.Command(m.binaryPath, "--version")
-	output, err := cmd.Output()
+	version, err := m.InstalledVersion()
 	if err != nil {
 		m.logger.Warnf("⚠️  无法获取 yt-dlp 版本信息: %v", err)
 		return nil
 	}
 
-	version := strings.TrimSpace(string(output))
 	m.logger.Infof("📋 当前 yt-dlp 版本: %s", version)
+	if m.options.PinnedVersion != "" && version != m.options.PinnedVersion {
+		m.logger.Warnf("⚠️  当前版本与固定版本 %s 不一致", m.options.PinnedVersion)
+	}
 	return nil
 }
 
-// Update 更新 yt-dlp 到最新版本
+// InstalledVersion 获取已安装的 yt-dlp 版本
+func (m *YtDlpManager) InstalledVersion() (string, error) {
+	cmd := exec.Command(m.binaryPath, "--version")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// Update 更新 yt-dlp（配置了固定版本时更新到固定版本，否则更新到最新版本）
 func (m *YtDlpManager) Update() error {
+	return m.UpdateTo(m.options.PinnedVersion)
+}
+
+// UpdateTo 更新安装目录中的 yt-dlp 到指定版本，version 为空表示最新版本
+// 新版本未通过冒烟测试时自动回滚到之前的版本；首次安装未通过时删除新下载的文件，继续使用原来的 yt-dlp
+func (m *YtDlpManager) UpdateTo(version string) error {
 	m.logger.Info("🔄 更新 yt-dlp...")
+	previousPath := m.binaryPath
 
 	// 备份当前版本
-	backupPath := m.binaryPath + ".backup"
-	if err := os.Rename(m.binaryPath, backupPath); err != nil {
-		m.logger.Warnf("⚠️  无法备份当前版本: %v", err)
+	backupPath := m.managedPath + ".backup"
+	hasBackup := false
+	if m.IsManagedInstalled() {
+		if err := os.Rename(m.managedPath, backupPath); err != nil {
+			m.logger.Warnf("⚠️  无法备份当前版本: %v", err)
+		} else {
+			hasBackup = true
+		}
 	}
 
-	// 安装最新版本
-	if err := m.Install(); err != nil {
+	// 安装新版本
+	if err := m.InstallVersion(version); err != nil {
 		// 恢复备份
-		if _, statErr := os.Stat(backupPath); statErr == nil {
-			os.Rename(backupPath, m.binaryPath)
+		if hasBackup {
+			os.Rename(backupPath, m.managedPath)
 		}
 		return err
 	}
 
+	// 冒烟测试，失败时回滚
+	if err := m.SmokeTest(version); err != nil {
+		m.logger.Errorf("❌ 新版本冒烟测试失败: %v", err)
+		if !hasBackup {
+			os.Remove(m.managedPath)
+			m.binaryPath = previousPath
+			return fmt.Errorf("新安装的 yt-dlp 未通过冒烟测试，已删除: %v", err)
+		}
+		if renameErr := os.Rename(backupPath, m.managedPath); renameErr != nil {
+			return fmt.Errorf("冒烟测试失败且回滚失败: %v (回滚错误: %v)", err, renameErr)
+		}
+		m.logger.Warn("⏪ 已回滚到之前的 yt-dlp 版本")
+		return &YtDlpRollbackError{Err: err}
+	}
+
 	// 删除备份
-	if _, err := os.Stat(backupPath); err == nil {
+	if hasBackup {
 		os.Remove(backupPath)
 	}
 
@@ -317,3 +474,42 @@ func (m *YtDlpManager) Validate() error {
 
 	return nil
 }
+
+// SmokeTest 冒烟测试：检查版本号，配置了测试视频时再解析一次视频信息
+// expectedVersion 不为空时要求版本号一致
+func (m *YtDlpManager) SmokeTest(expectedVersion string) error {
+	version, err := m.InstalledVersion()
+	if err != nil {
+		return fmt.Errorf("无法运行 yt-dlp --version: %v", err)
+	}
+	if expectedVersion != "" && version != expectedVersion {
+		return fmt.Errorf("版本不一致: 期望 %s，实际 %s", expectedVersion, version)
+	}
+
+	if m.options.SmokeTestURL == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, m.binaryPath, "--simulate", "--skip-download", "--no-warnings", "--no-playlist", m.options.SmokeTestURL)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+		return fmt.Errorf("解析测试视频失败: %v, %s", err, lines[len(lines)-1])
+	}
+	return nil
+}
+
+// YtDlpRollbackError 新版本未通过冒烟测试，已回滚到旧版本
+type YtDlpRollbackError struct {
+	Err error
+}
+
+func (e *YtDlpRollbackError) Error() string {
+	return fmt.Sprintf("新版本未通过冒烟测试，已回滚: %v", e.Err)
+}
+
+func (e *YtDlpRollbackError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,198 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// YtDlpUpdateStatus yt-dlp 版本与最近一次更新结果
+type YtDlpUpdateStatus struct {
+	InstalledVersion string     `json:"installed_version"`
+	PinnedVersion    string     `json:"pinned_version"`
+	LatestVersion    string     `json:"latest_version"`
+	BinaryPath       string     `json:"binary_path"`
+	LastCheckAt      *time.Time `json:"last_check_at,omitempty"`
+	LastUpdateAt     *time.Time `json:"last_update_at,omitempty"`
+	LastResult       string     `json:"last_result"` // up_to_date, updated, rolled_back, failed
+	LastError        string     `json:"last_error,omitempty"`
+	PreviousVersion  string     `json:"previous_version,omitempty"`
+}
+
+// 更新结果
+const (
+	YtDlpResultUpToDate   = "up_to_date"
+	YtDlpResultUpdated    = "updated"
+	YtDlpResultRolledBack = "rolled_back"
+	YtDlpResultFailed     = "failed"
+)
+
+// ytdlpStatusFile 更新状态保存的文件名（位于安装目录）
+const ytdlpStatusFile = "update_status.json"
+
+// YtDlpUpdater yt-dlp 定时更新器
+// 按固定版本或最新版本检查更新，更新失败或冒烟测试失败时保留旧版本，并记录最近一次结果
+type YtDlpUpdater struct {
+	mu         sync.Mutex
+	logger     *zap.SugaredLogger
+	installDir string
+	options    YtDlpOptions
+	status     YtDlpUpdateStatus
+}
+
+// NewYtDlpUpdater 创建 yt-dlp 更新器
+func NewYtDlpUpdater(logger *zap.SugaredLogger, installDir string, options YtDlpOptions) *YtDlpUpdater {
+	u := &YtDlpUpdater{
+		logger:     logger,
+		installDir: installDir,
+		options:    options,
+	}
+	u.loadStatus()
+	return u
+}
+
+// manager 创建当前配置下的 yt-dlp 管理器
+func (u *YtDlpUpdater) manager() *YtDlpManager {
+	return NewYtDlpManagerWithOptions(u.logger, u.installDir, u.options)
+}
+
+// SetOptions 更新安装/更新选项（配置 API 修改后调用）
+func (u *YtDlpUpdater) SetOptions(options YtDlpOptions) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if options.ReleaseAPIURL == "" {
+		options.ReleaseAPIURL = u.options.ReleaseAPIURL
+	}
+	u.options = options
+}
+
+// Status 获取当前版本与最近一次更新结果
+func (u *YtDlpUpdater) Status() YtDlpUpdateStatus {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	m := u.manager()
+	status := u.status
+	status.PinnedVersion = u.options.PinnedVersion
+	if m.IsInstalled() {
+		status.BinaryPath = m.GetBinaryPath()
+		if version, err := m.InstalledVersion(); err == nil {
+			status.InstalledVersion = version
+		}
+	}
+	return status
+}
+
+// CheckAndUpdate 检查并更新 yt-dlp（定时任务调用）
+// 配置了固定版本时只会安装固定版本；否则更新到 GitHub 最新版本
+func (u *YtDlpUpdater) CheckAndUpdate() YtDlpUpdateStatus {
+	return u.UpdateTo("")
+}
+
+// UpdateTo 更新到指定版本，version 为空时使用固定版本或最新版本
+func (u *YtDlpUpdater) UpdateTo(version string) YtDlpUpdateStatus {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	now := time.Now()
+	u.status.LastCheckAt = &now
+	u.status.LastError = ""
+	u.status.PinnedVersion = u.options.PinnedVersion
+
+	// 只更新安装目录中的 yt-dlp，系统或包管理器安装的版本不会被覆盖
+	m := u.manager()
+	installed := m.ManagedVersion()
+	u.status.InstalledVersion = installed
+	if m.IsInstalled() {
+		u.status.BinaryPath = m.GetBinaryPath()
+	}
+
+	target := version
+	if target == "" {
+		target = u.options.PinnedVersion
+	}
+
+	release, err := m.GetRelease(target)
+	if err != nil {
+		u.fail(err)
+		return u.status
+	}
+	if target == "" {
+		u.status.LatestVersion = release.TagName
+	}
+
+	if installed == release.TagName {
+		u.logger.Infof("✅ yt-dlp 已是目标版本: %s", installed)
+		u.status.LastResult = YtDlpResultUpToDate
+		u.saveStatus()
+		return u.status
+	}
+
+	u.logger.Infof("🔄 yt-dlp 版本 %s → %s", installed, release.TagName)
+	if err = m.UpdateTo(release.TagName); err != nil {
+		var rollbackErr *YtDlpRollbackError
+		if errors.As(err, &rollbackErr) {
+			u.status.LastResult = YtDlpResultRolledBack
+			u.status.LastError = err.Error()
+			u.logger.Warnf("⏪ yt-dlp %s 未通过冒烟测试，继续使用 %s", release.TagName, installed)
+			u.saveStatus()
+			return u.status
+		}
+		u.fail(err)
+		return u.status
+	}
+
+	updatedAt := time.Now()
+	u.status.LastUpdateAt = &updatedAt
+	u.status.LastResult = YtDlpResultUpdated
+	u.status.PreviousVersion = installed
+	u.status.InstalledVersion = release.TagName
+	u.status.BinaryPath = m.GetBinaryPath()
+	u.logger.Infof("✅ yt-dlp 已更新到 %s", release.TagName)
+	u.saveStatus()
+	return u.status
+}
+
+// fail 记录更新失败
+func (u *YtDlpUpdater) fail(err error) {
+	u.logger.Errorf("❌ yt-dlp 更新失败: %v", err)
+	u.status.LastResult = YtDlpResultFailed
+	u.status.LastError = err.Error()
+	u.saveStatus()
+}
+
+// statusPath 更新状态文件路径
+func (u *YtDlpUpdater) statusPath() string {
+	return filepath.Join(u.manager().installDir, ytdlpStatusFile)
+}
+
+// loadStatus 读取上次保存的更新状态（重启后仍可查询最近一次结果）
+func (u *YtDlpUpdater) loadStatus() {
+	data, err := os.ReadFile(u.statusPath())
+	if err != nil {
+		return
+	}
+	if err := json.Unmarshal(data, &u.status); err != nil {
+		u.logger.Warnf("⚠️ 读取 yt-dlp 更新状态失败: %v", err)
+	}
+}
+
+// saveStatus 保存更新状态
+func (u *YtDlpUpdater) saveStatus() {
+	data, err := json.MarshalIndent(u.status, "", "  ")
+	if err != nil {
+		return
+	}
+	path := u.statusPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		u.logger.Warnf("⚠️ 保存 yt-dlp 更新状态失败: %v", err)
+	}
+}
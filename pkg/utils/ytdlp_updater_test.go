@@ -0,0 +1,177 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// fakeYtDlpAssets 测试用的可执行文件名（与 getDownloadURL 中的名称一致）
+var fakeYtDlpAssets = []string{"yt-dlp_linux", "yt-dlp_linux_aarch64", "yt-dlp_linux_armv7l", "yt-dlp_macos"}
+
+// fakeRelease 测试用的 Release
+type fakeRelease struct {
+	binary      string // 可执行文件内容
+	badChecksum bool   // 是否返回错误的校验和
+}
+
+// newFakeReleaseServer 模拟 GitHub Release API
+func newFakeReleaseServer(t *testing.T, latest string, releases map[string]fakeRelease) *httptest.Server {
+	t.Helper()
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		switch {
+		case r.URL.Path == "/releases/latest" || (len(parts) == 3 && parts[0] == "releases" && parts[1] == "tags"):
+			tag := latest
+			if len(parts) == 3 {
+				tag = parts[2]
+			}
+			if _, ok := releases[tag]; !ok {
+				http.NotFound(w, r)
+				return
+			}
+			release := GitHubRelease{TagName: tag}
+			for _, name := range append(fakeYtDlpAssets, ytdlpChecksumAsset) {
+				release.Assets = append(release.Assets, struct {
+					Name               string `json:"name"`
+					BrowserDownloadURL string `json:"browser_download_url"`
+				}{name, fmt.Sprintf("%s/download/%s/%s", server.URL, tag, name)})
+			}
+			json.NewEncoder(w).Encode(release)
+		case len(parts) == 3 && parts[0] == "download":
+			release, ok := releases[parts[1]]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			if parts[2] != ytdlpChecksumAsset {
+				w.Write([]byte(release.binary))
+				return
+			}
+			sum := sha256.Sum256([]byte(release.binary))
+			checksum := hex.EncodeToString(sum[:])
+			if release.badChecksum {
+				checksum = strings.Repeat("0", 64)
+			}
+			for _, name := range fakeYtDlpAssets {
+				fmt.Fprintf(w, "%s  %s\n", checksum, name)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func fakeYtDlpScript(version string) string {
+	return "#!/bin/sh\necho " + version + "\n"
+}
+
+func TestYtDlpUpdater(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake yt-dlp binary is a shell script")
+	}
+
+	releases := map[string]fakeRelease{
+		"2024.01.01": {binary: fakeYtDlpScript("2024.01.01")},
+		"2024.02.01": {binary: fakeYtDlpScript("2024.02.01")},
+		"2024.03.01": {binary: "#!/bin/sh\nexit 1\n"},                            // 无法运行的版本
+		"2024.04.01": {binary: fakeYtDlpScript("2024.04.01"), badChecksum: true}, // 校验和错误
+	}
+	server := newFakeReleaseServer(t, "2024.01.01", releases)
+
+	// PATH 中的“系统” yt-dlp 不能被安装或更新覆盖
+	systemDir := t.TempDir()
+	systemBinary := filepath.Join(systemDir, "yt-dlp")
+	if err := os.WriteFile(systemBinary, []byte(fakeYtDlpScript("2023.12.30")), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", systemDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	installDir := t.TempDir()
+	updater := NewYtDlpUpdater(zap.NewNop().Sugar(), installDir, YtDlpOptions{
+		ReleaseAPIURL:  server.URL,
+		VerifyChecksum: true,
+	})
+	installedVersion := func() string {
+		return NewYtDlpManager(zap.NewNop().Sugar(), installDir).ManagedVersion()
+	}
+
+	// 1. 首次安装最新版本
+	status := updater.CheckAndUpdate()
+	if status.LastResult != YtDlpResultUpdated || installedVersion() != "2024.01.01" {
+		t.Fatalf("install latest: result=%s err=%s version=%s", status.LastResult, status.LastError, installedVersion())
+	}
+
+	// 2. 已是最新版本
+	status = updater.CheckAndUpdate()
+	if status.LastResult != YtDlpResultUpToDate {
+		t.Fatalf("expected up_to_date, got %s (%s)", status.LastResult, status.LastError)
+	}
+
+	// 3. 固定版本
+	updater.SetOptions(YtDlpOptions{ReleaseAPIURL: server.URL, VerifyChecksum: true, PinnedVersion: "2024.02.01"})
+	status = updater.CheckAndUpdate()
+	if status.LastResult != YtDlpResultUpdated || installedVersion() != "2024.02.01" || status.PreviousVersion != "2024.01.01" {
+		t.Fatalf("pinned update: result=%s err=%s version=%s", status.LastResult, status.LastError, installedVersion())
+	}
+
+	// 4. 新版本冒烟测试失败，回滚
+	status = updater.UpdateTo("2024.03.01")
+	if status.LastResult != YtDlpResultRolledBack || installedVersion() != "2024.02.01" {
+		t.Fatalf("rollback: result=%s err=%s version=%s", status.LastResult, status.LastError, installedVersion())
+	}
+
+	// 5. 校验和不匹配，保留旧版本
+	status = updater.UpdateTo("2024.04.01")
+	if status.LastResult != YtDlpResultFailed || !strings.Contains(status.LastError, "校验和不匹配") || installedVersion() != "2024.02.01" {
+		t.Fatalf("checksum: result=%s err=%s version=%s", status.LastResult, status.LastError, installedVersion())
+	}
+
+	if content, _ := os.ReadFile(systemBinary); string(content) != fakeYtDlpScript("2023.12.30") {
+		t.Fatalf("system yt-dlp was overwritten: %q", content)
+	}
+
+	// 6. 状态持久化
+	if _, err := os.Stat(filepath.Join(installDir, ytdlpStatusFile)); err != nil {
+		t.Fatalf("status file not written: %v", err)
+	}
+	reloaded := NewYtDlpUpdater(zap.NewNop().Sugar(), installDir, YtDlpOptions{ReleaseAPIURL: server.URL})
+	if reloaded.Status().LastResult != YtDlpResultFailed {
+		t.Fatalf("status not restored: %+v", reloaded.Status())
+	}
+}
+
+// TestYtDlpFirstInstallSmokeTestFailure 首次安装的版本无法运行时删除该文件，不留下损坏的 yt-dlp
+func TestYtDlpFirstInstallSmokeTestFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake yt-dlp binary is a shell script")
+	}
+
+	server := newFakeReleaseServer(t, "2024.03.01", map[string]fakeRelease{
+		"2024.03.01": {binary: "#!/bin/sh\nexit 1\n"},
+	})
+	installDir := t.TempDir()
+	updater := NewYtDlpUpdater(zap.NewNop().Sugar(), installDir, YtDlpOptions{ReleaseAPIURL: server.URL, VerifyChecksum: true})
+
+	status := updater.CheckAndUpdate()
+	if status.LastResult != YtDlpResultFailed || !strings.Contains(status.LastError, "冒烟测试") {
+		t.Fatalf("expected failed, got %s (%s)", status.LastResult, status.LastError)
+	}
+	if _, err := os.Stat(filepath.Join(installDir, "yt-dlp")); !os.IsNotExist(err) {
+		t.Fatalf("broken binary left in install dir: %v", err)
+	}
+}
@@ -182,6 +182,7 @@ export default function TaskQueueStats({ onVideoSelect }: TaskQueueStatsProps) {
     const statusMap: { [key: string]: { label: string; color: string; icon: any; category: TabType } } = {
       '001': { label: '待处理', color: 'bg-gray-100 text-gray-700', icon: Clock, category: 'pending' },
       '002': { label: '处理中', color: 'bg-blue-100 text-blue-700', icon: Play, category: 'preparing' },
+      '100': { label: '等待字幕审核', color: 'bg-amber-100 text-amber-700', icon: Clock, category: 'preparing' },
       '200': { label: '准备就绪', color: 'bg-green-100 text-green-700', icon: CheckCircle, category: 'ready' },
       '201': { label: '上传视频中', color: 'bg-purple-100 text-purple-700', icon: Upload, category: 'uploading' },
       '299': { label: '上传失败', color: 'bg-red-100 text-red-700', icon: AlertCircle, category: 'failed' },
@@ -199,6 +200,7 @@ export default function TaskQueueStats({ onVideoSelect }: TaskQueueStatsProps) {
     const stageMap: { [key: string]: string } = {
       '001': '等待开始处理',
       '002': '正在执行准备任务链（下载视频→生成字幕→翻译字幕→生成元数据）',
+      '100': '字幕已生成，等待人工审核通过后进入上传队列',
       '200': '准备阶段完成，等待视频上传（每小时上传1个）',
       '201': '正在上传视频到Bilibili',
       '299': '视频上传失败，需要重试',
@@ -215,7 +217,7 @@ export default function TaskQueueStats({ onVideoSelect }: TaskQueueStatsProps) {
   const categorizeVideos = () => {
     return {
       pending: videos.filter(v => v.status === '001'),
-      preparing: videos.filter(v => v.status === '002'),
+      preparing: videos.filter(v => ['002', '100'].includes(v.status)),
       ready: videos.filter(v => v.status === '200'),
       uploading: videos.filter(v => ['201', '300', '301'].includes(v.status)),
       completed: videos.filter(v => v.status === '400'),
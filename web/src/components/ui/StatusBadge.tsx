@@ -15,6 +15,12 @@ const STATUS_CONFIG = {
     icon: RefreshCw,
     description: '正在下载和处理视频'
   },
+  '100': {
+    label: '等待字幕审核',
+    className: 'status-awaiting-review',
+    icon: FileText,
+    description: '字幕已生成，等待人工审核通过后上传'
+  },
   '200': {
     label: '准备就绪',
     className: 'status-ready',
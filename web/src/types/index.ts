@@ -143,6 +143,7 @@ export const TASK_STEP_NAMES = {
   'generate_subtitles': '生成字幕',
   'translate_subtitles': '翻译字幕',
   'generate_metadata': '生成元数据',
+  'generate_chapters': '生成章节',
   'upload_to_bilibili': '上传到B站',
   'upload_subtitles': '上传字幕',
 } as const;